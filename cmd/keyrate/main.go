@@ -0,0 +1,74 @@
+// Команда keyrate загружает историю ключевой ставки ЦБ за период и сохраняет ее в main.central_bank_rates.
+//
+// Использование:
+//
+//	keyrate -from 2024-01-01 -to 2025-07-31
+//
+// По умолчанию период заканчивается сегодня. Уже сохраненные ставки перезаписываются, поэтому загрузку можно повторять.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	postgres "github.com/MaxFando/bank-system/internal/adapter/repository/postgres/bank"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/MaxFando/bank-system/pkg/cb"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	fromFlag := flag.String("from", "", "period start in YYYY-MM-DD format, required")
+	toFlag := flag.String("to", "", "period end in YYYY-MM-DD format, today by default")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	if err := run(logger, *fromFlag, *toFlag); err != nil {
+		logger.Error("failed to backfill key rates", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger, fromFlag, toFlag string) error {
+	if fromFlag == "" {
+		return fmt.Errorf("from date is required")
+	}
+
+	from, err := time.Parse(time.DateOnly, fromFlag)
+	if err != nil {
+		return fmt.Errorf("invalid from date: %w", err)
+	}
+
+	to := time.Now().UTC()
+	if toFlag != "" {
+		to, err = time.Parse(time.DateOnly, toFlag)
+		if err != nil {
+			return fmt.Errorf("invalid to date: %w", err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cfg := config.Load()
+	db, err := sqlext.NewPostgresDB(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	provider := bank.NewKeyRateProvider(logger, cfg, postgres.NewFloatingRateRepository(db), cb.NewClient(cfg.KeyRateURL))
+
+	count, err := provider.Backfill(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("saved %d key rates from %s to %s\n", count, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	return nil
+}
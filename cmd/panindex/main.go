@@ -0,0 +1,60 @@
+// Команда panindex рассчитывает слепой индекс номера для карт, выпущенных до его появления. Без индекса на карту
+// нельзя перевести деньги по номеру и провести по ней операцию из платежной сети.
+//
+// Использование:
+//
+//	panindex
+//
+// Команда обрабатывает только карты без индекса, поэтому ее можно запускать повторно.
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	postgres "github.com/MaxFando/bank-system/internal/adapter/repository/postgres/bank"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"log/slog"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	if err := run(logger); err != nil {
+		logger.Error("failed to backfill card pan indexes", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cfg := config.Load()
+	db, err := sqlext.NewPostgresDB(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	accountService := bank.NewAccountService(logger, postgres.NewAccountRepository(db))
+	service := bank.NewCardService(
+		logger,
+		cfg,
+		accountService,
+		nil,
+		postgres.NewCardRepository(db),
+		postgres.NewCardTransactionRepository(db),
+		postgres.NewCardRuleRepository(db),
+	)
+
+	count, err := service.BackfillPANIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("indexed %d cards\n", count)
+	return nil
+}
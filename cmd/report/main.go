@@ -0,0 +1,104 @@
+// Команда report выгружает отчеты по кредитному портфелю в формате CSV.
+//
+// Использование:
+//
+//	report -report portfolio -as-of 2025-07-31 -out portfolio.csv
+//	report -report roll-rates -from 2025-06-30 -as-of 2025-07-31
+//
+// По умолчанию отчетная дата — сегодня, начало периода миграции — за месяц до нее, отчет выводится в stdout.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	postgres "github.com/MaxFando/bank-system/internal/adapter/repository/postgres/bank"
+	"github.com/MaxFando/bank-system/internal/core/bank/report"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	kind := flag.String("report", "portfolio", "report type: portfolio or roll-rates")
+	asOfFlag := flag.String("as-of", "", "as-of date in YYYY-MM-DD format, today by default")
+	fromFlag := flag.String("from", "", "roll rates period start in YYYY-MM-DD format, one month before as-of by default")
+	out := flag.String("out", "", "output file, stdout by default")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	if err := run(logger, *kind, *asOfFlag, *fromFlag, *out); err != nil {
+		logger.Error("failed to export report", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger, kind, asOfFlag, fromFlag, out string) (err error) {
+	asOf, err := parseDate(asOfFlag, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("invalid as-of date: %w", err)
+	}
+
+	from, err := parseDate(fromFlag, asOf.AddDate(0, -1, 0))
+	if err != nil {
+		return fmt.Errorf("invalid from date: %w", err)
+	}
+
+	if kind != "portfolio" && kind != "roll-rates" {
+		return fmt.Errorf("unknown report type: %s", kind)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cfg := config.Load()
+	db, err := sqlext.NewPostgresDB(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	service := bank.NewReportService(logger, postgres.NewReportRepository(db))
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("failed to close output file: %w", closeErr)
+			}
+		}()
+		w = file
+	}
+
+	if kind == "roll-rates" {
+		rates, err := service.RollRates(ctx, from, asOf)
+		if err != nil {
+			return err
+		}
+		return report.WriteRollRatesCSV(w, rates)
+	}
+
+	portfolio, err := service.Portfolio(ctx, asOf)
+	if err != nil {
+		return err
+	}
+	return report.WritePortfolioCSV(w, portfolio)
+}
+
+// parseDate разбирает дату в формате YYYY-MM-DD или возвращает fallback, если дата не указана.
+func parseDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
@@ -20,12 +20,12 @@ func NewAccountRepository(db sqlext.DB) *AccountRepository {
 
 func (r *AccountRepository) Save(ctx context.Context, account *entity.Account) (*entity.Account, error) {
 	query := `
-		INSERT INTO main.accounts (user_id, account_number, balance, account_type)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, user_id, account_number, balance, account_type;
+		INSERT INTO main.accounts (user_id, account_number, balance, account_type, credit_limit)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, account_number, balance, account_type, credit_limit;
 	`
 
-	err := r.db.Get(ctx, account, query, account.UserID, account.AccountNumber, account.Balance, account.AccountType)
+	err := r.db.Get(ctx, account, query, account.UserID, account.AccountNumber, account.Balance, account.AccountType, account.CreditLimit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save account: %w", err)
 	}
@@ -35,7 +35,7 @@ func (r *AccountRepository) Save(ctx context.Context, account *entity.Account) (
 
 func (r *AccountRepository) FindByID(ctx context.Context, id int32) (*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, balance, account_type
+		SELECT id, user_id, account_number, balance, account_type, credit_limit
 		FROM main.accounts
 		WHERE id = $1;
 	`
@@ -49,9 +49,43 @@ func (r *AccountRepository) FindByID(ctx context.Context, id int32) (*entity.Acc
 	return account, nil
 }
 
+// LockByID находит счет и блокирует его строку до конца текущей транзакции.
+func (r *AccountRepository) LockByID(ctx context.Context, id int32) (*entity.Account, error) {
+	query := `
+		SELECT id, user_id, account_number, balance, account_type, credit_limit
+		FROM main.accounts
+		WHERE id = $1
+		FOR UPDATE;
+	`
+
+	account := &entity.Account{}
+	err := r.db.Get(ctx, account, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock account by ID: %w", err)
+	}
+
+	return account, nil
+}
+
+// Update сохраняет баланс существующего счета.
+func (r *AccountRepository) Update(ctx context.Context, account *entity.Account) error {
+	query := `
+		UPDATE main.accounts
+		SET balance = $2, updated_at = NOW()
+		WHERE id = $1;
+	`
+
+	_, err := r.db.Exec(ctx, query, account.ID, account.Balance)
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	return nil
+}
+
 func (r *AccountRepository) GetAccountByUserID(ctx context.Context, userID int32) (*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, balance, account_type
+		SELECT id, user_id, account_number, balance, account_type, credit_limit
 		FROM main.accounts
 		WHERE user_id = $1;
 	`
@@ -0,0 +1,57 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+)
+
+type AgreementRepository struct {
+	db sqlext.DB
+}
+
+func NewAgreementRepository(db sqlext.DB) *AgreementRepository {
+	return &AgreementRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет выпущенный документ договора и заполняет его идентификатор и дату выпуска.
+// Договоры не изменяются, поэтому метода обновления нет.
+func (r AgreementRepository) Create(ctx context.Context, agreement *entity.CreditAgreement) error {
+	query := `
+		INSERT INTO main.credit_agreements (credit_id, format, content, content_hash, signature)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	err := r.db.Get(ctx, agreement, query, agreement.CreditID, agreement.Format, agreement.Content, agreement.ContentHash, agreement.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to create credit agreement: %w", err)
+	}
+
+	return nil
+}
+
+// Get возвращает договор по кредиту creditID в формате format или entity.ErrAgreementNotFound, если его нет.
+func (r AgreementRepository) Get(ctx context.Context, creditID int32, format entity.DocumentFormat) (*entity.CreditAgreement, error) {
+	query := `
+		SELECT id, credit_id, format, content, content_hash, signature, created_at
+		FROM main.credit_agreements
+		WHERE credit_id = $1 AND format = $2;
+	`
+
+	agreement := new(entity.CreditAgreement)
+	err := r.db.Get(ctx, agreement, query, creditID, format)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrAgreementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit agreement: %w", err)
+	}
+
+	return agreement, nil
+}
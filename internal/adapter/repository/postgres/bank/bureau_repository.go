@@ -0,0 +1,138 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"time"
+)
+
+type BureauRepository struct {
+	db sqlext.DB
+}
+
+func NewBureauRepository(db sqlext.DB) *BureauRepository {
+	return &BureauRepository{
+		db: db,
+	}
+}
+
+// ListRecords возвращает кредиты, действовавшие в периоде [from, to), вместе с данными заемщиков и графиками платежей.
+// Кредит действовал в периоде, если оформлен раньше to и на начало периода по нему оставались непогашенные платежи.
+func (r BureauRepository) ListRecords(ctx context.Context, from, to time.Time) ([]entity.BureauRecord, error) {
+	creditsQuery := `
+		SELECT c.id, c.user_id, COALESCE(c.account_id, 0) AS account_id, c.amount, c.interest_rate, c.term_in_months,
+		       COALESCE(c.status, '') AS status, c.schedule_type, c.day_count, c.created_at, c.updated_at,
+		       COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name, u.date_of_birth
+		FROM main.credits c
+		JOIN main.users u ON u.id = c.user_id
+		WHERE c.created_at < $2
+		  AND EXISTS (SELECT 1 FROM main.payment_schedules ps
+		              WHERE ps.credit_id = c.id AND (ps.paid_at IS NULL OR ps.paid_at >= $1))
+		ORDER BY c.id;
+	`
+
+	var records []entity.BureauRecord
+	if err := r.db.Select(ctx, &records, creditsQuery, from, to); err != nil {
+		return nil, fmt.Errorf("failed to list bureau credits: %w", err)
+	}
+
+	scheduleQuery := `
+		SELECT ps.id, ps.credit_id, ps.payment_date, ps.payment_amount, ps.principal_amount, ps.interest_amount,
+		       COALESCE(ps.penalty, 0) AS penalty, COALESCE(ps.balance, 0) AS balance, ps.status, ps.paid_amount, ps.paid_at,
+		       ps.penalty_paid, ps.penalty_waived, ps.penalty_waived_by, ps.penalty_accrued_at
+		FROM main.payment_schedules ps
+		JOIN main.credits c ON c.id = ps.credit_id
+		WHERE c.created_at < $2
+		  AND EXISTS (SELECT 1 FROM main.payment_schedules p
+		              WHERE p.credit_id = c.id AND (p.paid_at IS NULL OR p.paid_at >= $1))
+		ORDER BY ps.credit_id, ps.payment_date, ps.id;
+	`
+
+	var payments []entity.PaymentSchedule
+	if err := r.db.Select(ctx, &payments, scheduleQuery, from, to); err != nil {
+		return nil, fmt.Errorf("failed to list bureau payment schedules: %w", err)
+	}
+
+	index := make(map[int32]int, len(records))
+	for i, record := range records {
+		index[record.ID] = i
+	}
+	for _, payment := range payments {
+		if i, ok := index[payment.CreditID]; ok {
+			records[i].Schedule = append(records[i].Schedule, payment)
+		}
+	}
+
+	return records, nil
+}
+
+// CreateSubmission сохраняет файл, переданный в бюро, и заполняет его идентификатор и дату формирования.
+// Возвращает entity.ErrBureauPeriodSubmitted, если файл за этот период уже сохранен.
+func (r BureauRepository) CreateSubmission(ctx context.Context, submission *entity.BureauSubmission) error {
+	query := `
+		INSERT INTO main.bureau_submissions (period, file_name, content, content_hash, records)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (period) DO NOTHING
+		RETURNING id, created_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		submission,
+		query,
+		submission.Period,
+		submission.FileName,
+		submission.Content,
+		submission.ContentHash,
+		submission.Records,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrBureauPeriodSubmitted
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create bureau submission: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubmission возвращает файл за период period или entity.ErrBureauSubmissionNotFound, если он не передавался.
+func (r BureauRepository) GetSubmission(ctx context.Context, period time.Time) (*entity.BureauSubmission, error) {
+	query := `
+		SELECT id, period, file_name, content, content_hash, records, created_at
+		FROM main.bureau_submissions
+		WHERE period = $1;
+	`
+
+	submission := new(entity.BureauSubmission)
+	err := r.db.Get(ctx, submission, query, period)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrBureauSubmissionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bureau submission: %w", err)
+	}
+
+	return submission, nil
+}
+
+// ListSubmissions возвращает переданные в бюро файлы без содержимого, начиная с последнего периода.
+func (r BureauRepository) ListSubmissions(ctx context.Context) ([]entity.BureauSubmission, error) {
+	query := `
+		SELECT id, period, file_name, content_hash, records, created_at
+		FROM main.bureau_submissions
+		ORDER BY period DESC;
+	`
+
+	submissions := []entity.BureauSubmission{}
+	err := r.db.Select(ctx, &submissions, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bureau submissions: %w", err)
+	}
+
+	return submissions, nil
+}
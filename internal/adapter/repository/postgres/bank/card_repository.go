@@ -2,9 +2,12 @@ package bank
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/MaxFando/bank-system/internal/core/bank/entity"
 	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"time"
 )
 
 type CardRepository struct {
@@ -18,10 +21,27 @@ func NewCardRepository(db sqlext.DB) *CardRepository {
 }
 
 func (c CardRepository) Save(ctx context.Context, card *entity.Card) (*entity.Card, error) {
-	query := `INSERT INTO main.cards (account_id, encrypted_data, hmac) VALUES ($1, $2, $3) RETURNING id`
+	query := `
+		INSERT INTO main.cards (account_id, encrypted_data, hmac, pan_index, kind, status, expiration_date, spend_limit, reissued_from_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
 
 	var id int32
-	err := c.db.Get(ctx, &id, query, card.AccountID, card.EncryptedData, card.HMAC)
+	err := c.db.Get(
+		ctx,
+		&id,
+		query,
+		card.AccountID,
+		card.EncryptedData,
+		card.HMAC,
+		card.PANIndex,
+		card.Kind,
+		card.Status,
+		card.ExpirationDate,
+		card.SpendLimit,
+		card.ReissuedFromID,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save card: %w", err)
 	}
@@ -31,8 +51,31 @@ func (c CardRepository) Save(ctx context.Context, card *entity.Card) (*entity.Ca
 	return card, nil
 }
 
+// Update сохраняет изменившиеся статус и сумму списаний по карте.
+func (c CardRepository) Update(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+	query := `
+		UPDATE main.cards
+		SET status       = $2,
+			spent_amount = $3,
+			updated_at   = NOW()
+		WHERE id = $1
+	`
+
+	_, err := c.db.Exec(ctx, query, card.ID, card.Status, card.SpentAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update card: %w", err)
+	}
+
+	return card, nil
+}
+
 func (c CardRepository) FindByID(ctx context.Context, id int32) (*entity.Card, error) {
-	query := `SELECT id, account_id, encrypted_data, hmac FROM main.cards WHERE id = $1`
+	query := `
+		SELECT id, account_id, encrypted_data, hmac, COALESCE(pan_index, '') AS pan_index,
+		       kind, status, expiration_date, spend_limit, spent_amount
+		FROM main.cards
+		WHERE id = $1
+	`
 
 	card := &entity.Card{}
 	err := c.db.Get(ctx, card, query, id)
@@ -43,8 +86,32 @@ func (c CardRepository) FindByID(ctx context.Context, id int32) (*entity.Card, e
 	return card, nil
 }
 
+// LockByID находит карту и блокирует ее строку до конца текущей транзакции.
+func (c CardRepository) LockByID(ctx context.Context, id int32) (*entity.Card, error) {
+	query := `
+		SELECT id, account_id, encrypted_data, hmac, COALESCE(pan_index, '') AS pan_index,
+		       kind, status, expiration_date, spend_limit, spent_amount
+		FROM main.cards
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	card := &entity.Card{}
+	err := c.db.Get(ctx, card, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock card by ID: %w", err)
+	}
+
+	return card, nil
+}
+
 func (c CardRepository) FindByAccountID(ctx context.Context, accountID int32) ([]entity.Card, error) {
-	query := `SELECT id, account_id, encrypted_data, hmac FROM main.cards WHERE account_id = $1`
+	query := `
+		SELECT id, account_id, encrypted_data, hmac, COALESCE(pan_index, '') AS pan_index,
+		       kind, status, expiration_date, spend_limit, spent_amount
+		FROM main.cards
+		WHERE account_id = $1
+	`
 
 	var cards []entity.Card
 	err := c.db.Select(ctx, &cards, query, accountID)
@@ -54,3 +121,138 @@ func (c CardRepository) FindByAccountID(ctx context.Context, accountID int32) ([
 
 	return cards, nil
 }
+
+// FindByPANIndex находит карту по слепому индексу номера.
+func (c CardRepository) FindByPANIndex(ctx context.Context, panIndex string) (*entity.Card, error) {
+	query := `
+		SELECT id, account_id, encrypted_data, hmac, pan_index,
+		       kind, status, expiration_date, spend_limit, spent_amount
+		FROM main.cards
+		WHERE pan_index = $1
+	`
+
+	card := &entity.Card{}
+	err := c.db.Get(ctx, card, query, panIndex)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find card by pan index: %w", err)
+	}
+
+	return card, nil
+}
+
+// FindRecipientByPANIndex находит карту по слепому индексу номера и возвращает данные ее держателя.
+func (c CardRepository) FindRecipientByPANIndex(ctx context.Context, panIndex string) (*entity.CardRecipient, error) {
+	query := `
+		SELECT c.id, c.status, COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name
+		FROM main.cards c
+		JOIN main.accounts a ON a.id = c.account_id
+		JOIN main.users u ON u.id = a.user_id
+		WHERE c.pan_index = $1
+	`
+
+	var row struct {
+		ID        int32             `db:"id"`
+		Status    entity.CardStatus `db:"status"`
+		FirstName string            `db:"first_name"`
+		LastName  string            `db:"last_name"`
+	}
+	err := c.db.Get(ctx, &row, query, panIndex)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find card by pan index: %w", err)
+	}
+
+	return &entity.CardRecipient{
+		CardID:     row.ID,
+		Status:     row.Status,
+		MaskedName: entity.MaskHolderName(row.FirstName, row.LastName),
+	}, nil
+}
+
+// FindWithoutPANIndex возвращает до limit карт без слепого индекса номера с идентификатором больше afterID
+// в порядке возрастания идентификатора.
+func (c CardRepository) FindWithoutPANIndex(ctx context.Context, afterID int32, limit int) ([]entity.Card, error) {
+	query := `
+		SELECT id, account_id, encrypted_data, hmac, '' AS pan_index,
+		       kind, status, expiration_date, spend_limit, spent_amount
+		FROM main.cards
+		WHERE pan_index IS NULL
+		  AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	var cards []entity.Card
+	err := c.db.Select(ctx, &cards, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cards without pan index: %w", err)
+	}
+
+	return cards, nil
+}
+
+// SetPANIndex сохраняет слепой индекс номера карты.
+func (c CardRepository) SetPANIndex(ctx context.Context, id int32, panIndex string) error {
+	query := `
+		UPDATE main.cards
+		SET pan_index  = $2,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := c.db.Exec(ctx, query, id, panIndex)
+	if err != nil {
+		return fmt.Errorf("failed to set card pan index: %w", err)
+	}
+
+	return nil
+}
+
+// FindExpiring возвращает активные физические и виртуальные карты со сроком действия до before,
+// для которых еще не выпущена замена.
+func (c CardRepository) FindExpiring(ctx context.Context, before time.Time) ([]entity.Card, error) {
+	query := `
+		SELECT c.id, c.account_id, c.encrypted_data, c.hmac, COALESCE(c.pan_index, '') AS pan_index,
+		       c.kind, c.status, c.expiration_date, c.spend_limit, c.spent_amount
+		FROM main.cards c
+		WHERE c.kind IN ('physical', 'virtual')
+		  AND c.status = 'active'
+		  AND c.expiration_date < $1::date
+		  AND NOT EXISTS (SELECT 1 FROM main.cards r WHERE r.reissued_from_id = c.id)
+		ORDER BY c.expiration_date
+	`
+
+	var cards []entity.Card
+	err := c.db.Select(ctx, &cards, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expiring cards: %w", err)
+	}
+
+	return cards, nil
+}
+
+// ExpireOutdated переводит в статус expired активные карты, срок действия которых истек до now.
+// Возвращает идентификаторы просроченных карт.
+func (c CardRepository) ExpireOutdated(ctx context.Context, now time.Time) ([]int32, error) {
+	query := `
+		UPDATE main.cards
+		SET status     = 'expired',
+			updated_at = NOW()
+		WHERE status = 'active'
+		  AND expiration_date < $1::date
+		RETURNING id
+	`
+
+	var ids []int32
+	err := c.db.Select(ctx, &ids, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire cards: %w", err)
+	}
+
+	return ids, nil
+}
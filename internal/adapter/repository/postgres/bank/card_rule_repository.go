@@ -0,0 +1,101 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+type CardRuleRepository struct {
+	db sqlext.DB
+}
+
+func NewCardRuleRepository(db sqlext.DB) *CardRuleRepository {
+	return &CardRuleRepository{
+		db: db,
+	}
+}
+
+type cardRulesRow struct {
+	CardID           int32          `db:"card_id"`
+	BlockedMCCGroups pq.StringArray `db:"blocked_mcc_groups"`
+	OnlineOnly       bool           `db:"online_only"`
+	DomesticOnly     bool           `db:"domestic_only"`
+	MonthlyLimits    []byte         `db:"monthly_limits"`
+}
+
+// GetByCardID возвращает правила карты. Если правила не заданы, возвращаются пустые правила.
+func (r CardRuleRepository) GetByCardID(ctx context.Context, cardID int32) (*entity.CardRules, error) {
+	query := `
+		SELECT card_id, blocked_mcc_groups, online_only, domestic_only, monthly_limits
+		FROM main.card_rules
+		WHERE card_id = $1;
+	`
+
+	var row cardRulesRow
+	err := r.db.Get(ctx, &row, query, cardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &entity.CardRules{CardID: cardID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find card rules: %w", err)
+	}
+
+	rules := &entity.CardRules{
+		CardID:       row.CardID,
+		OnlineOnly:   row.OnlineOnly,
+		DomesticOnly: row.DomesticOnly,
+	}
+
+	for _, group := range row.BlockedMCCGroups {
+		rules.BlockedMCCGroups = append(rules.BlockedMCCGroups, entity.MCCGroup(group))
+	}
+
+	if err := json.Unmarshal(row.MonthlyLimits, &rules.MonthlyLimits); err != nil {
+		return nil, fmt.Errorf("failed to decode monthly limits: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Save сохраняет правила карты, заменяя ранее установленные.
+func (r CardRuleRepository) Save(ctx context.Context, rules *entity.CardRules) (*entity.CardRules, error) {
+	query := `
+		INSERT INTO main.card_rules (card_id, blocked_mcc_groups, online_only, domestic_only, monthly_limits)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (card_id) DO UPDATE
+		SET blocked_mcc_groups = EXCLUDED.blocked_mcc_groups,
+			online_only        = EXCLUDED.online_only,
+			domestic_only      = EXCLUDED.domestic_only,
+			monthly_limits     = EXCLUDED.monthly_limits,
+			updated_at         = NOW();
+	`
+
+	blocked := make(pq.StringArray, 0, len(rules.BlockedMCCGroups))
+	for _, group := range rules.BlockedMCCGroups {
+		blocked = append(blocked, string(group))
+	}
+
+	limits := rules.MonthlyLimits
+	if limits == nil {
+		limits = map[entity.MCCGroup]decimal.Decimal{}
+	}
+
+	monthlyLimits, err := json.Marshal(limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode monthly limits: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, query, rules.CardID, blocked, rules.OnlineOnly, rules.DomesticOnly, monthlyLimits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save card rules: %w", err)
+	}
+
+	return rules, nil
+}
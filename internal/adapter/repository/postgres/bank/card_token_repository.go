@@ -0,0 +1,81 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+)
+
+type CardTokenRepository struct {
+	db sqlext.DB
+}
+
+func NewCardTokenRepository(db sqlext.DB) *CardTokenRepository {
+	return &CardTokenRepository{
+		db: db,
+	}
+}
+
+// FindByCardID возвращает токен карты или entity.ErrCardTokenNotFound, если токен еще не выпущен.
+func (r CardTokenRepository) FindByCardID(ctx context.Context, cardID int32) (entity.CardToken, error) {
+	query := `
+		SELECT token
+		FROM main.card_tokens
+		WHERE card_id = $1;
+	`
+
+	var token entity.CardToken
+	err := r.db.Get(ctx, &token, query, cardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", entity.ErrCardTokenNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find card token: %w", err)
+	}
+
+	return token, nil
+}
+
+// FindCardID возвращает идентификатор карты по токену или entity.ErrCardTokenNotFound, если токен неизвестен.
+func (r CardTokenRepository) FindCardID(ctx context.Context, token entity.CardToken) (int32, error) {
+	query := `
+		SELECT card_id
+		FROM main.card_tokens
+		WHERE token = $1;
+	`
+
+	var cardID int32
+	err := r.db.Get(ctx, &cardID, query, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, entity.ErrCardTokenNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find card by token: %w", err)
+	}
+
+	return cardID, nil
+}
+
+// Save сохраняет токен карты. Возвращает false, если у карты уже есть токен или такой токен уже выдан другой карте.
+func (r CardTokenRepository) Save(ctx context.Context, cardID int32, token entity.CardToken) (bool, error) {
+	query := `
+		INSERT INTO main.card_tokens (token, card_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+
+	result, err := r.db.Exec(ctx, query, token, cardID)
+	if err != nil {
+		return false, fmt.Errorf("failed to save card token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to save card token: %w", err)
+	}
+
+	return rows == 1, nil
+}
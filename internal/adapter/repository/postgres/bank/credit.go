@@ -2,10 +2,14 @@ package bank
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/MaxFando/bank-system/internal/core/bank/entity"
 	"github.com/MaxFando/bank-system/pkg/sqlext"
 	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/shopspring/decimal"
+	"time"
 )
 
 type CreditRepository struct {
@@ -20,9 +24,28 @@ func NewCreditRepository(db sqlext.DB) *CreditRepository {
 }
 
 func (c CreditRepository) Save(ctx context.Context, credit *entity.Credit) (*entity.Credit, error) {
-	query := `INSERT INTO main.credits (user_id, amount, interest_rate, term_in_months) values ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO main.credits (user_id, account_id, amount, interest_rate, term_in_months, schedule_type, day_count,
+                          rate_type, rate_margin, rate_reset_months, next_rate_reset, status, created_at)
+				values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
 
-	err := c.db.Get(ctx, credit, query, credit.UserID, credit.Amount, credit.InterestRate, credit.TermInMonths)
+	err := c.db.Get(
+		ctx,
+		credit,
+		query,
+		credit.UserID,
+		credit.AccountID,
+		credit.Amount,
+		credit.InterestRate,
+		credit.TermInMonths,
+		credit.ScheduleType,
+		credit.DayCount,
+		credit.RateType,
+		credit.RateMargin,
+		credit.RateResetMonths,
+		credit.NextRateReset,
+		credit.Status,
+		credit.CreatedAt,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save credit: %w", err)
 	}
@@ -31,8 +54,9 @@ func (c CreditRepository) Save(ctx context.Context, credit *entity.Credit) (*ent
 }
 
 func (c CreditRepository) CreatePaymentSchedule(ctx context.Context, paymentSchedule *entity.PaymentSchedule) (*entity.PaymentSchedule, error) {
-	query := `INSERT INTO main.payment_schedules (credit_id, payment_date, payment_amount, principal_amount, interest_amount)
-    				VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO main.payment_schedules (credit_id, payment_date, payment_amount, principal_amount, interest_amount, balance,
+                                    status, paid_amount, paid_at)
+    				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 
 	err := c.db.Get(
 		ctx,
@@ -43,6 +67,10 @@ func (c CreditRepository) CreatePaymentSchedule(ctx context.Context, paymentSche
 		paymentSchedule.PaymentAmount,
 		paymentSchedule.PrincipalAmount,
 		paymentSchedule.InterestAmount,
+		paymentSchedule.Balance,
+		paymentSchedule.Status,
+		paymentSchedule.PaidAmount,
+		paymentSchedule.PaidAt,
 	)
 
 	if err != nil {
@@ -52,11 +80,17 @@ func (c CreditRepository) CreatePaymentSchedule(ctx context.Context, paymentSche
 	return paymentSchedule, nil
 }
 
+// GetCreditByID возвращает кредит по идентификатору или entity.ErrCreditNotFound, если кредита нет.
 func (c CreditRepository) GetCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error) {
-	query := `SELECT id, user_id, amount, interest_rate, term_in_months FROM main.credits WHERE id = $1`
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits WHERE id = $1`
 	credit := new(entity.Credit)
 
 	err := c.db.Get(ctx, credit, query, creditID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credit by ID: %w", err)
 	}
@@ -64,9 +98,57 @@ func (c CreditRepository) GetCreditByID(ctx context.Context, creditID int32) (*e
 	return credit, nil
 }
 
+// ListByUserID возвращает кредиты пользователя, начиная с последнего оформленного.
+func (c CreditRepository) ListByUserID(ctx context.Context, userID int32) ([]entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+
+	var credits []entity.Credit
+	err := c.db.Select(ctx, &credits, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credits by user ID: %w", err)
+	}
+
+	return credits, nil
+}
+
+// LockCreditByID находит кредит и блокирует его строку до конца текущей транзакции.
+// Возвращает entity.ErrCreditNotFound, если кредита нет.
+func (c CreditRepository) LockCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits WHERE id = $1 FOR UPDATE`
+	credit := new(entity.Credit)
+
+	err := c.db.Get(ctx, credit, query, creditID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit by ID: %w", err)
+	}
+
+	return credit, nil
+}
+
+// DeletePendingPayments удаляет из графика кредита платежи, по которым еще ничего не погашено.
+func (c CreditRepository) DeletePendingPayments(ctx context.Context, creditID int32) error {
+	query := `DELETE FROM main.payment_schedules WHERE credit_id = $1 AND status = 'pending' AND paid_amount = 0`
+
+	_, err := c.db.Exec(ctx, query, creditID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending payments: %w", err)
+	}
+
+	return nil
+}
+
 func (c CreditRepository) GetPaymentSchedule(ctx context.Context, creditID int32) ([]entity.PaymentSchedule, error) {
-	query := `SELECT id, credit_id, payment_date, payment_amount, principal_amount, interest_amount
-				FROM main.payment_schedules WHERE credit_id = $1`
+	query := `SELECT id, credit_id, payment_date, payment_amount, principal_amount, interest_amount,
+       				COALESCE(penalty, 0) AS penalty, COALESCE(balance, 0) AS balance, status, paid_amount, paid_at,
+       				penalty_paid, penalty_waived, penalty_waived_by, penalty_accrued_at
+				FROM main.payment_schedules WHERE credit_id = $1 ORDER BY payment_date, id`
 
 	var paymentSchedules []entity.PaymentSchedule
 	err := c.db.Select(ctx, &paymentSchedules, query, creditID)
@@ -77,6 +159,336 @@ func (c CreditRepository) GetPaymentSchedule(ctx context.Context, creditID int32
 	return paymentSchedules, nil
 }
 
+// LockDueCredit находит следующий после afterID действующий кредит с непогашенными платежами, дата которых раньше
+// dueBy, и блокирует его строку до конца текущей транзакции. Кредиты, уже заблокированные другими транзакциями,
+// пропускаются, поэтому параллельно запущенные обработчики не спишут платежи дважды.
+// Возвращает entity.ErrCreditNotFound, если таких кредитов больше нет.
+func (c CreditRepository) LockDueCredit(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits c
+				WHERE status = 'active' AND id > $2
+				  AND EXISTS (SELECT 1 FROM main.payment_schedules ps WHERE ps.credit_id = c.id AND ps.status <> 'paid' AND ps.payment_date < $1)
+				ORDER BY id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED`
+	credit := new(entity.Credit)
+
+	err := c.db.Get(ctx, credit, query, dueBy, afterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit with due payments: %w", err)
+	}
+
+	return credit, nil
+}
+
+// LockDuePayments возвращает непогашенные платежи кредита creditID с датой раньше dueBy в порядке дат и блокирует
+// их строки до конца текущей транзакции. Строку кредита вызывающий должен заблокировать заранее.
+func (c CreditRepository) LockDuePayments(ctx context.Context, creditID int32, dueBy time.Time) ([]entity.PaymentSchedule, error) {
+	query := `
+		SELECT id, credit_id, payment_date, payment_amount, principal_amount, interest_amount,
+		       COALESCE(penalty, 0) AS penalty, COALESCE(balance, 0) AS balance, status, paid_amount, paid_at,
+		       penalty_paid, penalty_waived, penalty_waived_by, penalty_accrued_at
+		FROM main.payment_schedules
+		WHERE credit_id = $1 AND status <> 'paid' AND payment_date < $2
+		ORDER BY payment_date, id
+		FOR UPDATE
+	`
+
+	var payments []entity.PaymentSchedule
+	err := c.db.Select(ctx, &payments, query, creditID, dueBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock due payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// LockOverdueCredit находит следующий после afterID действующий кредит с просроченными платежами, штраф по которым
+// начислен раньше дня accruedBy, и блокирует его строку до конца текущей транзакции. Кредиты, уже заблокированные
+// другими транзакциями, пропускаются. Возвращает entity.ErrCreditNotFound, если таких кредитов больше нет.
+func (c CreditRepository) LockOverdueCredit(ctx context.Context, accruedBy time.Time, afterID int32) (*entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits c
+				WHERE status = 'active' AND id > $2
+				  AND EXISTS (SELECT 1 FROM main.payment_schedules ps WHERE ps.credit_id = c.id AND ps.status = 'overdue'
+				              AND (ps.penalty_accrued_at IS NULL OR ps.penalty_accrued_at < $1))
+				ORDER BY id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED`
+	credit := new(entity.Credit)
+
+	err := c.db.Get(ctx, credit, query, accruedBy, afterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit with overdue payments: %w", err)
+	}
+
+	return credit, nil
+}
+
+// LockOverduePayments возвращает просроченные платежи кредита creditID, штраф по которым начислен раньше дня
+// accruedBy, и блокирует их строки до конца текущей транзакции. Строку кредита вызывающий должен заблокировать заранее.
+func (c CreditRepository) LockOverduePayments(ctx context.Context, creditID int32, accruedBy time.Time) ([]entity.PaymentSchedule, error) {
+	query := `
+		SELECT id, credit_id, payment_date, payment_amount, principal_amount, interest_amount,
+		       COALESCE(penalty, 0) AS penalty, COALESCE(balance, 0) AS balance, status, paid_amount, paid_at,
+		       penalty_paid, penalty_waived, penalty_waived_by, penalty_accrued_at
+		FROM main.payment_schedules
+		WHERE credit_id = $1 AND status = 'overdue' AND (penalty_accrued_at IS NULL OR penalty_accrued_at < $2)
+		ORDER BY payment_date, id
+		FOR UPDATE
+	`
+
+	var payments []entity.PaymentSchedule
+	err := c.db.Select(ctx, &payments, query, creditID, accruedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock overdue payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// LockPaymentByID находит платеж paymentID по кредиту creditID и блокирует его строку до конца текущей транзакции.
+// Возвращает entity.ErrPaymentNotFound, если такого платежа нет.
+func (c CreditRepository) LockPaymentByID(ctx context.Context, creditID, paymentID int32) (*entity.PaymentSchedule, error) {
+	query := `
+		SELECT id, credit_id, payment_date, payment_amount, principal_amount, interest_amount,
+		       COALESCE(penalty, 0) AS penalty, COALESCE(balance, 0) AS balance, status, paid_amount, paid_at,
+		       penalty_paid, penalty_waived, penalty_waived_by, penalty_accrued_at
+		FROM main.payment_schedules
+		WHERE id = $1 AND credit_id = $2
+		FOR UPDATE
+	`
+
+	payment := new(entity.PaymentSchedule)
+	err := c.db.Get(ctx, payment, query, paymentID, creditID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// UpdatePayment сохраняет погашенную сумму, статус и штраф платежа.
+func (c CreditRepository) UpdatePayment(ctx context.Context, payment *entity.PaymentSchedule) error {
+	query := `
+		UPDATE main.payment_schedules
+		SET paid_amount = $2, status = $3, paid_at = $4, penalty = $5, penalty_paid = $6, penalty_waived = $7,
+		    penalty_waived_by = $8, penalty_accrued_at = $9, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := c.db.Exec(
+		ctx,
+		query,
+		payment.ID,
+		payment.PaidAmount,
+		payment.Status,
+		payment.PaidAt,
+		payment.Penalty,
+		payment.PenaltyPaid,
+		payment.PenaltyWaived,
+		payment.PenaltyWaivedBy,
+		payment.PenaltyAccruedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	return nil
+}
+
+// CountUnpaidPayments возвращает число непогашенных платежей по кредиту.
+func (c CreditRepository) CountUnpaidPayments(ctx context.Context, creditID int32) (int, error) {
+	query := `SELECT COUNT(*) FROM main.payment_schedules WHERE credit_id = $1 AND status <> 'paid'`
+
+	var count int
+	err := c.db.Get(ctx, &count, query, creditID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpaid payments: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateCreditTerm сохраняет срок кредита в месяцах.
+func (c CreditRepository) UpdateCreditTerm(ctx context.Context, creditID, termInMonths int32) error {
+	query := `UPDATE main.credits SET term_in_months = $2, updated_at = NOW() WHERE id = $1`
+
+	_, err := c.db.Exec(ctx, query, creditID, termInMonths)
+	if err != nil {
+		return fmt.Errorf("failed to update credit term: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCreditRate сохраняет ставку кредита и дату следующего пересмотра плавающей ставки.
+func (c CreditRepository) UpdateCreditRate(ctx context.Context, creditID int32, rate decimal.Decimal, nextReset *time.Time) error {
+	query := `UPDATE main.credits SET interest_rate = $2, next_rate_reset = $3, updated_at = NOW() WHERE id = $1`
+
+	_, err := c.db.Exec(ctx, query, creditID, rate, nextReset)
+	if err != nil {
+		return fmt.Errorf("failed to update credit rate: %w", err)
+	}
+
+	return nil
+}
+
+// LockRateResetDue находит следующий после afterID действующий кредит с плавающей ставкой, пересмотр которой
+// назначен раньше dueBy, и блокирует его строку до конца текущей транзакции. Кредиты, уже заблокированные другими
+// транзакциями, пропускаются. Возвращает entity.ErrCreditNotFound, если таких кредитов больше нет.
+func (c CreditRepository) LockRateResetDue(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits
+				WHERE rate_type = 'floating' AND status = 'active' AND next_rate_reset < $1 AND id > $2
+				ORDER BY id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED`
+	credit := new(entity.Credit)
+
+	err := c.db.Get(ctx, credit, query, dueBy, afterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit for rate reset: %w", err)
+	}
+
+	return credit, nil
+}
+
+// DeletePendingPaymentsAfter удаляет из графика кредита платежи с датой позже after, по которым еще ничего не погашено.
+func (c CreditRepository) DeletePendingPaymentsAfter(ctx context.Context, creditID int32, after time.Time) error {
+	query := `DELETE FROM main.payment_schedules WHERE credit_id = $1 AND payment_date > $2 AND status = 'pending' AND paid_amount = 0`
+
+	_, err := c.db.Exec(ctx, query, creditID, after)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending payments: %w", err)
+	}
+
+	return nil
+}
+
+// LockAccrualDue находит следующий после afterID действующий кредит, проценты по которому начислены не по день
+// перед today, и блокирует его строку до конца текущей транзакции. Кредиты, уже заблокированные другими транзакциями,
+// пропускаются. Возвращает entity.ErrCreditNotFound, если таких кредитов больше нет.
+func (c CreditRepository) LockAccrualDue(ctx context.Context, today time.Time, afterID int32) (*entity.Credit, error) {
+	query := `SELECT id, user_id, COALESCE(account_id, 0) AS account_id, amount, interest_rate, term_in_months, COALESCE(status, '') AS status, schedule_type, day_count,
+       				rate_type, rate_margin, rate_reset_months, next_rate_reset, interest_receivable, interest_accrued_to, created_at, updated_at
+				FROM main.credits
+				WHERE status = 'active' AND COALESCE(interest_accrued_to, date_trunc('day', created_at)) < $1 AND id > $2
+				ORDER BY id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED`
+	credit := new(entity.Credit)
+
+	err := c.db.Get(ctx, credit, query, today, afterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit for interest accrual: %w", err)
+	}
+
+	return credit, nil
+}
+
+// UpdateInterestReceivable сохраняет начисленные и не погашенные проценты по кредиту и день, до которого они начислены.
+func (c CreditRepository) UpdateInterestReceivable(ctx context.Context, creditID int32, receivable decimal.Decimal, accruedTo *time.Time) error {
+	query := `UPDATE main.credits SET interest_receivable = $2, interest_accrued_to = $3, updated_at = NOW() WHERE id = $1`
+
+	_, err := c.db.Exec(ctx, query, creditID, receivable, accruedTo)
+	if err != nil {
+		return fmt.Errorf("failed to update interest receivable: %w", err)
+	}
+
+	return nil
+}
+
+// CreateInterestPosting сохраняет проводку по начисленным процентам. Повторное начисление за тот же день
+// отклоняется уникальным индексом.
+func (c CreditRepository) CreateInterestPosting(ctx context.Context, posting *entity.InterestPosting) error {
+	query := `
+		INSERT INTO main.credit_interest_postings (credit_id, posting_date, type, principal, interest_rate, amount, payment_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at;
+	`
+
+	err := c.db.Get(
+		ctx,
+		posting,
+		query,
+		posting.CreditID,
+		posting.PostingDate,
+		posting.Type,
+		posting.Principal,
+		posting.InterestRate,
+		posting.Amount,
+		posting.PaymentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create interest posting: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePrincipalPosting сохраняет проводку по основному долгу.
+func (c CreditRepository) CreatePrincipalPosting(ctx context.Context, posting *entity.PrincipalPosting) error {
+	query := `
+		INSERT INTO main.credit_principal_postings (credit_id, posting_date, type, amount, payment_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	err := c.db.Get(ctx, posting, query, posting.CreditID, posting.PostingDate, posting.Type, posting.Amount, posting.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to create principal posting: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePaymentPosting сохраняет поступление средств в счет платежа.
+func (c CreditRepository) CreatePaymentPosting(ctx context.Context, posting *entity.PaymentPosting) error {
+	query := `
+		INSERT INTO main.credit_payment_postings (credit_id, payment_id, posting_date, amount, penalty_amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	err := c.db.Get(ctx, posting, query, posting.CreditID, posting.PaymentID, posting.PostingDate, posting.Amount, posting.PenaltyAmount)
+	if err != nil {
+		return fmt.Errorf("failed to create payment posting: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCreditStatus сохраняет статус кредита.
+func (c CreditRepository) UpdateCreditStatus(ctx context.Context, creditID int32, status entity.CreditStatus) error {
+	query := `UPDATE main.credits SET status = $2, updated_at = NOW() WHERE id = $1`
+
+	_, err := c.db.Exec(ctx, query, creditID, status)
+	if err != nil {
+		return fmt.Errorf("failed to update credit status: %w", err)
+	}
+
+	return nil
+}
+
 func (c CreditRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
 	return c.db.WithTx(ctx, fn, opts...)
 }
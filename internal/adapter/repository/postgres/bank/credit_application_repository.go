@@ -0,0 +1,199 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/lib/pq"
+	"time"
+)
+
+type CreditApplicationRepository struct {
+	db sqlext.DB
+}
+
+func NewCreditApplicationRepository(db sqlext.DB) *CreditApplicationRepository {
+	return &CreditApplicationRepository{
+		db: db,
+	}
+}
+
+type creditDecisionRow struct {
+	ID            int32                          `db:"id"`
+	ApplicationID int32                          `db:"application_id"`
+	Status        entity.CreditApplicationStatus `db:"status"`
+	Score         *int32                         `db:"score"`
+	Reasons       pq.StringArray                 `db:"reasons"`
+	DecidedBy     *int32                         `db:"decided_by"`
+	CreatedAt     time.Time                      `db:"created_at"`
+}
+
+func (r creditDecisionRow) toEntity() entity.CreditDecision {
+	return entity.CreditDecision{
+		ID:            r.ID,
+		ApplicationID: r.ApplicationID,
+		Status:        r.Status,
+		Score:         r.Score,
+		Reasons:       r.Reasons,
+		DecidedBy:     r.DecidedBy,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// Create сохраняет новую заявку и заполняет ее идентификатор и даты.
+func (r CreditApplicationRepository) Create(ctx context.Context, application *entity.CreditApplication) error {
+	query := `
+		INSERT INTO main.credit_applications (user_id, account_id, amount, term_in_months, schedule_type, monthly_income,
+		                                      interest_rate, rate_type, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		application,
+		query,
+		application.UserID,
+		application.AccountID,
+		application.Amount,
+		application.TermInMonths,
+		application.ScheduleType,
+		application.MonthlyIncome,
+		application.InterestRate,
+		application.RateType,
+		application.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create credit application: %w", err)
+	}
+
+	return nil
+}
+
+// Update сохраняет статус, балл и выданный кредит заявки.
+func (r CreditApplicationRepository) Update(ctx context.Context, application *entity.CreditApplication) error {
+	query := `
+		UPDATE main.credit_applications
+		SET status = $2, score = $3, credit_id = $4, updated_at = NOW()
+		WHERE id = $1;
+	`
+
+	_, err := r.db.Exec(ctx, query, application.ID, application.Status, application.Score, application.CreditID)
+	if err != nil {
+		return fmt.Errorf("failed to update credit application: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID возвращает заявку или entity.ErrCreditApplicationNotFound, если заявки нет.
+func (r CreditApplicationRepository) FindByID(ctx context.Context, id int32) (*entity.CreditApplication, error) {
+	query := `
+		SELECT id, user_id, account_id, amount, term_in_months, schedule_type, monthly_income, interest_rate, rate_type,
+		       status, score, credit_id, created_at, updated_at
+		FROM main.credit_applications
+		WHERE id = $1;
+	`
+
+	application := new(entity.CreditApplication)
+	err := r.db.Get(ctx, application, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditApplicationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credit application: %w", err)
+	}
+
+	return application, nil
+}
+
+// LockByID находит заявку и блокирует ее строку до конца текущей транзакции.
+func (r CreditApplicationRepository) LockByID(ctx context.Context, id int32) (*entity.CreditApplication, error) {
+	query := `
+		SELECT id, user_id, account_id, amount, term_in_months, schedule_type, monthly_income, interest_rate, rate_type,
+		       status, score, credit_id, created_at, updated_at
+		FROM main.credit_applications
+		WHERE id = $1
+		FOR UPDATE;
+	`
+
+	application := new(entity.CreditApplication)
+	err := r.db.Get(ctx, application, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditApplicationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit application: %w", err)
+	}
+
+	return application, nil
+}
+
+// ListByStatus возвращает заявки в статусе status в порядке подачи.
+func (r CreditApplicationRepository) ListByStatus(ctx context.Context, status entity.CreditApplicationStatus) ([]entity.CreditApplication, error) {
+	query := `
+		SELECT id, user_id, account_id, amount, term_in_months, schedule_type, monthly_income, interest_rate, rate_type,
+		       status, score, credit_id, created_at, updated_at
+		FROM main.credit_applications
+		WHERE status = $1
+		ORDER BY created_at, id;
+	`
+
+	var applications []entity.CreditApplication
+	err := r.db.Select(ctx, &applications, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit applications: %w", err)
+	}
+
+	return applications, nil
+}
+
+// CreateDecision сохраняет решение по заявке.
+func (r CreditApplicationRepository) CreateDecision(ctx context.Context, decision *entity.CreditDecision) error {
+	query := `
+		INSERT INTO main.credit_decisions (application_id, status, score, reasons, decided_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	reasons := make(pq.StringArray, 0, len(decision.Reasons))
+	reasons = append(reasons, decision.Reasons...)
+
+	err := r.db.Get(ctx, decision, query, decision.ApplicationID, decision.Status, decision.Score, reasons, decision.DecidedBy)
+	if err != nil {
+		return fmt.Errorf("failed to create credit decision: %w", err)
+	}
+
+	return nil
+}
+
+// FindDecisions возвращает решения по заявке в хронологическом порядке.
+func (r CreditApplicationRepository) FindDecisions(ctx context.Context, applicationID int32) ([]entity.CreditDecision, error) {
+	query := `
+		SELECT id, application_id, status, score, reasons, decided_by, created_at
+		FROM main.credit_decisions
+		WHERE application_id = $1
+		ORDER BY created_at, id;
+	`
+
+	var rows []creditDecisionRow
+	err := r.db.Select(ctx, &rows, query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credit decisions: %w", err)
+	}
+
+	decisions := make([]entity.CreditDecision, 0, len(rows))
+	for _, row := range rows {
+		decisions = append(decisions, row.toEntity())
+	}
+
+	return decisions, nil
+}
+
+func (r CreditApplicationRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	return r.db.WithTx(ctx, fn, opts...)
+}
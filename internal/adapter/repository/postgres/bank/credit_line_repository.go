@@ -0,0 +1,288 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type CreditLineRepository struct {
+	db sqlext.DB
+}
+
+func NewCreditLineRepository(db sqlext.DB) *CreditLineRepository {
+	return &CreditLineRepository{
+		db: db,
+	}
+}
+
+const creditLineColumns = `account_id, interest_rate, grace_days, min_payment_percent, min_payment_amount, statement_day,
+		       cycle_started_at, next_statement_at, accrued_interest, interest_accrued_at, created_at, updated_at`
+
+const statementColumns = `id, account_id, period_start, period_end, opening_debt, purchases, payments, closing_debt,
+		       minimum_payment, due_date, accrued_interest, interest_charged, repaid_amount, status, created_at, updated_at`
+
+// Create сохраняет новую кредитную линию и заполняет даты ее создания.
+func (r CreditLineRepository) Create(ctx context.Context, line *entity.CreditLine) error {
+	query := `
+		INSERT INTO main.credit_lines (account_id, interest_rate, grace_days, min_payment_percent, min_payment_amount,
+		                               statement_day, cycle_started_at, next_statement_at, accrued_interest, interest_accrued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		line,
+		query,
+		line.AccountID,
+		line.InterestRate,
+		line.GraceDays,
+		line.MinPaymentPercent,
+		line.MinPaymentAmount,
+		line.StatementDay,
+		line.CycleStartedAt,
+		line.NextStatementAt,
+		line.AccruedInterest,
+		line.InterestAccruedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create credit line: %w", err)
+	}
+
+	return nil
+}
+
+// GetByAccountID возвращает кредитную линию счета accountID или entity.ErrCreditLineNotFound, если ее нет.
+func (r CreditLineRepository) GetByAccountID(ctx context.Context, accountID int32) (*entity.CreditLine, error) {
+	query := `
+		SELECT ` + creditLineColumns + `
+		FROM main.credit_lines
+		WHERE account_id = $1;
+	`
+
+	line := new(entity.CreditLine)
+	err := r.db.Get(ctx, line, query, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditLineNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit line: %w", err)
+	}
+
+	return line, nil
+}
+
+// LockUnprocessed находит следующую после afterID кредитную линию, проценты по которой начислены раньше дня today,
+// и блокирует ее строку до конца текущей транзакции. Линии, уже заблокированные другими транзакциями, пропускаются.
+// Возвращает entity.ErrCreditLineNotFound, если таких линий больше нет.
+func (r CreditLineRepository) LockUnprocessed(ctx context.Context, today time.Time, afterID int32) (*entity.CreditLine, error) {
+	query := `
+		SELECT ` + creditLineColumns + `
+		FROM main.credit_lines
+		WHERE interest_accrued_at < $1 AND account_id > $2
+		ORDER BY account_id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	line := new(entity.CreditLine)
+	err := r.db.Get(ctx, line, query, today, afterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCreditLineNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock credit line: %w", err)
+	}
+
+	return line, nil
+}
+
+// Update сохраняет расчетный период и начисленные проценты кредитной линии.
+func (r CreditLineRepository) Update(ctx context.Context, line *entity.CreditLine) error {
+	query := `
+		UPDATE main.credit_lines
+		SET cycle_started_at = $2, next_statement_at = $3, accrued_interest = $4, interest_accrued_at = $5, updated_at = NOW()
+		WHERE account_id = $1;
+	`
+
+	_, err := r.db.Exec(ctx, query, line.AccountID, line.CycleStartedAt, line.NextStatementAt, line.AccruedInterest, line.InterestAccruedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update credit line: %w", err)
+	}
+
+	return nil
+}
+
+// CreateStatement сохраняет новую выписку и заполняет ее идентификатор и даты.
+func (r CreditLineRepository) CreateStatement(ctx context.Context, statement *entity.Statement) error {
+	query := `
+		INSERT INTO main.credit_line_statements (account_id, period_start, period_end, opening_debt, purchases, payments,
+		                                         closing_debt, minimum_payment, due_date, accrued_interest, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		statement,
+		query,
+		statement.AccountID,
+		statement.PeriodStart,
+		statement.PeriodEnd,
+		statement.OpeningDebt,
+		statement.Purchases,
+		statement.Payments,
+		statement.ClosingDebt,
+		statement.MinimumPayment,
+		statement.DueDate,
+		statement.AccruedInterest,
+		statement.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create statement: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatement сохраняет итог льготного периода по выписке.
+func (r CreditLineRepository) UpdateStatement(ctx context.Context, statement *entity.Statement) error {
+	query := `
+		UPDATE main.credit_line_statements
+		SET status = $2, repaid_amount = $3, interest_charged = $4, updated_at = NOW()
+		WHERE id = $1;
+	`
+
+	_, err := r.db.Exec(ctx, query, statement.ID, statement.Status, statement.RepaidAmount, statement.InterestCharged)
+	if err != nil {
+		return fmt.Errorf("failed to update statement: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueStatements возвращает выписки счета accountID, льготный период по которым закончился раньше дня today,
+// а итог еще не подведен.
+func (r CreditLineRepository) ListDueStatements(ctx context.Context, accountID int32, today time.Time) ([]entity.Statement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM main.credit_line_statements
+		WHERE account_id = $1 AND status = 'open' AND due_date < $2
+		ORDER BY period_end;
+	`
+
+	var statements []entity.Statement
+	err := r.db.Select(ctx, &statements, query, accountID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due statements: %w", err)
+	}
+
+	return statements, nil
+}
+
+// LastStatement возвращает последнюю выписку счета accountID или entity.ErrStatementNotFound, если выписок еще не было.
+func (r CreditLineRepository) LastStatement(ctx context.Context, accountID int32) (*entity.Statement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM main.credit_line_statements
+		WHERE account_id = $1
+		ORDER BY period_end DESC
+		LIMIT 1;
+	`
+
+	statement := new(entity.Statement)
+	err := r.db.Get(ctx, statement, query, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrStatementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last statement: %w", err)
+	}
+
+	return statement, nil
+}
+
+// ListStatements возвращает выписки счета accountID, начиная с последней.
+func (r CreditLineRepository) ListStatements(ctx context.Context, accountID int32) ([]entity.Statement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM main.credit_line_statements
+		WHERE account_id = $1
+		ORDER BY period_end DESC;
+	`
+
+	statements := []entity.Statement{}
+	err := r.db.Select(ctx, &statements, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statements: %w", err)
+	}
+
+	return statements, nil
+}
+
+// Turnover возвращает суммы успешных списаний и зачислений по картам счета accountID за период [from, to).
+func (r CreditLineRepository) Turnover(ctx context.Context, accountID int32, from, to time.Time) (*entity.CardTransactionTotals, error) {
+	query := `
+		SELECT COUNT(*) AS count,
+		       COALESCE(SUM(t.amount) FILTER (WHERE t.direction = 'debit'), 0) AS debit,
+		       COALESCE(SUM(t.amount) FILTER (WHERE t.direction = 'credit'), 0) AS credit
+		FROM main.card_transactions t
+		JOIN main.cards c ON c.id = t.card_id
+		WHERE c.account_id = $1 AND t.status = 'success' AND t.transaction_date >= $2 AND t.transaction_date < $3;
+	`
+
+	totals := &entity.CardTransactionTotals{}
+	err := r.db.Get(ctx, totals, query, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate account turnover: %w", err)
+	}
+
+	return totals, nil
+}
+
+// Repayments возвращает сумму зачислений на счет accountID за период [from, to) по журналу движений баланса,
+// через какой бы канал они ни поступили.
+func (r CreditLineRepository) Repayments(ctx context.Context, accountID int32, from, to time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM main.account_movements
+		WHERE account_id = $1 AND amount > 0 AND created_at >= $2 AND created_at < $3;
+	`
+
+	var repaid decimal.Decimal
+	err := r.db.Get(ctx, &repaid, query, accountID, from, to)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to calculate account repayments: %w", err)
+	}
+
+	return repaid, nil
+}
+
+// BalanceAt возвращает баланс счета accountID на момент at: текущий баланс за вычетом движений начиная с at.
+func (r CreditLineRepository) BalanceAt(ctx context.Context, accountID int32, at time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT a.balance - COALESCE((SELECT SUM(m.amount) FROM main.account_movements m
+		                             WHERE m.account_id = a.id AND m.created_at >= $2), 0)
+		FROM main.accounts a
+		WHERE a.id = $1;
+	`
+
+	var balance decimal.Decimal
+	err := r.db.Get(ctx, &balance, query, accountID, at)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to calculate account balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (r CreditLineRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	return r.db.WithTx(ctx, fn, opts...)
+}
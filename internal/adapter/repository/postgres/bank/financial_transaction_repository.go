@@ -0,0 +1,46 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+)
+
+type FinancialTransactionRepository struct {
+	db sqlext.DB
+}
+
+func NewFinancialTransactionRepository(db sqlext.DB) *FinancialTransactionRepository {
+	return &FinancialTransactionRepository{
+		db: db,
+	}
+}
+
+// Create сохраняет финансовую операцию и возвращает ее идентификатор.
+func (r FinancialTransactionRepository) Create(ctx context.Context, transaction *entity.FinancialTransaction) (int32, error) {
+	query := `
+		INSERT INTO main.financial_transactions (user_id, account_id, credit_id, transaction_type, amount, transaction_date, transaction_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id;
+	`
+
+	var id int32
+	err := r.db.Get(
+		ctx,
+		&id,
+		query,
+		transaction.UserID,
+		transaction.AccountID,
+		transaction.CreditID,
+		transaction.TransactionType,
+		transaction.Amount,
+		transaction.TransactionDate,
+		transaction.TransactionStatus,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create financial transaction: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,125 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"time"
+)
+
+type FloatingRateRepository struct {
+	db sqlext.DB
+}
+
+func NewFloatingRateRepository(db sqlext.DB) *FloatingRateRepository {
+	return &FloatingRateRepository{
+		db: db,
+	}
+}
+
+// FindKeyRate возвращает ключевую ставку ЦБ, действовавшую на дату at, то есть последнюю установленную не позже этой даты.
+// Возвращает entity.ErrKeyRateNotFound, если таких ставок в истории нет.
+func (r FloatingRateRepository) FindKeyRate(ctx context.Context, at time.Time) (*entity.CentralBankRate, error) {
+	query := `
+		SELECT id, rate, rate_date, created_at
+		FROM main.central_bank_rates
+		WHERE rate_date <= $1
+		ORDER BY rate_date DESC, id DESC
+		LIMIT 1;
+	`
+
+	rate := new(entity.CentralBankRate)
+	err := r.db.Get(ctx, rate, query, at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrKeyRateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find key rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+// CreateRateReset сохраняет пересмотр ставки и заполняет его идентификатор и дату записи.
+func (r FloatingRateRepository) CreateRateReset(ctx context.Context, reset *entity.RateReset) error {
+	query := `
+		INSERT INTO main.credit_rate_resets (credit_id, reset_date, key_rate, key_rate_date, previous_rate, new_rate, schedule_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		reset,
+		query,
+		reset.CreditID,
+		reset.ResetDate,
+		reset.KeyRate,
+		reset.KeyRateDate,
+		reset.PreviousRate,
+		reset.NewRate,
+		reset.ScheduleVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create rate reset: %w", err)
+	}
+
+	return nil
+}
+
+// ListRateResets возвращает пересмотры ставки по кредиту creditID в порядке их проведения.
+func (r FloatingRateRepository) ListRateResets(ctx context.Context, creditID int32) ([]entity.RateReset, error) {
+	query := `
+		SELECT id, credit_id, reset_date, key_rate, key_rate_date, previous_rate, new_rate, schedule_version, created_at
+		FROM main.credit_rate_resets
+		WHERE credit_id = $1
+		ORDER BY reset_date, id;
+	`
+
+	resets := []entity.RateReset{}
+	err := r.db.Select(ctx, &resets, query, creditID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate resets: %w", err)
+	}
+
+	return resets, nil
+}
+
+// SaveKeyRate сохраняет ключевую ставку ЦБ на ее дату и заполняет идентификатор и дату записи.
+// Если ставка на эту дату уже сохранена, ее значение обновляется.
+func (r FloatingRateRepository) SaveKeyRate(ctx context.Context, rate *entity.CentralBankRate) error {
+	query := `
+		INSERT INTO main.central_bank_rates (rate, rate_date)
+		VALUES ($1, $2)
+		ON CONFLICT (rate_date) DO UPDATE SET rate = EXCLUDED.rate
+		RETURNING id, created_at;
+	`
+
+	err := r.db.Get(ctx, rate, query, rate.Rate, rate.RateDate)
+	if err != nil {
+		return fmt.Errorf("failed to save key rate: %w", err)
+	}
+
+	return nil
+}
+
+// ListKeyRates возвращает ключевые ставки ЦБ с датами с from по to включительно в порядке возрастания дат.
+func (r FloatingRateRepository) ListKeyRates(ctx context.Context, from, to time.Time) ([]entity.CentralBankRate, error) {
+	query := `
+		SELECT id, rate, rate_date, created_at
+		FROM main.central_bank_rates
+		WHERE rate_date BETWEEN $1 AND $2
+		ORDER BY rate_date;
+	`
+
+	rates := []entity.CentralBankRate{}
+	err := r.db.Select(ctx, &rates, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key rates: %w", err)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,65 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+)
+
+type NotificationRepository struct {
+	db sqlext.DB
+}
+
+func NewNotificationRepository(db sqlext.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+func (r *NotificationRepository) Save(ctx context.Context, notification *entity.Notification) (*entity.Notification, error) {
+	query := `
+		INSERT INTO main.notifications (user_id, type, subject, body, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		notification,
+		query,
+		notification.UserID,
+		notification.Type,
+		notification.Subject,
+		notification.Body,
+		notification.Status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (r *NotificationRepository) UpdateStatus(ctx context.Context, id int32, status entity.NotificationStatus) error {
+	query := `UPDATE main.notifications SET status = $2 WHERE id = $1;`
+
+	_, err := r.db.Exec(ctx, query, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update notification status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) GetUserEmail(ctx context.Context, userID int32) (string, error) {
+	query := `SELECT email FROM main.users WHERE id = $1;`
+
+	var email string
+	err := r.db.Get(ctx, &email, query, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	return email, nil
+}
@@ -0,0 +1,60 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"time"
+)
+
+type ReportRepository struct {
+	db sqlext.DB
+}
+
+func NewReportRepository(db sqlext.DB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+// CreditExposures восстанавливает задолженность по кредитам на конец дня asOf. Платеж считается погашенным,
+// если он полностью погашен до конца этого дня, а просроченным, если дата платежа раньше asOf и он не погашен.
+// В выборку попадают кредиты, оформленные не позже asOf и имеющие непогашенные на эту дату платежи.
+// Основной долг, начисленные проценты и внесенные по платежам суммы восстанавливаются по проводкам, датированным
+// не позже asOf, поэтому погашения, капитализация процентов и перестроение графика после asOf на отчет не влияют.
+func (r ReportRepository) CreditExposures(ctx context.Context, asOf time.Time) ([]entity.CreditExposure, error) {
+	query := `
+		SELECT c.id AS credit_id, c.user_id, c.amount,
+		       COALESCE((SELECT SUM(pp.amount)
+		                 FROM main.credit_principal_postings pp
+		                 WHERE pp.credit_id = c.id AND pp.posting_date <= $2), 0) AS outstanding_principal,
+		       COALESCE(SUM(ps.payment_amount - LEAST(GREATEST(paid.amount, 0), ps.payment_amount))
+		                FILTER (WHERE ps.payment_date < $2 AND (ps.paid_at IS NULL OR ps.paid_at >= $1)), 0) AS overdue_amount,
+		       COALESCE($2::date - MIN(ps.payment_date::date)
+		                FILTER (WHERE ps.payment_date < $2 AND (ps.paid_at IS NULL OR ps.paid_at >= $1)), 0) AS days_past_due,
+		       COALESCE((SELECT SUM(ip.amount)
+		                 FROM main.credit_interest_postings ip
+		                 WHERE ip.credit_id = c.id AND ip.posting_date <= $2), 0) AS accrued_interest
+		FROM main.credits c
+		JOIN main.payment_schedules ps ON ps.credit_id = c.id
+		CROSS JOIN LATERAL (SELECT COALESCE(SUM(pp.amount - pp.penalty_amount), 0) AS amount
+		                    FROM main.credit_payment_postings pp
+		                    WHERE pp.payment_id = ps.id AND pp.posting_date <= $2) paid
+		WHERE c.created_at < $1
+		GROUP BY c.id
+		HAVING COUNT(*) FILTER (WHERE ps.paid_at IS NULL OR ps.paid_at >= $1) > 0
+		ORDER BY c.id;
+	`
+
+	year, month, day := asOf.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	exposures := []entity.CreditExposure{}
+	err := r.db.Select(ctx, &exposures, query, date.AddDate(0, 0, 1), date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit exposures: %w", err)
+	}
+
+	return exposures, nil
+}
@@ -0,0 +1,189 @@
+package bank
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"time"
+)
+
+type RestructuringRepository struct {
+	db sqlext.DB
+}
+
+func NewRestructuringRepository(db sqlext.DB) *RestructuringRepository {
+	return &RestructuringRepository{
+		db: db,
+	}
+}
+
+type scheduleVersionRow struct {
+	ID              int32     `db:"id"`
+	CreditID        int32     `db:"credit_id"`
+	Version         int32     `db:"version"`
+	RestructuringID *int32    `db:"restructuring_id"`
+	Schedule        []byte    `db:"schedule"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// Create сохраняет новую заявку на реструктуризацию и заполняет ее идентификатор и даты.
+func (r RestructuringRepository) Create(ctx context.Context, restructuring *entity.Restructuring) error {
+	query := `
+		INSERT INTO main.credit_restructurings (credit_id, user_id, type, months, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at;
+	`
+
+	err := r.db.Get(
+		ctx,
+		restructuring,
+		query,
+		restructuring.CreditID,
+		restructuring.UserID,
+		restructuring.Type,
+		restructuring.Months,
+		restructuring.Reason,
+		restructuring.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create restructuring: %w", err)
+	}
+
+	return nil
+}
+
+// Update сохраняет решение по заявке на реструктуризацию.
+func (r RestructuringRepository) Update(ctx context.Context, restructuring *entity.Restructuring) error {
+	query := `
+		UPDATE main.credit_restructurings
+		SET status = $2, decided_by = $3, decision_note = $4, schedule_version = $5, updated_at = NOW()
+		WHERE id = $1;
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		restructuring.ID,
+		restructuring.Status,
+		restructuring.DecidedBy,
+		restructuring.DecisionNote,
+		restructuring.ScheduleVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update restructuring: %w", err)
+	}
+
+	return nil
+}
+
+// LockByID находит заявку на реструктуризацию и блокирует ее строку до конца текущей транзакции.
+// Возвращает entity.ErrRestructuringNotFound, если заявки нет.
+func (r RestructuringRepository) LockByID(ctx context.Context, id int32) (*entity.Restructuring, error) {
+	query := `
+		SELECT id, credit_id, user_id, type, months, reason, status, decided_by, decision_note, schedule_version,
+		       created_at, updated_at
+		FROM main.credit_restructurings
+		WHERE id = $1
+		FOR UPDATE;
+	`
+
+	restructuring := new(entity.Restructuring)
+	err := r.db.Get(ctx, restructuring, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrRestructuringNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock restructuring: %w", err)
+	}
+
+	return restructuring, nil
+}
+
+// ListByStatus возвращает заявки на реструктуризацию в статусе status в порядке подачи.
+func (r RestructuringRepository) ListByStatus(ctx context.Context, status entity.RestructuringStatus) ([]entity.Restructuring, error) {
+	query := `
+		SELECT id, credit_id, user_id, type, months, reason, status, decided_by, decision_note, schedule_version,
+		       created_at, updated_at
+		FROM main.credit_restructurings
+		WHERE status = $1
+		ORDER BY created_at, id;
+	`
+
+	var restructurings []entity.Restructuring
+	err := r.db.Select(ctx, &restructurings, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list restructurings: %w", err)
+	}
+
+	return restructurings, nil
+}
+
+// CreateScheduleVersion сохраняет снимок графика со следующим по порядку номером версии и заполняет
+// идентификатор, номер и дату версии. Вызывается под блокировкой кредита.
+func (r RestructuringRepository) CreateScheduleVersion(ctx context.Context, version *entity.ScheduleVersion) error {
+	query := `
+		INSERT INTO main.payment_schedule_versions (credit_id, version, restructuring_id, schedule)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3
+		FROM main.payment_schedule_versions
+		WHERE credit_id = $1
+		RETURNING id, version, created_at;
+	`
+
+	schedule, err := json.Marshal(version.Schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	var row scheduleVersionRow
+	err = r.db.Get(ctx, &row, query, version.CreditID, version.RestructuringID, schedule)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule version: %w", err)
+	}
+
+	version.ID = row.ID
+	version.Version = row.Version
+	version.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// FindScheduleVersions возвращает сохраненные версии графика кредита в порядке их создания.
+func (r RestructuringRepository) FindScheduleVersions(ctx context.Context, creditID int32) ([]entity.ScheduleVersion, error) {
+	query := `
+		SELECT id, credit_id, version, restructuring_id, schedule, created_at
+		FROM main.payment_schedule_versions
+		WHERE credit_id = $1
+		ORDER BY version;
+	`
+
+	var rows []scheduleVersionRow
+	err := r.db.Select(ctx, &rows, query, creditID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule versions: %w", err)
+	}
+
+	versions := make([]entity.ScheduleVersion, 0, len(rows))
+	for _, row := range rows {
+		version := entity.ScheduleVersion{
+			ID:              row.ID,
+			CreditID:        row.CreditID,
+			Version:         row.Version,
+			RestructuringID: row.RestructuringID,
+			CreatedAt:       row.CreatedAt,
+		}
+		if err := json.Unmarshal(row.Schedule, &version.Schedule); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schedule version %d: %w", row.Version, err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+func (r RestructuringRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	return r.db.WithTx(ctx, fn, opts...)
+}
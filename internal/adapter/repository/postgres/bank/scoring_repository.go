@@ -0,0 +1,89 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// ScoringRepository предоставляет данные о заявителе, необходимые для скоринга.
+type ScoringRepository struct {
+	db sqlext.DB
+}
+
+func NewScoringRepository(db sqlext.DB) *ScoringRepository {
+	return &ScoringRepository{
+		db: db,
+	}
+}
+
+// FindUserByID возвращает данные пользователя. Если дата рождения не указана, DateOfBirth остается нулевой.
+func (r ScoringRepository) FindUserByID(ctx context.Context, id int32) (*entity.User, error) {
+	query := `
+		SELECT id, email, COALESCE(first_name, '') AS first_name, COALESCE(last_name, '') AS last_name,
+		       COALESCE(date_of_birth, DATE '0001-01-01') AS date_of_birth, role
+		FROM main.users
+		WHERE id = $1;
+	`
+
+	user := new(entity.User)
+	err := r.db.Get(ctx, user, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by ID: %w", err)
+	}
+
+	return user, nil
+}
+
+// AccountTurnover возвращает сумму успешных зачислений на карты счета начиная с since.
+func (r ScoringRepository) AccountTurnover(ctx context.Context, accountID int32, since time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(t.amount), 0)
+		FROM main.card_transactions t
+		JOIN main.cards c ON c.id = t.card_id
+		WHERE c.account_id = $1 AND t.direction = 'credit' AND t.status = 'success' AND t.transaction_date >= $2;
+	`
+
+	var turnover decimal.Decimal
+	err := r.db.Get(ctx, &turnover, query, accountID, since)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to calculate account turnover: %w", err)
+	}
+
+	return turnover, nil
+}
+
+// CreditHistory возвращает сведения о действующих кредитах пользователя: их число, число просроченных платежей
+// и сумму ближайших непогашенных платежей.
+func (r ScoringRepository) CreditHistory(ctx context.Context, userID int32) (*entity.CreditHistory, error) {
+	query := `
+		SELECT COUNT(*) AS active_credits,
+		       COALESCE(SUM(overdue.count), 0) AS overdue_payments,
+		       COALESCE(SUM(next.payment_amount), 0) AS monthly_payments
+		FROM main.credits c
+		LEFT JOIN LATERAL (
+		    SELECT payment_amount
+		    FROM main.payment_schedules
+		    WHERE credit_id = c.id AND status <> 'paid'
+		    ORDER BY payment_date
+		    LIMIT 1
+		) next ON TRUE
+		LEFT JOIN LATERAL (
+		    SELECT COUNT(*) AS count
+		    FROM main.payment_schedules
+		    WHERE credit_id = c.id AND status = 'overdue'
+		) overdue ON TRUE
+		WHERE c.user_id = $1 AND c.status = 'active';
+	`
+
+	history := new(entity.CreditHistory)
+	err := r.db.Get(ctx, history, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credit history: %w", err)
+	}
+
+	return history, nil
+}
@@ -2,11 +2,16 @@ package bank
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/MaxFando/bank-system/internal/core/bank/entity"
 	"github.com/MaxFando/bank-system/pkg/sqlext"
 	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
+	"strings"
+	"time"
 )
 
 type CardTransactionRepository struct {
@@ -19,68 +24,311 @@ func NewCardTransactionRepository(db sqlext.DB) *CardTransactionRepository {
 	}
 }
 
-func (c CardTransactionRepository) Transfer(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+type cardTransactionRow struct {
+	ID                 int32                       `db:"id"`
+	CardID             int32                       `db:"card_id"`
+	Amount             decimal.Decimal             `db:"amount"`
+	TransactionType    entity.TransactionType      `db:"transaction_type"`
+	Direction          entity.TransactionDirection `db:"direction"`
+	TransactionDate    time.Time                   `db:"transaction_date"`
+	Status             entity.TransactionStatus    `db:"status"`
+	CounterpartyCardID *int32                      `db:"counterparty_card_id"`
+	Counterparty       sql.NullString              `db:"counterparty"`
+	MerchantName       sql.NullString              `db:"merchant_name"`
+	MCC                sql.NullString              `db:"mcc"`
+	MerchantCountry    sql.NullString              `db:"merchant_country"`
+	Online             sql.NullBool                `db:"online"`
+	RRN                sql.NullString              `db:"rrn"`
+	STAN               sql.NullString              `db:"stan"`
+	ReversedAt         *time.Time                  `db:"reversed_at"`
+}
+
+func (r cardTransactionRow) toEntity() entity.CardTransaction {
+	cardTransaction := entity.CardTransaction{
+		ID:                 r.ID,
+		CardID:             r.CardID,
+		Amount:             r.Amount,
+		TransactionType:    r.TransactionType,
+		Direction:          r.Direction,
+		TransactionDate:    r.TransactionDate,
+		Status:             r.Status,
+		CounterpartyCardID: r.CounterpartyCardID,
+		Counterparty:       r.Counterparty.String,
+		RRN:                r.RRN.String,
+		STAN:               r.STAN.String,
+		ReversedAt:         r.ReversedAt,
+	}
+
+	if r.MCC.Valid {
+		cardTransaction.Merchant = &entity.Merchant{
+			Name:    r.MerchantName.String,
+			MCC:     entity.MCC(r.MCC.String),
+			Country: r.MerchantCountry.String,
+			Online:  r.Online.Bool,
+		}
+	}
+
+	return cardTransaction
+}
+
+const cardTransactionColumns = `
+	id, card_id, amount, transaction_type, direction, transaction_date, status,
+	counterparty_card_id, counterparty, merchant_name, mcc, merchant_country, online, rrn, stan, reversed_at
+`
+
+// Create сохраняет операцию по карте и возвращает ее идентификатор. Если дата операции не задана, используется текущее время.
+func (c CardTransactionRepository) Create(ctx context.Context, cardTransaction *entity.CardTransaction) (int32, error) {
 	query := `
-		INSERT INTO main.card_transactions (card_id, amount, transaction_type)
-		VALUES ($1, $2, 'transfer')
+		INSERT INTO main.card_transactions (
+			card_id, amount, transaction_type, direction, transaction_date, status,
+			counterparty_card_id, counterparty, merchant_name, mcc, merchant_country, online, rrn, stan
+		)
+		VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id;
 	`
 
+	var (
+		transactionDate            sql.NullTime
+		counterparty               sql.NullString
+		merchantName, mcc, country sql.NullString
+		online                     sql.NullBool
+		rrn, stan                  sql.NullString
+	)
+	if cardTransaction.RRN != "" {
+		rrn = sql.NullString{String: cardTransaction.RRN, Valid: true}
+		stan = sql.NullString{String: cardTransaction.STAN, Valid: cardTransaction.STAN != ""}
+	}
+	if !cardTransaction.TransactionDate.IsZero() {
+		transactionDate = sql.NullTime{Time: cardTransaction.TransactionDate, Valid: true}
+	}
+	if cardTransaction.Counterparty != "" {
+		counterparty = sql.NullString{String: cardTransaction.Counterparty, Valid: true}
+	}
+	if merchant := cardTransaction.Merchant; merchant != nil {
+		merchantName = sql.NullString{String: merchant.Name, Valid: true}
+		mcc = sql.NullString{String: merchant.MCC.String(), Valid: true}
+		country = sql.NullString{String: merchant.Country, Valid: true}
+		online = sql.NullBool{Bool: merchant.Online, Valid: true}
+	}
+
 	var id int32
-	err := c.db.Get(ctx, &id, query, cardID, amount)
+	err := c.db.Get(
+		ctx,
+		&id,
+		query,
+		cardTransaction.CardID,
+		cardTransaction.Amount,
+		cardTransaction.TransactionType,
+		cardTransaction.Direction,
+		transactionDate,
+		cardTransaction.Status,
+		cardTransaction.CounterpartyCardID,
+		counterparty,
+		merchantName,
+		mcc,
+		country,
+		online,
+		rrn,
+		stan,
+	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to save card transaction: %w", err)
 	}
 
+	cardTransaction.ID = id
+
 	return id, nil
 }
 
-func (c CardTransactionRepository) Withdraw(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+func (c CardTransactionRepository) FindByID(ctx context.Context, id int32) (*entity.CardTransaction, error) {
 	query := `
-		INSERT INTO main.card_transactions (card_id, amount, transaction_type)
-		VALUES ($1, $2, 'withdraw')
-		RETURNING id;
+		SELECT ` + cardTransactionColumns + `
+		FROM main.card_transactions
+		WHERE id = $1;
 	`
 
-	var id int32
-	err := c.db.Get(ctx, &id, query, cardID, amount)
+	var row cardTransactionRow
+	err := c.db.Get(ctx, &row, query, id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to save card transaction: %w", err)
+		return nil, fmt.Errorf("failed to find card transaction by ID: %w", err)
 	}
 
-	return id, nil
+	cardTransaction := row.toEntity()
+	return &cardTransaction, nil
 }
 
-func (c CardTransactionRepository) Deposit(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+// FindByRRN возвращает одобренное списание по карте с номером операции в платежной сети rrn.
+// Возвращает entity.ErrCardTransactionNotFound, если такого списания нет.
+func (c CardTransactionRepository) FindByRRN(ctx context.Context, rrn string) (*entity.CardTransaction, error) {
 	query := `
-		INSERT INTO main.card_transactions (card_id, amount, transaction_type)
-		VALUES ($1, $2, 'deposit')
-		RETURNING id;
+		SELECT ` + cardTransactionColumns + `
+		FROM main.card_transactions
+		WHERE rrn = $1 AND direction = 'debit' AND status = 'success';
 	`
 
-	var id int32
-	err := c.db.Get(ctx, &id, query, cardID, amount)
+	var row cardTransactionRow
+	err := c.db.Get(ctx, &row, query, rrn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrCardTransactionNotFound
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to save card transaction: %w", err)
+		return nil, fmt.Errorf("failed to find card transaction by RRN: %w", err)
 	}
 
-	return id, nil
+	cardTransaction := row.toEntity()
+	return &cardTransaction, nil
 }
 
-func (c CardTransactionRepository) FindByID(ctx context.Context, id int32) (*entity.CardTransaction, error) {
+// MarkReversed отмечает операцию id сторнированной в момент at.
+func (c CardTransactionRepository) MarkReversed(ctx context.Context, id int32, at time.Time) error {
+	query := `UPDATE main.card_transactions SET reversed_at = $2 WHERE id = $1`
+
+	_, err := c.db.Exec(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to mark card transaction reversed: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает до limit операций по карте, подходящих под фильтр, в порядке сортировки фильтра.
+// Если задан after, возвращаются операции, следующие за ним (постраничная выборка по ключу).
+func (c CardTransactionRepository) List(
+	ctx context.Context,
+	filter entity.CardTransactionFilter,
+	after *entity.CardTransactionCursor,
+	limit int,
+) ([]entity.CardTransaction, error) {
+	conditions, args := cardTransactionConditions(filter)
+
+	column := "transaction_date"
+	if filter.Sort == entity.SortByAmount {
+		column = "amount"
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Order == entity.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if after != nil {
+		var value any = after.Date
+		if filter.Sort == entity.SortByAmount {
+			value = after.Amount
+		}
+
+		args = append(args, value, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM main.card_transactions
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT $%d;
+	`, cardTransactionColumns, strings.Join(conditions, " AND "), column, direction, direction, len(args))
+
+	var rows []cardTransactionRow
+	err := c.db.Select(ctx, &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list card transactions: %w", err)
+	}
+
+	transactions := make([]entity.CardTransaction, 0, len(rows))
+	for _, row := range rows {
+		transactions = append(transactions, row.toEntity())
+	}
+
+	return transactions, nil
+}
+
+// Totals возвращает количество операций, подходящих под фильтр, и суммы успешных списаний и зачислений.
+func (c CardTransactionRepository) Totals(ctx context.Context, filter entity.CardTransactionFilter) (*entity.CardTransactionTotals, error) {
+	conditions, args := cardTransactionConditions(filter)
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) AS count,
+		       COALESCE(SUM(amount) FILTER (WHERE direction = 'debit' AND status = 'success'), 0) AS debit,
+		       COALESCE(SUM(amount) FILTER (WHERE direction = 'credit' AND status = 'success'), 0) AS credit
+		FROM main.card_transactions
+		WHERE %s;
+	`, strings.Join(conditions, " AND "))
+
+	totals := &entity.CardTransactionTotals{}
+	err := c.db.Get(ctx, totals, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card transaction totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// cardTransactionConditions строит условия выборки по фильтру и их аргументы.
+func cardTransactionConditions(filter entity.CardTransactionFilter) ([]string, []any) {
+	conditions := []string{"card_id = $1"}
+	args := []any{filter.CardID}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("transaction_date >= $%d", len(args)))
+	}
+
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("transaction_date < $%d", len(args)))
+	}
+
+	if len(filter.Types) > 0 {
+		types := make(pq.StringArray, 0, len(filter.Types))
+		for _, t := range filter.Types {
+			types = append(types, string(t))
+		}
+		args = append(args, types)
+		conditions = append(conditions, fmt.Sprintf("transaction_type = ANY($%d)", len(args)))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make(pq.StringArray, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, string(s))
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+
+	return conditions, args
+}
+
+// SumByMCCSince возвращает суммы успешных списаний по карте начиная с указанного момента, сгруппированные по MCC.
+func (c CardTransactionRepository) SumByMCCSince(ctx context.Context, cardID int32, since time.Time) (map[entity.MCC]decimal.Decimal, error) {
 	query := `
-		SELECT id, card_id, amount, transaction_type
+		SELECT mcc, SUM(amount) AS amount
 		FROM main.card_transactions
-		WHERE id = $1;
+		WHERE card_id = $1
+		  AND mcc IS NOT NULL
+		  AND direction = 'debit'
+		  AND status = 'success'
+		  AND transaction_date >= $2
+		GROUP BY mcc;
 	`
 
-	cardTransaction := &entity.CardTransaction{}
-	err := c.db.Get(ctx, cardTransaction, query, id)
+	var rows []struct {
+		MCC    entity.MCC      `db:"mcc"`
+		Amount decimal.Decimal `db:"amount"`
+	}
+	err := c.db.Select(ctx, &rows, query, cardID, since)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find card transaction by ID: %w", err)
+		return nil, fmt.Errorf("failed to sum card transactions by mcc: %w", err)
+	}
+
+	sums := make(map[entity.MCC]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		sums[row.MCC] = row.Amount
 	}
 
-	return cardTransaction, nil
+	return sums, nil
 }
 
 func (c CardTransactionRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
@@ -48,7 +48,7 @@ func (r *Repository) Register(ctx context.Context, email, password string) (int3
 
 func (r *Repository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, date_of_birth
+		SELECT id, email, password_hash, first_name, last_name, date_of_birth, role
 		FROM main.users
 		WHERE email = :email
 	`
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/MaxFando/bank-system/config"
 	"github.com/MaxFando/bank-system/internal/delivery/cli"
+	"github.com/MaxFando/bank-system/internal/delivery/gateway"
 	"github.com/MaxFando/bank-system/internal/delivery/http"
 	"github.com/MaxFando/bank-system/internal/providers"
+	"github.com/MaxFando/bank-system/pkg/calendar"
 	"github.com/MaxFando/bank-system/pkg/sqlext"
 	"log/slog"
 	"os"
@@ -19,6 +21,7 @@ type App struct {
 	config          *config.Config
 	serviceProvider *providers.ServiceProvider
 	httpServer      *http.Server
+	gatewayServer   *gateway.Server
 }
 
 func NewApp(cfg *config.Config) *App {
@@ -49,8 +52,16 @@ func (a *App) Run(ctx context.Context) error {
 	a.httpServer = httpServer
 	a.logger.Info("HTTP server started", "port", a.config.Port)
 
-	cron := cli.New()
-	cron.Start()
+	gatewayServer := gateway.NewServer(a.logger, gateway.NewHandler(a.logger, a.serviceProvider.CardService), ":"+a.config.ISO8583Port)
+	if err := gatewayServer.Serve(); err != nil {
+		return fmt.Errorf("failed to start iso8583 gateway: %w", err)
+	}
+
+	a.gatewayServer = gatewayServer
+	a.logger.Info("ISO 8583 gateway started", "port", a.config.ISO8583Port)
+
+	cron := cli.New(a.logger, a.serviceProvider)
+	cron.Start(ctx)
 
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, syscall.SIGTERM, syscall.SIGINT)
@@ -60,11 +71,17 @@ func (a *App) Run(ctx context.Context) error {
 		return fmt.Errorf("signal: %s", s.String())
 	case err := <-httpServer.Notify():
 		return fmt.Errorf("server: %w", err)
+	case err := <-gatewayServer.Notify():
+		return fmt.Errorf("iso8583 gateway: %w", err)
 	}
 }
 
 func (a *App) Shutdown(ctx context.Context) {
 	_ = a.httpServer.Shutdown()
+
+	if a.gatewayServer != nil {
+		_ = a.gatewayServer.Shutdown()
+	}
 }
 
 func (a *App) initProviders(ctx context.Context) error {
@@ -76,7 +93,12 @@ func (a *App) initProviders(ctx context.Context) error {
 	repositoryProvider := providers.NewRepositoryProvider(postgresConn)
 	repositoryProvider.RegisterDependency()
 
-	serviceProvider := providers.NewServiceProvider(a.logger, a.config)
+	cal, err := calendar.Load(a.config.CalendarFile)
+	if err != nil {
+		return fmt.Errorf("failed to load calendar: %w", err)
+	}
+
+	serviceProvider := providers.NewServiceProvider(a.logger, a.config, cal)
 	serviceProvider.RegisterDependency(repositoryProvider)
 
 	a.serviceProvider = serviceProvider
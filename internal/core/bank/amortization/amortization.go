@@ -0,0 +1,290 @@
+// Package amortization рассчитывает графики погашения кредитов: аннуитетные и дифференцированные платежи
+// с начислением процентов по выбранному способу подсчета дней.
+package amortization
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Method определяет способ погашения кредита.
+type Method string
+
+const (
+	// Annuity — равные ежемесячные платежи; доля основного долга в платеже растет со временем.
+	Annuity Method = "annuity"
+	// Differentiated — основной долг гасится равными частями, проценты начисляются на остаток.
+	Differentiated Method = "differentiated"
+)
+
+// Validate проверяет способ погашения.
+func (m Method) Validate() error {
+	switch m {
+	case Annuity, Differentiated:
+		return nil
+	default:
+		return fmt.Errorf("unknown amortization method: %s", m)
+	}
+}
+
+// DayCount определяет способ подсчета дней при начислении процентов за период.
+type DayCount string
+
+const (
+	// Thirty360 — каждый месяц считается равным 30 дням, год — 360 дням (30E/360).
+	Thirty360 DayCount = "30/360"
+	// Actual365 — фактическое число дней в периоде, год — 365 дней.
+	Actual365 DayCount = "act/365"
+	// ActualActual — фактическое число дней, приходящихся на каждый календарный год, делится на число дней в этом году.
+	ActualActual DayCount = "act/act"
+)
+
+// Validate проверяет способ подсчета дней.
+func (d DayCount) Validate() error {
+	switch d {
+	case Thirty360, Actual365, ActualActual:
+		return nil
+	default:
+		return fmt.Errorf("unknown day count convention: %s", d)
+	}
+}
+
+var (
+	ErrInvalidPrincipal    = errors.New("principal must be positive")
+	ErrInvalidRate         = errors.New("interest rate must not be negative")
+	ErrInvalidTerm         = errors.New("term must be positive")
+	ErrInvalidPaymentDates = errors.New("payment dates must be increasing and follow the start date")
+)
+
+var (
+	hundred = decimal.NewFromInt(100)
+	twelve  = decimal.NewFromInt(12)
+)
+
+// Params описывает кредит, для которого составляется график.
+type Params struct {
+	Principal  decimal.Decimal // Сумма кредита
+	AnnualRate decimal.Decimal // Годовая процентная ставка в процентах, например 16.5
+	Term       int             // Срок кредита в месяцах
+	Method     Method          // Способ погашения
+	DayCount   DayCount        // Способ подсчета дней
+	StartDate  time.Time       // Дата выдачи кредита, с которой начисляются проценты
+
+	// PaymentDates задает даты платежей. Если не задано, платежи назначаются ежемесячно в день выдачи кредита,
+	// а в месяцах без такого дня — в последний день месяца.
+	PaymentDates []time.Time
+}
+
+// Installment описывает один платеж графика.
+type Installment struct {
+	Number    int             // Порядковый номер платежа
+	Date      time.Time       // Дата платежа
+	Payment   decimal.Decimal // Сумма платежа
+	Principal decimal.Decimal // Погашение основного долга
+	Interest  decimal.Decimal // Проценты за период
+	Balance   decimal.Decimal // Остаток основного долга после платежа
+}
+
+// Generate составляет график платежей. Проценты за каждый период и суммы платежей округляются до копеек;
+// последний платеж погашает весь оставшийся долг, поэтому остаток после него равен нулю.
+func Generate(params Params) ([]Installment, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	dates := params.PaymentDates
+	if len(dates) == 0 {
+		dates = MonthlyDates(params.StartDate, params.Term)
+	}
+
+	var payment, principalPart decimal.Decimal
+	switch params.Method {
+	case Annuity:
+		payment = AnnuityPayment(params.Principal, params.AnnualRate, params.Term)
+	case Differentiated:
+		principalPart = params.Principal.Div(decimal.NewFromInt(int64(params.Term))).Round(2)
+	}
+
+	schedule := make([]Installment, 0, params.Term)
+	balance := params.Principal
+	periodStart := params.StartDate
+
+	for i, date := range dates {
+		interest := Interest(balance, params.AnnualRate, params.DayCount, periodStart, date)
+
+		var principal decimal.Decimal
+		switch {
+		case i == len(dates)-1:
+			principal = balance
+		case params.Method == Annuity:
+			principal = decimal.Min(decimal.Max(payment.Sub(interest), decimal.Zero), balance)
+		default:
+			principal = decimal.Min(principalPart, balance)
+		}
+
+		balance = balance.Sub(principal)
+		schedule = append(schedule, Installment{
+			Number:    i + 1,
+			Date:      date,
+			Payment:   principal.Add(interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+
+		periodStart = date
+	}
+
+	return schedule, nil
+}
+
+// AnnuityPayment возвращает ежемесячный аннуитетный платеж, округленный до копеек:
+// A = P * r * (1 + r)^n / ((1 + r)^n - 1), где r — годовая ставка в процентах, деленная на 100 и на 12.
+func AnnuityPayment(principal, annualRate decimal.Decimal, term int) decimal.Decimal {
+	n := decimal.NewFromInt(int64(term))
+	if annualRate.IsZero() {
+		return principal.Div(n).Round(2)
+	}
+
+	r := annualRate.Div(hundred).Div(twelve)
+	growth := decimal.NewFromInt(1).Add(r).Pow(n)
+
+	return principal.Mul(r).Mul(growth).Div(growth.Sub(decimal.NewFromInt(1))).Round(2)
+}
+
+// MinTerm возвращает наименьший срок не больше maxTerm, за который можно погасить principal, не превышая limit:
+// для аннуитета limit ограничивает ежемесячный платеж, для дифференцированного графика — ежемесячное погашение
+// основного долга. Если погасить долг в пределах limit за maxTerm месяцев нельзя, возвращается maxTerm.
+func MinTerm(method Method, principal, annualRate, limit decimal.Decimal, maxTerm int) int {
+	for term := 1; term < maxTerm; term++ {
+		var amount decimal.Decimal
+		switch method {
+		case Annuity:
+			amount = AnnuityPayment(principal, annualRate, term)
+		default:
+			amount = principal.Div(decimal.NewFromInt(int64(term))).Round(2)
+		}
+
+		if amount.LessThanOrEqual(limit) {
+			return term
+		}
+	}
+
+	return maxTerm
+}
+
+// Interest возвращает проценты на остаток balance за период с from по to, округленные до копеек.
+func Interest(balance, annualRate decimal.Decimal, dayCount DayCount, from, to time.Time) decimal.Decimal {
+	rate := annualRate.Div(hundred)
+	return balance.Mul(rate).Mul(YearFraction(dayCount, from, to)).Round(2)
+}
+
+// YearFraction возвращает долю года между датами from и to по способу подсчета дней.
+func YearFraction(dayCount DayCount, from, to time.Time) decimal.Decimal {
+	from, to = truncateDay(from), truncateDay(to)
+
+	switch dayCount {
+	case Thirty360:
+		return decimal.NewFromInt(int64(days360(from, to))).Div(decimal.NewFromInt(360))
+	case ActualActual:
+		fraction := decimal.Zero
+		for start := from; start.Before(to); {
+			yearEnd := time.Date(start.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+			end := to
+			if yearEnd.Before(to) {
+				end = yearEnd
+			}
+
+			fraction = fraction.Add(decimal.NewFromInt(int64(daysBetween(start, end))).Div(decimal.NewFromInt(int64(daysInYear(start.Year())))))
+			start = end
+		}
+		return fraction
+	default:
+		return decimal.NewFromInt(int64(daysBetween(from, to))).Div(decimal.NewFromInt(365))
+	}
+}
+
+// MonthlyDates возвращает term ежемесячных дат, начиная через месяц после start. Если в месяце нет дня start,
+// дата переносится на последний день месяца, а в следующих месяцах снова используется день start.
+func MonthlyDates(start time.Time, term int) []time.Time {
+	dates := make([]time.Time, 0, term)
+	for i := 1; i <= term; i++ {
+		dates = append(dates, AddMonths(start, i))
+	}
+	return dates
+}
+
+// AddMonths прибавляет к дате months месяцев, не перескакивая в следующий месяц для дней 29–31.
+func AddMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, date.Location())
+}
+
+func (p Params) validate() error {
+	if !p.Principal.IsPositive() {
+		return ErrInvalidPrincipal
+	}
+
+	if p.AnnualRate.IsNegative() {
+		return ErrInvalidRate
+	}
+
+	if p.Term <= 0 {
+		return ErrInvalidTerm
+	}
+
+	if err := p.Method.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.DayCount.Validate(); err != nil {
+		return err
+	}
+
+	if len(p.PaymentDates) == 0 {
+		return nil
+	}
+
+	if len(p.PaymentDates) != p.Term {
+		return fmt.Errorf("%w: expected %d dates, got %d", ErrInvalidPaymentDates, p.Term, len(p.PaymentDates))
+	}
+
+	previous := truncateDay(p.StartDate)
+	for _, date := range p.PaymentDates {
+		if !truncateDay(date).After(previous) {
+			return ErrInvalidPaymentDates
+		}
+		previous = truncateDay(date)
+	}
+
+	return nil
+}
+
+// days360 возвращает число дней между датами по правилу 30E/360: 31-е число считается 30-м.
+func days360(from, to time.Time) int {
+	d1, d2 := min(from.Day(), 30), min(to.Day(), 30)
+	return (to.Year()-from.Year())*360 + (int(to.Month())-int(from.Month()))*30 + d2 - d1
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// truncateDay отбрасывает время и часовой пояс, чтобы разница между датами считалась в целых днях.
+func truncateDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
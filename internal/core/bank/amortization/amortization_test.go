@@ -0,0 +1,263 @@
+package amortization
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+// row описывает эталонную строку графика: платеж, основной долг, проценты и остаток.
+type row struct {
+	payment, principal, interest, balance string
+}
+
+func TestGenerate_ReferenceSchedules(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params Params
+		want   []row
+	}{
+		{
+			name: "annuity 100000 at 12% for 12 months, 30/360",
+			params: Params{
+				Principal:  d("100000"),
+				AnnualRate: d("12"),
+				Term:       12,
+				Method:     Annuity,
+				DayCount:   Thirty360,
+				StartDate:  date(2025, 1, 15),
+			},
+			want: []row{
+				{"8884.88", "7884.88", "1000.00", "92115.12"},
+				{"8884.88", "7963.73", "921.15", "84151.39"},
+				{"8884.88", "8043.37", "841.51", "76108.02"},
+				{"8884.88", "8123.80", "761.08", "67984.22"},
+				{"8884.88", "8205.04", "679.84", "59779.18"},
+				{"8884.88", "8287.09", "597.79", "51492.09"},
+				{"8884.88", "8369.96", "514.92", "43122.13"},
+				{"8884.88", "8453.66", "431.22", "34668.47"},
+				{"8884.88", "8538.20", "346.68", "26130.27"},
+				{"8884.88", "8623.58", "261.30", "17506.69"},
+				{"8884.88", "8709.81", "175.07", "8796.88"},
+				{"8884.85", "8796.88", "87.97", "0.00"},
+			},
+		},
+		{
+			name: "differentiated 120000 at 12% for 12 months, 30/360",
+			params: Params{
+				Principal:  d("120000"),
+				AnnualRate: d("12"),
+				Term:       12,
+				Method:     Differentiated,
+				DayCount:   Thirty360,
+				StartDate:  date(2025, 1, 10),
+			},
+			want: []row{
+				{"11200.00", "10000.00", "1200.00", "110000.00"},
+				{"11100.00", "10000.00", "1100.00", "100000.00"},
+				{"11000.00", "10000.00", "1000.00", "90000.00"},
+				{"10900.00", "10000.00", "900.00", "80000.00"},
+				{"10800.00", "10000.00", "800.00", "70000.00"},
+				{"10700.00", "10000.00", "700.00", "60000.00"},
+				{"10600.00", "10000.00", "600.00", "50000.00"},
+				{"10500.00", "10000.00", "500.00", "40000.00"},
+				{"10400.00", "10000.00", "400.00", "30000.00"},
+				{"10300.00", "10000.00", "300.00", "20000.00"},
+				{"10200.00", "10000.00", "200.00", "10000.00"},
+				{"10100.00", "10000.00", "100.00", "0.00"},
+			},
+		},
+		{
+			name: "differentiated with uneven split, act/365",
+			params: Params{
+				Principal:  d("100000"),
+				AnnualRate: d("12"),
+				Term:       3,
+				Method:     Differentiated,
+				DayCount:   Actual365,
+				StartDate:  date(2025, 1, 15),
+			},
+			want: []row{
+				{"34352.51", "33333.33", "1019.18", "66666.67"},
+				{"33947.03", "33333.33", "613.70", "33333.34"},
+				{"33673.07", "33333.34", "339.73", "0.00"},
+			},
+		},
+		{
+			name: "zero rate annuity",
+			params: Params{
+				Principal:  d("1000"),
+				AnnualRate: d("0"),
+				Term:       3,
+				Method:     Annuity,
+				DayCount:   Actual365,
+				StartDate:  date(2025, 1, 15),
+			},
+			want: []row{
+				{"333.33", "333.33", "0.00", "666.67"},
+				{"333.33", "333.33", "0.00", "333.34"},
+				{"333.34", "333.34", "0.00", "0.00"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Generate(tc.params)
+			assert.NoError(t, err)
+			assert.Len(t, schedule, len(tc.want))
+
+			principal := decimal.Zero
+			for i, installment := range schedule {
+				want := tc.want[i]
+				assert.Equal(t, i+1, installment.Number)
+				assert.True(t, d(want.payment).Equal(installment.Payment), "payment %d: %s", i+1, installment.Payment)
+				assert.True(t, d(want.principal).Equal(installment.Principal), "principal %d: %s", i+1, installment.Principal)
+				assert.True(t, d(want.interest).Equal(installment.Interest), "interest %d: %s", i+1, installment.Interest)
+				assert.True(t, d(want.balance).Equal(installment.Balance), "balance %d: %s", i+1, installment.Balance)
+				assert.True(t, installment.Payment.Equal(installment.Principal.Add(installment.Interest)))
+				principal = principal.Add(installment.Principal)
+			}
+
+			assert.True(t, tc.params.Principal.Equal(principal))
+		})
+	}
+}
+
+func TestGenerate_ExplicitPaymentDates(t *testing.T) {
+	schedule, err := Generate(Params{
+		Principal:    d("10000"),
+		AnnualRate:   d("10"),
+		Term:         2,
+		Method:       Differentiated,
+		DayCount:     Actual365,
+		StartDate:    date(2025, 3, 1),
+		PaymentDates: []time.Time{date(2025, 3, 11), date(2025, 4, 30)},
+	})
+	assert.NoError(t, err)
+
+	// 10000 * 10% * 10 / 365 и 5000 * 10% * 50 / 365.
+	assert.True(t, d("27.40").Equal(schedule[0].Interest))
+	assert.True(t, d("68.49").Equal(schedule[1].Interest))
+	assert.Equal(t, date(2025, 4, 30), schedule[1].Date)
+}
+
+func TestGenerate_InvalidParams(t *testing.T) {
+	valid := Params{
+		Principal:  d("1000"),
+		AnnualRate: d("10"),
+		Term:       2,
+		Method:     Annuity,
+		DayCount:   Actual365,
+		StartDate:  date(2025, 1, 1),
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(p *Params)
+		err    error
+	}{
+		{name: "zero principal", modify: func(p *Params) { p.Principal = decimal.Zero }, err: ErrInvalidPrincipal},
+		{name: "negative rate", modify: func(p *Params) { p.AnnualRate = d("-1") }, err: ErrInvalidRate},
+		{name: "zero term", modify: func(p *Params) { p.Term = 0 }, err: ErrInvalidTerm},
+		{name: "dates count mismatch", modify: func(p *Params) { p.PaymentDates = []time.Time{date(2025, 2, 1)} }, err: ErrInvalidPaymentDates},
+		{
+			name:   "dates not increasing",
+			modify: func(p *Params) { p.PaymentDates = []time.Time{date(2025, 3, 1), date(2025, 2, 1)} },
+			err:    ErrInvalidPaymentDates,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := valid
+			tc.modify(&params)
+
+			_, err := Generate(params)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestYearFraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dayCount DayCount
+		from, to time.Time
+		balance  string
+		rate     string
+		interest string
+	}{
+		{
+			name:     "30/360 treats the 31st as the 30th",
+			dayCount: Thirty360,
+			from:     date(2025, 1, 31),
+			to:       date(2025, 3, 31),
+			balance:  "36000",
+			rate:     "10",
+			interest: "600.00",
+		},
+		{
+			name:     "act/365 in a leap year",
+			dayCount: Actual365,
+			from:     date(2024, 2, 1),
+			to:       date(2024, 3, 1),
+			balance:  "36500",
+			rate:     "10",
+			interest: "290.00",
+		},
+		{
+			name:     "act/act across a leap year boundary",
+			dayCount: ActualActual,
+			from:     date(2024, 12, 15),
+			to:       date(2025, 1, 15),
+			balance:  "50000",
+			rate:     "10",
+			interest: "424.02",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			interest := Interest(d(tc.balance), d(tc.rate), tc.dayCount, tc.from, tc.to)
+			assert.True(t, d(tc.interest).Equal(interest), "got %s", interest)
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	dates := MonthlyDates(date(2025, 1, 31), 3)
+	assert.Equal(t, []time.Time{date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30)}, dates)
+
+	assert.Equal(t, date(2024, 2, 29), AddMonths(date(2023, 11, 30), 3))
+}
+
+func TestMinTerm(t *testing.T) {
+	testCases := []struct {
+		name      string
+		method    Method
+		principal string
+		limit     string
+		want      int
+	}{
+		{name: "annuity keeps payment", method: Annuity, principal: "100000", limit: "8884.88", want: 12},
+		{name: "annuity after prepayment", method: Annuity, principal: "50000", limit: "8884.88", want: 6},
+		{name: "differentiated keeps principal part", method: Differentiated, principal: "50001", limit: "8333.33", want: 7},
+		{name: "limit too low", method: Annuity, principal: "100000", limit: "100", want: 12},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MinTerm(tc.method, d(tc.principal), d("12"), d(tc.limit), 12))
+		})
+	}
+}
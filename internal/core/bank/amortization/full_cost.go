@@ -0,0 +1,86 @@
+package amortization
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"math"
+	"time"
+)
+
+// ErrInvalidCashFlows возвращается, если по денежным потокам невозможно рассчитать полную стоимость кредита.
+var ErrInvalidCashFlows = errors.New("cash flows must start with a disbursement and contain repayments")
+
+// basePeriodDays средняя длительность базового периода (месяца) в днях.
+const basePeriodDays = 365.0 / 12
+
+// CashFlow описывает денежный поток по договору: выдачу кредита со знаком минус, платежи заемщика со знаком плюс.
+type CashFlow struct {
+	Date   time.Time
+	Amount decimal.Decimal
+}
+
+// FullCostRate рассчитывает полную стоимость кредита в процентах годовых с точностью до третьего знака по формуле
+// ст. 6 Федерального закона № 353-ФЗ «О потребительском кредите» с базовым периодом в один месяц:
+//
+//	ПСК = i × ЧБП × 100, где i — решение уравнения Σ ДПk / ((1 + ek × i) × (1 + i)^qk) = 0,
+//
+// ДПk — сумма k-го денежного потока, qk — число полных базовых периодов с даты первого потока до даты k-го,
+// ek — оставшаяся часть базового периода, ЧБП = 12. Первым должен быть поток выдачи кредита.
+func FullCostRate(flows []CashFlow) (decimal.Decimal, error) {
+	if len(flows) < 2 || !flows[0].Amount.IsNegative() {
+		return decimal.Zero, ErrInvalidCashFlows
+	}
+
+	start := truncateDay(flows[0].Date)
+	amounts := make([]float64, len(flows))
+	q := make([]float64, len(flows))
+	e := make([]float64, len(flows))
+	var total float64
+	for k, flow := range flows {
+		date := truncateDay(flow.Date)
+		if date.Before(start) {
+			return decimal.Zero, ErrInvalidCashFlows
+		}
+
+		periods := 0
+		for !AddMonths(start, periods+1).After(date) {
+			periods++
+		}
+
+		amounts[k] = flow.Amount.InexactFloat64()
+		q[k] = float64(periods)
+		e[k] = float64(daysBetween(AddMonths(start, periods), date)) / basePeriodDays
+		total += amounts[k]
+	}
+
+	if total < 0 {
+		return decimal.Zero, ErrInvalidCashFlows
+	}
+	if total == 0 {
+		return decimal.Zero, nil
+	}
+
+	presentValue := func(i float64) float64 {
+		var sum float64
+		for k := range amounts {
+			sum += amounts[k] / ((1 + e[k]*i) * math.Pow(1+i, q[k]))
+		}
+		return sum
+	}
+
+	// Приведенная стоимость убывает по i, поэтому корень ищется делением отрезка пополам.
+	low, high := 0.0, 1.0
+	for presentValue(high) > 0 {
+		high *= 2
+	}
+	for n := 0; n < 200 && high-low > 1e-12; n++ {
+		mid := (low + high) / 2
+		if presentValue(mid) > 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return decimal.NewFromFloat((low + high) / 2 * 12 * 100).Round(3), nil
+}
@@ -0,0 +1,54 @@
+package amortization
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFullCostRate(t *testing.T) {
+	schedule, err := Generate(Params{
+		Principal:  d("100000"),
+		AnnualRate: d("12"),
+		Term:       12,
+		Method:     Annuity,
+		DayCount:   Thirty360,
+		StartDate:  date(2025, 1, 15),
+	})
+	assert.NoError(t, err)
+
+	flows := func(fee string) []CashFlow {
+		result := []CashFlow{{Date: date(2025, 1, 15), Amount: d("-100000").Add(d(fee))}}
+		for _, installment := range schedule {
+			result = append(result, CashFlow{Date: installment.Date, Amount: installment.Payment})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name  string
+		flows []CashFlow
+		want  string
+	}{
+		{name: "monthly schedule without fees equals nominal rate", flows: flows("0"), want: "12.000"},
+		{name: "upfront fee raises full cost", flows: flows("2000"), want: "15.854"},
+		{
+			name: "partial first period",
+			flows: []CashFlow{
+				{Date: date(2025, 1, 1), Amount: d("-10000")},
+				{Date: date(2025, 1, 16), Amount: d("10050")},
+			},
+			want: "12.167",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := FullCostRate(tc.flows)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, rate.StringFixed(3))
+		})
+	}
+
+	_, err = FullCostRate([]CashFlow{{Date: date(2025, 1, 15), Amount: d("1000")}})
+	assert.ErrorIs(t, err, ErrInvalidCashFlows)
+}
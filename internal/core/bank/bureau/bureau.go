@@ -0,0 +1,199 @@
+// Package bureau формирует ежемесячный файл с кредитными историями заемщиков для бюро кредитных историй
+// и проверяет его на соответствие схеме бюро.
+package bureau
+
+import (
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/beevik/etree"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"time"
+)
+
+const (
+	Version  = "1.0" // Версия формата файла
+	Currency = "RUB" // Валюта кредитов
+)
+
+// PaymentStatus своевременность платежа по графику, наступившего в отчетном месяце.
+type PaymentStatus string
+
+const (
+	PaymentOnTime PaymentStatus = "on_time" // Погашен не позже дня платежа
+	PaymentLate   PaymentStatus = "late"    // Погашен с опозданием до конца отчетного месяца
+	PaymentMissed PaymentStatus = "missed"  // Не погашен на конец отчетного месяца
+)
+
+// Payment платеж по графику, наступивший в отчетном месяце.
+type Payment struct {
+	DueDate  time.Time
+	Amount   decimal.Decimal
+	Status   PaymentStatus
+	PaidDate *time.Time
+}
+
+// Discipline платежная дисциплина заемщика по кредиту за отчетный месяц.
+type Discipline struct {
+	OutstandingPrincipal decimal.Decimal // Непогашенный основной долг на конец месяца
+	OverdueAmount        decimal.Decimal // Сумма просроченных платежей на конец месяца
+	DaysPastDue          int             // Дней просрочки самого раннего непогашенного платежа
+	Closed               bool            // Кредит погашен полностью
+	CloseDate            *time.Time      // Дата полного погашения
+	Payments             []Payment       // Платежи, наступившие в месяце
+}
+
+// NewDiscipline восстанавливает платежную дисциплину по кредиту record за месяц, начинающийся в period.
+// Платеж считается погашенным, если он полностью погашен до конца месяца, а просроченным, если его дата
+// раньше последнего дня месяца и он не погашен.
+func NewDiscipline(record entity.BureauRecord, period time.Time) Discipline {
+	end := period.AddDate(0, 1, 0)
+	reportDate := end.AddDate(0, 0, -1)
+
+	discipline := Discipline{
+		OutstandingPrincipal: record.Amount,
+		OverdueAmount:        decimal.Zero,
+		Closed:               len(record.Schedule) > 0,
+	}
+
+	for _, payment := range record.Schedule {
+		paid := payment.PaidAt != nil && payment.PaidAt.Before(end)
+		if paid {
+			discipline.OutstandingPrincipal = discipline.OutstandingPrincipal.Sub(payment.PrincipalAmount)
+			if discipline.CloseDate == nil || payment.PaidAt.After(*discipline.CloseDate) {
+				discipline.CloseDate = payment.PaidAt
+			}
+		} else {
+			discipline.Closed = false
+		}
+
+		if !paid && payment.PaymentDate.Before(reportDate) {
+			repaid := decimal.Min(decimal.Max(payment.PaidAmount.Sub(payment.PenaltyPaid), decimal.Zero), payment.PaymentAmount)
+			discipline.OverdueAmount = discipline.OverdueAmount.Add(payment.PaymentAmount.Sub(repaid))
+			if days := int(reportDate.Sub(date(payment.PaymentDate)).Hours() / 24); days > discipline.DaysPastDue {
+				discipline.DaysPastDue = days
+			}
+		}
+
+		if payment.PaymentDate.Before(period) || !payment.PaymentDate.Before(end) {
+			continue
+		}
+
+		status := PaymentMissed
+		if paid {
+			status = PaymentLate
+			if payment.PaidAt.Before(date(payment.PaymentDate).AddDate(0, 0, 1)) {
+				status = PaymentOnTime
+			}
+		}
+		due := Payment{DueDate: payment.PaymentDate, Amount: payment.PaymentAmount, Status: status}
+		if paid {
+			due.PaidDate = payment.PaidAt
+		}
+		discipline.Payments = append(discipline.Payments, due)
+	}
+
+	if !discipline.Closed {
+		discipline.CloseDate = nil
+	}
+
+	return discipline
+}
+
+// NewDocument формирует файл за месяц period по кредитам records от имени участника бюро memberCode.
+func NewDocument(memberCode string, period time.Time, records []entity.BureauRecord) *etree.Document {
+	period = Period(period)
+
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+
+	root := doc.CreateElement("CreditHistorySubmission")
+	root.CreateAttr("version", Version)
+
+	header := root.CreateElement("Header")
+	header.CreateElement("MemberCode").SetText(memberCode)
+	header.CreateElement("Period").SetText(period.Format("2006-01"))
+	header.CreateElement("ReportDate").SetText(period.AddDate(0, 1, -1).Format(time.DateOnly))
+	header.CreateElement("RecordCount").SetText(strconv.Itoa(len(records)))
+
+	for _, record := range records {
+		discipline := NewDiscipline(record, period)
+		el := root.CreateElement("Record")
+
+		borrower := el.CreateElement("Borrower")
+		borrower.CreateElement("ID").SetText(strconv.Itoa(int(record.UserID)))
+		borrower.CreateElement("LastName").SetText(record.LastName)
+		borrower.CreateElement("FirstName").SetText(record.FirstName)
+		dateOfBirth := ""
+		if record.DateOfBirth != nil {
+			dateOfBirth = record.DateOfBirth.Format(time.DateOnly)
+		}
+		borrower.CreateElement("DateOfBirth").SetText(dateOfBirth)
+
+		contract := el.CreateElement("Contract")
+		contract.CreateElement("ID").SetText(strconv.Itoa(int(record.ID)))
+		contract.CreateElement("OpenDate").SetText(record.CreatedAt.Format(time.DateOnly))
+		contract.CreateElement("Amount").SetText(record.Amount.StringFixed(2))
+		contract.CreateElement("Currency").SetText(Currency)
+		contract.CreateElement("InterestRate").SetText(record.InterestRate.String())
+		contract.CreateElement("TermMonths").SetText(strconv.Itoa(int(record.TermInMonths)))
+		contract.CreateElement("ScheduleType").SetText(string(record.ScheduleType))
+		if discipline.Closed {
+			contract.CreateElement("Status").SetText("closed")
+			contract.CreateElement("CloseDate").SetText(discipline.CloseDate.Format(time.DateOnly))
+		} else {
+			contract.CreateElement("Status").SetText("open")
+		}
+
+		history := el.CreateElement("Discipline")
+		history.CreateElement("OutstandingPrincipal").SetText(discipline.OutstandingPrincipal.StringFixed(2))
+		history.CreateElement("OverdueAmount").SetText(discipline.OverdueAmount.StringFixed(2))
+		history.CreateElement("DaysPastDue").SetText(strconv.Itoa(discipline.DaysPastDue))
+
+		payments := history.CreateElement("Payments")
+		for _, payment := range discipline.Payments {
+			p := payments.CreateElement("Payment")
+			p.CreateElement("DueDate").SetText(payment.DueDate.Format(time.DateOnly))
+			p.CreateElement("Amount").SetText(payment.Amount.StringFixed(2))
+			p.CreateElement("Status").SetText(string(payment.Status))
+			if payment.PaidDate != nil {
+				p.CreateElement("PaidDate").SetText(payment.PaidDate.Format(time.DateOnly))
+			}
+		}
+	}
+
+	return doc
+}
+
+// Build формирует файл за месяц period, проверяет его по схеме и возвращает его содержимое.
+func Build(memberCode string, period time.Time, records []entity.BureauRecord) ([]byte, error) {
+	doc := NewDocument(memberCode, period, records)
+	if err := Validate(doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidBureauFile, err)
+	}
+
+	doc.Indent(2)
+	content, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write bureau file: %w", err)
+	}
+
+	return content, nil
+}
+
+// FileName возвращает имя файла участника бюро memberCode за месяц period.
+func FileName(memberCode string, period time.Time) string {
+	return fmt.Sprintf("%s_%s.xml", memberCode, Period(period).Format("200601"))
+}
+
+// Period возвращает первый день месяца, в который попадает t.
+func Period(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// date отбрасывает время.
+func date(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,205 @@
+package bureau
+
+import (
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/beevik/etree"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func paidAt(t time.Time) *time.Time {
+	return &t
+}
+
+func payment(due time.Time, amount, principal string, paid *time.Time) entity.PaymentSchedule {
+	return entity.PaymentSchedule{
+		PaymentDate:     due,
+		PaymentAmount:   decimal.RequireFromString(amount),
+		PrincipalAmount: decimal.RequireFromString(principal),
+		PaidAmount:      decimal.Zero,
+		PenaltyPaid:     decimal.Zero,
+		PaidAt:          paid,
+	}
+}
+
+func record() entity.BureauRecord {
+	birth := day(1990, 4, 12)
+
+	return entity.BureauRecord{
+		Credit: entity.Credit{
+			ID:           7,
+			UserID:       3,
+			Amount:       decimal.NewFromInt(30000),
+			InterestRate: decimal.RequireFromString("12.5"),
+			TermInMonths: 3,
+			ScheduleType: amortization.Annuity,
+			CreatedAt:    day(2025, 5, 15),
+		},
+		FirstName:   "Иван",
+		LastName:    "Петров",
+		DateOfBirth: &birth,
+		Schedule: []entity.PaymentSchedule{
+			payment(day(2025, 6, 15), "10200", "9900", paidAt(day(2025, 6, 15).Add(10*time.Hour))),
+			payment(day(2025, 7, 15), "10200", "10000", paidAt(day(2025, 7, 20).Add(9*time.Hour))),
+			payment(day(2025, 8, 15), "10200", "10100", nil),
+		},
+	}
+}
+
+func TestNewDiscipline(t *testing.T) {
+	testCases := []struct {
+		name        string
+		period      time.Time
+		outstanding string
+		overdue     string
+		dpd         int
+		closed      bool
+		statuses    []PaymentStatus
+	}{
+		{
+			name:        "paid on time",
+			period:      day(2025, 6, 1),
+			outstanding: "20100",
+			overdue:     "0",
+			statuses:    []PaymentStatus{PaymentOnTime},
+		},
+		{
+			name:        "paid late within month",
+			period:      day(2025, 7, 1),
+			outstanding: "10100",
+			overdue:     "0",
+			statuses:    []PaymentStatus{PaymentLate},
+		},
+		{
+			name:        "missed",
+			period:      day(2025, 8, 1),
+			outstanding: "10100",
+			overdue:     "10200",
+			dpd:         16,
+			statuses:    []PaymentStatus{PaymentMissed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			discipline := NewDiscipline(record(), tc.period)
+
+			assert.Equal(t, tc.outstanding, discipline.OutstandingPrincipal.String())
+			assert.Equal(t, tc.overdue, discipline.OverdueAmount.String())
+			assert.Equal(t, tc.dpd, discipline.DaysPastDue)
+			assert.Equal(t, tc.closed, discipline.Closed)
+			require.Len(t, discipline.Payments, len(tc.statuses))
+			for i, status := range tc.statuses {
+				assert.Equal(t, status, discipline.Payments[i].Status)
+			}
+		})
+	}
+
+	t.Run("closed", func(t *testing.T) {
+		closed := record()
+		closed.Schedule[2].PaidAt = paidAt(day(2025, 8, 10))
+
+		discipline := NewDiscipline(closed, day(2025, 8, 1))
+		assert.True(t, discipline.Closed)
+		assert.Equal(t, day(2025, 8, 10), *discipline.CloseDate)
+		assert.True(t, discipline.OutstandingPrincipal.IsZero())
+	})
+}
+
+func TestBuild(t *testing.T) {
+	content, err := Build("BANK000001", day(2025, 7, 9), []entity.BureauRecord{record()})
+	require.NoError(t, err)
+
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(content))
+	assert.NoError(t, Validate(doc))
+
+	xml := string(content)
+	assert.Contains(t, xml, "<Period>2025-07</Period>")
+	assert.Contains(t, xml, "<ReportDate>2025-07-31</ReportDate>")
+	assert.Contains(t, xml, "<LastName>Петров</LastName>")
+	assert.Contains(t, xml, "<Status>late</Status>")
+	assert.Contains(t, xml, "<PaidDate>2025-07-20</PaidDate>")
+	assert.Equal(t, "BANK000001_202507.xml", FileName("BANK000001", day(2025, 7, 9)))
+
+	again, err := Build("BANK000001", day(2025, 7, 9), []entity.BureauRecord{record()})
+	require.NoError(t, err)
+	assert.Equal(t, content, again)
+}
+
+func TestBuild_InvalidBorrower(t *testing.T) {
+	incomplete := record()
+	incomplete.LastName = ""
+	incomplete.DateOfBirth = nil
+
+	_, err := Build("BANK000001", day(2025, 7, 1), []entity.BureauRecord{incomplete})
+	require.ErrorIs(t, err, entity.ErrInvalidBureauFile)
+	assert.Contains(t, err.Error(), "/CreditHistorySubmission/Record[1]/Borrower/LastName")
+	assert.Contains(t, err.Error(), "/CreditHistorySubmission/Record[1]/Borrower/DateOfBirth")
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		edit func(doc *etree.Document)
+		want string
+	}{
+		{
+			name: "record count mismatch",
+			edit: func(doc *etree.Document) {
+				doc.FindElement("//RecordCount").SetText("2")
+			},
+			want: "does not match 1 records",
+		},
+		{
+			name: "missing element",
+			edit: func(doc *etree.Document) {
+				contract := doc.FindElement("//Contract")
+				contract.RemoveChild(contract.SelectElement("Currency"))
+			},
+			want: "missing element Currency",
+		},
+		{
+			name: "unexpected element",
+			edit: func(doc *etree.Document) {
+				doc.FindElement("//Borrower").CreateElement("Passport").SetText("4500 123456")
+			},
+			want: "unexpected element Passport",
+		},
+		{
+			name: "invalid attribute",
+			edit: func(doc *etree.Document) {
+				doc.Root().CreateAttr("version", "2.0")
+			},
+			want: "invalid attribute version",
+		},
+		{
+			name: "invalid amount",
+			edit: func(doc *etree.Document) {
+				doc.FindElement("//Contract/Amount").SetText("30000")
+			},
+			want: `/Contract/Amount: invalid value "30000"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := NewDocument("BANK000001", day(2025, 7, 1), []entity.BureauRecord{record()})
+			require.NoError(t, Validate(doc))
+
+			tc.edit(doc)
+			err := Validate(doc)
+			require.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), tc.want), err.Error())
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package bureau
+
+import (
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unbounded снимает ограничение на число повторений элемента.
+const Unbounded = -1
+
+// Element описание элемента файла: сколько раз он может встретиться подряд, какие атрибуты обязательны,
+// какому шаблону должен соответствовать текст листового элемента и из каких элементов в каком порядке состоит составной.
+type Element struct {
+	Name       string
+	MinOccurs  int
+	MaxOccurs  int
+	Attributes map[string]*regexp.Regexp
+	Pattern    *regexp.Regexp
+	Children   []Element
+}
+
+var (
+	datePattern   = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])$`)
+	periodPattern = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+	amountPattern = regexp.MustCompile(`^\d{1,15}\.\d{2}$`)
+	ratePattern   = regexp.MustCompile(`^\d{1,3}(\.\d{1,4})?$`)
+	countPattern  = regexp.MustCompile(`^\d{1,9}$`)
+	namePattern   = regexp.MustCompile(`^\S.{0,99}$`)
+)
+
+// leaf описывает обязательный листовой элемент.
+func leaf(name string, pattern *regexp.Regexp) Element {
+	return Element{Name: name, MinOccurs: 1, MaxOccurs: 1, Pattern: pattern}
+}
+
+// optional делает элемент необязательным.
+func optional(e Element) Element {
+	e.MinOccurs = 0
+	return e
+}
+
+// Schema структура файла для бюро кредитных историй.
+var Schema = Element{
+	Name:       "CreditHistorySubmission",
+	MinOccurs:  1,
+	MaxOccurs:  1,
+	Attributes: map[string]*regexp.Regexp{"version": regexp.MustCompile(`^` + regexp.QuoteMeta(Version) + `$`)},
+	Children: []Element{
+		{
+			Name: "Header", MinOccurs: 1, MaxOccurs: 1,
+			Children: []Element{
+				leaf("MemberCode", regexp.MustCompile(`^[0-9A-Z]{6,12}$`)),
+				leaf("Period", periodPattern),
+				leaf("ReportDate", datePattern),
+				leaf("RecordCount", countPattern),
+			},
+		},
+		{
+			Name: "Record", MinOccurs: 0, MaxOccurs: Unbounded,
+			Children: []Element{
+				{
+					Name: "Borrower", MinOccurs: 1, MaxOccurs: 1,
+					Children: []Element{
+						leaf("ID", countPattern),
+						leaf("LastName", namePattern),
+						leaf("FirstName", namePattern),
+						leaf("DateOfBirth", datePattern),
+					},
+				},
+				{
+					Name: "Contract", MinOccurs: 1, MaxOccurs: 1,
+					Children: []Element{
+						leaf("ID", countPattern),
+						leaf("OpenDate", datePattern),
+						leaf("Amount", amountPattern),
+						leaf("Currency", regexp.MustCompile(`^[A-Z]{3}$`)),
+						leaf("InterestRate", ratePattern),
+						leaf("TermMonths", countPattern),
+						leaf("ScheduleType", regexp.MustCompile(`^(annuity|differentiated)$`)),
+						leaf("Status", regexp.MustCompile(`^(open|closed)$`)),
+						optional(leaf("CloseDate", datePattern)),
+					},
+				},
+				{
+					Name: "Discipline", MinOccurs: 1, MaxOccurs: 1,
+					Children: []Element{
+						leaf("OutstandingPrincipal", amountPattern),
+						leaf("OverdueAmount", amountPattern),
+						leaf("DaysPastDue", countPattern),
+						{
+							Name: "Payments", MinOccurs: 1, MaxOccurs: 1,
+							Children: []Element{
+								{
+									Name: "Payment", MinOccurs: 0, MaxOccurs: Unbounded,
+									Children: []Element{
+										leaf("DueDate", datePattern),
+										leaf("Amount", amountPattern),
+										leaf("Status", regexp.MustCompile(`^(on_time|late|missed)$`)),
+										optional(leaf("PaidDate", datePattern)),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// Validate проверяет документ doc на соответствие схеме Schema и количество записей, указанное в заголовке.
+// Возвращает все найденные нарушения с путями к элементам.
+func Validate(doc *etree.Document) error {
+	root := doc.Root()
+	if root == nil {
+		return errors.New("document has no root element")
+	}
+	if root.Tag != Schema.Name {
+		return fmt.Errorf("/%s: unexpected root element, want %s", root.Tag, Schema.Name)
+	}
+
+	var errs []error
+	validateElement(root, Schema, "/"+root.Tag, &errs)
+
+	if len(errs) == 0 {
+		count, _ := strconv.Atoi(root.FindElement("./Header/RecordCount").Text())
+		if records := len(root.SelectElements("Record")); records != count {
+			errs = append(errs, fmt.Errorf("/%s/Header/RecordCount: %d does not match %d records", root.Tag, count, records))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateElement проверяет атрибуты, текст и дочерние элементы el по описанию schema.
+func validateElement(el *etree.Element, schema Element, path string, errs *[]error) {
+	for name, pattern := range schema.Attributes {
+		attr := el.SelectAttr(name)
+		if attr == nil {
+			*errs = append(*errs, fmt.Errorf("%s: missing attribute %s", path, name))
+			continue
+		}
+		if !pattern.MatchString(attr.Value) {
+			*errs = append(*errs, fmt.Errorf("%s: invalid attribute %s value %q", path, name, attr.Value))
+		}
+	}
+
+	children := el.ChildElements()
+	if len(schema.Children) == 0 {
+		if len(children) > 0 {
+			*errs = append(*errs, fmt.Errorf("%s: unexpected child element %s", path, children[0].Tag))
+		}
+		if schema.Pattern != nil && !schema.Pattern.MatchString(el.Text()) {
+			*errs = append(*errs, fmt.Errorf("%s: invalid value %q", path, el.Text()))
+		}
+		return
+	}
+
+	if text := strings.TrimSpace(el.Text()); text != "" {
+		*errs = append(*errs, fmt.Errorf("%s: unexpected text %q", path, text))
+	}
+
+	pos := 0
+	for _, child := range schema.Children {
+		occurs := 0
+		for pos < len(children) && children[pos].Tag == child.Name {
+			occurs++
+			childPath := path + "/" + child.Name
+			if child.MaxOccurs != 1 {
+				childPath = fmt.Sprintf("%s[%d]", childPath, occurs)
+			}
+			validateElement(children[pos], child, childPath, errs)
+			pos++
+		}
+
+		if occurs < child.MinOccurs {
+			*errs = append(*errs, fmt.Errorf("%s: missing element %s", path, child.Name))
+		}
+		if child.MaxOccurs != Unbounded && occurs > child.MaxOccurs {
+			*errs = append(*errs, fmt.Errorf("%s: element %s occurs %d times, at most %d allowed", path, child.Name, occurs, child.MaxOccurs))
+		}
+	}
+
+	if pos < len(children) {
+		*errs = append(*errs, fmt.Errorf("%s: unexpected element %s", path, children[pos].Tag))
+	}
+}
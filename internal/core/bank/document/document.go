@@ -0,0 +1,125 @@
+// Package document формирует документы по кредитам из шаблонов.
+package document
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/pdf"
+	"github.com/shopspring/decimal"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// funcs функции, доступные в шаблонах документов.
+var funcs = map[string]any{
+	"money": func(d decimal.Decimal) string { return d.StringFixed(2) },
+	"rate":  func(d decimal.Decimal) string { return d.String() },
+	"date":  func(t time.Time) string { return t.Format("02.01.2006") },
+	"inc":   func(i int) int { return i + 1 },
+	"method": func(m amortization.Method) string {
+		if m == amortization.Differentiated {
+			return "дифференцированные платежи"
+		}
+		return "аннуитетные платежи"
+	},
+}
+
+// agreementData данные, подставляемые в шаблон кредитного договора.
+type agreementData struct {
+	Credit         *entity.Credit
+	Schedule       []entity.PaymentSchedule
+	TotalInterest  decimal.Decimal
+	TotalPayments  decimal.Decimal
+	FullCostRate   decimal.Decimal
+	FullCostAmount decimal.Decimal
+}
+
+// Generator формирует кредитные договоры в форматах HTML и PDF и подписывает их ключом банка.
+type Generator struct {
+	html       *htmltemplate.Template
+	text       *texttemplate.Template
+	signingKey ed25519.PrivateKey
+}
+
+// NewGenerator создает генератор документов из встроенных шаблонов, подписывающий документы ключом signingKey.
+func NewGenerator(signingKey ed25519.PrivateKey) *Generator {
+	return &Generator{
+		html:       htmltemplate.Must(htmltemplate.New("agreement.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/agreement.html.tmpl")),
+		text:       texttemplate.Must(texttemplate.New("agreement.txt.tmpl").Funcs(funcs).ParseFS(templates, "templates/agreement.txt.tmpl")),
+		signingKey: signingKey,
+	}
+}
+
+// SigningKey выводит ключ подписи Ed25519 из секрета secret.
+func SigningKey(secret string) ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(secret))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
+
+// Agreement формирует кредитный договор по кредиту credit с графиком платежей schedule в форматах HTML и PDF,
+// рассчитывает контрольную сумму каждого документа и подписывает его. Для одних и тех же данных содержимое
+// документов и подписи совпадают.
+func (g *Generator) Agreement(credit *entity.Credit, schedule []entity.PaymentSchedule) ([]entity.CreditAgreement, error) {
+	data := &agreementData{
+		Credit:        credit,
+		Schedule:      schedule,
+		TotalInterest: decimal.Zero,
+		TotalPayments: decimal.Zero,
+	}
+
+	flows := []amortization.CashFlow{{Date: credit.CreatedAt, Amount: credit.Amount.Neg()}}
+	for _, payment := range schedule {
+		data.TotalInterest = data.TotalInterest.Add(payment.InterestAmount)
+		data.TotalPayments = data.TotalPayments.Add(payment.PaymentAmount)
+		flows = append(flows, amortization.CashFlow{Date: payment.PaymentDate, Amount: payment.PaymentAmount})
+	}
+
+	var err error
+	data.FullCostRate, err = amortization.FullCostRate(flows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate full cost of credit: %w", err)
+	}
+	data.FullCostAmount = data.TotalInterest
+
+	var html bytes.Buffer
+	if err := g.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render agreement html: %w", err)
+	}
+
+	var text bytes.Buffer
+	if err := g.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render agreement text: %w", err)
+	}
+
+	doc := pdf.New(fmt.Sprintf("Кредитный договор № %d", credit.ID))
+	doc.Write(text.String())
+
+	return []entity.CreditAgreement{
+		g.newAgreement(credit.ID, entity.HTMLDocument, html.Bytes()),
+		g.newAgreement(credit.ID, entity.PDFDocument, doc.Bytes()),
+	}, nil
+}
+
+// newAgreement создает документ договора, рассчитывает SHA-256 его содержимого и подписывает содержимое ключом банка.
+func (g *Generator) newAgreement(creditID int32, format entity.DocumentFormat, content []byte) entity.CreditAgreement {
+	hash := sha256.Sum256(content)
+
+	return entity.CreditAgreement{
+		CreditID:    creditID,
+		Format:      format,
+		Content:     content,
+		ContentHash: hex.EncodeToString(hash[:]),
+		Signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(g.signingKey, content)),
+	}
+}
@@ -0,0 +1,66 @@
+package document
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerator_Agreement(t *testing.T) {
+	credit := &entity.Credit{
+		ID:           12,
+		UserID:       3,
+		AccountID:    5,
+		Amount:       decimal.NewFromInt(30000),
+		InterestRate: decimal.NewFromInt(12),
+		TermInMonths: 3,
+		ScheduleType: amortization.Annuity,
+		CreatedAt:    time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+	}
+	schedule := []entity.PaymentSchedule{
+		{PaymentDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), PaymentAmount: decimal.RequireFromString("10200.66"), PrincipalAmount: decimal.RequireFromString("9900.66"), InterestAmount: decimal.RequireFromString("300.00"), Balance: decimal.RequireFromString("20099.34")},
+		{PaymentDate: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), PaymentAmount: decimal.RequireFromString("10200.66"), PrincipalAmount: decimal.RequireFromString("9999.67"), InterestAmount: decimal.RequireFromString("200.99"), Balance: decimal.RequireFromString("10099.67")},
+		{PaymentDate: time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), PaymentAmount: decimal.RequireFromString("10200.67"), PrincipalAmount: decimal.RequireFromString("10099.67"), InterestAmount: decimal.RequireFromString("101.00"), Balance: decimal.Zero},
+	}
+
+	key := SigningKey("test-secret")
+	generator := NewGenerator(key)
+	agreements, err := generator.Agreement(credit, schedule)
+	require.NoError(t, err)
+	require.Len(t, agreements, 2)
+
+	html := agreements[0]
+	assert.Equal(t, entity.HTMLDocument, html.Format)
+	assert.Equal(t, int32(12), html.CreditID)
+	assert.Contains(t, string(html.Content), "Кредитный договор № 12")
+	assert.Contains(t, string(html.Content), "<td>15.04.2025</td>")
+	assert.Contains(t, string(html.Content), "30601.99")
+	assert.Equal(t, 3, strings.Count(string(html.Content), `<td class="amount">10200.6`))
+
+	pdf := agreements[1]
+	assert.Equal(t, entity.PDFDocument, pdf.Format)
+	assert.True(t, strings.HasPrefix(string(pdf.Content), "%PDF-"))
+	assert.Contains(t, string(pdf.Content), "/Count 2")
+
+	for _, agreement := range agreements {
+		hash := sha256.Sum256(agreement.Content)
+		assert.Equal(t, hex.EncodeToString(hash[:]), agreement.ContentHash)
+
+		signature, err := base64.StdEncoding.DecodeString(agreement.Signature)
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(key.Public().(ed25519.PublicKey), agreement.Content, signature))
+	}
+
+	again, err := generator.Agreement(credit, schedule)
+	require.NoError(t, err)
+	assert.Equal(t, agreements, again)
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// DocumentFormat формат, в котором выпущен документ.
+type DocumentFormat string
+
+const (
+	HTMLDocument DocumentFormat = "html"
+	PDFDocument  DocumentFormat = "pdf"
+)
+
+// Validate проверяет формат документа.
+func (f DocumentFormat) Validate() error {
+	switch f {
+	case HTMLDocument, PDFDocument:
+		return nil
+	default:
+		return fmt.Errorf("unknown document format: %s", f)
+	}
+}
+
+// ContentType возвращает MIME-тип документа в формате f.
+func (f DocumentFormat) ContentType() string {
+	if f == PDFDocument {
+		return "application/pdf"
+	}
+
+	return "text/html; charset=utf-8"
+}
+
+// CreditAgreement кредитный договор, выпущенный при оформлении кредита. После выпуска договор не изменяется.
+// Договор подписывается электронной подписью банка. Подпись заемщика (например, кодом подтверждения)
+// не запрашивается и в документе не фиксируется.
+type CreditAgreement struct {
+	ID          int32          `db:"id" json:"id"`                     // Идентификатор документа
+	CreditID    int32          `db:"credit_id" json:"credit_id"`       // Внешний ключ на кредит
+	Format      DocumentFormat `db:"format" json:"format"`             // Формат документа
+	Content     []byte         `db:"content" json:"-"`                 // Содержимое документа
+	ContentHash string         `db:"content_hash" json:"content_hash"` // SHA-256 содержимого в шестнадцатеричном виде
+	Signature   string         `db:"signature" json:"signature"`       // Подпись банка Ed25519 содержимого в base64
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`     // Дата выпуска документа
+}
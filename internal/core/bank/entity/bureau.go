@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// BureauRecord кредит вместе с данными заемщика и графиком платежей для передачи в бюро кредитных историй.
+type BureauRecord struct {
+	Credit
+	FirstName   string            `db:"first_name"`    // Имя заемщика
+	LastName    string            `db:"last_name"`     // Фамилия заемщика
+	DateOfBirth *time.Time        `db:"date_of_birth"` // Дата рождения заемщика
+	Schedule    []PaymentSchedule `db:"-"`             // График платежей по кредиту
+}
+
+// BureauSubmission файл, переданный в бюро кредитных историй за отчетный месяц. За каждый месяц передается один файл.
+type BureauSubmission struct {
+	ID          int32     `db:"id" json:"id"`                     // Идентификатор передачи
+	Period      time.Time `db:"period" json:"period"`             // Первый день отчетного месяца
+	FileName    string    `db:"file_name" json:"file_name"`       // Имя файла
+	Content     []byte    `db:"content" json:"-"`                 // Содержимое файла
+	ContentHash string    `db:"content_hash" json:"content_hash"` // SHA-256 содержимого в шестнадцатеричном виде
+	Records     int32     `db:"records" json:"records"`           // Количество кредитов в файле
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`     // Дата формирования файла
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// CreditApplicationStatus описывает этап рассмотрения заявки на кредит.
+type CreditApplicationStatus string
+
+const (
+	ApplicationSubmitted   CreditApplicationStatus = "submitted"    // Заявка подана и ожидает скоринга
+	ApplicationUnderReview CreditApplicationStatus = "under_review" // Скоринг не принял решения, заявку рассматривает сотрудник
+	ApplicationApproved    CreditApplicationStatus = "approved"     // Заявка одобрена, кредит еще не выдан
+	ApplicationRejected    CreditApplicationStatus = "rejected"     // Заявка отклонена
+	ApplicationDisbursed   CreditApplicationStatus = "disbursed"    // Кредит выдан и зачислен на счет
+)
+
+// Validate проверяет статус заявки.
+func (s CreditApplicationStatus) Validate() error {
+	switch s {
+	case ApplicationSubmitted, ApplicationUnderReview, ApplicationApproved, ApplicationRejected, ApplicationDisbursed:
+		return nil
+	default:
+		return fmt.Errorf("unknown credit application status: %s", s)
+	}
+}
+
+// applicationTransitions перечисляет допустимые переходы между статусами заявки.
+var applicationTransitions = map[CreditApplicationStatus][]CreditApplicationStatus{
+	ApplicationSubmitted:   {ApplicationUnderReview, ApplicationApproved, ApplicationRejected},
+	ApplicationUnderReview: {ApplicationApproved, ApplicationRejected},
+	ApplicationApproved:    {ApplicationDisbursed},
+}
+
+// CreditApplication описывает заявку пользователя на кредит.
+type CreditApplication struct {
+	ID            int32                   `db:"id" json:"id"`                         // Идентификатор заявки
+	UserID        int32                   `db:"user_id" json:"user_id"`               // Внешний ключ на заявителя
+	AccountID     int32                   `db:"account_id" json:"account_id"`         // Счет для зачисления кредита
+	Amount        decimal.Decimal         `db:"amount" json:"amount"`                 // Запрошенная сумма
+	TermInMonths  int32                   `db:"term_in_months" json:"term_in_months"` // Запрошенный срок (в месяцах)
+	ScheduleType  amortization.Method     `db:"schedule_type" json:"schedule_type"`   // Способ погашения
+	MonthlyIncome decimal.Decimal         `db:"monthly_income" json:"monthly_income"` // Ежемесячный доход, заявленный заемщиком
+	InterestRate  decimal.Decimal         `db:"interest_rate" json:"interest_rate"`   // Ставка, предложенная при подаче заявки, в процентах
+	RateType      RateType                `db:"rate_type" json:"rate_type"`           // Вид ставки
+	Status        CreditApplicationStatus `db:"status" json:"status"`                 // Статус заявки
+	Score         *int32                  `db:"score" json:"score,omitempty"`         // Скоринговый балл
+	CreditID      *int32                  `db:"credit_id" json:"credit_id,omitempty"` // Выданный по заявке кредит
+	CreatedAt     time.Time               `db:"created_at" json:"created_at"`         // Дата подачи заявки
+	UpdatedAt     time.Time               `db:"updated_at" json:"updated_at"`         // Дата последнего изменения
+}
+
+// Transition переводит заявку в статус to, если такой переход допустим.
+func (a *CreditApplication) Transition(to CreditApplicationStatus) error {
+	for _, allowed := range applicationTransitions[a.Status] {
+		if allowed == to {
+			a.Status = to
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidApplicationStatus, a.Status, to)
+}
+
+// CreditDecision фиксирует решение по заявке: автоматическое по результатам скоринга или ручное решение сотрудника.
+type CreditDecision struct {
+	ID            int32                   `db:"id" json:"id"`                           // Идентификатор решения
+	ApplicationID int32                   `db:"application_id" json:"application_id"`   // Внешний ключ на заявку
+	Status        CreditApplicationStatus `db:"status" json:"status"`                   // Статус, в который решение перевело заявку
+	Score         *int32                  `db:"score" json:"score,omitempty"`           // Скоринговый балл для автоматического решения
+	Reasons       []string                `db:"reasons" json:"reasons"`                 // Причины решения
+	DecidedBy     *int32                  `db:"decided_by" json:"decided_by,omitempty"` // Сотрудник, принявший решение; пусто для скоринга
+	CreatedAt     time.Time               `db:"created_at" json:"created_at"`           // Дата решения
+}
+
+// CreditHistory описывает действующие кредиты заявителя.
+type CreditHistory struct {
+	ActiveCredits   int             `db:"active_credits"`   // Число действующих кредитов
+	OverduePayments int             `db:"overdue_payments"` // Число просроченных платежей
+	MonthlyPayments decimal.Decimal `db:"monthly_payments"` // Сумма ближайших ежемесячных платежей по действующим кредитам
+}
+
+// ScoringResult содержит итог скоринга заявки.
+type ScoringResult struct {
+	Status  CreditApplicationStatus // approved, rejected или under_review
+	Score   int32                   // Балл от 0 до 100
+	Reasons []string                // Причины снижения балла или отказа
+}
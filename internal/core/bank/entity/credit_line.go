@@ -0,0 +1,142 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// MaxStatementDay последний день месяца, который можно выбрать днем формирования выписки,
+// чтобы он был в каждом месяце.
+const MaxStatementDay = 28
+
+// CreditLine описывает условия возобновляемой кредитной линии, открытой на кредитном счете.
+type CreditLine struct {
+	AccountID         int32           `db:"account_id" json:"account_id"`                   // Внешний ключ на кредитный счет
+	InterestRate      decimal.Decimal `db:"interest_rate" json:"interest_rate"`             // Процентная ставка, годовых
+	GraceDays         int32           `db:"grace_days" json:"grace_days"`                   // Дней после выписки на полное погашение без процентов
+	MinPaymentPercent decimal.Decimal `db:"min_payment_percent" json:"min_payment_percent"` // Минимальный платеж в процентах от долга по выписке
+	MinPaymentAmount  decimal.Decimal `db:"min_payment_amount" json:"min_payment_amount"`   // Нижняя граница минимального платежа
+	StatementDay      int32           `db:"statement_day" json:"statement_day"`             // День месяца, в который формируется выписка
+	CycleStartedAt    time.Time       `db:"cycle_started_at" json:"cycle_started_at"`       // Начало текущего расчетного периода
+	NextStatementAt   time.Time       `db:"next_statement_at" json:"next_statement_at"`     // Дата формирования следующей выписки
+	AccruedInterest   decimal.Decimal `db:"accrued_interest" json:"accrued_interest"`       // Проценты, начисленные в текущем периоде и еще не списанные
+	InterestAccruedAt time.Time       `db:"interest_accrued_at" json:"interest_accrued_at"` // День, по который начислены проценты
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`                   // Дата открытия линии
+	UpdatedAt         time.Time       `db:"updated_at" json:"updated_at"`                   // Дата последнего изменения
+}
+
+// Validate проверяет условия кредитной линии.
+func (l *CreditLine) Validate() error {
+	if l.InterestRate.IsNegative() {
+		return fmt.Errorf("%w: interest rate must be non-negative", ErrInvalidCreditLine)
+	}
+	if l.GraceDays < 0 {
+		return fmt.Errorf("%w: grace days must be non-negative", ErrInvalidCreditLine)
+	}
+	if l.MinPaymentPercent.IsNegative() || l.MinPaymentPercent.GreaterThan(decimal.NewFromInt(100)) {
+		return fmt.Errorf("%w: minimum payment percent must be between 0 and 100", ErrInvalidCreditLine)
+	}
+	if l.MinPaymentAmount.IsNegative() {
+		return fmt.Errorf("%w: minimum payment amount must be non-negative", ErrInvalidCreditLine)
+	}
+	if l.StatementDay < 1 || l.StatementDay > MaxStatementDay {
+		return fmt.Errorf("%w: statement day must be between 1 and %d", ErrInvalidCreditLine, MaxStatementDay)
+	}
+
+	return nil
+}
+
+// AccrueInterest начисляет проценты на долг debt за дни после предыдущего начисления по день today
+// и возвращает начисленную сумму. Проценты копятся до решения о льготном периоде по выписке.
+func (l *CreditLine) AccrueInterest(debt decimal.Decimal, today time.Time) decimal.Decimal {
+	days := int64(today.Sub(l.InterestAccruedAt).Hours() / 24)
+	if days <= 0 {
+		return decimal.Zero
+	}
+
+	interest := debt.Mul(l.InterestRate).Div(decimal.NewFromInt(100)).
+		Mul(decimal.NewFromInt(days)).Div(decimal.NewFromInt(365)).Round(4)
+
+	l.AccruedInterest = l.AccruedInterest.Add(interest)
+	l.InterestAccruedAt = today
+
+	return interest
+}
+
+// MinimumPayment возвращает минимальный платеж по долгу debt: процент от долга, но не меньше
+// MinPaymentAmount и не больше самого долга.
+func (l *CreditLine) MinimumPayment(debt decimal.Decimal) decimal.Decimal {
+	payment := debt.Mul(l.MinPaymentPercent).Div(decimal.NewFromInt(100)).Round(2)
+
+	return decimal.Min(decimal.Max(payment, l.MinPaymentAmount), debt)
+}
+
+// NextStatementDate возвращает ближайшую после from дату формирования выписки.
+func (l *CreditLine) NextStatementDate(from time.Time) time.Time {
+	year, month, day := from.Date()
+	if day >= int(l.StatementDay) {
+		month++
+	}
+
+	return time.Date(year, month, int(l.StatementDay), 0, 0, 0, 0, time.UTC)
+}
+
+// StatementStatus описывает, как погашен долг по выписке к концу льготного периода.
+type StatementStatus string
+
+const (
+	StatementOpen      StatementStatus = "open"       // Льготный период по выписке еще не закончился
+	StatementPaid      StatementStatus = "paid"       // Долг погашен полностью в льготный период, проценты не списаны
+	StatementGraceLost StatementStatus = "grace_lost" // Внесен минимальный платеж, но не весь долг: проценты списаны
+	StatementOverdue   StatementStatus = "overdue"    // Минимальный платеж не внесен: проценты списаны
+)
+
+// Statement выписка по кредитной линии за расчетный период.
+type Statement struct {
+	ID              int32           `db:"id" json:"id"`                             // Идентификатор выписки
+	AccountID       int32           `db:"account_id" json:"account_id"`             // Внешний ключ на кредитный счет
+	PeriodStart     time.Time       `db:"period_start" json:"period_start"`         // Начало расчетного периода
+	PeriodEnd       time.Time       `db:"period_end" json:"period_end"`             // Дата формирования выписки
+	OpeningDebt     decimal.Decimal `db:"opening_debt" json:"opening_debt"`         // Долг на начало периода
+	Purchases       decimal.Decimal `db:"purchases" json:"purchases"`               // Списания по картам счета за период
+	Payments        decimal.Decimal `db:"payments" json:"payments"`                 // Зачисления на счет за период
+	ClosingDebt     decimal.Decimal `db:"closing_debt" json:"closing_debt"`         // Долг на дату выписки
+	MinimumPayment  decimal.Decimal `db:"minimum_payment" json:"minimum_payment"`   // Минимальный платеж
+	DueDate         time.Time       `db:"due_date" json:"due_date"`                 // Последний день льготного периода
+	AccruedInterest decimal.Decimal `db:"accrued_interest" json:"accrued_interest"` // Проценты, начисленные за период
+	InterestCharged decimal.Decimal `db:"interest_charged" json:"interest_charged"` // Проценты, списанные после потери льготного периода
+	RepaidAmount    decimal.Decimal `db:"repaid_amount" json:"repaid_amount"`       // Сумма, внесенная в льготный период
+	Status          StatementStatus `db:"status" json:"status"`                     // Статус выписки
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`             // Дата создания выписки
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`             // Дата последнего изменения
+}
+
+// Settle подводит итог льготного периода по выписке с учетом суммы repaid, внесенной после ее формирования,
+// и возвращает проценты, которые нужно списать со счета. Если долг по выписке погашен полностью,
+// начисленные за период проценты не взимаются.
+func (s *Statement) Settle(repaid decimal.Decimal) decimal.Decimal {
+	s.RepaidAmount = repaid
+
+	switch {
+	case repaid.GreaterThanOrEqual(s.ClosingDebt):
+		s.Status = StatementPaid
+		return decimal.Zero
+	case repaid.GreaterThanOrEqual(s.MinimumPayment):
+		s.Status = StatementGraceLost
+	default:
+		s.Status = StatementOverdue
+	}
+
+	s.InterestCharged = s.AccruedInterest.Round(2)
+	return s.InterestCharged
+}
+
+// CreditLineDetails описывает состояние кредитной линии вместе с ее счетом.
+type CreditLineDetails struct {
+	CreditLine
+	AccountNumber AccountNumber   `json:"account_number"` // Номер кредитного счета
+	CreditLimit   decimal.Decimal `json:"credit_limit"`   // Кредитный лимит
+	Debt          decimal.Decimal `json:"debt"`           // Использованная часть лимита
+	Available     decimal.Decimal `json:"available"`      // Доступная для трат сумма
+}
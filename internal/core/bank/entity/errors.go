@@ -5,7 +5,52 @@ import "fmt"
 var (
 	ErrInsufficientFunds     = fmt.Errorf("insufficient funds")
 	ErrDepositNegativeAmount = fmt.Errorf("deposit amount must be positive")
+	ErrAccountAccessDenied   = fmt.Errorf("account does not belong to user")
 
-	ErrCreditNotActive      = fmt.Errorf("credit is not active")
-	ErrCreditAmountExceeded = fmt.Errorf("credit amount exceeds limit")
+	ErrCreditNotActive        = fmt.Errorf("credit is not active")
+	ErrCreditNotFound         = fmt.Errorf("credit not found")
+	ErrPaymentNotFound        = fmt.Errorf("payment not found")
+	ErrOutstandingPayments    = fmt.Errorf("credit has outstanding due payments")
+	ErrInvalidRepaymentAmount = fmt.Errorf("repayment amount must exceed accrued interest")
+	ErrInvalidCreditFee       = fmt.Errorf("credit fees must be non-negative and less than the credit amount")
+	ErrAgreementNotFound      = fmt.Errorf("credit agreement not found")
+	ErrKeyRateNotFound        = fmt.Errorf("key rate not found")
+	ErrKeyRateStale           = fmt.Errorf("key rate is stale")
+	ErrInvalidKeyRatePeriod   = fmt.Errorf("key rate period start must not be after its end")
+
+	ErrRestructuringNotFound     = fmt.Errorf("restructuring not found")
+	ErrInvalidRestructuring      = fmt.Errorf("invalid restructuring")
+	ErrRestructuringNotRequested = fmt.Errorf("restructuring has already been decided")
+	ErrNothingToRestructure      = fmt.Errorf("credit has no pending payments to restructure")
+
+	ErrCreditLineNotFound = fmt.Errorf("credit line not found")
+	ErrInvalidCreditLine  = fmt.Errorf("invalid credit line")
+	ErrStatementNotFound  = fmt.Errorf("statement not found")
+
+	ErrCreditApplicationNotFound = fmt.Errorf("credit application not found")
+	ErrInvalidCreditApplication  = fmt.Errorf("invalid credit application")
+	ErrInvalidApplicationStatus  = fmt.Errorf("invalid credit application status transition")
+	ErrDecisionReasonRequired    = fmt.Errorf("decision reason is required")
+
+	ErrInvalidReportPeriod = fmt.Errorf("report period start must be before its end")
+
+	ErrBureauSubmissionNotFound = fmt.Errorf("credit bureau submission not found")
+	ErrBureauPeriodSubmitted    = fmt.Errorf("credit bureau period has already been submitted")
+	ErrBureauPeriodNotClosed    = fmt.Errorf("credit bureau period has not ended yet")
+	ErrInvalidBureauFile        = fmt.Errorf("credit bureau file does not match schema")
+
+	ErrInvalidCard       = fmt.Errorf("invalid card parameters")
+	ErrCardRuleViolation = fmt.Errorf("operation rejected by card rules")
+	ErrCardNotActive     = fmt.Errorf("card is not active")
+	ErrCardExpired       = fmt.Errorf("card is expired")
+	ErrCardLimitExceeded = fmt.Errorf("card spend limit exceeded")
+	ErrCardNotFound      = fmt.Errorf("card not found")
+
+	ErrCardTokenNotFound = fmt.Errorf("card token not found")
+	ErrVaultAccessDenied = fmt.Errorf("vault access denied")
+
+	ErrCardTransactionNotFound  = fmt.Errorf("card transaction not found")
+	ErrDuplicateRRN             = fmt.Errorf("rrn belongs to another card operation")
+	ErrInvalidTransactionFilter = fmt.Errorf("invalid transaction filter")
+	ErrInvalidCursor            = fmt.Errorf("invalid cursor")
 )
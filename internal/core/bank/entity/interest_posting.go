@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// InterestPostingType определяет вид проводки по начисленным процентам.
+type InterestPostingType string
+
+const (
+	InterestAccrual    InterestPostingType = "accrual"    // Начисление процентов за день
+	InterestSettlement InterestPostingType = "settlement" // Погашение начисленных процентов платежом
+	// InterestCapitalization перенос начисленных за кредитные каникулы процентов в основной долг.
+	InterestCapitalization InterestPostingType = "capitalization"
+)
+
+// InterestPosting проводка по начисленным процентам кредита. Сумма начисления положительна, погашения — отрицательна,
+// поэтому сумма проводок по дату включительно равна начисленным и не погашенным на эту дату процентам.
+type InterestPosting struct {
+	ID           int32               `db:"id" json:"id"`                           // Идентификатор проводки
+	CreditID     int32               `db:"credit_id" json:"credit_id"`             // Внешний ключ на кредит
+	PostingDate  time.Time           `db:"posting_date" json:"posting_date"`       // Дата проводки
+	Type         InterestPostingType `db:"type" json:"type"`                       // Вид проводки (начисление, погашение, капитализация)
+	Principal    decimal.Decimal     `db:"principal" json:"principal"`             // Основной долг, на который начислены проценты
+	InterestRate decimal.Decimal     `db:"interest_rate" json:"interest_rate"`     // Годовая ставка, по которой начислены проценты
+	Amount       decimal.Decimal     `db:"amount" json:"amount"`                   // Изменение начисленных процентов
+	PaymentID    *int32              `db:"payment_id" json:"payment_id,omitempty"` // Платеж, которым погашены или капитализированы проценты
+	CreatedAt    time.Time           `db:"created_at" json:"created_at"`           // Дата записи
+}
@@ -0,0 +1,216 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// DomesticCountry код страны (ISO 3166-1 alpha-2), операции в которой считаются внутренними.
+const DomesticCountry = "RU"
+
+// MCC представляет код категории торговой точки (Merchant Category Code).
+type MCC string
+
+// Validate проверяет, что MCC состоит ровно из 4 цифр.
+func (m MCC) Validate() error {
+	re := regexp.MustCompile(`^\d{4}$`)
+	if !re.MatchString(string(m)) {
+		return fmt.Errorf("invalid mcc: %s, must be 4 digits", m)
+	}
+	return nil
+}
+
+func (m MCC) String() string {
+	return string(m)
+}
+
+// Group возвращает группу, к которой относится MCC. Неизвестные коды попадают в MCCGroupOther.
+func (m MCC) Group() MCCGroup {
+	if group, ok := mccGroups[m]; ok {
+		return group
+	}
+
+	code, err := strconv.Atoi(string(m))
+	if err != nil {
+		return MCCGroupOther
+	}
+
+	// Коды авиакомпаний, прокатов автомобилей и отелей выделены диапазонами.
+	if code >= 3000 && code <= 3999 {
+		return MCCGroupTravel
+	}
+
+	return MCCGroupOther
+}
+
+// MCCGroup представляет группу MCC-кодов, для которой владелец карты может задать ограничения.
+type MCCGroup string
+
+const (
+	MCCGroupGambling      MCCGroup = "gambling"
+	MCCGroupCash          MCCGroup = "cash"
+	MCCGroupTransfers     MCCGroup = "transfers"
+	MCCGroupTravel        MCCGroup = "travel"
+	MCCGroupGrocery       MCCGroup = "grocery"
+	MCCGroupRestaurants   MCCGroup = "restaurants"
+	MCCGroupFuel          MCCGroup = "fuel"
+	MCCGroupEntertainment MCCGroup = "entertainment"
+	MCCGroupOther         MCCGroup = "other"
+)
+
+// Validate проверяет, что группа MCC известна системе.
+func (g MCCGroup) Validate() error {
+	switch g {
+	case MCCGroupGambling, MCCGroupCash, MCCGroupTransfers, MCCGroupTravel, MCCGroupGrocery,
+		MCCGroupRestaurants, MCCGroupFuel, MCCGroupEntertainment, MCCGroupOther:
+		return nil
+	default:
+		return fmt.Errorf("invalid mcc group: %s", g)
+	}
+}
+
+// mccGroups сопоставляет отдельные MCC-коды их группам.
+var mccGroups = map[MCC]MCCGroup{
+	"7995": MCCGroupGambling, // Азартные игры, ставки
+	"7800": MCCGroupGambling, // Государственные лотереи
+	"7801": MCCGroupGambling, // Онлайн-казино
+	"7802": MCCGroupGambling, // Скачки и собачьи бега
+	"9754": MCCGroupGambling, // Азартные игры (государственные)
+
+	"6010": MCCGroupCash, // Выдача наличных в отделении
+	"6011": MCCGroupCash, // Выдача наличных в банкомате
+	"6051": MCCGroupCash, // Квази-кэш
+
+	"4829": MCCGroupTransfers, // Денежные переводы
+	"6536": MCCGroupTransfers, // MoneySend внутри страны
+	"6537": MCCGroupTransfers, // MoneySend между странами
+	"6538": MCCGroupTransfers, // MoneySend с финансированием
+
+	"4111": MCCGroupTravel, // Пригородный транспорт
+	"4112": MCCGroupTravel, // Железнодорожные перевозки
+	"4131": MCCGroupTravel, // Автобусные линии
+	"4411": MCCGroupTravel, // Круизы
+	"4511": MCCGroupTravel, // Авиакомпании
+	"4722": MCCGroupTravel, // Туристические агентства
+	"7011": MCCGroupTravel, // Отели
+
+	"5411": MCCGroupGrocery, // Супермаркеты
+	"5422": MCCGroupGrocery, // Мясные лавки
+	"5441": MCCGroupGrocery, // Кондитерские
+	"5451": MCCGroupGrocery, // Молочные продукты
+	"5462": MCCGroupGrocery, // Пекарни
+	"5499": MCCGroupGrocery, // Продуктовые магазины
+
+	"5812": MCCGroupRestaurants, // Рестораны
+	"5813": MCCGroupRestaurants, // Бары
+	"5814": MCCGroupRestaurants, // Фастфуд
+
+	"5541": MCCGroupFuel, // Заправочные станции
+	"5542": MCCGroupFuel, // Автоматические заправки
+	"5983": MCCGroupFuel, // Топливо
+
+	"7832": MCCGroupEntertainment, // Кинотеатры
+	"7841": MCCGroupEntertainment, // Видеопрокат
+	"7922": MCCGroupEntertainment, // Театры
+	"7929": MCCGroupEntertainment, // Концерты
+	"7991": MCCGroupEntertainment, // Достопримечательности
+	"7996": MCCGroupEntertainment, // Парки развлечений
+	"7999": MCCGroupEntertainment, // Прочие развлечения
+}
+
+// Merchant описывает торговую точку, в которой совершается операция по карте.
+type Merchant struct {
+	Name    string `json:"name"`    // Название торговой точки
+	MCC     MCC    `json:"mcc"`     // Код категории торговой точки
+	Country string `json:"country"` // Страна торговой точки (ISO 3166-1 alpha-2)
+	Online  bool   `json:"online"`  // Операция без присутствия карты (интернет-платеж)
+}
+
+// TransferMCC код категории, под которым учитываются переводы с карты на карту внутри банка.
+const TransferMCC MCC = "6536"
+
+// TransferMerchant возвращает описание перевода с карты на карту для проверки правил карты отправителя:
+// перевод внутри банка считается внутренней операцией без присутствия карты в группе MCCGroupTransfers.
+func TransferMerchant() *Merchant {
+	return &Merchant{MCC: TransferMCC, Country: DomesticCountry, Online: true}
+}
+
+// IsDomestic сообщает, находится ли торговая точка в стране обслуживания банка.
+func (m *Merchant) IsDomestic() bool {
+	return m.Country == DomesticCountry
+}
+
+// CardRules описывает ограничения, которые владелец карты установил на операции по ней.
+type CardRules struct {
+	CardID           int32                        `json:"-"`                  // Внешний ключ на карту
+	BlockedMCCGroups []MCCGroup                   `json:"blocked_mcc_groups"` // Запрещенные группы MCC
+	OnlineOnly       bool                         `json:"online_only"`        // Разрешены только интернет-платежи
+	DomesticOnly     bool                         `json:"domestic_only"`      // Разрешены только операции внутри страны
+	MonthlyLimits    map[MCCGroup]decimal.Decimal `json:"monthly_limits"`     // Месячные лимиты по группам MCC
+}
+
+// IsEmpty сообщает, что для карты не задано ни одного ограничения.
+func (r *CardRules) IsEmpty() bool {
+	return len(r.BlockedMCCGroups) == 0 && !r.OnlineOnly && !r.DomesticOnly && len(r.MonthlyLimits) == 0
+}
+
+// IsBlocked сообщает, запрещены ли операции в указанной группе MCC.
+func (r *CardRules) IsBlocked(group MCCGroup) bool {
+	for _, blocked := range r.BlockedMCCGroups {
+		if blocked == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate проверяет корректность групп MCC и лимитов.
+func (r *CardRules) Validate() error {
+	for _, group := range r.BlockedMCCGroups {
+		if err := group.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for group, limit := range r.MonthlyLimits {
+		if err := group.Validate(); err != nil {
+			return err
+		}
+		if limit.LessThan(decimal.Zero) {
+			return fmt.Errorf("monthly limit for %s must not be negative", group)
+		}
+	}
+
+	return nil
+}
+
+// CardRuleReason код причины отказа в операции по правилам карты.
+type CardRuleReason string
+
+const (
+	CardRuleMerchantRequired      CardRuleReason = "merchant_required"
+	CardRuleMCCBlocked            CardRuleReason = "mcc_blocked"
+	CardRuleOnlineOnly            CardRuleReason = "online_only"
+	CardRuleDomesticOnly          CardRuleReason = "domestic_only"
+	CardRuleCategoryLimitExceeded CardRuleReason = "category_limit_exceeded"
+)
+
+// CardRuleViolationError возвращается, когда операция нарушает правила, установленные для карты.
+type CardRuleViolationError struct {
+	Reason CardRuleReason
+	Group  MCCGroup
+}
+
+func (e *CardRuleViolationError) Error() string {
+	if e.Group != "" {
+		return fmt.Sprintf("%s: %s (%s)", ErrCardRuleViolation, e.Reason, e.Group)
+	}
+	return fmt.Sprintf("%s: %s", ErrCardRuleViolation, e.Reason)
+}
+
+func (e *CardRuleViolationError) Unwrap() error {
+	return ErrCardRuleViolation
+}
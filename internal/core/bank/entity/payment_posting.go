@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// PaymentPosting поступление средств в счет платежа по графику. Сумма поступлений по дату включительно равна
+// сумме, внесенной по платежу на эту дату, поэтому по проводкам восстанавливается задолженность на прошлую дату.
+type PaymentPosting struct {
+	ID            int32           `db:"id" json:"id"`                         // Идентификатор проводки
+	CreditID      int32           `db:"credit_id" json:"credit_id"`           // Внешний ключ на кредит
+	PaymentID     int32           `db:"payment_id" json:"payment_id"`         // Платеж, в счет которого поступили средства
+	PostingDate   time.Time       `db:"posting_date" json:"posting_date"`     // Дата поступления
+	Amount        decimal.Decimal `db:"amount" json:"amount"`                 // Поступившая сумма вместе со штрафом
+	PenaltyAmount decimal.Decimal `db:"penalty_amount" json:"penalty_amount"` // Часть суммы, погасившая штраф
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`         // Дата записи
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// PrincipalPostingType определяет вид проводки по основному долгу.
+type PrincipalPostingType string
+
+const (
+	PrincipalDisbursement   PrincipalPostingType = "disbursement"   // Выдача кредита
+	PrincipalRepayment      PrincipalPostingType = "repayment"      // Погашение основного долга платежом
+	PrincipalCapitalization PrincipalPostingType = "capitalization" // Перенос процентов за кредитные каникулы в основной долг
+)
+
+// PrincipalPosting проводка по основному долгу кредита. Выдача и капитализация положительны, погашение — отрицательно,
+// поэтому сумма проводок по дату включительно равна остатку основного долга на эту дату независимо от того,
+// как после нее перестраивался график платежей.
+type PrincipalPosting struct {
+	ID          int32                `db:"id" json:"id"`                           // Идентификатор проводки
+	CreditID    int32                `db:"credit_id" json:"credit_id"`             // Внешний ключ на кредит
+	PostingDate time.Time            `db:"posting_date" json:"posting_date"`       // Дата проводки
+	Type        PrincipalPostingType `db:"type" json:"type"`                       // Вид проводки (выдача, погашение, капитализация)
+	Amount      decimal.Decimal      `db:"amount" json:"amount"`                   // Изменение основного долга
+	PaymentID   *int32               `db:"payment_id" json:"payment_id,omitempty"` // Платеж, которым погашен или капитализирован долг
+	CreatedAt   time.Time            `db:"created_at" json:"created_at"`           // Дата записи
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// RateReset пересмотр плавающей ставки по кредиту.
+type RateReset struct {
+	ID              int32           `db:"id" json:"id"`                                       // Идентификатор пересмотра
+	CreditID        int32           `db:"credit_id" json:"credit_id"`                         // Внешний ключ на кредит
+	ResetDate       time.Time       `db:"reset_date" json:"reset_date"`                       // Дата пересмотра
+	KeyRate         decimal.Decimal `db:"key_rate" json:"key_rate"`                           // Ключевая ставка ЦБ, действовавшая на дату пересмотра
+	KeyRateDate     time.Time       `db:"key_rate_date" json:"key_rate_date"`                 // Дата, с которой действует ключевая ставка
+	PreviousRate    decimal.Decimal `db:"previous_rate" json:"previous_rate"`                 // Ставка по кредиту до пересмотра
+	NewRate         decimal.Decimal `db:"new_rate" json:"new_rate"`                           // Ставка по кредиту после пересмотра
+	ScheduleVersion *int32          `db:"schedule_version" json:"schedule_version,omitempty"` // Версия графика, сохраненная перед перестроением
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`                       // Дата записи
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// CreditExposure задолженность по кредиту на отчетную дату.
+type CreditExposure struct {
+	CreditID             int32           `db:"credit_id" json:"credit_id"`                         // Идентификатор кредита
+	UserID               int32           `db:"user_id" json:"user_id"`                             // Заемщик
+	Amount               decimal.Decimal `db:"amount" json:"amount"`                               // Сумма кредита
+	OutstandingPrincipal decimal.Decimal `db:"outstanding_principal" json:"outstanding_principal"` // Непогашенный основной долг
+	OverdueAmount        decimal.Decimal `db:"overdue_amount" json:"overdue_amount"`               // Сумма просроченных платежей
+	AccruedInterest      decimal.Decimal `db:"accrued_interest" json:"accrued_interest"`           // Начисленные и не погашенные проценты
+	DaysPastDue          int32           `db:"days_past_due" json:"days_past_due"`                 // Дней просрочки самого раннего непогашенного платежа
+}
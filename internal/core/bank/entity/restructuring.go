@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestructuringType определяет, как меняется график на время кредитных каникул.
+type RestructuringType string
+
+const (
+	PaymentHoliday RestructuringType = "payment_holiday" // Платежи не вносятся, начисленные проценты прибавляются к долгу
+	InterestOnly   RestructuringType = "interest_only"   // Вносятся только проценты, основной долг не погашается
+)
+
+// Validate проверяет вид реструктуризации.
+func (t RestructuringType) Validate() error {
+	switch t {
+	case PaymentHoliday, InterestOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown restructuring type: %s", t)
+	}
+}
+
+// RestructuringStatus описывает этап рассмотрения заявки на реструктуризацию.
+type RestructuringStatus string
+
+const (
+	RestructuringRequested RestructuringStatus = "requested" // Заявка подана и ожидает решения сотрудника
+	RestructuringApproved  RestructuringStatus = "approved"  // Заявка одобрена, график перестроен
+	RestructuringRejected  RestructuringStatus = "rejected"  // Заявка отклонена
+)
+
+// Validate проверяет статус заявки на реструктуризацию.
+func (s RestructuringStatus) Validate() error {
+	switch s {
+	case RestructuringRequested, RestructuringApproved, RestructuringRejected:
+		return nil
+	default:
+		return fmt.Errorf("unknown restructuring status: %s", s)
+	}
+}
+
+const (
+	MinHolidayMonths = 1 // Минимальная продолжительность кредитных каникул в месяцах
+	MaxHolidayMonths = 6 // Максимальная продолжительность кредитных каникул в месяцах
+)
+
+// Restructuring описывает заявку заемщика на кредитные каникулы.
+type Restructuring struct {
+	ID              int32               `db:"id" json:"id"`                                       // Идентификатор заявки
+	CreditID        int32               `db:"credit_id" json:"credit_id"`                         // Внешний ключ на кредит
+	UserID          int32               `db:"user_id" json:"user_id"`                             // Заемщик, подавший заявку
+	Type            RestructuringType   `db:"type" json:"type"`                                   // Вид реструктуризации
+	Months          int32               `db:"months" json:"months"`                               // Продолжительность каникул в месяцах
+	Reason          string              `db:"reason" json:"reason"`                               // Причина, указанная заемщиком
+	Status          RestructuringStatus `db:"status" json:"status"`                               // Статус заявки
+	DecidedBy       *int32              `db:"decided_by" json:"decided_by,omitempty"`             // Сотрудник, принявший решение
+	DecisionNote    string              `db:"decision_note" json:"decision_note,omitempty"`       // Комментарий сотрудника
+	ScheduleVersion *int32              `db:"schedule_version" json:"schedule_version,omitempty"` // Версия графика, сохраненная перед перестроением
+	CreatedAt       time.Time           `db:"created_at" json:"created_at"`                       // Дата подачи заявки
+	UpdatedAt       time.Time           `db:"updated_at" json:"updated_at"`                       // Дата последнего изменения
+}
+
+// Validate проверяет параметры заявки на реструктуризацию.
+func (r *Restructuring) Validate() error {
+	if err := r.Type.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRestructuring, err)
+	}
+
+	if r.Months < MinHolidayMonths || r.Months > MaxHolidayMonths {
+		return fmt.Errorf("%w: months must be between %d and %d", ErrInvalidRestructuring, MinHolidayMonths, MaxHolidayMonths)
+	}
+
+	return nil
+}
+
+// ScheduleVersion хранит снимок графика платежей, каким он был до перестроения.
+type ScheduleVersion struct {
+	ID              int32             `json:"id"`                         // Идентификатор версии
+	CreditID        int32             `json:"credit_id"`                  // Внешний ключ на кредит
+	Version         int32             `json:"version"`                    // Порядковый номер версии графика
+	RestructuringID *int32            `json:"restructuring_id,omitempty"` // Реструктуризация, перед которой сохранен график
+	Schedule        []PaymentSchedule `json:"schedule"`                   // График платежей
+	CreatedAt       time.Time         `json:"created_at"`                 // Дата сохранения версии
+}
@@ -1,8 +1,20 @@
 package entity
 
 import (
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
 	"github.com/shopspring/decimal"
+	"strings"
 	"time"
+	"unicode/utf8"
+)
+
+// UserRole определяет права пользователя.
+type UserRole string
+
+const (
+	ClientRole UserRole = "client" // Клиент банка
+	AdminRole  UserRole = "admin"  // Сотрудник банка, принимающий решения по заявкам
 )
 
 type User struct {
@@ -12,10 +24,20 @@ type User struct {
 	FirstName   string    `db:"first_name"`
 	LastName    string    `db:"last_name"`
 	DateOfBirth time.Time `db:"date_of_birth"`
+	Role        UserRole  `db:"role"`
 	CreatedAt   string    `db:"created_at"`
 	UpdatedAt   string    `db:"updated_at"`
 }
 
+// Age возвращает полное число лет пользователя на дату at.
+func (u *User) Age(at time.Time) int {
+	age := at.Year() - u.DateOfBirth.Year()
+	if at.Month() < u.DateOfBirth.Month() || at.Month() == u.DateOfBirth.Month() && at.Day() < u.DateOfBirth.Day() {
+		age--
+	}
+	return age
+}
+
 type AccountType string
 
 const (
@@ -31,6 +53,7 @@ type Account struct {
 	Balance       decimal.Decimal `db:"balance"`
 	Currency      Currency        `db:"currency"`
 	AccountType   AccountType     `db:"account_type"`
+	CreditLimit   decimal.Decimal `db:"credit_limit"` // Кредитный лимит, на который баланс кредитного счета может уходить в минус
 	CreatedAt     string          `db:"created_at"`
 	UpdatedAt     string          `db:"updated_at"`
 }
@@ -44,7 +67,7 @@ func (a *Account) Transfer(target *Account, amount decimal.Decimal) error {
 }
 
 func (a *Account) Withdraw(amount decimal.Decimal) error {
-	if a.Balance.LessThan(amount) {
+	if a.Available().LessThan(amount) {
 		return ErrInsufficientFunds
 	}
 
@@ -53,6 +76,21 @@ func (a *Account) Withdraw(amount decimal.Decimal) error {
 	return nil
 }
 
+// Available возвращает сумму, которую можно списать со счета: остаток вместе с неиспользованным кредитным лимитом.
+func (a *Account) Available() decimal.Decimal {
+	return a.Balance.Add(a.CreditLimit)
+}
+
+// Debt возвращает использованную часть кредитного лимита.
+func (a *Account) Debt() decimal.Decimal {
+	return decimal.Max(a.Balance.Neg(), decimal.Zero)
+}
+
+// Charge списывает со счета начисленную банком сумму, например проценты, независимо от доступного остатка.
+func (a *Account) Charge(amount decimal.Decimal) {
+	a.Balance = a.Balance.Sub(amount)
+}
+
 func (a *Account) Deposit(amount decimal.Decimal) error {
 	if amount.LessThan(decimal.Zero) {
 		return ErrDepositNegativeAmount
@@ -70,19 +108,177 @@ const (
 	CardInactive CardStatus = "inactive"
 	CardBlocked  CardStatus = "blocked"
 	CardExpired  CardStatus = "expired"
+	CardClosed   CardStatus = "closed"
+)
+
+// CardKind определяет вид карты и ограничения на ее использование.
+type CardKind string
+
+const (
+	PhysicalCard   CardKind = "physical"    // Пластиковая карта
+	VirtualCard    CardKind = "virtual"     // Виртуальная карта без ограничений на число операций
+	SingleUseCard  CardKind = "single_use"  // Виртуальная карта, закрывающаяся после первого списания
+	LimitedUseCard CardKind = "limited_use" // Виртуальная карта с лимитом суммы и сроком действия
 )
 
+// Validate проверяет, является ли вид карты допустимым.
+func (k CardKind) Validate() error {
+	switch k {
+	case PhysicalCard, VirtualCard, SingleUseCard, LimitedUseCard:
+		return nil
+	default:
+		return fmt.Errorf("invalid card kind: %s", k)
+	}
+}
+
+// IsVirtual сообщает, является ли карта виртуальной.
+func (k CardKind) IsVirtual() bool {
+	return k == VirtualCard || k == SingleUseCard || k == LimitedUseCard
+}
+
 type Card struct {
-	ID             int32 `db:"id"`
-	AccountID      int32 `db:"account_id"`
-	CardNumber     string
-	ExpirationDate time.Time
-	CVV            string
-	Status         CardStatus
-	EncryptedData  string `db:"encrypted_data"`
-	HMAC           string `db:"hash"`
-	CreatedAt      string `db:"created_at"`
-	UpdatedAt      string `db:"updated_at"`
+	ID             int32               `db:"id"`
+	AccountID      int32               `db:"account_id"`
+	CardNumber     string              `db:"-"`
+	ExpirationDate time.Time           `db:"expiration_date"`
+	CVV            string              `db:"-"`
+	Kind           CardKind            `db:"kind"`
+	Status         CardStatus          `db:"status"`
+	SpendLimit     decimal.NullDecimal `db:"spend_limit"`  // Лимит суммы списаний (для карт с ограниченным использованием)
+	SpentAmount    decimal.Decimal     `db:"spent_amount"` // Сумма списаний по карте
+	EncryptedData  string              `db:"encrypted_data"`
+	HMAC           string              `db:"hmac"`
+	PANIndex       string              `db:"pan_index"`        // Слепой индекс номера карты для поиска без расшифровки
+	ReissuedFromID *int32              `db:"reissued_from_id"` // Карта, взамен которой выпущена данная карта
+	CreatedAt      string              `db:"created_at"`
+	UpdatedAt      string              `db:"updated_at"`
+}
+
+// Validate проверяет согласованность вида карты и ее ограничений, заданных при выпуске в момент now.
+// Срок действия, если он задан, не должен истечь к моменту выпуска. Ошибки проверки оборачивают ErrInvalidCard.
+func (c *Card) Validate(now time.Time) error {
+	if err := c.Kind.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCard, err)
+	}
+
+	if c.SpendLimit.Valid && !c.SpendLimit.Decimal.IsPositive() {
+		return fmt.Errorf("%w: spend limit must be positive", ErrInvalidCard)
+	}
+
+	if c.Kind == LimitedUseCard && (!c.SpendLimit.Valid || c.ExpirationDate.IsZero()) {
+		return fmt.Errorf("%w: limited-use card requires spend limit and expiration date", ErrInvalidCard)
+	}
+
+	if c.IsExpired(now) {
+		return fmt.Errorf("%w: expiration date must not be in the past", ErrInvalidCard)
+	}
+
+	return nil
+}
+
+// IsExpired сообщает, истек ли срок действия карты к моменту now. Карта действует до конца дня ExpirationDate.
+func (c *Card) IsExpired(now time.Time) bool {
+	if c.ExpirationDate.IsZero() {
+		return false
+	}
+
+	y, m, d := c.ExpirationDate.Date()
+	validUntil := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	return !now.Before(validUntil)
+}
+
+// CanCredit проверяет, можно ли зачислить средства на карту в момент now.
+func (c *Card) CanCredit(now time.Time) error {
+	if c.Status == CardExpired || c.IsExpired(now) {
+		return ErrCardExpired
+	}
+
+	if c.Status != CardActive {
+		return ErrCardNotActive
+	}
+
+	return nil
+}
+
+// CanDebit проверяет, можно ли списать сумму amount с карты в момент now.
+func (c *Card) CanDebit(amount decimal.Decimal, now time.Time) error {
+	if err := c.CanCredit(now); err != nil {
+		return err
+	}
+
+	if c.SpendLimit.Valid && c.SpentAmount.Add(amount).GreaterThan(c.SpendLimit.Decimal) {
+		return ErrCardLimitExceeded
+	}
+
+	return nil
+}
+
+// RegisterDebit учитывает успешное списание с карты. Одноразовая карта после первого списания закрывается.
+func (c *Card) RegisterDebit(amount decimal.Decimal) {
+	c.SpentAmount = c.SpentAmount.Add(amount)
+
+	if c.Kind == SingleUseCard {
+		c.Status = CardClosed
+	}
+}
+
+// RevertDebit отменяет учет списания при его сторнировании. Закрытая после списания одноразовая карта снова становится активной.
+func (c *Card) RevertDebit(amount decimal.Decimal) {
+	c.SpentAmount = decimal.Max(c.SpentAmount.Sub(amount), decimal.Zero)
+
+	if c.Kind == SingleUseCard && c.Status == CardClosed {
+		c.Status = CardActive
+	}
+}
+
+// CardView описывает карту для внешних потребителей: вместо идентификатора и номера карты используется токен.
+type CardView struct {
+	Token          CardToken           `json:"token"`           // Токен карты
+	MaskedPAN      string              `json:"masked_pan"`      // Маскированный номер карты
+	Kind           CardKind            `json:"kind"`            // Вид карты
+	Status         CardStatus          `json:"status"`          // Статус карты
+	ExpirationDate time.Time           `json:"expiration_date"` // Срок действия
+	SpendLimit     decimal.NullDecimal `json:"spend_limit"`     // Лимит суммы списаний
+	SpentAmount    decimal.Decimal     `json:"spent_amount"`    // Сумма списаний по карте
+}
+
+// NewCardView возвращает представление карты с токеном token.
+func NewCardView(card *Card, token CardToken) CardView {
+	return CardView{
+		Token:          token,
+		MaskedPAN:      token.Masked(),
+		Kind:           card.Kind,
+		Status:         card.Status,
+		ExpirationDate: card.ExpirationDate,
+		SpendLimit:     card.SpendLimit,
+		SpentAmount:    card.SpentAmount,
+	}
+}
+
+// CardRecipient описывает получателя перевода по номеру карты для подтверждения отправителем.
+type CardRecipient struct {
+	CardID     int32      `json:"-"`           // Идентификатор карты получателя
+	Status     CardStatus `json:"-"`           // Статус карты получателя
+	MaskedPAN  string     `json:"masked_pan"`  // Маскированный номер карты
+	MaskedName string     `json:"masked_name"` // Маскированное имя держателя, например "Иван П."
+}
+
+// MaskHolderName возвращает имя держателя карты в виде "Имя Ф." для подтверждения получателя перевода.
+func MaskHolderName(firstName, lastName string) string {
+	firstName = strings.TrimSpace(firstName)
+	lastName = strings.TrimSpace(lastName)
+
+	if lastName == "" {
+		return firstName
+	}
+
+	initial, _ := utf8.DecodeRuneInString(lastName)
+	if firstName == "" {
+		return string(initial) + "."
+	}
+
+	return firstName + " " + string(initial) + "."
 }
 
 type TransactionType string
@@ -92,6 +288,8 @@ const (
 	WithdrawalTransaction TransactionType = "withdrawal"
 	DepositTransaction    TransactionType = "deposit"
 	TransferTransaction   TransactionType = "transfer"
+
+	CreditDisbursementTransaction TransactionType = "credit_disbursement" // Зачисление суммы кредита на счет заемщика
 )
 
 type TransactionStatus string
@@ -102,13 +300,127 @@ const (
 	TransactionPending TransactionStatus = "pending"
 )
 
+// TransactionDirection показывает, списаны средства с карты или зачислены на нее.
+type TransactionDirection string
+
+const (
+	DebitDirection  TransactionDirection = "debit"
+	CreditDirection TransactionDirection = "credit"
+)
+
+// Validate проверяет тип операции.
+func (t TransactionType) Validate() error {
+	switch t {
+	case PaymentTransaction, WithdrawalTransaction, DepositTransaction, TransferTransaction:
+		return nil
+	default:
+		return fmt.Errorf("unknown transaction type: %s", t)
+	}
+}
+
+// Validate проверяет статус операции.
+func (s TransactionStatus) Validate() error {
+	switch s {
+	case TransactionSuccess, TransactionFailed, TransactionPending:
+		return nil
+	default:
+		return fmt.Errorf("unknown transaction status: %s", s)
+	}
+}
+
 type CardTransaction struct {
-	ID              int32             `db:"id"`
-	CardID          int32             `db:"card_id"`
-	Amount          decimal.Decimal   `db:"amount"`
-	TransactionType TransactionType   `db:"transaction_type"`
-	TransactionDate string            `db:"transaction_date"`
-	Status          TransactionStatus `db:"status"`
+	ID                 int32                `db:"id" json:"id"`                               // Идентификатор операции
+	CardID             int32                `db:"card_id" json:"-"`                           // Внешний ключ на карту
+	Amount             decimal.Decimal      `db:"amount" json:"amount"`                       // Сумма операции
+	TransactionType    TransactionType      `db:"transaction_type" json:"transaction_type"`   // Тип операции
+	Direction          TransactionDirection `db:"direction" json:"direction"`                 // Списание или зачисление
+	TransactionDate    time.Time            `db:"transaction_date" json:"transaction_date"`   // Дата операции
+	Status             TransactionStatus    `db:"status" json:"status"`                       // Статус операции
+	CounterpartyCardID *int32               `db:"counterparty_card_id" json:"-"`              // Карта второй стороны перевода
+	CounterpartyToken  CardToken            `db:"-" json:"counterparty_card_token,omitempty"` // Токен карты второй стороны перевода
+	Counterparty       string               `db:"counterparty" json:"counterparty,omitempty"` // Контрагент: торговая точка или маскированный номер карты
+	Merchant           *Merchant            `db:"-" json:"merchant,omitempty"`                // Торговая точка для оплаты и снятия
+	RRN                string               `db:"rrn" json:"-"`                               // Номер операции в платежной сети (поле 37 ISO 8583)
+	STAN               string               `db:"stan" json:"-"`                              // Номер трассировки операции (поле 11 ISO 8583)
+	ReversedAt         *time.Time           `db:"reversed_at" json:"-"`                       // Дата сторнирования операции платежной сетью
+}
+
+// CardTransactionSort задает поле сортировки истории операций.
+type CardTransactionSort string
+
+const (
+	SortByDate   CardTransactionSort = "date"
+	SortByAmount CardTransactionSort = "amount"
+)
+
+// SortOrder задает направление сортировки.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// CardTransactionFilter описывает выборку истории операций по карте.
+// Пустые From и To не ограничивают период, пустые Types и Statuses не ограничивают тип и статус.
+type CardTransactionFilter struct {
+	CardID   int32
+	From     time.Time
+	To       time.Time
+	Types    []TransactionType
+	Statuses []TransactionStatus
+	Sort     CardTransactionSort
+	Order    SortOrder
+}
+
+// Validate проверяет фильтр истории операций.
+func (f *CardTransactionFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return fmt.Errorf("invalid period: end is before start")
+	}
+
+	for _, t := range f.Types {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range f.Statuses {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if f.Sort != SortByDate && f.Sort != SortByAmount {
+		return fmt.Errorf("unknown sort field: %s", f.Sort)
+	}
+
+	if f.Order != SortAsc && f.Order != SortDesc {
+		return fmt.Errorf("unknown sort order: %s", f.Order)
+	}
+
+	return nil
+}
+
+// CardTransactionCursor указывает на последнюю операцию страницы: значение поля сортировки и идентификатор.
+type CardTransactionCursor struct {
+	Date   time.Time       `json:"d,omitempty"`
+	Amount decimal.Decimal `json:"a"`
+	ID     int32           `json:"id"`
+}
+
+// CardTransactionTotals содержит итоги по успешным операциям, попавшим под фильтр.
+type CardTransactionTotals struct {
+	Count  int64           `db:"count" json:"count"`   // Количество операций
+	Debit  decimal.Decimal `db:"debit" json:"debit"`   // Сумма списаний
+	Credit decimal.Decimal `db:"credit" json:"credit"` // Сумма зачислений
+}
+
+// CardTransactionPage содержит страницу истории операций, курсор следующей страницы и итоги по фильтру.
+type CardTransactionPage struct {
+	Transactions []CardTransaction     `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+	Totals       CardTransactionTotals `json:"totals"`
 }
 
 type Transfer struct {
@@ -127,31 +439,102 @@ const (
 	CreditStatusPaid   CreditStatus = "paid"
 )
 
+// RateType вид процентной ставки по кредиту.
+type RateType string
+
+const (
+	FixedRate    RateType = "fixed"    // Ставка не меняется весь срок кредита
+	FloatingRate RateType = "floating" // Ставка периодически пересматривается по ключевой ставке ЦБ с маржой
+)
+
+// Validate проверяет вид ставки.
+func (t RateType) Validate() error {
+	switch t {
+	case FixedRate, FloatingRate:
+		return nil
+	default:
+		return fmt.Errorf("unknown rate type: %s", t)
+	}
+}
+
 type Credit struct {
-	ID           int32           `db:"id" json:"id,omitempty"`                         // Идентификатор кредита
-	UserID       int32           `db:"user_id" json:"user_id,omitempty"`               // Внешний ключ на пользователя
-	Amount       decimal.Decimal `db:"amount" json:"amount"`                           // Сумма кредита
-	InterestRate decimal.Decimal `db:"interest_rate" json:"interest_rate"`             // Процентная ставка
-	TermInMonths int32           `db:"term_in_months" json:"term_in_months,omitempty"` // Срок кредита (в месяцах)
-	Status       CreditStatus    `db:"status" json:"status,omitempty"`                 // Статус кредита (оформлен, погашен)
-	CreatedAt    string          `db:"created_at" json:"created_at,omitempty"`         // Дата оформления кредита
-	UpdatedAt    string          `db:"updated_at" json:"updated_at,omitempty"`         // Дата последнего обновления
+	ID                 int32                 `db:"id" json:"id,omitempty"`                                   // Идентификатор кредита
+	UserID             int32                 `db:"user_id" json:"user_id,omitempty"`                         // Внешний ключ на пользователя
+	AccountID          int32                 `db:"account_id" json:"account_id,omitempty"`                   // Счет, на который зачислен кредит и с которого списываются платежи
+	Amount             decimal.Decimal       `db:"amount" json:"amount"`                                     // Сумма кредита
+	InterestRate       decimal.Decimal       `db:"interest_rate" json:"interest_rate"`                       // Годовая процентная ставка в процентах
+	TermInMonths       int32                 `db:"term_in_months" json:"term_in_months,omitempty"`           // Срок кредита (в месяцах)
+	ScheduleType       amortization.Method   `db:"schedule_type" json:"schedule_type,omitempty"`             // Способ погашения (аннуитетный, дифференцированный)
+	DayCount           amortization.DayCount `db:"day_count" json:"day_count,omitempty"`                     // Способ подсчета дней при начислении процентов
+	RateType           RateType              `db:"rate_type" json:"rate_type,omitempty"`                     // Вид ставки (фиксированная, плавающая)
+	RateMargin         decimal.Decimal       `db:"rate_margin" json:"rate_margin"`                           // Маржа к ключевой ставке для плавающей ставки, в процентах
+	RateResetMonths    int32                 `db:"rate_reset_months" json:"rate_reset_months,omitempty"`     // Период пересмотра плавающей ставки (в месяцах)
+	NextRateReset      *time.Time            `db:"next_rate_reset" json:"next_rate_reset,omitempty"`         // Дата следующего пересмотра плавающей ставки
+	InterestReceivable decimal.Decimal       `db:"interest_receivable" json:"interest_receivable"`           // Начисленные и еще не погашенные проценты
+	InterestAccruedTo  *time.Time            `db:"interest_accrued_to" json:"interest_accrued_to,omitempty"` // День, до которого (не включая) начислены проценты
+	Status             CreditStatus          `db:"status" json:"status,omitempty"`                           // Статус кредита (оформлен, погашен)
+	CreatedAt          time.Time             `db:"created_at" json:"created_at"`                             // Дата оформления кредита
+	UpdatedAt          time.Time             `db:"updated_at" json:"updated_at"`                             // Дата последнего обновления
 }
 
-func (c *Credit) Withdraw(amount decimal.Decimal) error {
-	if c.Status != CreditStatusActive {
-		return ErrCreditNotActive
-	}
+// CreditDetails содержит кредит вместе с текущей задолженностью по нему и историей платежей.
+type CreditDetails struct {
+	Credit
+	OutstandingPrincipal decimal.Decimal   `json:"outstanding_principal"`       // Непогашенный основной долг
+	AccruedInterest      decimal.Decimal   `json:"accrued_interest"`            // Начисленные и не погашенные проценты
+	OverdueAmount        decimal.Decimal   `json:"overdue_amount"`              // Просроченная задолженность вместе со штрафами
+	NextPaymentDate      *time.Time        `json:"next_payment_date,omitempty"` // Дата ближайшего платежа, срок которого не наступил
+	Payments             []PaymentSchedule `json:"payments"`                    // Платежи, по которым внесены средства
+}
 
-	if amount.GreaterThan(c.Amount) {
-		return ErrCreditAmountExceeded
-	}
+// CreditFee описывает разовую комиссию, уплачиваемую заемщиком при выдаче кредита.
+type CreditFee struct {
+	Name   string          `json:"name"`   // Назначение комиссии
+	Amount decimal.Decimal `json:"amount"` // Сумма комиссии
+}
 
-	c.Amount = c.Amount.Sub(amount)
+// CreditQuote содержит предварительный расчет кредита, раскрываемый заемщику до заключения договора.
+type CreditQuote struct {
+	Amount         decimal.Decimal     `json:"amount"`           // Сумма кредита
+	InterestRate   decimal.Decimal     `json:"interest_rate"`    // Годовая процентная ставка в процентах
+	TermInMonths   int32               `json:"term_in_months"`   // Срок кредита (в месяцах)
+	ScheduleType   amortization.Method `json:"schedule_type"`    // Способ погашения
+	Schedule       []PaymentSchedule   `json:"schedule"`         // График платежей
+	Fees           []CreditFee         `json:"fees"`             // Комиссии при выдаче кредита
+	TotalInterest  decimal.Decimal     `json:"total_interest"`   // Сумма процентов за весь срок
+	TotalPayments  decimal.Decimal     `json:"total_payments"`   // Сумма всех платежей по графику
+	FullCostRate   decimal.Decimal     `json:"full_cost_rate"`   // Полная стоимость кредита в процентах годовых
+	FullCostAmount decimal.Decimal     `json:"full_cost_amount"` // Полная стоимость кредита в денежном выражении: проценты и комиссии
+}
 
-	return nil
+// RepaymentMode определяет, как пересчитывается график после частичного досрочного погашения.
+type RepaymentMode string
+
+const (
+	ReduceTerm    RepaymentMode = "reduce_term"    // Размер платежа сохраняется, срок кредита сокращается
+	ReducePayment RepaymentMode = "reduce_payment" // Срок кредита сохраняется, ежемесячный платеж уменьшается
+)
+
+// Validate проверяет способ пересчета графика.
+func (m RepaymentMode) Validate() error {
+	switch m {
+	case ReduceTerm, ReducePayment:
+		return nil
+	default:
+		return fmt.Errorf("unknown repayment mode: %s", m)
+	}
 }
 
+// PaymentStatus описывает состояние платежа по графику.
+type PaymentStatus string
+
+const (
+	PaymentPending       PaymentStatus = "pending"        // Срок платежа еще не наступил или списание не выполнялось
+	PaymentPaid          PaymentStatus = "paid"           // Платеж погашен полностью
+	PaymentPartiallyPaid PaymentStatus = "partially_paid" // Погашена часть платежа, день платежа еще не закончился
+	PaymentOverdue       PaymentStatus = "overdue"        // День платежа прошел, а платеж погашен не полностью
+)
+
 type PaymentSchedule struct {
 	ID              int32           `db:"id" json:"id,omitempty"`                   // Идентификатор записи
 	CreditID        int32           `db:"credit_id" json:"credit_id,omitempty"`     // Внешний ключ на кредит
@@ -159,15 +542,95 @@ type PaymentSchedule struct {
 	PaymentAmount   decimal.Decimal `db:"payment_amount" json:"payment_amount"`     // Сумма платежа
 	PrincipalAmount decimal.Decimal `db:"principal_amount" json:"principal_amount"` // Сумма, погашенная по телу кредита
 	InterestAmount  decimal.Decimal `db:"interest_amount" json:"interest_amount"`   // Сумма, погашенная по процентам
-	Penalty         decimal.Decimal `db:"penalty" json:"penalty"`                   // Штраф за просрочку
+	Penalty         decimal.Decimal `db:"penalty" json:"penalty"`                   // Начисленный штраф за просрочку
 	Balance         decimal.Decimal `db:"balance" json:"balance"`                   // Остаток долга после платежа
+	Status          PaymentStatus   `db:"status" json:"status"`                     // Статус платежа
+	PaidAmount      decimal.Decimal `db:"paid_amount" json:"paid_amount"`           // Уже погашенная часть платежа вместе со штрафом
+	PaidAt          *time.Time      `db:"paid_at" json:"paid_at,omitempty"`         // Дата полного погашения
 	CreatedAt       string          `db:"created_at" json:"created_at,omitempty"`   // Дата создания записи
 	UpdatedAt       string          `db:"updated_at" json:"updated_at,omitempty"`   // Дата последнего обновления
+
+	PenaltyPaid      decimal.Decimal `db:"penalty_paid" json:"penalty_paid"`                       // Погашенная часть штрафа
+	PenaltyWaived    decimal.Decimal `db:"penalty_waived" json:"penalty_waived"`                   // Списанная сотрудником часть штрафа
+	PenaltyWaivedBy  *int32          `db:"penalty_waived_by" json:"penalty_waived_by,omitempty"`   // Сотрудник, списавший штраф
+	PenaltyAccruedAt *time.Time      `db:"penalty_accrued_at" json:"penalty_accrued_at,omitempty"` // День, по который начислен штраф
+}
+
+// Outstanding возвращает непогашенную часть платежа вместе со штрафом.
+func (p *PaymentSchedule) Outstanding() decimal.Decimal {
+	return decimal.Max(p.PaymentAmount.Add(p.Penalty).Sub(p.PenaltyWaived).Sub(p.PaidAmount), decimal.Zero)
+}
+
+// PenaltyOutstanding возвращает непогашенную и не списанную часть штрафа.
+func (p *PaymentSchedule) PenaltyOutstanding() decimal.Decimal {
+	return decimal.Max(p.Penalty.Sub(p.PenaltyWaived).Sub(p.PenaltyPaid), decimal.Zero)
+}
+
+// OverdueAmount возвращает непогашенную часть платежа без штрафа, на которую начисляется штраф.
+func (p *PaymentSchedule) OverdueAmount() decimal.Decimal {
+	return decimal.Max(p.PaymentAmount.Sub(p.PaidAmount.Sub(p.PenaltyPaid)), decimal.Zero)
+}
+
+// PrincipalPaid возвращает погашенную часть основного долга по платежу. Внесенные средства сверх штрафа сначала
+// погашают проценты, затем основной долг.
+func (p *PaymentSchedule) PrincipalPaid() decimal.Decimal {
+	paid := p.PaidAmount.Sub(p.PenaltyPaid).Sub(p.InterestAmount)
+	return decimal.Min(decimal.Max(paid, decimal.Zero), p.PrincipalAmount)
+}
+
+// PrincipalOutstanding возвращает непогашенную часть основного долга по платежу.
+func (p *PaymentSchedule) PrincipalOutstanding() decimal.Decimal {
+	return p.PrincipalAmount.Sub(p.PrincipalPaid())
+}
+
+// CapitalizesInterest сообщает, что платеж приходится на кредитные каникулы: он не вносится, а проценты за период
+// прибавляются к основному долгу. Основной долг такого платежа отрицателен и равен капитализированным процентам,
+// а непогашенного основного долга по нему нет.
+func (p *PaymentSchedule) CapitalizesInterest() bool {
+	return p.PrincipalAmount.IsNegative()
+}
+
+// Apply зачисляет amount в счет платежа и пересчитывает его статус на момент now.
+// В первую очередь погашается штраф, затем сам платеж.
+// Платеж становится просроченным, если к концу дня платежа он погашен не полностью.
+func (p *PaymentSchedule) Apply(amount decimal.Decimal, now time.Time) {
+	p.PenaltyPaid = p.PenaltyPaid.Add(decimal.Min(amount, p.PenaltyOutstanding()))
+	p.PaidAmount = p.PaidAmount.Add(amount)
+	p.updateStatus(now)
+}
+
+// WaivePenalty списывает непогашенную часть штрафа от имени сотрудника adminID и возвращает списанную сумму.
+func (p *PaymentSchedule) WaivePenalty(adminID int32, now time.Time) decimal.Decimal {
+	waived := p.PenaltyOutstanding()
+	p.PenaltyWaived = p.PenaltyWaived.Add(waived)
+	p.PenaltyWaivedBy = &adminID
+	p.updateStatus(now)
+
+	return waived
+}
+
+func (p *PaymentSchedule) updateStatus(now time.Time) {
+	year, month, day := p.PaymentDate.Date()
+	dueEnd := time.Date(year, month, day+1, 0, 0, 0, 0, p.PaymentDate.Location())
+
+	switch {
+	case !p.Outstanding().IsPositive():
+		p.Status = PaymentPaid
+		p.PaidAt = &now
+	case !now.Before(dueEnd):
+		p.Status = PaymentOverdue
+	case p.PaidAmount.IsPositive():
+		p.Status = PaymentPartiallyPaid
+	default:
+		p.Status = PaymentPending
+	}
 }
 
 type FinancialTransaction struct {
 	ID                int32             `db:"id" json:"id,omitempty"`                                 // Идентификатор операции
 	UserID            int32             `db:"user_id" json:"user_id,omitempty"`                       // Внешний ключ на пользователя
+	AccountID         *int32            `db:"account_id" json:"account_id,omitempty"`                 // Внешний ключ на счет
+	CreditID          *int32            `db:"credit_id" json:"credit_id,omitempty"`                   // Внешний ключ на кредит, к которому относится операция
 	TransactionType   TransactionType   `db:"transaction_type" json:"transaction_type,omitempty"`     // Тип операции (пополнение, снятие и т.д.)
 	Amount            decimal.Decimal   `db:"amount" json:"amount"`                                   // Сумма операции
 	TransactionDate   time.Time         `db:"transaction_date" json:"transaction_date"`               // Дата операции
@@ -39,3 +39,69 @@ func (a AccountNumber) Validate() error {
 func (a AccountNumber) String() string {
 	return string(a)
 }
+
+// PAN представляет номер банковской карты (Primary Account Number).
+type PAN string
+
+// Validate проверяет, что номер карты состоит из 16 цифр и проходит проверку по алгоритму Луна.
+func (p PAN) Validate() error {
+	re := regexp.MustCompile(`^\d{16}$`)
+	if !re.MatchString(string(p)) {
+		return fmt.Errorf("invalid card number: must be 16 digits")
+	}
+
+	sum := 0
+	for i, r := range string(p) {
+		digit := int(r - '0')
+		if i%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	if sum%10 != 0 {
+		return fmt.Errorf("invalid card number: checksum mismatch")
+	}
+	return nil
+}
+
+// Masked возвращает номер карты, в котором видны только последние 4 цифры.
+func (p PAN) Masked() string {
+	if len(p) < 4 {
+		return "****"
+	}
+	return "**** " + string(p[len(p)-4:])
+}
+
+func (p PAN) String() string {
+	return string(p)
+}
+
+// CardToken заменяет номер карты во внешних системах. Токен состоит из 16 цифр, сохраняет последние 4 цифры
+// номера и намеренно не проходит проверку по алгоритму Луна, чтобы его нельзя было принять за настоящий номер.
+type CardToken string
+
+// Validate проверяет формат токена.
+func (t CardToken) Validate() error {
+	re := regexp.MustCompile(`^\d{16}$`)
+	if !re.MatchString(string(t)) {
+		return fmt.Errorf("invalid card token: must be 16 digits")
+	}
+
+	if PAN(t).Validate() == nil {
+		return fmt.Errorf("invalid card token: must not pass checksum")
+	}
+	return nil
+}
+
+// Masked возвращает маску номера карты, которую представляет токен.
+func (t CardToken) Masked() string {
+	return PAN(t).Masked()
+}
+
+func (t CardToken) String() string {
+	return string(t)
+}
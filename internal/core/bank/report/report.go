@@ -0,0 +1,222 @@
+// Package report строит отчеты по кредитному портфелю: распределение задолженности по корзинам просрочки (DPD)
+// и миграцию кредитов между корзинами (roll rates).
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Bucket корзина просрочки по числу дней просрочки самого раннего непогашенного платежа.
+type Bucket string
+
+const (
+	Current   Bucket = "current" // Просрочки нет
+	DPD1To30  Bucket = "1-30"    // Просрочка от 1 до 30 дней
+	DPD31To60 Bucket = "31-60"   // Просрочка от 31 до 60 дней
+	DPD61To90 Bucket = "61-90"   // Просрочка от 61 до 90 дней
+	DPD90Plus Bucket = "90+"     // Просрочка больше 90 дней
+	Closed    Bucket = "closed"  // Кредит погашен, используется только как конечная корзина миграции
+)
+
+// Buckets корзины просрочки в порядке ее роста.
+var Buckets = []Bucket{Current, DPD1To30, DPD31To60, DPD61To90, DPD90Plus}
+
+// BucketOf возвращает корзину для просрочки в daysPastDue дней.
+func BucketOf(daysPastDue int32) Bucket {
+	switch {
+	case daysPastDue <= 0:
+		return Current
+	case daysPastDue <= 30:
+		return DPD1To30
+	case daysPastDue <= 60:
+		return DPD31To60
+	case daysPastDue <= 90:
+		return DPD61To90
+	default:
+		return DPD90Plus
+	}
+}
+
+// BucketSummary задолженность кредитов одной корзины.
+type BucketSummary struct {
+	Bucket               Bucket          `json:"bucket"`                // Корзина просрочки
+	Credits              int             `json:"credits"`               // Количество кредитов
+	OutstandingPrincipal decimal.Decimal `json:"outstanding_principal"` // Непогашенный основной долг
+	OverdueAmount        decimal.Decimal `json:"overdue_amount"`        // Сумма просроченных платежей
+	AccruedInterest      decimal.Decimal `json:"accrued_interest"`      // Начисленные и не погашенные проценты
+	Share                decimal.Decimal `json:"share"`                 // Доля в основном долге портфеля, в процентах
+}
+
+// Portfolio состояние кредитного портфеля на отчетную дату.
+type Portfolio struct {
+	AsOf                 time.Time       `json:"as_of"`                 // Отчетная дата
+	Credits              int             `json:"credits"`               // Количество действующих кредитов
+	OutstandingPrincipal decimal.Decimal `json:"outstanding_principal"` // Непогашенный основной долг
+	OverdueAmount        decimal.Decimal `json:"overdue_amount"`        // Сумма просроченных платежей
+	AccruedInterest      decimal.Decimal `json:"accrued_interest"`      // Начисленные и не погашенные проценты
+	Buckets              []BucketSummary `json:"buckets"`               // Распределение по корзинам просрочки
+}
+
+// NewPortfolio группирует задолженность по кредитам exposures на дату asOf по корзинам просрочки.
+func NewPortfolio(asOf time.Time, exposures []entity.CreditExposure) *Portfolio {
+	portfolio := &Portfolio{
+		AsOf:                 asOf,
+		OutstandingPrincipal: decimal.Zero,
+		OverdueAmount:        decimal.Zero,
+		AccruedInterest:      decimal.Zero,
+		Buckets:              make([]BucketSummary, len(Buckets)),
+	}
+
+	index := make(map[Bucket]int, len(Buckets))
+	for i, bucket := range Buckets {
+		index[bucket] = i
+		portfolio.Buckets[i] = BucketSummary{
+			Bucket:               bucket,
+			OutstandingPrincipal: decimal.Zero,
+			OverdueAmount:        decimal.Zero,
+			AccruedInterest:      decimal.Zero,
+			Share:                decimal.Zero,
+		}
+	}
+
+	for _, exposure := range exposures {
+		summary := &portfolio.Buckets[index[BucketOf(exposure.DaysPastDue)]]
+		summary.Credits++
+		summary.OutstandingPrincipal = summary.OutstandingPrincipal.Add(exposure.OutstandingPrincipal)
+		summary.OverdueAmount = summary.OverdueAmount.Add(exposure.OverdueAmount)
+		summary.AccruedInterest = summary.AccruedInterest.Add(exposure.AccruedInterest)
+
+		portfolio.Credits++
+		portfolio.OutstandingPrincipal = portfolio.OutstandingPrincipal.Add(exposure.OutstandingPrincipal)
+		portfolio.OverdueAmount = portfolio.OverdueAmount.Add(exposure.OverdueAmount)
+		portfolio.AccruedInterest = portfolio.AccruedInterest.Add(exposure.AccruedInterest)
+	}
+
+	if portfolio.OutstandingPrincipal.IsPositive() {
+		for i := range portfolio.Buckets {
+			summary := &portfolio.Buckets[i]
+			summary.Share = summary.OutstandingPrincipal.Mul(decimal.NewFromInt(100)).Div(portfolio.OutstandingPrincipal).Round(2)
+		}
+	}
+
+	return portfolio
+}
+
+// RollRate доля кредитов корзины From на начало периода, оказавшихся в корзине To на его конец.
+type RollRate struct {
+	From    Bucket          `json:"from"`    // Корзина на начало периода
+	To      Bucket          `json:"to"`      // Корзина на конец периода
+	Credits int             `json:"credits"` // Количество перешедших кредитов
+	Rate    decimal.Decimal `json:"rate"`    // Доля от кредитов корзины From, в процентах
+}
+
+// RollRates матрица миграции кредитов между корзинами просрочки за период.
+type RollRates struct {
+	From  time.Time  `json:"from"`  // Начало периода
+	To    time.Time  `json:"to"`    // Конец периода
+	Rates []RollRate `json:"rates"` // Переходы для каждой пары корзин
+}
+
+// NewRollRates сопоставляет задолженность по кредитам на начало периода start и на его конец end.
+// Кредиты, отсутствующие в end, считаются погашенными и попадают в корзину Closed. Кредиты, выданные
+// внутри периода, в расчет не входят.
+func NewRollRates(from, to time.Time, start, end []entity.CreditExposure) *RollRates {
+	targets := append(append([]Bucket{}, Buckets...), Closed)
+
+	endBuckets := make(map[int32]Bucket, len(end))
+	for _, exposure := range end {
+		endBuckets[exposure.CreditID] = BucketOf(exposure.DaysPastDue)
+	}
+
+	counts := make(map[Bucket]map[Bucket]int, len(Buckets))
+	totals := make(map[Bucket]int, len(Buckets))
+	for _, exposure := range start {
+		source := BucketOf(exposure.DaysPastDue)
+		target, ok := endBuckets[exposure.CreditID]
+		if !ok {
+			target = Closed
+		}
+
+		if counts[source] == nil {
+			counts[source] = make(map[Bucket]int, len(targets))
+		}
+		counts[source][target]++
+		totals[source]++
+	}
+
+	rates := &RollRates{From: from, To: to, Rates: make([]RollRate, 0, len(Buckets)*len(targets))}
+	for _, source := range Buckets {
+		for _, target := range targets {
+			rate := RollRate{From: source, To: target, Credits: counts[source][target], Rate: decimal.Zero}
+			if totals[source] > 0 {
+				rate.Rate = decimal.NewFromInt(int64(rate.Credits * 100)).Div(decimal.NewFromInt(int64(totals[source]))).Round(2)
+			}
+			rates.Rates = append(rates.Rates, rate)
+		}
+	}
+
+	return rates
+}
+
+// WritePortfolioCSV выгружает распределение портфеля по корзинам просрочки в формате CSV.
+// Последняя строка содержит итог по портфелю.
+func WritePortfolioCSV(w io.Writer, portfolio *Portfolio) error {
+	writer := csv.NewWriter(w)
+	date := portfolio.AsOf.Format(time.DateOnly)
+
+	total := decimal.Zero
+	if portfolio.OutstandingPrincipal.IsPositive() {
+		total = decimal.NewFromInt(100)
+	}
+
+	records := [][]string{{"as_of", "bucket", "credits", "outstanding_principal", "overdue_amount", "accrued_interest", "share"}}
+	for _, summary := range portfolio.Buckets {
+		records = append(records, []string{
+			date,
+			string(summary.Bucket),
+			strconv.Itoa(summary.Credits),
+			summary.OutstandingPrincipal.StringFixed(2),
+			summary.OverdueAmount.StringFixed(2),
+			summary.AccruedInterest.StringFixed(2),
+			summary.Share.StringFixed(2),
+		})
+	}
+	records = append(records, []string{
+		date,
+		"total",
+		strconv.Itoa(portfolio.Credits),
+		portfolio.OutstandingPrincipal.StringFixed(2),
+		portfolio.OverdueAmount.StringFixed(2),
+		portfolio.AccruedInterest.StringFixed(2),
+		total.StringFixed(2),
+	})
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write portfolio csv: %w", err)
+	}
+
+	return nil
+}
+
+// WriteRollRatesCSV выгружает матрицу миграции между корзинами просрочки в формате CSV.
+func WriteRollRatesCSV(w io.Writer, rates *RollRates) error {
+	writer := csv.NewWriter(w)
+	from, to := rates.From.Format(time.DateOnly), rates.To.Format(time.DateOnly)
+
+	records := [][]string{{"from_date", "to_date", "from_bucket", "to_bucket", "credits", "rate"}}
+	for _, rate := range rates.Rates {
+		records = append(records, []string{from, to, string(rate.From), string(rate.To), strconv.Itoa(rate.Credits), rate.Rate.StringFixed(2)})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write roll rates csv: %w", err)
+	}
+
+	return nil
+}
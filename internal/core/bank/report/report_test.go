@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exposure(creditID int32, outstanding, overdue string, dpd int32) entity.CreditExposure {
+	return entity.CreditExposure{
+		CreditID:             creditID,
+		OutstandingPrincipal: decimal.RequireFromString(outstanding),
+		OverdueAmount:        decimal.RequireFromString(overdue),
+		DaysPastDue:          dpd,
+	}
+}
+
+func TestBucketOf(t *testing.T) {
+	testCases := []struct {
+		dpd  int32
+		want Bucket
+	}{
+		{0, Current},
+		{1, DPD1To30},
+		{30, DPD1To30},
+		{31, DPD31To60},
+		{60, DPD31To60},
+		{61, DPD61To90},
+		{90, DPD61To90},
+		{91, DPD90Plus},
+		{400, DPD90Plus},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, BucketOf(tc.dpd), "dpd %d", tc.dpd)
+	}
+}
+
+func TestNewPortfolio(t *testing.T) {
+	asOf := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	exposures := []entity.CreditExposure{
+		exposure(1, "50000", "0", 0),
+		exposure(2, "30000", "0", 0),
+		exposure(3, "15000", "5000", 12),
+		exposure(4, "5000", "3000", 95),
+	}
+	exposures[2].AccruedInterest = decimal.RequireFromString("125.50")
+
+	portfolio := NewPortfolio(asOf, exposures)
+
+	assert.Equal(t, 4, portfolio.Credits)
+	assert.True(t, decimal.NewFromInt(100000).Equal(portfolio.OutstandingPrincipal))
+	assert.True(t, decimal.NewFromInt(8000).Equal(portfolio.OverdueAmount))
+	assert.True(t, decimal.RequireFromString("125.50").Equal(portfolio.AccruedInterest))
+
+	require.Len(t, portfolio.Buckets, 5)
+	assert.Equal(t, 2, portfolio.Buckets[0].Credits)
+	assert.True(t, decimal.NewFromInt(80).Equal(portfolio.Buckets[0].Share))
+	assert.Equal(t, 1, portfolio.Buckets[1].Credits)
+	assert.True(t, decimal.NewFromInt(15).Equal(portfolio.Buckets[1].Share))
+	assert.Equal(t, 0, portfolio.Buckets[2].Credits)
+	assert.Equal(t, DPD90Plus, portfolio.Buckets[4].Bucket)
+	assert.True(t, decimal.NewFromInt(3000).Equal(portfolio.Buckets[4].OverdueAmount))
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePortfolioCSV(&buf, portfolio))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 7)
+	assert.Equal(t, "as_of,bucket,credits,outstanding_principal,overdue_amount,accrued_interest,share", lines[0])
+	assert.Equal(t, "2025-07-31,1-30,1,15000.00,5000.00,125.50,15.00", lines[2])
+	assert.Equal(t, "2025-07-31,total,4,100000.00,8000.00,125.50,100.00", lines[6])
+}
+
+func TestNewRollRates(t *testing.T) {
+	from := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	start := []entity.CreditExposure{
+		exposure(1, "50000", "0", 0),
+		exposure(2, "30000", "0", 0),
+		exposure(3, "15000", "0", 0),
+		exposure(4, "10000", "0", 0),
+		exposure(5, "5000", "3000", 20),
+		exposure(6, "5000", "3000", 25),
+	}
+	end := []entity.CreditExposure{
+		exposure(1, "45000", "0", 0),
+		exposure(2, "27000", "0", 0),
+		exposure(3, "14000", "2000", 10),
+		exposure(5, "5000", "6000", 51),
+		exposure(6, "2000", "0", 0),
+		// Кредит 7 выдан внутри периода и в миграции не учитывается.
+		exposure(7, "20000", "0", 0),
+	}
+
+	rates := NewRollRates(from, to, start, end)
+
+	require.Len(t, rates.Rates, 30)
+	find := func(source, target Bucket) RollRate {
+		for _, rate := range rates.Rates {
+			if rate.From == source && rate.To == target {
+				return rate
+			}
+		}
+		t.Fatalf("no roll rate %s -> %s", source, target)
+		return RollRate{}
+	}
+
+	assert.Equal(t, 2, find(Current, Current).Credits)
+	assert.True(t, decimal.NewFromInt(50).Equal(find(Current, Current).Rate))
+	assert.True(t, decimal.NewFromInt(25).Equal(find(Current, DPD1To30).Rate))
+	assert.True(t, decimal.NewFromInt(25).Equal(find(Current, Closed).Rate))
+	assert.True(t, decimal.NewFromInt(50).Equal(find(DPD1To30, DPD31To60).Rate))
+	assert.True(t, decimal.NewFromInt(50).Equal(find(DPD1To30, Current).Rate))
+	assert.True(t, decimal.Zero.Equal(find(DPD61To90, DPD90Plus).Rate))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRollRatesCSV(&buf, rates))
+	assert.Contains(t, buf.String(), "2025-06-30,2025-07-31,1-30,31-60,1,50.00\n")
+}
@@ -17,6 +17,8 @@ type AccountRepository interface {
 	Save(ctx context.Context, account *entity.Account) (*entity.Account, error)
 	FindByID(ctx context.Context, id int32) (*entity.Account, error)
 	GetAccountByUserID(ctx context.Context, userID int32) (*entity.Account, error)
+	LockByID(ctx context.Context, id int32) (*entity.Account, error)
+	Update(ctx context.Context, account *entity.Account) error
 
 	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
 }
@@ -84,6 +86,35 @@ func (s *AccountService) Create(
 	return createdAccount, nil
 }
 
+// CreateCreditAccount открывает пользователю кредитный счет с нулевым балансом и кредитным лимитом creditLimit.
+func (s *AccountService) CreateCreditAccount(ctx context.Context, userID int32, creditLimit decimal.Decimal) (*entity.Account, error) {
+	if !creditLimit.IsPositive() {
+		return nil, entity.ErrInvalidCreditLine
+	}
+
+	number, err := generateAccountNumber(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate account number: %w", err)
+	}
+
+	account := &entity.Account{
+		UserID:        userID,
+		AccountNumber: number,
+		Balance:       decimal.Zero,
+		Currency:      entity.RUB,
+		AccountType:   entity.CreditAccount,
+		CreditLimit:   creditLimit,
+	}
+
+	createdAccount, err := s.repo.Save(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	s.logger.Info("Credit account created successfully", "account_number", createdAccount.AccountNumber, "credit_limit", creditLimit)
+	return createdAccount, nil
+}
+
 // generateAccountNumber генерирует уникальный номер счета на основе userID и случайного числа.
 // Возвращает номер счета и ошибку, если номер не прошел проверку валидности.
 func generateAccountNumber(userID int32) (entity.AccountNumber, error) {
@@ -105,7 +136,7 @@ func (s *AccountService) Deposit(ctx context.Context, accountID int32, amount de
 	}
 
 	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
-		account, err := s.repo.FindByID(ctx, accountID)
+		account, err := s.repo.LockByID(ctx, accountID)
 		if err != nil {
 			return fmt.Errorf("failed to find account: %w", err)
 		}
@@ -114,7 +145,7 @@ func (s *AccountService) Deposit(ctx context.Context, accountID int32, amount de
 			return fmt.Errorf("failed to deposit amount: %w", err)
 		}
 
-		if _, err := s.repo.Save(ctx, account); err != nil {
+		if err := s.repo.Update(ctx, account); err != nil {
 			return fmt.Errorf("failed to update account balance: %w", err)
 		}
 
@@ -131,7 +162,7 @@ func (s *AccountService) Deposit(ctx context.Context, accountID int32, amount de
 // Withdraw выполняет операцию снятия указанной суммы со счета. Возвращает ошибку, если операция невозможна.
 func (s *AccountService) Withdraw(ctx context.Context, accountID int32, amount decimal.Decimal) error {
 	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
-		account, err := s.repo.FindByID(ctx, accountID)
+		account, err := s.repo.LockByID(ctx, accountID)
 		if err != nil {
 			return fmt.Errorf("failed to find account: %w", err)
 		}
@@ -140,7 +171,7 @@ func (s *AccountService) Withdraw(ctx context.Context, accountID int32, amount d
 			return fmt.Errorf("failed to withdraw amount: %w", err)
 		}
 
-		if _, err := s.repo.Save(ctx, account); err != nil {
+		if err := s.repo.Update(ctx, account); err != nil {
 			return fmt.Errorf("failed to update account balance: %w", err)
 		}
 
@@ -157,25 +188,30 @@ func (s *AccountService) Withdraw(ctx context.Context, accountID int32, amount d
 // Transfer выполняет перевод суммы между указанными счетами. Возвращает ошибку в случае неудачи.
 func (s *AccountService) Transfer(ctx context.Context, fromAccountID, toAccountID int32, amount decimal.Decimal) error {
 	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
-		fromAccount, err := s.repo.FindByID(ctx, fromAccountID)
-		if err != nil {
-			return fmt.Errorf("failed to find source account: %w", err)
+		// Счета блокируются в порядке возрастания идентификаторов, чтобы встречные переводы не взаимоблокировались.
+		locked := make(map[int32]*entity.Account, 2)
+		for _, id := range lockOrder(fromAccountID, toAccountID) {
+			account, err := s.repo.LockByID(ctx, id)
+			if err != nil {
+				if id == fromAccountID {
+					return fmt.Errorf("failed to find source account: %w", err)
+				}
+				return fmt.Errorf("failed to find target account: %w", err)
+			}
+			locked[id] = account
 		}
 
-		toAccount, err := s.repo.FindByID(ctx, toAccountID)
-		if err != nil {
-			return fmt.Errorf("failed to find target account: %w", err)
-		}
+		fromAccount, toAccount := locked[fromAccountID], locked[toAccountID]
 
 		if err := fromAccount.Transfer(toAccount, amount); err != nil {
 			return fmt.Errorf("failed to transfer amount: %w", err)
 		}
 
-		if _, err := s.repo.Save(ctx, fromAccount); err != nil {
+		if err := s.repo.Update(ctx, fromAccount); err != nil {
 			return fmt.Errorf("failed to update source account balance: %w", err)
 		}
 
-		if _, err := s.repo.Save(ctx, toAccount); err != nil {
+		if err := s.repo.Update(ctx, toAccount); err != nil {
 			return fmt.Errorf("failed to update target account balance: %w", err)
 		}
 
@@ -188,3 +224,69 @@ func (s *AccountService) Transfer(ctx context.Context, fromAccountID, toAccountI
 	}
 	return nil
 }
+
+// WithdrawAvailable списывает со счета сумму не больше limit, но не больше доступного остатка,
+// и возвращает фактически списанную сумму. Если средств на счете нет, ничего не списывается.
+func (s *AccountService) WithdrawAvailable(ctx context.Context, accountID int32, limit decimal.Decimal) (decimal.Decimal, error) {
+	withdrawn := decimal.Zero
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		account, err := s.repo.LockByID(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to find account: %w", err)
+		}
+
+		amount := decimal.Min(account.Balance, limit)
+		if !amount.IsPositive() {
+			return nil
+		}
+
+		if err := account.Withdraw(amount); err != nil {
+			return fmt.Errorf("failed to withdraw amount: %w", err)
+		}
+
+		if err := s.repo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+
+		withdrawn = amount
+		s.logger.Info("Withdrawal successful", "account_number", account.AccountNumber, "amount", amount)
+		return nil
+	})
+
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to withdraw amount: %w", err)
+	}
+	return withdrawn, nil
+}
+
+// Charge списывает со счета начисленную банком сумму, даже если она превышает доступный остаток.
+func (s *AccountService) Charge(ctx context.Context, accountID int32, amount decimal.Decimal) error {
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		account, err := s.repo.LockByID(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to find account: %w", err)
+		}
+
+		account.Charge(amount)
+
+		if err := s.repo.Update(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+
+		s.logger.Info("Charge successful", "account_number", account.AccountNumber, "amount", amount)
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to charge amount: %w", err)
+	}
+	return nil
+}
+
+// lockOrder возвращает идентификаторы счетов в порядке, в котором их строки блокируются.
+func lockOrder(a, b int32) []int32 {
+	if a > b {
+		return []int32{b, a}
+	}
+	return []int32{a, b}
+}
@@ -0,0 +1,141 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/bureau"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"log/slog"
+	"time"
+)
+
+// BureauRepository выбирает данные для бюро кредитных историй и хранит переданные в него файлы.
+type BureauRepository interface {
+	ListRecords(ctx context.Context, from, to time.Time) ([]entity.BureauRecord, error)
+	CreateSubmission(ctx context.Context, submission *entity.BureauSubmission) error
+	GetSubmission(ctx context.Context, period time.Time) (*entity.BureauSubmission, error)
+	ListSubmissions(ctx context.Context) ([]entity.BureauSubmission, error)
+}
+
+// BureauService ежемесячно формирует файл с кредитными историями для бюро и отслеживает, за какие месяцы он уже передан.
+type BureauService struct {
+	bureauRepository BureauRepository
+	memberCode       string
+	logger           *slog.Logger
+}
+
+// NewBureauService создает новый экземпляр BureauService.
+func NewBureauService(logger *slog.Logger, cfg *config.Config, bureauRepository BureauRepository) *BureauService {
+	return &BureauService{
+		bureauRepository: bureauRepository,
+		memberCode:       cfg.BureauMemberCode,
+		logger:           logger,
+	}
+}
+
+// Export формирует и сохраняет файл за месяц, в который попадает period. Месяц должен закончиться к моменту now.
+// Возвращает entity.ErrBureauPeriodSubmitted, если файл за этот месяц уже передан.
+func (s *BureauService) Export(ctx context.Context, period, now time.Time) (*entity.BureauSubmission, error) {
+	period = bureau.Period(period)
+	end := period.AddDate(0, 1, 0)
+	if end.After(now) {
+		return nil, entity.ErrBureauPeriodNotClosed
+	}
+
+	_, err := s.bureauRepository.GetSubmission(ctx, period)
+	if err == nil {
+		return nil, entity.ErrBureauPeriodSubmitted
+	}
+	if !errors.Is(err, entity.ErrBureauSubmissionNotFound) {
+		s.logger.Error("failed to get bureau submission", "period", period, "error", err)
+		return nil, fmt.Errorf("failed to get bureau submission: %w", err)
+	}
+
+	records, err := s.bureauRepository.ListRecords(ctx, period, end)
+	if err != nil {
+		s.logger.Error("failed to list bureau records", "period", period, "error", err)
+		return nil, fmt.Errorf("failed to list bureau records: %w", err)
+	}
+
+	content, err := bureau.Build(s.memberCode, period, records)
+	if err != nil {
+		s.logger.Error("failed to build bureau file", "period", period, "error", err)
+		return nil, fmt.Errorf("failed to build bureau file: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+	submission := &entity.BureauSubmission{
+		Period:      period,
+		FileName:    bureau.FileName(s.memberCode, period),
+		Content:     content,
+		ContentHash: hex.EncodeToString(hash[:]),
+		Records:     int32(len(records)),
+	}
+	if err := s.bureauRepository.CreateSubmission(ctx, submission); err != nil {
+		if errors.Is(err, entity.ErrBureauPeriodSubmitted) {
+			return nil, err
+		}
+		s.logger.Error("failed to save bureau submission", "period", period, "error", err)
+		return nil, fmt.Errorf("failed to save bureau submission: %w", err)
+	}
+
+	s.logger.Info("bureau file exported", "period", period, "records", submission.Records)
+
+	return submission, nil
+}
+
+// ExportPending формирует файлы за все закончившиеся к моменту now месяцы после последнего переданного, по порядку,
+// поэтому пропущенные запуски наверстываются. Если файлы еще не передавались, формируется файл за прошлый месяц.
+// При ошибке выгрузка останавливается, и следующий запуск продолжает с того же месяца.
+func (s *BureauService) ExportPending(ctx context.Context, now time.Time) error {
+	submissions, err := s.bureauRepository.ListSubmissions(ctx)
+	if err != nil {
+		s.logger.Error("failed to list bureau submissions", "error", err)
+		return fmt.Errorf("failed to list bureau submissions: %w", err)
+	}
+
+	current := bureau.Period(now)
+	period := current.AddDate(0, -1, 0)
+	if len(submissions) > 0 {
+		period = bureau.Period(submissions[0].Period).AddDate(0, 1, 0)
+	}
+
+	for ; period.Before(current); period = period.AddDate(0, 1, 0) {
+		_, err := s.Export(ctx, period, now)
+		if err != nil && !errors.Is(err, entity.ErrBureauPeriodSubmitted) {
+			return fmt.Errorf("failed to export bureau file for %s: %w", period.Format("2006-01"), err)
+		}
+	}
+
+	return nil
+}
+
+// GetSubmission возвращает файл, переданный за месяц, в который попадает period.
+func (s *BureauService) GetSubmission(ctx context.Context, period time.Time) (*entity.BureauSubmission, error) {
+	submission, err := s.bureauRepository.GetSubmission(ctx, bureau.Period(period))
+	if err != nil {
+		if errors.Is(err, entity.ErrBureauSubmissionNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get bureau submission", "period", period, "error", err)
+		return nil, fmt.Errorf("failed to get bureau submission: %w", err)
+	}
+
+	return submission, nil
+}
+
+// ListSubmissions возвращает переданные в бюро файлы, начиная с последнего месяца.
+func (s *BureauService) ListSubmissions(ctx context.Context) ([]entity.BureauSubmission, error) {
+	submissions, err := s.bureauRepository.ListSubmissions(ctx)
+	if err != nil {
+		s.logger.Error("failed to list bureau submissions", "error", err)
+		return nil, fmt.Errorf("failed to list bureau submissions: %w", err)
+	}
+
+	return submissions, nil
+}
@@ -0,0 +1,103 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestBureauService_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	period := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 8, 1, 1, 0, 0, 0, time.UTC)
+
+	t.Run("period not closed", func(t *testing.T) {
+		service := &BureauService{bureauRepository: NewMockBureauRepository(ctrl), memberCode: "BANK000001", logger: logger}
+
+		_, err := service.Export(context.TODO(), period, now.AddDate(0, 0, -2))
+		assert.ErrorIs(t, err, entity.ErrBureauPeriodNotClosed)
+	})
+
+	t.Run("already submitted", func(t *testing.T) {
+		repo := NewMockBureauRepository(ctrl)
+		repo.EXPECT().GetSubmission(gomock.Any(), period).Return(&entity.BureauSubmission{ID: 1, Period: period}, nil)
+
+		service := &BureauService{bureauRepository: repo, memberCode: "BANK000001", logger: logger}
+
+		_, err := service.Export(context.TODO(), period.AddDate(0, 0, 14), now)
+		assert.ErrorIs(t, err, entity.ErrBureauPeriodSubmitted)
+	})
+
+	t.Run("exports and saves file", func(t *testing.T) {
+		repo := NewMockBureauRepository(ctrl)
+		repo.EXPECT().GetSubmission(gomock.Any(), period).Return(nil, entity.ErrBureauSubmissionNotFound)
+		repo.EXPECT().ListRecords(gomock.Any(), period, period.AddDate(0, 1, 0)).Return(nil, nil)
+		repo.EXPECT().CreateSubmission(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, submission *entity.BureauSubmission) error {
+			submission.ID = 5
+			return nil
+		})
+
+		service := &BureauService{bureauRepository: repo, memberCode: "BANK000001", logger: logger}
+
+		submission, err := service.Export(context.TODO(), period, now)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), submission.ID)
+		assert.Equal(t, "BANK000001_202507.xml", submission.FileName)
+		assert.Equal(t, int32(0), submission.Records)
+		assert.Len(t, submission.ContentHash, 64)
+		assert.Contains(t, string(submission.Content), "<RecordCount>0</RecordCount>")
+	})
+
+	t.Run("pending export skips submitted period", func(t *testing.T) {
+		repo := NewMockBureauRepository(ctrl)
+		repo.EXPECT().ListSubmissions(gomock.Any()).Return(nil, nil)
+		repo.EXPECT().GetSubmission(gomock.Any(), period).Return(&entity.BureauSubmission{ID: 1, Period: period}, nil)
+
+		service := &BureauService{bureauRepository: repo, memberCode: "BANK000001", logger: logger}
+
+		assert.NoError(t, service.ExportPending(context.TODO(), now.AddDate(0, 0, 5)))
+	})
+
+	t.Run("pending export catches up every closed month since last submission", func(t *testing.T) {
+		last := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+		repo := NewMockBureauRepository(ctrl)
+		repo.EXPECT().ListSubmissions(gomock.Any()).Return([]entity.BureauSubmission{{ID: 1, Period: last}}, nil)
+
+		var exported []string
+		for month := last.AddDate(0, 1, 0); !month.After(period); month = month.AddDate(0, 1, 0) {
+			repo.EXPECT().GetSubmission(gomock.Any(), month).Return(nil, entity.ErrBureauSubmissionNotFound)
+			repo.EXPECT().ListRecords(gomock.Any(), month, month.AddDate(0, 1, 0)).Return(nil, nil)
+		}
+		repo.EXPECT().CreateSubmission(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, submission *entity.BureauSubmission) error {
+			exported = append(exported, submission.FileName)
+			return nil
+		}).Times(3)
+
+		service := &BureauService{bureauRepository: repo, memberCode: "BANK000001", logger: logger}
+
+		assert.NoError(t, service.ExportPending(context.TODO(), now.AddDate(0, 0, 5)))
+		assert.Equal(t, []string{"BANK000001_202505.xml", "BANK000001_202506.xml", "BANK000001_202507.xml"}, exported)
+	})
+
+	t.Run("pending export stops at failed month", func(t *testing.T) {
+		last := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+		failed := last.AddDate(0, 1, 0)
+		repo := NewMockBureauRepository(ctrl)
+		repo.EXPECT().ListSubmissions(gomock.Any()).Return([]entity.BureauSubmission{{ID: 1, Period: last}}, nil)
+		repo.EXPECT().GetSubmission(gomock.Any(), failed).Return(nil, entity.ErrBureauSubmissionNotFound)
+		repo.EXPECT().ListRecords(gomock.Any(), failed, failed.AddDate(0, 1, 0)).Return(nil, assert.AnError)
+
+		service := &BureauService{bureauRepository: repo, memberCode: "BANK000001", logger: logger}
+
+		err := service.ExportPending(context.TODO(), now.AddDate(0, 0, 5))
+		assert.ErrorContains(t, err, "failed to export bureau file for 2025-06")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/MaxFando/bank-system/config"
 	"github.com/MaxFando/bank-system/internal/core/bank/entity"
@@ -24,32 +25,59 @@ import (
 // Save сохраняет новую карту или обновляет существующую.
 // FindByID ищет карту по уникальному идентификатору.
 // FindByAccountID возвращает список карт, привязанных к определенному аккаунту.
+// LockByID ищет карту и блокирует ее до конца транзакции, Update сохраняет статус и сумму списаний.
+// FindRecipientByPANIndex ищет карту получателя перевода по слепому индексу номера.
+// FindExpiring возвращает карты, которые нужно перевыпустить, ExpireOutdated переводит просроченные карты в статус expired.
 type CardRepository interface {
 	Save(ctx context.Context, card *entity.Card) (*entity.Card, error)
+	Update(ctx context.Context, card *entity.Card) (*entity.Card, error)
 	FindByID(ctx context.Context, id int32) (*entity.Card, error)
+	LockByID(ctx context.Context, id int32) (*entity.Card, error)
 	FindByAccountID(ctx context.Context, accountID int32) ([]entity.Card, error)
+	FindRecipientByPANIndex(ctx context.Context, panIndex string) (*entity.CardRecipient, error)
+	FindByPANIndex(ctx context.Context, panIndex string) (*entity.Card, error)
+	FindExpiring(ctx context.Context, before time.Time) ([]entity.Card, error)
+	ExpireOutdated(ctx context.Context, now time.Time) ([]int32, error)
+	FindWithoutPANIndex(ctx context.Context, afterID int32, limit int) ([]entity.Card, error)
+	SetPANIndex(ctx context.Context, id int32, panIndex string) error
 }
 
-// CardTransactionRepository предоставляет методы для работы с операциями по картам, такими как перевод, снятие и пополнение.
+// CardTransactionRepository хранит историю операций по картам: переводы, оплаты, снятия и пополнения.
+// List возвращает страницу операций по фильтру после указанного курсора, Totals — итоги по тому же фильтру.
+// FindByRRN находит одобренное списание по номеру операции в платежной сети, MarkReversed отмечает его сторно.
 type CardTransactionRepository interface {
-	Transfer(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error)
-	Withdraw(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error)
-	Deposit(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error)
+	Create(ctx context.Context, cardTransaction *entity.CardTransaction) (int32, error)
 	FindByID(ctx context.Context, id int32) (*entity.CardTransaction, error)
+	List(ctx context.Context, filter entity.CardTransactionFilter, after *entity.CardTransactionCursor, limit int) ([]entity.CardTransaction, error)
+	Totals(ctx context.Context, filter entity.CardTransactionFilter) (*entity.CardTransactionTotals, error)
+	SumByMCCSince(ctx context.Context, cardID int32, since time.Time) (map[entity.MCC]decimal.Decimal, error)
+	FindByRRN(ctx context.Context, rrn string) (*entity.CardTransaction, error)
+	MarkReversed(ctx context.Context, id int32, at time.Time) error
 
 	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
 }
 
+// CardRuleRepository хранит ограничения, которые владельцы карт устанавливают на операции по своим картам.
+type CardRuleRepository interface {
+	GetByCardID(ctx context.Context, cardID int32) (*entity.CardRules, error)
+	Save(ctx context.Context, rules *entity.CardRules) (*entity.CardRules, error)
+}
+
 // CardService предоставляет методы для работы с банковскими картами, включая создание, обновление и обработку транзакций.
 type CardService struct {
 	cardRepository            CardRepository
 	cardTransactionRepository CardTransactionRepository
+	cardRuleRepository        CardRuleRepository
 
-	accountService *AccountService
+	accountService      *AccountService
+	notificationService *NotificationService
 
 	publicKeyPath  string
 	privateKeyPath string
 	passphrase     string
+	panIndexKey    string
+
+	reissueLeadTime time.Duration
 
 	logger *slog.Logger
 }
@@ -59,34 +87,102 @@ func NewCardService(
 	logger *slog.Logger,
 	cfg *config.Config,
 	accountService *AccountService,
+	notificationService *NotificationService,
 	cardRepository CardRepository,
 	cardTransactionRepository CardTransactionRepository,
+	cardRuleRepository CardRuleRepository,
 ) *CardService {
 	return &CardService{
 		accountService:            accountService,
+		notificationService:       notificationService,
 		cardRepository:            cardRepository,
 		cardTransactionRepository: cardTransactionRepository,
+		cardRuleRepository:        cardRuleRepository,
 		publicKeyPath:             cfg.PublicKeyPath,
 		privateKeyPath:            cfg.PrivateKeyPath,
 		passphrase:                cfg.Passphrase,
+		panIndexKey:               cfg.CardIndexKey,
+		reissueLeadTime:           cfg.CardReissueLeadTime,
 		logger:                    logger,
 	}
 }
 
+// CardOption задает вид и ограничения выпускаемой карты.
+type CardOption func(card *entity.Card)
+
+// WithCardKind задает вид карты. По умолчанию выпускается физическая карта.
+func WithCardKind(kind entity.CardKind) CardOption {
+	return func(card *entity.Card) {
+		card.Kind = kind
+	}
+}
+
+// WithSpendLimit ограничивает общую сумму списаний по карте.
+func WithSpendLimit(limit decimal.Decimal) CardOption {
+	return func(card *entity.Card) {
+		card.SpendLimit = decimal.NewNullDecimal(limit)
+	}
+}
+
+// WithExpirationDate задает срок действия карты вместо срока по умолчанию для ее вида.
+func WithExpirationDate(date time.Time) CardOption {
+	return func(card *entity.Card) {
+		card.ExpirationDate = date
+	}
+}
+
+// reissueOf помечает карту как выпущенную взамен карты cardID.
+func reissueOf(cardID int32) CardOption {
+	return func(card *entity.Card) {
+		card.ReissuedFromID = &cardID
+	}
+}
+
+// defaultExpirationDate возвращает срок действия карты по умолчанию в зависимости от ее вида.
+func defaultExpirationDate(kind entity.CardKind, now time.Time) time.Time {
+	switch kind {
+	case entity.SingleUseCard:
+		return now.AddDate(0, 1, 0)
+	case entity.VirtualCard, entity.LimitedUseCard:
+		return now.AddDate(3, 0, 0)
+	default:
+		return now.AddDate(10, 0, 0)
+	}
+}
+
 // Create создает новую карту для указанного аккаунта, сохраняет данные карты и возвращает созданную карту или ошибку.
-func (s *CardService) Create(ctx context.Context, account *entity.Account) (*entity.Card, error) {
+// Вид карты и ее ограничения задаются опциями; без опций выпускается физическая карта.
+func (s *CardService) Create(ctx context.Context, account *entity.Account, opts ...CardOption) (*entity.Card, error) {
 	card := &entity.Card{
-		AccountID:      account.ID,
-		CardNumber:     generateCardNumber(account.ID),
-		ExpirationDate: time.Now().AddDate(10, 0, 0),
-		CVV:            generateCVV(),
-		Status:         entity.CardActive,
+		AccountID:  account.ID,
+		CardNumber: generateCardNumber(account.ID),
+		CVV:        generateCVV(),
+		Kind:       entity.PhysicalCard,
+		Status:     entity.CardActive,
+	}
+
+	for _, opt := range opts {
+		opt(card)
+	}
+
+	// Параметры проверяются до подстановки срока действия по умолчанию, чтобы карта с ограничениями не была
+	// выпущена без заданного срока.
+	now := time.Now()
+	if err := card.Validate(now); err != nil {
+		return nil, err
+	}
+
+	if card.ExpirationDate.IsZero() {
+		card.ExpirationDate = defaultExpirationDate(card.Kind, now)
 	}
 
 	// Генерация HMAC для проверки целостности данных карты
 	hmac := s.generateHMAC(fmt.Sprintf("%s:%s:%s", card.CardNumber, card.CVV, card.ExpirationDate.Format("2006-01-02")), account.AccountNumber.String())
 	card.HMAC = hmac
 
+	// Слепой индекс позволяет найти карту по номеру без расшифровки данных всех карт
+	card.PANIndex = s.panIndex(entity.PAN(card.CardNumber))
+
 	// Шифрование данных карты
 	encryptedData, err := s.encryptCardDataPGP(card, s.publicKeyPath, nil)
 	if err != nil {
@@ -103,10 +199,78 @@ func (s *CardService) Create(ctx context.Context, account *entity.Account) (*ent
 		return nil, fmt.Errorf("failed to save card: %w", err)
 	}
 
-	s.logger.Info("card created successfully", "card_id", savedCard.ID, "account_id", account.ID)
+	s.logger.Info("card created successfully", "card_id", savedCard.ID, "account_id", account.ID, "kind", savedCard.Kind)
 	return savedCard, nil
 }
 
+// ExpireCards переводит в статус expired карты, срок действия которых истек к моменту now,
+// в том числе неиспользованные виртуальные карты.
+func (s *CardService) ExpireCards(ctx context.Context, now time.Time) error {
+	ids, err := s.cardRepository.ExpireOutdated(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to expire cards", "error", err)
+		return fmt.Errorf("failed to expire cards: %w", err)
+	}
+
+	s.logger.Info("cards expired", "count", len(ids), "card_ids", ids)
+	return nil
+}
+
+// ReissueExpiringCards выпускает новые карты взамен карт, срок действия которых истекает в течение
+// заранее заданного периода, и уведомляет владельцев. Одноразовые карты и карты с ограничением не перевыпускаются.
+func (s *CardService) ReissueExpiringCards(ctx context.Context, now time.Time) error {
+	cards, err := s.cardRepository.FindExpiring(ctx, now.Add(s.reissueLeadTime))
+	if err != nil {
+		s.logger.Error("failed to find expiring cards", "error", err)
+		return fmt.Errorf("failed to find expiring cards: %w", err)
+	}
+
+	var failed int
+	for i := range cards {
+		if err := s.reissue(ctx, &cards[i]); err != nil {
+			s.logger.Error("failed to reissue card", "card_id", cards[i].ID, "error", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to reissue %d of %d cards", failed, len(cards))
+	}
+
+	s.logger.Info("expiring cards reissued", "count", len(cards))
+	return nil
+}
+
+// reissue выпускает карту взамен card на тот же счет и уведомляет владельца счета.
+func (s *CardService) reissue(ctx context.Context, card *entity.Card) error {
+	account, err := s.accountService.GetAccountByID(ctx, card.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to find card account: %w", err)
+	}
+
+	renewal, err := s.Create(ctx, account, WithCardKind(card.Kind), reissueOf(card.ID))
+	if err != nil {
+		return fmt.Errorf("failed to create renewal card: %w", err)
+	}
+
+	s.logger.Info("card reissued", "card_id", card.ID, "renewal_card_id", renewal.ID)
+
+	subject := "Перевыпуск карты"
+	body := fmt.Sprintf(
+		"Срок действия вашей карты истекает %s. Мы выпустили новую карту %s, она действует до %s.",
+		card.ExpirationDate.Format("02.01.2006"),
+		entity.PAN(renewal.CardNumber).Masked(),
+		renewal.ExpirationDate.Format("01/06"),
+	)
+
+	// Карта уже перевыпущена, поэтому ошибка доставки уведомления не прерывает задание.
+	if err := s.notificationService.Notify(ctx, account.UserID, subject, body); err != nil {
+		s.logger.Error("failed to notify about reissued card", "card_id", renewal.ID, "error", err)
+	}
+
+	return nil
+}
+
 // FindByID находит и возвращает карту по указанному идентификатору, либо ошибку, если карта не найдена или произошла ошибка.
 func (s *CardService) FindByID(ctx context.Context, id int32) (*entity.Card, error) {
 	card, err := s.cardRepository.FindByID(ctx, id)
@@ -115,9 +279,8 @@ func (s *CardService) FindByID(ctx context.Context, id int32) (*entity.Card, err
 		return nil, fmt.Errorf("failed to find card by ID: %w", err)
 	}
 
-	e, err2 := s.smth(err, card)
-	if err2 != nil {
-		return e, err2
+	if err := s.smth(ctx, card); err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("card found successfully", "card_id", card.ID)
@@ -136,8 +299,7 @@ func (s *CardService) FindByAccountID(ctx context.Context, accountID int32) ([]e
 	s.logger.Info("cards found successfully", "account_id", accountID, "cards_count", len(cards))
 
 	for i := range cards {
-		_, err := s.smth(err, &cards[i])
-		if err != nil {
+		if err := s.smth(ctx, &cards[i]); err != nil {
 			s.logger.Error("failed to decrypt card data", "error", err)
 			return nil, fmt.Errorf("failed to decrypt card data: %w", err)
 		}
@@ -147,77 +309,119 @@ func (s *CardService) FindByAccountID(ctx context.Context, accountID int32) ([]e
 }
 
 // smth проверяет целостность данных карты, расшифровывает их и обновляет информацию объекта карты.
-func (s *CardService) smth(err error, card *entity.Card) (*entity.Card, error) {
+// HMAC подписывается номером счета карты при выпуске, поэтому для проверки счет загружается заново.
+func (s *CardService) smth(ctx context.Context, card *entity.Card) error {
 	// Расшифровка данных карты
 	decryptCardData, err := s.decryptCardData(card.EncryptedData, s.privateKeyPath, s.passphrase)
 	if err != nil {
 		s.logger.Error("failed to decrypt card data", "error", err)
-		return nil, fmt.Errorf("failed to decrypt card data: %w", err)
+		return fmt.Errorf("failed to decrypt card data: %w", err)
+	}
+
+	account, err := s.accountService.GetAccountByID(ctx, card.AccountID)
+	if err != nil {
+		s.logger.Error("failed to find card account", "card_id", card.ID, "error", err)
+		return fmt.Errorf("failed to find card account: %w", err)
 	}
 
 	// Проверка HMAC для целостности данных карты
-	expectedHMAC := s.generateHMAC(decryptCardData, fmt.Sprintf("%d", card.AccountID))
+	expectedHMAC := s.generateHMAC(decryptCardData, account.AccountNumber.String())
 	if card.HMAC != expectedHMAC {
 		s.logger.Error("HMAC verification failed", "expected_hmac", expectedHMAC, "actual_hmac", card.HMAC)
-		return nil, fmt.Errorf("HMAC verification failed")
+		return fmt.Errorf("HMAC verification failed")
 	}
 
 	decryptCardDataParts := bytes.Split([]byte(decryptCardData), []byte(":"))
 	if len(decryptCardDataParts) != 3 {
 		s.logger.Error("invalid decrypted card data format")
-		return nil, fmt.Errorf("invalid decrypted card data format")
+		return fmt.Errorf("invalid decrypted card data format")
 	}
 
 	card.CardNumber = string(decryptCardDataParts[0])
 	card.CVV = string(decryptCardDataParts[1])
-	card.ExpirationDate, err = time.Parse("2006-01-02", string(decryptCardDataParts[2]))
-	return nil, nil
+	return nil
 }
 
 // Transfer выполняет перевод указанной суммы с одной карты на другую в рамках заданного контекста.
+// По карте отправителя записывается списание, по карте получателя — зачисление; каждая сторона указана как контрагент другой.
+// Перевод проверяется по правилам карты отправителя как операция группы MCCGroupTransfers.
+// Если перевод не выполнен, по карте отправителя записывается отклоненная операция.
 func (s *CardService) Transfer(ctx context.Context, fromCardID, toCardID int32, amount decimal.Decimal) error {
+	var attempt *entity.CardTransaction
+	merchant := entity.TransferMerchant()
+
 	err := s.cardTransactionRepository.WithTx(ctx, func(ctx context.Context) error {
-		fromCard, err := s.cardRepository.FindByID(ctx, fromCardID)
+		fromCard, err := s.cardRepository.LockByID(ctx, fromCardID)
 		if err != nil {
 			s.logger.Error("failed to find source card", "error", err)
 			return fmt.Errorf("failed to find source card: %w", err)
 		}
 
+		attempt = &entity.CardTransaction{
+			CardID:             fromCardID,
+			Amount:             amount,
+			TransactionType:    entity.TransferTransaction,
+			Direction:          entity.DebitDirection,
+			CounterpartyCardID: &toCardID,
+			Merchant:           merchant,
+		}
+
+		if err := fromCard.CanDebit(amount, time.Now()); err != nil {
+			s.logger.Warn("source card cannot be debited", "card_id", fromCardID, "error", err)
+			return fmt.Errorf("source card cannot be debited: %w", err)
+		}
+
+		if err := s.checkRules(ctx, fromCard, amount, merchant); err != nil {
+			s.logger.Warn("transfer rejected by card rules", "card_id", fromCardID, "error", err)
+			return err
+		}
+
 		toCard, err := s.cardRepository.FindByID(ctx, toCardID)
 		if err != nil {
 			s.logger.Error("failed to find target card", "error", err)
 			return fmt.Errorf("failed to find target card: %w", err)
 		}
 
-		fromAccount, err := s.accountService.GetAccountByID(ctx, fromCard.AccountID)
-		if err != nil {
-			s.logger.Error("failed to find source account", "error", err)
-			return fmt.Errorf("failed to find source account: %w", err)
+		if err := toCard.CanCredit(time.Now()); err != nil {
+			s.logger.Warn("target card cannot be credited", "card_id", toCardID, "error", err)
+			return fmt.Errorf("target card cannot be credited: %w", err)
 		}
 
-		toAccount, err := s.accountService.GetAccountByID(ctx, toCard.AccountID)
-		if err != nil {
-			s.logger.Error("failed to find target account", "error", err)
-			return fmt.Errorf("failed to find target account: %w", err)
-		}
-
-		if err := fromAccount.Transfer(toAccount, amount); err != nil {
+		if err := s.accountService.Transfer(ctx, fromCard.AccountID, toCard.AccountID, amount); err != nil {
 			s.logger.Error("failed to transfer amount", "error", err)
 			return fmt.Errorf("failed to transfer amount: %w", err)
 		}
 
-		transactionID, err := s.cardTransactionRepository.Transfer(ctx, fromCardID, amount)
+		transactionID, err := s.cardTransactionRepository.Create(ctx, succeeded(attempt))
+		if err != nil {
+			s.logger.Error("failed to transfer money", "error", err)
+			return fmt.Errorf("failed to transfer money: %w", err)
+		}
+
+		_, err = s.cardTransactionRepository.Create(ctx, &entity.CardTransaction{
+			CardID:             toCardID,
+			Amount:             amount,
+			TransactionType:    entity.TransferTransaction,
+			Direction:          entity.CreditDirection,
+			Status:             entity.TransactionSuccess,
+			CounterpartyCardID: &fromCardID,
+		})
 		if err != nil {
 			s.logger.Error("failed to transfer money", "error", err)
 			return fmt.Errorf("failed to transfer money: %w", err)
 		}
 
+		if err := s.registerDebit(ctx, fromCard, amount); err != nil {
+			return err
+		}
+
 		s.logger.Info("money transferred successfully", "transaction_id", transactionID, "card_id", fromCardID, "amount", amount)
 		return nil
 	})
 
 	if err != nil {
 		s.logger.Error("transaction failed", "error", err)
+		s.recordFailed(ctx, attempt)
 		return fmt.Errorf("transaction failed: %w", err)
 	}
 
@@ -225,15 +429,190 @@ func (s *CardService) Transfer(ctx context.Context, fromCardID, toCardID int32,
 	return nil
 }
 
+// ResolveRecipient находит карту получателя перевода по ее номеру и возвращает маскированные данные
+// держателя, чтобы отправитель мог подтвердить получателя до выполнения перевода.
+func (s *CardService) ResolveRecipient(ctx context.Context, pan entity.PAN) (*entity.CardRecipient, error) {
+	if err := pan.Validate(); err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.cardRepository.FindRecipientByPANIndex(ctx, s.panIndex(pan))
+	if err != nil {
+		s.logger.Error("failed to find recipient card", "error", err)
+		return nil, fmt.Errorf("failed to find recipient card: %w", err)
+	}
+
+	if recipient.Status == entity.CardExpired {
+		return nil, fmt.Errorf("recipient card cannot be credited: %w", entity.ErrCardExpired)
+	}
+
+	if recipient.Status != entity.CardActive {
+		return nil, fmt.Errorf("recipient card cannot be credited: %w", entity.ErrCardNotActive)
+	}
+
+	recipient.MaskedPAN = pan.Masked()
+	return recipient, nil
+}
+
+// TransferByPAN выполняет перевод с карты отправителя на карту получателя, заданную номером.
+// Возвращает маскированные данные получателя.
+func (s *CardService) TransferByPAN(ctx context.Context, fromCardID int32, pan entity.PAN, amount decimal.Decimal) (*entity.CardRecipient, error) {
+	recipient, err := s.ResolveRecipient(ctx, pan)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Transfer(ctx, fromCardID, recipient.CardID, amount); err != nil {
+		return nil, err
+	}
+
+	return recipient, nil
+}
+
+// FindByPAN находит карту по ее номеру с помощью слепого индекса. Данные карты не расшифровываются: известны
+// идентификатор, статус и срок действия карты. Возвращает entity.ErrCardNotFound, если карта с таким номером
+// не выпускалась.
+func (s *CardService) FindByPAN(ctx context.Context, pan entity.PAN) (*entity.Card, error) {
+	if err := pan.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrCardNotFound, err)
+	}
+
+	card, err := s.cardRepository.FindByPANIndex(ctx, s.panIndex(pan))
+	if err != nil {
+		s.logger.Error("failed to find card by PAN", "error", err)
+		return nil, fmt.Errorf("failed to find card by PAN: %w", err)
+	}
+
+	return card, nil
+}
+
+// panIndexBatchSize количество карт, которые BackfillPANIndex загружает за один запрос.
+const panIndexBatchSize = 100
+
+// BackfillPANIndex рассчитывает слепой индекс номера для карт, выпущенных до его появления: без индекса карту
+// нельзя найти по номеру. Данные каждой карты расшифровываются и проверяются по HMAC. Ошибка по одной карте
+// не прерывает заполнение остальных. Возвращает количество карт, получивших индекс.
+func (s *CardService) BackfillPANIndex(ctx context.Context) (int, error) {
+	var afterID int32
+	var indexed, failed int
+	for {
+		cards, err := s.cardRepository.FindWithoutPANIndex(ctx, afterID, panIndexBatchSize)
+		if err != nil {
+			s.logger.Error("failed to find cards without pan index", "error", err)
+			return indexed, fmt.Errorf("failed to find cards without pan index: %w", err)
+		}
+
+		for i := range cards {
+			card := &cards[i]
+			afterID = card.ID
+
+			if err := s.smth(ctx, card); err != nil {
+				s.logger.Error("failed to read card data", "card_id", card.ID, "error", err)
+				failed++
+				continue
+			}
+
+			if err := s.cardRepository.SetPANIndex(ctx, card.ID, s.panIndex(entity.PAN(card.CardNumber))); err != nil {
+				s.logger.Error("failed to set card pan index", "card_id", card.ID, "error", err)
+				failed++
+				continue
+			}
+			indexed++
+		}
+
+		if len(cards) < panIndexBatchSize {
+			break
+		}
+	}
+
+	if failed > 0 {
+		return indexed, fmt.Errorf("failed to index %d of %d cards", failed, indexed+failed)
+	}
+
+	s.logger.Info("card pan indexes backfilled", "count", indexed)
+	return indexed, nil
+}
+
 // Withdraw выполняет снятие указанной суммы с карты, идентифицированной cardID, с учетом контекста выполнения.
-func (s *CardService) Withdraw(ctx context.Context, cardID int32, amount decimal.Decimal) error {
+// merchant описывает торговую точку; операция отклоняется, если нарушает правила, установленные для карты.
+func (s *CardService) Withdraw(ctx context.Context, cardID int32, amount decimal.Decimal, merchant *entity.Merchant) error {
+	_, err := s.withdraw(ctx, cardID, amount, merchant, "", "")
+	return err
+}
+
+// Authorize списывает сумму amount с карты cardID по запросу платежной сети, как Withdraw, и сохраняет в операции
+// ее номер rrn и номер трассировки stan. Операция по RRN ищется после блокировки карты, поэтому запросы по одной
+// карте обрабатываются по очереди, а повторная передача запроса не списывает средства дважды и возвращает
+// ранее одобренную операцию. Запрос с тем же RRN по другой карте или на другую сумму отклоняется
+// с ошибкой entity.ErrDuplicateRRN.
+func (s *CardService) Authorize(
+	ctx context.Context,
+	cardID int32,
+	amount decimal.Decimal,
+	merchant *entity.Merchant,
+	rrn, stan string,
+) (*entity.CardTransaction, error) {
+	return s.withdraw(ctx, cardID, amount, merchant, rrn, stan)
+}
+
+// withdraw списывает сумму amount с карты cardID и возвращает операцию списания. Если задан rrn и по нему уже
+// одобрено списание той же суммы с той же карты, возвращается оно без повторного списания.
+func (s *CardService) withdraw(
+	ctx context.Context,
+	cardID int32,
+	amount decimal.Decimal,
+	merchant *entity.Merchant,
+	rrn, stan string,
+) (*entity.CardTransaction, error) {
+	var attempt, result *entity.CardTransaction
+
 	err := s.cardTransactionRepository.WithTx(ctx, func(ctx context.Context) error {
-		fromCard, err := s.cardRepository.FindByID(ctx, cardID)
+		fromCard, err := s.cardRepository.LockByID(ctx, cardID)
 		if err != nil {
 			s.logger.Error("failed to find source card", "error", err)
 			return fmt.Errorf("failed to find source card: %w", err)
 		}
 
+		if rrn != "" {
+			existing, err := s.cardTransactionRepository.FindByRRN(ctx, rrn)
+			if err == nil && (existing.CardID != cardID || !existing.Amount.Equal(amount)) {
+				s.logger.Warn("rrn belongs to another card operation", "card_id", cardID, "rrn", rrn, "transaction_id", existing.ID)
+				return entity.ErrDuplicateRRN
+			}
+			if err == nil {
+				s.logger.Info("duplicate card operation", "card_id", cardID, "rrn", rrn, "transaction_id", existing.ID)
+				result = existing
+				return nil
+			}
+			if !errors.Is(err, entity.ErrCardTransactionNotFound) {
+				s.logger.Error("failed to find card operation", "error", err)
+				return fmt.Errorf("failed to find card operation: %w", err)
+			}
+		}
+
+		attempt = &entity.CardTransaction{
+			CardID:          cardID,
+			Amount:          amount,
+			TransactionType: debitType(merchant),
+			Direction:       entity.DebitDirection,
+			Merchant:        merchant,
+			RRN:             rrn,
+			STAN:            stan,
+		}
+		if merchant != nil {
+			attempt.Counterparty = merchant.Name
+		}
+
+		if err := fromCard.CanDebit(amount, time.Now()); err != nil {
+			s.logger.Warn("card cannot be debited", "card_id", cardID, "error", err)
+			return fmt.Errorf("card cannot be debited: %w", err)
+		}
+
+		if err := s.checkRules(ctx, fromCard, amount, merchant); err != nil {
+			s.logger.Warn("operation rejected by card rules", "card_id", cardID, "error", err)
+			return err
+		}
+
 		fromAccount, err := s.accountService.GetAccountByID(ctx, fromCard.AccountID)
 		if err != nil {
 			s.logger.Error("failed to find source account", "error", err)
@@ -246,27 +625,35 @@ func (s *CardService) Withdraw(ctx context.Context, cardID int32, amount decimal
 			return fmt.Errorf("failed to withdraw amount: %w", err)
 		}
 
-		transactionID, err := s.cardTransactionRepository.Withdraw(ctx, cardID, amount)
+		transactionID, err := s.cardTransactionRepository.Create(ctx, succeeded(attempt))
 		if err != nil {
 			s.logger.Error("failed to withdraw money", "error", err)
 			return fmt.Errorf("failed to withdraw money: %w", err)
 		}
 
+		if err := s.registerDebit(ctx, fromCard, amount); err != nil {
+			return err
+		}
+
+		result = attempt
 		s.logger.Info("money withdrawn successfully", "transaction_id", transactionID, "card_id", cardID, "amount", amount)
 		return nil
 	})
 	if err != nil {
 		s.logger.Error("transaction failed", "error", err)
-		return fmt.Errorf("transaction failed: %w", err)
+		s.recordFailed(ctx, attempt)
+		return nil, fmt.Errorf("transaction failed: %w", err)
 	}
 
 	s.logger.Info("transaction completed successfully", "card_id", cardID, "amount", amount)
-	return nil
+	return result, nil
 }
 
 // Deposit пополняет баланс карты на указанную сумму.
 // Возвращает ошибку, если операция завершилась неуспешно.
 func (s *CardService) Deposit(ctx context.Context, cardID int32, amount decimal.Decimal) error {
+	var attempt *entity.CardTransaction
+
 	err := s.cardTransactionRepository.WithTx(ctx, func(ctx context.Context) error {
 		card, err := s.cardRepository.FindByID(ctx, cardID)
 		if err != nil {
@@ -274,6 +661,18 @@ func (s *CardService) Deposit(ctx context.Context, cardID int32, amount decimal.
 			return fmt.Errorf("failed to find source card: %w", err)
 		}
 
+		attempt = &entity.CardTransaction{
+			CardID:          cardID,
+			Amount:          amount,
+			TransactionType: entity.DepositTransaction,
+			Direction:       entity.CreditDirection,
+		}
+
+		if err := card.CanCredit(time.Now()); err != nil {
+			s.logger.Warn("card cannot be credited", "card_id", cardID, "error", err)
+			return fmt.Errorf("card cannot be credited: %w", err)
+		}
+
 		account, err := s.accountService.GetAccountByID(ctx, card.AccountID)
 		if err != nil {
 			s.logger.Error("failed to find source account", "error", err)
@@ -286,7 +685,7 @@ func (s *CardService) Deposit(ctx context.Context, cardID int32, amount decimal.
 			return fmt.Errorf("failed to deposit amount: %w", err)
 		}
 
-		transactionID, err := s.cardTransactionRepository.Deposit(ctx, cardID, amount)
+		transactionID, err := s.cardTransactionRepository.Create(ctx, succeeded(attempt))
 		if err != nil {
 			s.logger.Error("failed to deposit money", "error", err)
 			return fmt.Errorf("failed to deposit money: %w", err)
@@ -297,12 +696,104 @@ func (s *CardService) Deposit(ctx context.Context, cardID int32, amount decimal.
 	})
 	if err != nil {
 		s.logger.Error("transaction failed", "error", err)
+		s.recordFailed(ctx, attempt)
 		return fmt.Errorf("transaction failed: %w", err)
 	}
 	s.logger.Info("transaction completed successfully", "card_id", cardID, "amount", amount)
 	return nil
 }
 
+// ReverseByRRN сторнирует одобренное списание платежной сети с номером rrn: возвращает списанную сумму на карту
+// и отмечает операцию сторнированной. В отличие от Deposit зачисление выполняется независимо от статуса карты,
+// а списание исключается из учета лимита карты. Операция перечитывается после блокировки ее карты, поэтому повторное
+// сторно подтверждается без повторного зачисления. Возвращает entity.ErrCardTransactionNotFound, если списания нет.
+func (s *CardService) ReverseByRRN(ctx context.Context, rrn string) (*entity.CardTransaction, error) {
+	original, err := s.cardTransactionRepository.FindByRRN(ctx, rrn)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cardTransactionRepository.WithTx(ctx, func(ctx context.Context) error {
+		card, err := s.cardRepository.LockByID(ctx, original.CardID)
+		if err != nil {
+			s.logger.Error("failed to find card", "error", err)
+			return fmt.Errorf("failed to find card: %w", err)
+		}
+
+		original, err = s.cardTransactionRepository.FindByRRN(ctx, rrn)
+		if err != nil {
+			return err
+		}
+
+		if original.ReversedAt != nil {
+			s.logger.Info("card operation already reversed", "card_id", card.ID, "rrn", rrn)
+			return nil
+		}
+
+		if err := s.accountService.Deposit(ctx, card.AccountID, original.Amount); err != nil {
+			s.logger.Error("failed to deposit amount", "error", err)
+			return fmt.Errorf("failed to deposit amount: %w", err)
+		}
+
+		transactionID, err := s.cardTransactionRepository.Create(ctx, &entity.CardTransaction{
+			CardID:          card.ID,
+			Amount:          original.Amount,
+			TransactionType: entity.DepositTransaction,
+			Direction:       entity.CreditDirection,
+			Status:          entity.TransactionSuccess,
+			RRN:             rrn,
+		})
+		if err != nil {
+			s.logger.Error("failed to deposit money", "error", err)
+			return fmt.Errorf("failed to deposit money: %w", err)
+		}
+
+		reversedAt := time.Now()
+		if err := s.cardTransactionRepository.MarkReversed(ctx, original.ID, reversedAt); err != nil {
+			s.logger.Error("failed to mark operation reversed", "error", err)
+			return fmt.Errorf("failed to mark operation reversed: %w", err)
+		}
+		original.ReversedAt = &reversedAt
+
+		if card.Kind == entity.SingleUseCard || card.SpendLimit.Valid {
+			card.RevertDebit(original.Amount)
+			if _, err := s.cardRepository.Update(ctx, card); err != nil {
+				s.logger.Error("failed to update card", "error", err)
+				return fmt.Errorf("failed to update card: %w", err)
+			}
+		}
+
+		s.logger.Info("operation reversed successfully", "transaction_id", transactionID, "card_id", card.ID, "amount", original.Amount)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("transaction failed", "error", err)
+		return nil, fmt.Errorf("transaction failed: %w", err)
+	}
+
+	return original, nil
+}
+
+// registerDebit учитывает списание по карте с ограничениями на использование и сохраняет ее новое состояние.
+// Одноразовая карта после первого списания закрывается.
+func (s *CardService) registerDebit(ctx context.Context, card *entity.Card, amount decimal.Decimal) error {
+	if card.Kind != entity.SingleUseCard && !card.SpendLimit.Valid {
+		return nil
+	}
+
+	card.RegisterDebit(amount)
+	if _, err := s.cardRepository.Update(ctx, card); err != nil {
+		s.logger.Error("failed to update card", "error", err)
+		return fmt.Errorf("failed to update card: %w", err)
+	}
+
+	if card.Status == entity.CardClosed {
+		s.logger.Info("single-use card closed", "card_id", card.ID)
+	}
+
+	return nil
+}
+
 // generateCVV генерирует случайный CVV код длиной 3 символа в виде строки.
 func generateCVV() string {
 	rand.Seed(time.Now().UnixNano())
@@ -392,7 +883,7 @@ func (s *CardService) encryptCardDataPGP(card *entity.Card, publicKeyPath string
 		return "", fmt.Errorf("could not read public key: %v", err)
 	}
 
-	cardData := fmt.Sprintf("%s:%s:%s", card.CardNumber, card.CVV, card.ExpirationDate)
+	cardData := fmt.Sprintf("%s:%s:%s", card.CardNumber, card.CVV, card.ExpirationDate.Format("2006-01-02"))
 
 	var encryptedData bytes.Buffer
 
@@ -452,6 +943,11 @@ func (s *CardService) decryptCardData(encryptedData string, privateKeyPath strin
 	return decryptedData.String(), nil
 }
 
+// panIndex вычисляет слепой индекс номера карты — HMAC-SHA256 с секретным ключом, не позволяющий восстановить номер.
+func (s *CardService) panIndex(pan entity.PAN) string {
+	return s.generateHMAC(pan.String(), s.panIndexKey)
+}
+
 // generateHMAC генерирует HMAC-хэш строки `data` с использованием заданного секретного ключа `secretKey`.
 func (s *CardService) generateHMAC(data string, secretKey string) string {
 	hash := hmac.New(sha256.New, []byte(secretKey))
@@ -0,0 +1,105 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// GetRules возвращает ограничения, установленные для карты.
+func (s *CardService) GetRules(ctx context.Context, cardID int32) (*entity.CardRules, error) {
+	rules, err := s.cardRuleRepository.GetByCardID(ctx, cardID)
+	if err != nil {
+		s.logger.Error("failed to get card rules", "error", err)
+		return nil, fmt.Errorf("failed to get card rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SetRules проверяет и сохраняет ограничения для карты, заменяя ранее установленные.
+func (s *CardService) SetRules(ctx context.Context, rules *entity.CardRules) (*entity.CardRules, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid card rules: %w", err)
+	}
+
+	savedRules, err := s.cardRuleRepository.Save(ctx, rules)
+	if err != nil {
+		s.logger.Error("failed to save card rules", "error", err)
+		return nil, fmt.Errorf("failed to save card rules: %w", err)
+	}
+
+	s.logger.Info("card rules updated successfully", "card_id", rules.CardID)
+	return savedRules, nil
+}
+
+// checkRules загружает правила карты и траты за текущий месяц и проверяет, допустима ли операция.
+// Вызывается в транзакции списания после блокировки строки карты card, поэтому параллельные списания по карте
+// учитывают траты друг друга и не превышают месячный лимит.
+func (s *CardService) checkRules(ctx context.Context, card *entity.Card, amount decimal.Decimal, merchant *entity.Merchant) error {
+	cardID := card.ID
+	rules, err := s.cardRuleRepository.GetByCardID(ctx, cardID)
+	if err != nil {
+		return fmt.Errorf("failed to get card rules: %w", err)
+	}
+
+	if rules.IsEmpty() {
+		return nil
+	}
+
+	spent := decimal.Zero
+	if merchant != nil {
+		if _, ok := rules.MonthlyLimits[merchant.MCC.Group()]; ok {
+			now := time.Now()
+			monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+			sums, err := s.cardTransactionRepository.SumByMCCSince(ctx, cardID, monthStart)
+			if err != nil {
+				return fmt.Errorf("failed to get monthly spending: %w", err)
+			}
+
+			for mcc, sum := range sums {
+				if mcc.Group() == merchant.MCC.Group() {
+					spent = spent.Add(sum)
+				}
+			}
+		}
+	}
+
+	return checkCardRules(rules, merchant, amount, spent)
+}
+
+// checkCardRules проверяет операцию на сумму amount в торговой точке merchant по правилам карты.
+// spent — сумма, уже потраченная в текущем месяце в группе MCC торговой точки.
+// Возвращает *entity.CardRuleViolationError с кодом причины, если операция нарушает правила.
+func checkCardRules(rules *entity.CardRules, merchant *entity.Merchant, amount, spent decimal.Decimal) error {
+	if rules.IsEmpty() {
+		return nil
+	}
+
+	// Без сведений о торговой точке нельзя проверить ни одно из ограничений.
+	if merchant == nil {
+		return &entity.CardRuleViolationError{Reason: entity.CardRuleMerchantRequired}
+	}
+
+	group := merchant.MCC.Group()
+	if rules.IsBlocked(group) {
+		return &entity.CardRuleViolationError{Reason: entity.CardRuleMCCBlocked, Group: group}
+	}
+
+	if rules.OnlineOnly && !merchant.Online {
+		return &entity.CardRuleViolationError{Reason: entity.CardRuleOnlineOnly}
+	}
+
+	if rules.DomesticOnly && !merchant.IsDomestic() {
+		return &entity.CardRuleViolationError{Reason: entity.CardRuleDomesticOnly}
+	}
+
+	if limit, ok := rules.MonthlyLimits[group]; ok && spent.Add(amount).GreaterThan(limit) {
+		return &entity.CardRuleViolationError{Reason: entity.CardRuleCategoryLimitExceeded, Group: group}
+	}
+
+	return nil
+}
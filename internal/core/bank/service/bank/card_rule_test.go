@@ -0,0 +1,235 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCheckCardRules(t *testing.T) {
+	grocery := &entity.Merchant{Name: "Магнит", MCC: "5411", Country: "RU"}
+	casino := &entity.Merchant{Name: "Casino", MCC: "7995", Country: "RU", Online: true}
+	foreignShop := &entity.Merchant{Name: "Shop", MCC: "5411", Country: "DE", Online: true}
+
+	testCases := []struct {
+		name     string
+		rules    *entity.CardRules
+		merchant *entity.Merchant
+		amount   decimal.Decimal
+		spent    decimal.Decimal
+		reason   entity.CardRuleReason
+	}{
+		{
+			name:     "no rules allow any operation",
+			rules:    &entity.CardRules{CardID: 1},
+			merchant: nil,
+			amount:   decimal.NewFromInt(100),
+		},
+		{
+			name:     "merchant is required when rules are set",
+			rules:    &entity.CardRules{CardID: 1, DomesticOnly: true},
+			merchant: nil,
+			amount:   decimal.NewFromInt(100),
+			reason:   entity.CardRuleMerchantRequired,
+		},
+		{
+			name:     "blocked mcc group",
+			rules:    &entity.CardRules{CardID: 1, BlockedMCCGroups: []entity.MCCGroup{entity.MCCGroupGambling}},
+			merchant: casino,
+			amount:   decimal.NewFromInt(100),
+			reason:   entity.CardRuleMCCBlocked,
+		},
+		{
+			name:     "online only rejects card present operation",
+			rules:    &entity.CardRules{CardID: 1, OnlineOnly: true},
+			merchant: grocery,
+			amount:   decimal.NewFromInt(100),
+			reason:   entity.CardRuleOnlineOnly,
+		},
+		{
+			name:     "domestic only rejects foreign merchant",
+			rules:    &entity.CardRules{CardID: 1, DomesticOnly: true},
+			merchant: foreignShop,
+			amount:   decimal.NewFromInt(100),
+			reason:   entity.CardRuleDomesticOnly,
+		},
+		{
+			name: "monthly limit exceeded",
+			rules: &entity.CardRules{CardID: 1, MonthlyLimits: map[entity.MCCGroup]decimal.Decimal{
+				entity.MCCGroupGrocery: decimal.NewFromInt(1000),
+			}},
+			merchant: grocery,
+			amount:   decimal.NewFromInt(300),
+			spent:    decimal.NewFromInt(800),
+			reason:   entity.CardRuleCategoryLimitExceeded,
+		},
+		{
+			name: "monthly limit reached exactly",
+			rules: &entity.CardRules{CardID: 1, MonthlyLimits: map[entity.MCCGroup]decimal.Decimal{
+				entity.MCCGroupGrocery: decimal.NewFromInt(1000),
+			}},
+			merchant: grocery,
+			amount:   decimal.NewFromInt(200),
+			spent:    decimal.NewFromInt(800),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkCardRules(tc.rules, tc.merchant, tc.amount, tc.spent)
+			if tc.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var violation *entity.CardRuleViolationError
+			assert.True(t, errors.As(err, &violation))
+			assert.Equal(t, tc.reason, violation.Reason)
+			assert.ErrorIs(t, err, entity.ErrCardRuleViolation)
+		})
+	}
+}
+
+func TestMCC_Group(t *testing.T) {
+	assert.Equal(t, entity.MCCGroupGambling, entity.MCC("7995").Group())
+	assert.Equal(t, entity.MCCGroupTravel, entity.MCC("3012").Group())
+	assert.Equal(t, entity.MCCGroupOther, entity.MCC("1234").Group())
+}
+
+func TestCardService_SetRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	testCases := []struct {
+		name     string
+		rules    *entity.CardRules
+		mockFunc func(m *MockCardRuleRepository)
+		wantErr  bool
+	}{
+		{
+			name:  "successful save",
+			rules: &entity.CardRules{CardID: 1, BlockedMCCGroups: []entity.MCCGroup{entity.MCCGroupGambling}},
+			mockFunc: func(m *MockCardRuleRepository) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, rules *entity.CardRules) (*entity.CardRules, error) {
+						return rules, nil
+					})
+			},
+		},
+		{
+			name:    "unknown mcc group",
+			rules:   &entity.CardRules{CardID: 1, BlockedMCCGroups: []entity.MCCGroup{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name: "negative monthly limit",
+			rules: &entity.CardRules{CardID: 1, MonthlyLimits: map[entity.MCCGroup]decimal.Decimal{
+				entity.MCCGroupCash: decimal.NewFromInt(-1),
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewMockCardRuleRepository(ctrl)
+			if tc.mockFunc != nil {
+				tc.mockFunc(repo)
+			}
+
+			service := &CardService{cardRuleRepository: repo, logger: logger}
+			got, err := service.SetRules(context.TODO(), tc.rules)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.rules, got)
+			}
+		})
+	}
+}
+
+func TestCardService_Transfer_AppliesCardRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	testCases := []struct {
+		name       string
+		rules      *entity.CardRules
+		spent      map[entity.MCC]decimal.Decimal
+		wantReason entity.CardRuleReason
+	}{
+		{
+			name:       "transfers are blocked",
+			rules:      &entity.CardRules{CardID: 1, BlockedMCCGroups: []entity.MCCGroup{entity.MCCGroupTransfers}},
+			wantReason: entity.CardRuleMCCBlocked,
+		},
+		{
+			name: "monthly transfer limit is exceeded",
+			rules: &entity.CardRules{CardID: 1, MonthlyLimits: map[entity.MCCGroup]decimal.Decimal{
+				entity.MCCGroupTransfers: decimal.NewFromInt(1000),
+			}},
+			spent:      map[entity.MCC]decimal.Decimal{entity.TransferMCC: decimal.NewFromInt(900)},
+			wantReason: entity.CardRuleCategoryLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			toCardID := int32(2)
+
+			cardRepo := NewMockCardRepository(ctrl)
+			cardRepo.EXPECT().LockByID(gomock.Any(), int32(1)).Return(&entity.Card{
+				ID:             1,
+				Kind:           entity.VirtualCard,
+				Status:         entity.CardActive,
+				ExpirationDate: time.Now().AddDate(1, 0, 0),
+			}, nil)
+
+			ruleRepo := NewMockCardRuleRepository(ctrl)
+			ruleRepo.EXPECT().GetByCardID(gomock.Any(), int32(1)).Return(tc.rules, nil)
+
+			transactionRepo := NewMockCardTransactionRepository(ctrl)
+			transactionRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+					return fn(ctx)
+				})
+			if tc.spent != nil {
+				transactionRepo.EXPECT().SumByMCCSince(gomock.Any(), int32(1), gomock.Any()).Return(tc.spent, nil)
+			}
+			transactionRepo.EXPECT().Create(gomock.Any(), &entity.CardTransaction{
+				CardID:             1,
+				Amount:             decimal.NewFromInt(200),
+				TransactionType:    entity.TransferTransaction,
+				Direction:          entity.DebitDirection,
+				Status:             entity.TransactionFailed,
+				CounterpartyCardID: &toCardID,
+				Merchant:           entity.TransferMerchant(),
+			}).Return(int32(10), nil)
+
+			service := &CardService{
+				cardRepository:            cardRepo,
+				cardTransactionRepository: transactionRepo,
+				cardRuleRepository:        ruleRepo,
+				logger:                    logger,
+			}
+			err := service.Transfer(context.TODO(), 1, toCardID, decimal.NewFromInt(200))
+
+			var violation *entity.CardRuleViolationError
+			assert.True(t, errors.As(err, &violation))
+			assert.Equal(t, tc.wantReason, violation.Reason)
+			assert.Equal(t, entity.MCCGroupTransfers, violation.Group)
+		})
+	}
+}
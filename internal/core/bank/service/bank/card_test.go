@@ -1,7 +1,20 @@
 package bank
 
 import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGenerateCVV(t *testing.T) {
@@ -17,3 +30,392 @@ func TestGenerateCardNumber(t *testing.T) {
 		t.Errorf("invalid card number length, got %d, want 16", len(cardNumber))
 	}
 }
+
+func TestCard_CanDebit(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		card   entity.Card
+		amount decimal.Decimal
+		err    error
+	}{
+		{
+			name:   "active physical card",
+			card:   entity.Card{Kind: entity.PhysicalCard, Status: entity.CardActive},
+			amount: decimal.NewFromInt(100),
+		},
+		{
+			name:   "closed single-use card",
+			card:   entity.Card{Kind: entity.SingleUseCard, Status: entity.CardClosed},
+			amount: decimal.NewFromInt(100),
+			err:    entity.ErrCardNotActive,
+		},
+		{
+			name: "expired limited-use card",
+			card: entity.Card{
+				Kind:           entity.LimitedUseCard,
+				Status:         entity.CardActive,
+				ExpirationDate: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+				SpendLimit:     decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+			},
+			amount: decimal.NewFromInt(100),
+			err:    entity.ErrCardExpired,
+		},
+		{
+			name: "limited-use card valid through expiration day",
+			card: entity.Card{
+				Kind:           entity.LimitedUseCard,
+				Status:         entity.CardActive,
+				ExpirationDate: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC),
+				SpendLimit:     decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+			},
+			amount: decimal.NewFromInt(100),
+		},
+		{
+			name: "expired physical card",
+			card: entity.Card{
+				Kind:           entity.PhysicalCard,
+				Status:         entity.CardActive,
+				ExpirationDate: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+			},
+			amount: decimal.NewFromInt(100),
+			err:    entity.ErrCardExpired,
+		},
+		{
+			name:   "card marked as expired",
+			card:   entity.Card{Kind: entity.PhysicalCard, Status: entity.CardExpired},
+			amount: decimal.NewFromInt(100),
+			err:    entity.ErrCardExpired,
+		},
+		{
+			name: "spend limit exceeded",
+			card: entity.Card{
+				Kind:           entity.LimitedUseCard,
+				Status:         entity.CardActive,
+				ExpirationDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				SpendLimit:     decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+				SpentAmount:    decimal.NewFromInt(950),
+			},
+			amount: decimal.NewFromInt(100),
+			err:    entity.ErrCardLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.card.CanDebit(tc.amount, now)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestCard_RegisterDebit(t *testing.T) {
+	card := entity.Card{Kind: entity.SingleUseCard, Status: entity.CardActive}
+	card.RegisterDebit(decimal.NewFromInt(100))
+
+	assert.Equal(t, entity.CardClosed, card.Status)
+	assert.True(t, decimal.NewFromInt(100).Equal(card.SpentAmount))
+}
+
+func TestCard_RevertDebit(t *testing.T) {
+	card := entity.Card{Kind: entity.SingleUseCard, Status: entity.CardClosed, SpentAmount: decimal.NewFromInt(100)}
+	card.RevertDebit(decimal.NewFromInt(150))
+
+	assert.Equal(t, entity.CardActive, card.Status)
+	assert.True(t, card.SpentAmount.IsZero())
+}
+
+func TestCardService_FindByPAN_InvalidPAN(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	service := &CardService{logger: logger}
+
+	_, err := service.FindByPAN(context.TODO(), "4111111111111112")
+	assert.ErrorIs(t, err, entity.ErrCardNotFound)
+}
+
+func TestCardService_Create_InvalidLimitedUseCard(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	service := &CardService{logger: logger}
+	limit := WithSpendLimit(decimal.NewFromInt(1000))
+
+	testCases := []struct {
+		name string
+		opts []CardOption
+	}{
+		{name: "without spend limit", opts: []CardOption{WithExpirationDate(time.Now().AddDate(0, 1, 0))}},
+		{name: "without expiration date", opts: []CardOption{limit}},
+		{name: "expiration date in the past", opts: []CardOption{limit, WithExpirationDate(time.Now().AddDate(0, 0, -1))}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]CardOption{WithCardKind(entity.LimitedUseCard)}, tc.opts...)
+			_, err := service.Create(context.TODO(), &entity.Account{ID: 1}, opts...)
+			assert.ErrorIs(t, err, entity.ErrInvalidCard)
+		})
+	}
+}
+
+func TestPAN_Validate(t *testing.T) {
+	assert.NoError(t, entity.PAN(generateCardNumber(42)).Validate())
+	assert.NoError(t, entity.PAN("4111111111111111").Validate())
+	assert.Error(t, entity.PAN("4111111111111112").Validate())
+	assert.Error(t, entity.PAN("411111111111").Validate())
+}
+
+func TestMaskHolderName(t *testing.T) {
+	assert.Equal(t, "Иван П.", entity.MaskHolderName("Иван", "Петров"))
+	assert.Equal(t, "Иван", entity.MaskHolderName("Иван", ""))
+	assert.Equal(t, "П.", entity.MaskHolderName("", "Петров"))
+}
+
+func TestCardService_ResolveRecipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	testCases := []struct {
+		name     string
+		pan      entity.PAN
+		mockFunc func(m *MockCardRepository)
+		expected *entity.CardRecipient
+		err      error
+	}{
+		{
+			name: "recipient found",
+			pan:  "4111111111111111",
+			mockFunc: func(m *MockCardRepository) {
+				m.EXPECT().FindRecipientByPANIndex(gomock.Any(), gomock.Any()).Return(&entity.CardRecipient{
+					CardID:     7,
+					Status:     entity.CardActive,
+					MaskedName: "Иван П.",
+				}, nil)
+			},
+			expected: &entity.CardRecipient{
+				CardID:     7,
+				Status:     entity.CardActive,
+				MaskedPAN:  "**** 1111",
+				MaskedName: "Иван П.",
+			},
+		},
+		{
+			name: "recipient card is blocked",
+			pan:  "4111111111111111",
+			mockFunc: func(m *MockCardRepository) {
+				m.EXPECT().FindRecipientByPANIndex(gomock.Any(), gomock.Any()).Return(&entity.CardRecipient{
+					CardID: 7,
+					Status: entity.CardBlocked,
+				}, nil)
+			},
+			err: entity.ErrCardNotActive,
+		},
+		{
+			name: "recipient card not found",
+			pan:  "4111111111111111",
+			mockFunc: func(m *MockCardRepository) {
+				m.EXPECT().FindRecipientByPANIndex(gomock.Any(), gomock.Any()).Return(nil, entity.ErrCardNotFound)
+			},
+			err: entity.ErrCardNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewMockCardRepository(ctrl)
+			tc.mockFunc(repo)
+
+			service := &CardService{cardRepository: repo, panIndexKey: "secret", logger: logger}
+			got, err := service.ResolveRecipient(context.TODO(), tc.pan)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCardService_PANIndex(t *testing.T) {
+	service := &CardService{panIndexKey: "secret"}
+
+	index := service.panIndex("4111111111111111")
+	assert.Equal(t, index, service.panIndex("4111111111111111"))
+	assert.NotEqual(t, index, service.panIndex("4012888888881881"))
+	assert.NotContains(t, index, "4111111111111111")
+}
+
+func TestCardService_ExpireCards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	repo := NewMockCardRepository(ctrl)
+	repo.EXPECT().ExpireOutdated(gomock.Any(), now).Return([]int32{1, 2}, nil)
+
+	service := &CardService{cardRepository: repo, logger: logger}
+	assert.NoError(t, service.ExpireCards(context.TODO(), now))
+}
+
+func TestCardService_ReissueExpiringCards_UsesLeadTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	repo := NewMockCardRepository(ctrl)
+	repo.EXPECT().FindExpiring(gomock.Any(), now.Add(30*24*time.Hour)).Return(nil, nil)
+
+	service := &CardService{cardRepository: repo, reissueLeadTime: 30 * 24 * time.Hour, logger: logger}
+	assert.NoError(t, service.ReissueExpiringCards(context.TODO(), now))
+}
+
+func TestCardService_CreateAndFindByID_RoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	publicKeyPath, privateKeyPath := writeTestPGPKeys(t)
+	account := &entity.Account{ID: 7, AccountNumber: "40817810000000000007"}
+
+	var saved entity.Card
+	cardRepository := NewMockCardRepository(ctrl)
+	cardRepository.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, card *entity.Card) (*entity.Card, error) {
+			saved = *card
+			saved.ID = 1
+			return &saved, nil
+		},
+	)
+	// Из хранилища карта возвращается без открытых номера и CVV — только зашифрованные данные.
+	cardRepository.EXPECT().FindByID(gomock.Any(), int32(1)).DoAndReturn(
+		func(context.Context, int32) (*entity.Card, error) {
+			stored := saved
+			stored.CardNumber, stored.CVV = "", ""
+			return &stored, nil
+		},
+	)
+
+	accountRepository := NewMockAccountRepository(ctrl)
+	accountRepository.EXPECT().FindByID(gomock.Any(), account.ID).Return(account, nil)
+
+	service := &CardService{
+		cardRepository: cardRepository,
+		accountService: &AccountService{repo: accountRepository, logger: logger},
+		publicKeyPath:  publicKeyPath,
+		privateKeyPath: privateKeyPath,
+		logger:         logger,
+	}
+
+	created, err := service.Create(context.TODO(), account)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	found, err := service.FindByID(context.TODO(), created.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, created.CardNumber, found.CardNumber)
+	assert.Equal(t, created.CVV, found.CVV)
+	assert.Equal(t, created.ExpirationDate.Format("2006-01-02"), found.ExpirationDate.Format("2006-01-02"))
+}
+
+// writeTestPGPKeys создает пару ключей PGP без парольной фразы и возвращает пути к публичному и приватному ключам.
+func writeTestPGPKeys(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := openpgp.NewEntity("card vault", "test", "vault@example.com", nil)
+	assert.NoError(t, err)
+
+	// Без явных предпочтений в подписи ключ допускает только RIPEMD160, который не входит в стандартную библиотеку.
+	for _, identity := range key.Identities {
+		identity.SelfSignature.PreferredHash = []uint8{8} // SHA256
+		assert.NoError(t, identity.SelfSignature.SignUserId(identity.UserId.Id, key.PrimaryKey, key.PrivateKey, nil))
+	}
+
+	dir := t.TempDir()
+	write := func(name, blockType string, serialize func(io.Writer) error) string {
+		path := filepath.Join(dir, name)
+		file, err := os.Create(path)
+		assert.NoError(t, err)
+		defer file.Close()
+
+		w, err := armor.Encode(file, blockType, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, serialize(w))
+		assert.NoError(t, w.Close())
+		return path
+	}
+
+	publicKeyPath := write("public.asc", openpgp.PublicKeyType, key.Serialize)
+	privateKeyPath := write("private.asc", openpgp.PrivateKeyType, func(w io.Writer) error {
+		return key.SerializePrivate(w, nil)
+	})
+
+	return publicKeyPath, privateKeyPath
+}
+
+func TestCardService_BackfillPANIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	publicKeyPath, privateKeyPath := writeTestPGPKeys(t)
+	account := &entity.Account{ID: 7, AccountNumber: "40817810000000000007"}
+
+	cardRepository := NewMockCardRepository(ctrl)
+	accountRepository := NewMockAccountRepository(ctrl)
+	accountRepository.EXPECT().FindByID(gomock.Any(), account.ID).Return(account, nil).AnyTimes()
+
+	service := &CardService{
+		cardRepository: cardRepository,
+		accountService: &AccountService{repo: accountRepository, logger: logger},
+		publicKeyPath:  publicKeyPath,
+		privateKeyPath: privateKeyPath,
+		panIndexKey:    "index-key",
+		logger:         logger,
+	}
+
+	issue := func(id int32, hmacKey string) entity.Card {
+		card := entity.Card{
+			ID:             id,
+			AccountID:      account.ID,
+			CardNumber:     generateCardNumber(account.ID),
+			CVV:            generateCVV(),
+			ExpirationDate: time.Date(2028, 6, 30, 0, 0, 0, 0, time.UTC),
+		}
+		card.HMAC = service.generateHMAC(fmt.Sprintf("%s:%s:%s", card.CardNumber, card.CVV, card.ExpirationDate.Format("2006-01-02")), hmacKey)
+
+		encryptedData, err := service.encryptCardDataPGP(&card, publicKeyPath, nil)
+		assert.NoError(t, err)
+
+		stored := card
+		stored.CardNumber, stored.CVV, stored.EncryptedData = "", "", encryptedData
+		return stored
+	}
+
+	valid := issue(1, account.AccountNumber.String())
+	tampered := issue(2, "another-account")
+
+	cardRepository.EXPECT().FindWithoutPANIndex(gomock.Any(), int32(0), panIndexBatchSize).Return([]entity.Card{valid, tampered}, nil)
+
+	var index string
+	cardRepository.EXPECT().SetPANIndex(gomock.Any(), int32(1), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int32, panIndex string) error {
+			index = panIndex
+			return nil
+		},
+	)
+
+	count, err := service.BackfillPANIndex(context.TODO())
+	assert.EqualError(t, err, "failed to index 1 of 2 cards")
+	assert.Equal(t, 1, count)
+	assert.Len(t, index, 64)
+}
@@ -0,0 +1,135 @@
+package bank
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+)
+
+const (
+	defaultTransactionsPageSize = 20
+	maxTransactionsPageSize     = 100
+)
+
+// ListTransactions возвращает страницу истории операций по карте, подходящих под фильтр, и итоги по всему фильтру.
+// cursor — значение NextCursor предыдущей страницы; пустой курсор означает первую страницу.
+// limit ограничивает размер страницы; при нулевом значении используется 20 операций, максимум — 100.
+func (s *CardService) ListTransactions(
+	ctx context.Context,
+	filter entity.CardTransactionFilter,
+	cursor string,
+	limit int,
+) (*entity.CardTransactionPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.SortByDate
+	}
+	if filter.Order == "" {
+		filter.Order = entity.SortDesc
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidTransactionFilter, err)
+	}
+
+	if limit <= 0 {
+		limit = defaultTransactionsPageSize
+	}
+	limit = min(limit, maxTransactionsPageSize)
+
+	after, err := decodeTransactionCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну операцию больше, чтобы узнать, есть ли следующая страница.
+	transactions, err := s.cardTransactionRepository.List(ctx, filter, after, limit+1)
+	if err != nil {
+		s.logger.Error("failed to list card transactions", "error", err)
+		return nil, fmt.Errorf("failed to list card transactions: %w", err)
+	}
+
+	totals, err := s.cardTransactionRepository.Totals(ctx, filter)
+	if err != nil {
+		s.logger.Error("failed to get card transaction totals", "error", err)
+		return nil, fmt.Errorf("failed to get card transaction totals: %w", err)
+	}
+
+	page := &entity.CardTransactionPage{
+		Transactions: transactions,
+		Totals:       *totals,
+	}
+
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+
+		last := page.Transactions[limit-1]
+		page.NextCursor, err = encodeTransactionCursor(&entity.CardTransactionCursor{
+			Date:   last.TransactionDate,
+			Amount: last.Amount,
+			ID:     last.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// succeeded отмечает операцию как успешную и возвращает ее.
+func succeeded(cardTransaction *entity.CardTransaction) *entity.CardTransaction {
+	cardTransaction.Status = entity.TransactionSuccess
+	return cardTransaction
+}
+
+// recordFailed сохраняет отклоненную операцию вне откатившейся транзакции, чтобы попытка осталась в истории карты.
+// attempt равен nil, если операция не дошла до проверки карты.
+func (s *CardService) recordFailed(ctx context.Context, attempt *entity.CardTransaction) {
+	if attempt == nil {
+		return
+	}
+
+	attempt.ID = 0
+	attempt.Status = entity.TransactionFailed
+
+	if _, err := s.cardTransactionRepository.Create(ctx, attempt); err != nil {
+		s.logger.Error("failed to record failed card transaction", "card_id", attempt.CardID, "error", err)
+	}
+}
+
+// debitType определяет тип списания: снятие наличных или без торговой точки — withdrawal, иначе — оплата.
+func debitType(merchant *entity.Merchant) entity.TransactionType {
+	if merchant == nil || merchant.MCC.Group() == entity.MCCGroupCash {
+		return entity.WithdrawalTransaction
+	}
+	return entity.PaymentTransaction
+}
+
+func encodeTransactionCursor(cursor *entity.CardTransactionCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeTransactionCursor(value string) (*entity.CardTransactionCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCursor, err)
+	}
+
+	cursor := &entity.CardTransactionCursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCursor, err)
+	}
+
+	return cursor, nil
+}
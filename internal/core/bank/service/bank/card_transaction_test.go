@@ -0,0 +1,245 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCardService_ListTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	date := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	transactions := []entity.CardTransaction{
+		{ID: 3, CardID: 1, Amount: decimal.NewFromInt(300), TransactionDate: date.Add(2 * time.Hour)},
+		{ID: 2, CardID: 1, Amount: decimal.NewFromInt(200), TransactionDate: date.Add(time.Hour)},
+		{ID: 1, CardID: 1, Amount: decimal.NewFromInt(100), TransactionDate: date},
+	}
+	totals := &entity.CardTransactionTotals{Count: 3, Debit: decimal.NewFromInt(600)}
+
+	cursor, err := encodeTransactionCursor(&entity.CardTransactionCursor{Date: date.Add(time.Hour), Amount: decimal.NewFromInt(200), ID: 2})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		filter     entity.CardTransactionFilter
+		cursor     string
+		limit      int
+		mockFunc   func(m *MockCardTransactionRepository)
+		wantCount  int
+		wantCursor string
+		wantErr    error
+	}{
+		{
+			name:   "first page has next cursor",
+			filter: entity.CardTransactionFilter{CardID: 1},
+			limit:  2,
+			mockFunc: func(m *MockCardTransactionRepository) {
+				m.EXPECT().List(gomock.Any(), entity.CardTransactionFilter{CardID: 1, Sort: entity.SortByDate, Order: entity.SortDesc}, gomock.Nil(), 3).
+					Return(transactions, nil)
+				m.EXPECT().Totals(gomock.Any(), gomock.Any()).Return(totals, nil)
+			},
+			wantCount:  2,
+			wantCursor: cursor,
+		},
+		{
+			name:   "last page has no cursor",
+			filter: entity.CardTransactionFilter{CardID: 1, Sort: entity.SortByAmount, Order: entity.SortAsc},
+			cursor: cursor,
+			limit:  2,
+			mockFunc: func(m *MockCardTransactionRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any(), &entity.CardTransactionCursor{Date: date.Add(time.Hour), Amount: decimal.NewFromInt(200), ID: 2}, 3).
+					Return(transactions[2:], nil)
+				m.EXPECT().Totals(gomock.Any(), gomock.Any()).Return(totals, nil)
+			},
+			wantCount: 1,
+		},
+		{
+			name:    "unknown transaction type",
+			filter:  entity.CardTransactionFilter{CardID: 1, Types: []entity.TransactionType{"refund"}},
+			wantErr: entity.ErrInvalidTransactionFilter,
+		},
+		{
+			name:    "period end before start",
+			filter:  entity.CardTransactionFilter{CardID: 1, From: date, To: date.Add(-time.Hour)},
+			wantErr: entity.ErrInvalidTransactionFilter,
+		},
+		{
+			name:    "malformed cursor",
+			filter:  entity.CardTransactionFilter{CardID: 1},
+			cursor:  "not a cursor",
+			wantErr: entity.ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewMockCardTransactionRepository(ctrl)
+			if tc.mockFunc != nil {
+				tc.mockFunc(repo)
+			}
+
+			service := &CardService{cardTransactionRepository: repo, logger: logger}
+			page, err := service.ListTransactions(context.TODO(), tc.filter, tc.cursor, tc.limit)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, page.Transactions, tc.wantCount)
+			assert.Equal(t, tc.wantCursor, page.NextCursor)
+			assert.Equal(t, *totals, page.Totals)
+		})
+	}
+}
+
+func TestCardService_Withdraw_RecordsFailedAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	merchant := &entity.Merchant{Name: "Магнит", MCC: "5411", Country: "RU"}
+
+	cardRepo := NewMockCardRepository(ctrl)
+	cardRepo.EXPECT().LockByID(gomock.Any(), int32(1)).Return(&entity.Card{
+		ID:             1,
+		Kind:           entity.VirtualCard,
+		Status:         entity.CardActive,
+		ExpirationDate: time.Now().AddDate(0, 0, -1),
+	}, nil)
+
+	transactionRepo := NewMockCardTransactionRepository(ctrl)
+	transactionRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+			return fn(ctx)
+		})
+	transactionRepo.EXPECT().Create(gomock.Any(), &entity.CardTransaction{
+		CardID:          1,
+		Amount:          decimal.NewFromInt(100),
+		TransactionType: entity.PaymentTransaction,
+		Direction:       entity.DebitDirection,
+		Status:          entity.TransactionFailed,
+		Counterparty:    merchant.Name,
+		Merchant:        merchant,
+	}).Return(int32(10), nil)
+
+	service := &CardService{cardRepository: cardRepo, cardTransactionRepository: transactionRepo, logger: logger}
+	err := service.Withdraw(context.TODO(), 1, decimal.NewFromInt(100), merchant)
+
+	assert.ErrorIs(t, err, entity.ErrCardExpired)
+}
+
+func TestDebitType(t *testing.T) {
+	assert.Equal(t, entity.WithdrawalTransaction, debitType(nil))
+	assert.Equal(t, entity.WithdrawalTransaction, debitType(&entity.Merchant{MCC: "6011"}))
+	assert.Equal(t, entity.PaymentTransaction, debitType(&entity.Merchant{MCC: "5411"}))
+}
+
+func TestCardService_AuthorizeReturnsDuplicateByRRN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	existing := &entity.CardTransaction{ID: 10, CardID: 1, Amount: decimal.NewFromInt(100), RRN: "000000000010", STAN: "000001"}
+
+	cardRepo := NewMockCardRepository(ctrl)
+	cardRepo.EXPECT().LockByID(gomock.Any(), int32(1)).Return(&entity.Card{ID: 1, Status: entity.CardActive}, nil)
+
+	transactionRepo := NewMockCardTransactionRepository(ctrl)
+	transactionRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+			return fn(ctx)
+		})
+	transactionRepo.EXPECT().FindByRRN(gomock.Any(), "000000000010").Return(existing, nil)
+
+	service := &CardService{cardRepository: cardRepo, cardTransactionRepository: transactionRepo, logger: logger}
+	got, err := service.Authorize(context.TODO(), 1, decimal.NewFromInt(100), nil, "000000000010", "000001")
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, got)
+}
+
+func TestCardService_AuthorizeRejectsRRNOfAnotherOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	existing := &entity.CardTransaction{ID: 10, CardID: 1, Amount: decimal.NewFromInt(100), RRN: "000000000010", STAN: "000001"}
+
+	testCases := []struct {
+		name   string
+		cardID int32
+		amount decimal.Decimal
+	}{
+		{name: "another card", cardID: 2, amount: decimal.NewFromInt(100)},
+		{name: "another amount", cardID: 1, amount: decimal.NewFromInt(150)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cardRepo := NewMockCardRepository(ctrl)
+			cardRepo.EXPECT().LockByID(gomock.Any(), tc.cardID).Return(&entity.Card{ID: tc.cardID, Status: entity.CardActive}, nil)
+
+			transactionRepo := NewMockCardTransactionRepository(ctrl)
+			transactionRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+					return fn(ctx)
+				})
+			transactionRepo.EXPECT().FindByRRN(gomock.Any(), "000000000010").Return(existing, nil)
+
+			service := &CardService{cardRepository: cardRepo, cardTransactionRepository: transactionRepo, logger: logger}
+			_, err := service.Authorize(context.TODO(), tc.cardID, tc.amount, nil, "000000000010", "000002")
+
+			assert.ErrorIs(t, err, entity.ErrDuplicateRRN)
+		})
+	}
+}
+
+func TestCardService_ReverseByRRN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	t.Run("unknown rrn", func(t *testing.T) {
+		transactionRepo := NewMockCardTransactionRepository(ctrl)
+		transactionRepo.EXPECT().FindByRRN(gomock.Any(), "000000000099").Return(nil, entity.ErrCardTransactionNotFound)
+
+		service := &CardService{cardTransactionRepository: transactionRepo, logger: logger}
+		_, err := service.ReverseByRRN(context.TODO(), "000000000099")
+
+		assert.ErrorIs(t, err, entity.ErrCardTransactionNotFound)
+	})
+
+	t.Run("repeated reversal does not credit twice", func(t *testing.T) {
+		reversedAt := time.Now()
+		original := &entity.CardTransaction{ID: 10, CardID: 1, Amount: decimal.NewFromInt(100), RRN: "000000000010", ReversedAt: &reversedAt}
+
+		cardRepo := NewMockCardRepository(ctrl)
+		cardRepo.EXPECT().LockByID(gomock.Any(), int32(1)).Return(&entity.Card{ID: 1, AccountID: 5}, nil)
+
+		transactionRepo := NewMockCardTransactionRepository(ctrl)
+		transactionRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+				return fn(ctx)
+			})
+		transactionRepo.EXPECT().FindByRRN(gomock.Any(), "000000000010").Return(original, nil).Times(2)
+
+		service := &CardService{cardRepository: cardRepo, cardTransactionRepository: transactionRepo, logger: logger}
+		got, err := service.ReverseByRRN(context.TODO(), "000000000010")
+
+		assert.NoError(t, err)
+		assert.Equal(t, original, got)
+	})
+}
@@ -3,9 +3,13 @@ package bank
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/document"
 	"github.com/MaxFando/bank-system/internal/core/bank/entity"
-	"github.com/MaxFando/bank-system/pkg/cb"
+	"github.com/MaxFando/bank-system/pkg/calendar"
 	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
 	"github.com/shopspring/decimal"
 	"log/slog"
@@ -20,27 +24,95 @@ type CreditRepository interface {
 	CreatePaymentSchedule(ctx context.Context, paymentSchedule *entity.PaymentSchedule) (*entity.PaymentSchedule, error)
 	GetPaymentSchedule(ctx context.Context, creditID int32) ([]entity.PaymentSchedule, error)
 	GetCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error)
+	ListByUserID(ctx context.Context, userID int32) ([]entity.Credit, error)
+	LockCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error)
+	DeletePendingPayments(ctx context.Context, creditID int32) error
+	LockDueCredit(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error)
+	LockDuePayments(ctx context.Context, creditID int32, dueBy time.Time) ([]entity.PaymentSchedule, error)
+	LockOverdueCredit(ctx context.Context, accruedBy time.Time, afterID int32) (*entity.Credit, error)
+	LockOverduePayments(ctx context.Context, creditID int32, accruedBy time.Time) ([]entity.PaymentSchedule, error)
+	LockPaymentByID(ctx context.Context, creditID, paymentID int32) (*entity.PaymentSchedule, error)
+	UpdatePayment(ctx context.Context, payment *entity.PaymentSchedule) error
+	CountUnpaidPayments(ctx context.Context, creditID int32) (int, error)
+	UpdateCreditStatus(ctx context.Context, creditID int32, status entity.CreditStatus) error
+	UpdateCreditTerm(ctx context.Context, creditID, termInMonths int32) error
+	UpdateCreditRate(ctx context.Context, creditID int32, rate decimal.Decimal, nextReset *time.Time) error
+	LockRateResetDue(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error)
+	DeletePendingPaymentsAfter(ctx context.Context, creditID int32, after time.Time) error
+	LockAccrualDue(ctx context.Context, today time.Time, afterID int32) (*entity.Credit, error)
+	UpdateInterestReceivable(ctx context.Context, creditID int32, receivable decimal.Decimal, accruedTo *time.Time) error
+	CreateInterestPosting(ctx context.Context, posting *entity.InterestPosting) error
+	CreatePrincipalPosting(ctx context.Context, posting *entity.PrincipalPosting) error
+	CreatePaymentPosting(ctx context.Context, posting *entity.PaymentPosting) error
 
 	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
 }
 
+// FinancialTransactionRepository сохраняет финансовые операции пользователей, не связанные с картами.
+type FinancialTransactionRepository interface {
+	Create(ctx context.Context, transaction *entity.FinancialTransaction) (int32, error)
+}
+
 // CreditService предоставляет функциональность для управления кредитами, включая создание, расчет и хранение данных.
 type CreditService struct {
-	creditRepository CreditRepository
-	accountService   *AccountService
-	logger           *slog.Logger
+	creditRepository               CreditRepository
+	financialTransactionRepository FinancialTransactionRepository
+	restructuringRepository        RestructuringRepository
+	agreementRepository            AgreementRepository
+	floatingRateRepository         FloatingRateRepository
+	rateProvider                   RateProvider
+	documentGenerator              *document.Generator
+	calendar                       *calendar.Calendar
+	accountService                 *AccountService
+	notificationService            *NotificationService
+	penaltyDailyRate               decimal.Decimal
+	fixedRateMargin                decimal.Decimal
+	floatingRateMargin             decimal.Decimal
+	floatingRateResetMonths        int32
+	logger                         *slog.Logger
 }
 
-// NewCreditService создает новый экземпляр CreditService с заданным логгером, репозиторием кредита и сервисом счетов.
-func NewCreditService(logger *slog.Logger, creditRepository CreditRepository, accountService *AccountService) *CreditService {
+// NewCreditService создает новый экземпляр CreditService с заданным логгером, конфигурацией, производственным
+// календарем, репозиториями кредитов, финансовых операций, реструктуризаций, договоров и плавающих ставок,
+// поставщиком ключевой ставки ЦБ, сервисом счетов и сервисом уведомлений.
+func NewCreditService(
+	logger *slog.Logger,
+	cfg *config.Config,
+	cal *calendar.Calendar,
+	creditRepository CreditRepository,
+	financialTransactionRepository FinancialTransactionRepository,
+	restructuringRepository RestructuringRepository,
+	agreementRepository AgreementRepository,
+	floatingRateRepository FloatingRateRepository,
+	rateProvider RateProvider,
+	accountService *AccountService,
+	notificationService *NotificationService,
+) *CreditService {
 	return &CreditService{
-		creditRepository: creditRepository,
-		accountService:   accountService,
-		logger:           logger,
+		creditRepository:               creditRepository,
+		financialTransactionRepository: financialTransactionRepository,
+		restructuringRepository:        restructuringRepository,
+		agreementRepository:            agreementRepository,
+		floatingRateRepository:         floatingRateRepository,
+		rateProvider:                   rateProvider,
+		documentGenerator:              document.NewGenerator(document.SigningKey(cfg.AgreementSigningKey)),
+		calendar:                       cal,
+		accountService:                 accountService,
+		notificationService:            notificationService,
+		penaltyDailyRate:               decimal.NewFromFloat(cfg.PenaltyDailyRate),
+		fixedRateMargin:                decimal.NewFromFloat(cfg.FixedRateMargin),
+		floatingRateMargin:             decimal.NewFromFloat(cfg.FloatingRateMargin),
+		floatingRateResetMonths:        cfg.FloatingRateResetMonths,
+		logger:                         logger,
 	}
 }
 
-func (s *CreditService) GetPaymentSchedule(ctx context.Context, creditID int32) ([]entity.PaymentSchedule, error) {
+// GetPaymentSchedule возвращает график платежей по кредиту creditID пользователя userID.
+func (s *CreditService) GetPaymentSchedule(ctx context.Context, userID, creditID int32) ([]entity.PaymentSchedule, error) {
+	if _, err := s.getUserCredit(ctx, userID, creditID); err != nil {
+		return nil, err
+	}
+
 	paymentSchedules, err := s.creditRepository.GetPaymentSchedule(ctx, creditID)
 	if err != nil {
 		s.logger.Error("failed to get payment schedule", "error", err)
@@ -51,31 +123,166 @@ func (s *CreditService) GetPaymentSchedule(ctx context.Context, creditID int32)
 	return paymentSchedules, nil
 }
 
-// Create создает новый кредит для указанного пользователя и составляет график платежей на основе переданных параметров.
-func (s *CreditService) Create(ctx context.Context, userID int32, principal decimal.Decimal, termMonths int32) (*entity.Credit, error) {
-	interest, err := cb.GetCentralBankRateWithMargin() // Получение процентной ставки из ЦБ
+// ListCredits возвращает кредиты пользователя userID.
+func (s *CreditService) ListCredits(ctx context.Context, userID int32) ([]entity.Credit, error) {
+	credits, err := s.creditRepository.ListByUserID(ctx, userID)
 	if err != nil {
-		s.logger.Error("failed to get interest rate", "error", err)
-		return nil, fmt.Errorf("failed to get interest rate: %w", err)
+		s.logger.Error("failed to list credits", "error", err)
+		return nil, fmt.Errorf("failed to list credits: %w", err)
+	}
+
+	return credits, nil
+}
+
+// GetCredit возвращает кредит creditID пользователя userID с задолженностью на момент now и историей платежей.
+func (s *CreditService) GetCredit(ctx context.Context, userID, creditID int32, now time.Time) (*entity.CreditDetails, error) {
+	credit, err := s.getUserCredit(ctx, userID, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.creditRepository.GetPaymentSchedule(ctx, creditID)
+	if err != nil {
+		s.logger.Error("failed to get payment schedule", "error", err)
+		return nil, fmt.Errorf("failed to get payment schedule: %w", err)
+	}
+
+	return creditDetails(credit, payments, now), nil
+}
+
+// getUserCredit возвращает кредит creditID, если он принадлежит пользователю userID, и entity.ErrCreditNotFound иначе.
+func (s *CreditService) getUserCredit(ctx context.Context, userID, creditID int32) (*entity.Credit, error) {
+	credit, err := s.creditRepository.GetCreditByID(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	if credit.UserID != userID {
+		return nil, entity.ErrCreditNotFound
+	}
+
+	return credit, nil
+}
+
+// creditDetails рассчитывает задолженность по кредиту на момент now. Начисленные проценты берутся из журнала
+// начислений вместе с начислениями по день now, которые ежедневное задание еще не провело.
+func creditDetails(credit *entity.Credit, payments []entity.PaymentSchedule, now time.Time) *entity.CreditDetails {
+	details := &entity.CreditDetails{
+		Credit:               *credit,
+		OutstandingPrincipal: decimal.Zero,
+		AccruedInterest:      credit.InterestReceivable,
+		OverdueAmount:        decimal.Zero,
+		Payments:             []entity.PaymentSchedule{},
+	}
+
+	for i := range payments {
+		payment := &payments[i]
+		if payment.PaidAmount.IsPositive() {
+			details.Payments = append(details.Payments, *payment)
+		}
+
+		details.OutstandingPrincipal = details.OutstandingPrincipal.Add(payment.PrincipalOutstanding())
+
+		if payment.Status == entity.PaymentOverdue {
+			details.OverdueAmount = details.OverdueAmount.Add(payment.Outstanding())
+		} else if payment.Status == entity.PaymentPending && details.NextPaymentDate == nil {
+			date := payment.PaymentDate
+			details.NextPaymentDate = &date
+		}
+	}
+
+	if credit.Status == entity.CreditStatusActive {
+		for _, posting := range planInterestAccruals(credit, payments, accrualDay(now)) {
+			details.AccruedInterest = details.AccruedInterest.Add(posting.Amount)
+		}
+	}
+
+	return details
+}
+
+// CreditOption задает условия выдаваемого кредита.
+type CreditOption func(credit *entity.Credit)
+
+// WithScheduleType задает способ погашения кредита. По умолчанию составляется аннуитетный график.
+func WithScheduleType(method amortization.Method) CreditOption {
+	return func(credit *entity.Credit) {
+		credit.ScheduleType = method
+	}
+}
+
+// WithDayCount задает способ подсчета дней при начислении процентов. По умолчанию используется act/365.
+func WithDayCount(dayCount amortization.DayCount) CreditOption {
+	return func(credit *entity.Credit) {
+		credit.DayCount = dayCount
+	}
+}
+
+// WithInterestRate задает годовую ставку кредита в процентах. По умолчанию используется ставка ЦБ с маржой банка.
+func WithInterestRate(rate decimal.Decimal) CreditOption {
+	return func(credit *entity.Credit) {
+		credit.InterestRate = rate
+	}
+}
+
+// WithFloatingRate делает ставку кредита плавающей: каждые resetMonths месяцев она пересматривается по ключевой ставке ЦБ
+// с маржой margin. Если начальная ставка не задана, она определяется так же на дату оформления кредита.
+func WithFloatingRate(margin decimal.Decimal, resetMonths int32) CreditOption {
+	return func(credit *entity.Credit) {
+		credit.RateType = entity.FloatingRate
+		credit.RateMargin = margin
+		credit.RateResetMonths = resetMonths
+	}
+}
+
+// Create создает новый кредит для указанного пользователя, составляет график платежей на основе переданных параметров,
+// выпускает кредитный договор и в той же транзакции зачисляет сумму кредита на счет accountID, который должен
+// принадлежать пользователю.
+func (s *CreditService) Create(
+	ctx context.Context,
+	userID, accountID int32,
+	principal decimal.Decimal,
+	termMonths int32,
+	opts ...CreditOption,
+) (*entity.Credit, error) {
+	credit := &entity.Credit{
+		UserID:       userID,
+		AccountID:    accountID,
+		Amount:       principal,
+		TermInMonths: termMonths,
+		Status:       entity.CreditStatusActive,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Actual365,
+		RateType:     entity.FixedRate,
+		RateMargin:   decimal.Zero,
+		CreatedAt:    time.Now(),
 	}
 
-	interestRate := decimal.NewFromFloat(interest).Div(decimal.NewFromInt(100)) // Преобразование в десятичный формат
+	for _, opt := range opts {
+		opt(credit)
+	}
 
-	annuityPayment, err := calculateAnnuityPayment(principal, interestRate, int(termMonths))
+	if err := s.resolveInterestRate(ctx, credit); err != nil {
+		return nil, err
+	}
+
+	schedule, err := buildPaymentSchedule(credit, credit.CreatedAt, s.calendar)
 	if err != nil {
-		s.logger.Error("failed to calculate annuity payment", "error", err)
-		return nil, fmt.Errorf("failed to calculate annuity payment: %w", err)
+		s.logger.Error("failed to calculate payment schedule", "error", err)
+		return nil, fmt.Errorf("failed to calculate payment schedule: %w", err)
 	}
 
 	// Создание кредита
-	credit := new(entity.Credit)
 	err = s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
-		credit = &entity.Credit{
-			UserID:       userID,
-			Amount:       principal,
-			InterestRate: interestRate,
-			TermInMonths: termMonths,
+		account, err := s.accountService.GetAccountByID(ctx, accountID)
+		if err != nil {
+			s.logger.Error("failed to get account", "error", err)
+			return err
+		}
+
+		if account.UserID != userID {
+			return entity.ErrAccountAccessDenied
 		}
+
 		createdCredit, err := s.creditRepository.Save(ctx, credit)
 		if err != nil {
 			s.logger.Error("failed to create credit", "error", err)
@@ -86,17 +293,10 @@ func (s *CreditService) Create(ctx context.Context, userID int32, principal deci
 		credit = createdCredit
 
 		// Создание графика платежей
-		for i := 0; i < int(termMonths); i++ {
-			paymentSchedule := &entity.PaymentSchedule{
-				CreditID:        createdCredit.ID,
-				PaymentDate:     time.Now().AddDate(0, 0, (i+1)*30),
-				PaymentAmount:   annuityPayment,
-				PrincipalAmount: principal,
-				InterestAmount:  interestRate,
-				Balance:         principal.Sub(annuityPayment.Mul(decimal.NewFromInt(int64(i)))),
-			}
+		for i := range schedule {
+			schedule[i].CreditID = createdCredit.ID
 
-			_, err = s.creditRepository.CreatePaymentSchedule(ctx, paymentSchedule)
+			_, err = s.creditRepository.CreatePaymentSchedule(ctx, &schedule[i])
 			if err != nil {
 				s.logger.Error("failed to create payment schedule", "error", err)
 				return fmt.Errorf("failed to create payment schedule: %w", err)
@@ -104,7 +304,12 @@ func (s *CreditService) Create(ctx context.Context, userID int32, principal deci
 		}
 
 		s.logger.Info("payment schedule created successfully", "credit_id", credit.ID)
-		return nil
+
+		if err := s.issueAgreement(ctx, credit, schedule); err != nil {
+			return err
+		}
+
+		return s.disburse(ctx, credit)
 	})
 	if err != nil {
 		s.logger.Error("transaction failed", "error", err)
@@ -115,69 +320,213 @@ func (s *CreditService) Create(ctx context.Context, userID int32, principal deci
 	return credit, nil
 }
 
-// WithdrawPayment выполняет списание указанной суммы `amount` из кредитного счёта `credit` с проверкой доступности средств.
-// Возвращает ошибку, если операция не может быть завершена, например, из-за отсутствия средств на счету.
-// Использует транзакцию для обеспечения согласованности данных между кредитным счётом и пользователем.
-func (s *CreditService) WithdrawPayment(ctx context.Context, credit *entity.Credit, amount decimal.Decimal) error {
-	err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
-		account, err := s.accountService.GetAccountByUserID(ctx, credit.UserID)
-		if err != nil {
-			s.logger.Error("failed to get account", "error", err)
-			return fmt.Errorf("failed to get account: %w", err)
+// resolveInterestRate назначает кредиту текущую ключевую ставку ЦБ с маржой банка, если ставка не задана опциями. Для плавающей ставки
+// используется ключевая ставка из истории на дату оформления с маржой кредита и назначается дата первого пересмотра.
+func (s *CreditService) resolveInterestRate(ctx context.Context, credit *entity.Credit) error {
+	if credit.RateType == entity.FloatingRate {
+		if credit.RateResetMonths <= 0 {
+			return fmt.Errorf("floating rate reset period must be positive")
 		}
 
-		err = s.accountService.Withdraw(ctx, account.ID, amount)
-		if err != nil {
-			s.logger.Error("failed to withdraw amount", "error", err)
-			return fmt.Errorf("failed to withdraw amount: %w", err)
-		}
-		s.logger.Info("withdrawal successful", "account_id", account.ID, "amount", amount)
+		nextReset := amortization.AddMonths(credit.CreatedAt, int(credit.RateResetMonths))
+		credit.NextRateReset = &nextReset
 
-		err = credit.Withdraw(amount)
-		if err != nil {
-			s.logger.Error("failed to withdraw from credit", "error", err)
-			return fmt.Errorf("failed to withdraw from credit: %w", err)
+		if !credit.InterestRate.IsZero() {
+			return nil
 		}
 
-		s.logger.Info("withdrawal from credit successful", "credit_id", credit.ID, "amount", amount)
-
-		// Обновление кредита
-		_, err = s.creditRepository.Save(ctx, credit)
+		keyRate, err := s.floatingRateRepository.FindKeyRate(ctx, credit.CreatedAt)
 		if err != nil {
-			s.logger.Error("failed to update credit", "error", err)
-			return fmt.Errorf("failed to update credit: %w", err)
+			s.logger.Error("failed to get key rate", "error", err)
+			return fmt.Errorf("failed to get key rate: %w", err)
 		}
 
-		s.logger.Info("credit updated successfully", "credit_id", credit.ID)
+		credit.InterestRate = keyRate.Rate.Add(credit.RateMargin)
 		return nil
+	}
+
+	if !credit.InterestRate.IsZero() {
+		return nil
+	}
+
+	interest, err := s.FixedRate(ctx, credit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to get interest rate: %w", err)
+	}
+
+	credit.InterestRate = interest // Ставка хранится в процентах годовых
+	return nil
+}
+
+// disburse зачисляет сумму кредита на счет заемщика и записывает операцию выдачи кредита.
+func (s *CreditService) disburse(ctx context.Context, credit *entity.Credit) error {
+	if err := s.accountService.Deposit(ctx, credit.AccountID, credit.Amount); err != nil {
+		s.logger.Error("failed to disburse credit", "error", err)
+		return fmt.Errorf("failed to disburse credit: %w", err)
+	}
+
+	_, err := s.financialTransactionRepository.Create(ctx, &entity.FinancialTransaction{
+		UserID:            credit.UserID,
+		AccountID:         &credit.AccountID,
+		CreditID:          &credit.ID,
+		TransactionType:   entity.CreditDisbursementTransaction,
+		Amount:            credit.Amount,
+		TransactionDate:   credit.CreatedAt,
+		TransactionStatus: entity.TransactionSuccess,
 	})
+	if err != nil {
+		s.logger.Error("failed to record credit disbursement", "error", err)
+		return fmt.Errorf("failed to record credit disbursement: %w", err)
+	}
+
+	if err := s.postPrincipal(ctx, credit, entity.PrincipalDisbursement, nil, credit.Amount, credit.CreatedAt); err != nil {
+		return err
+	}
+
+	s.logger.Info("credit disbursed", "credit_id", credit.ID, "account_id", credit.AccountID, "amount", credit.Amount)
+	return nil
+}
+
+// postPrincipal записывает изменение основного долга кредита credit на amount проводкой вида postingType
+// по платежу paymentID. Нулевые изменения не записываются.
+func (s *CreditService) postPrincipal(
+	ctx context.Context,
+	credit *entity.Credit,
+	postingType entity.PrincipalPostingType,
+	paymentID *int32,
+	amount decimal.Decimal,
+	now time.Time,
+) error {
+	if amount.IsZero() {
+		return nil
+	}
 
+	posting := &entity.PrincipalPosting{
+		CreditID:    credit.ID,
+		PostingDate: accrualDay(now),
+		Type:        postingType,
+		Amount:      amount,
+		PaymentID:   paymentID,
+	}
+	if err := s.creditRepository.CreatePrincipalPosting(ctx, posting); err != nil {
+		s.logger.Error("failed to create principal posting", "error", err)
+		return fmt.Errorf("failed to create principal posting: %w", err)
+	}
+
+	return nil
+}
+
+// creditAccountID возвращает счет, с которого списываются платежи по кредиту. Для кредитов, выданных
+// до привязки к счету, используется счет заемщика.
+func (s *CreditService) creditAccountID(ctx context.Context, credit *entity.Credit) (int32, error) {
+	if credit.AccountID != 0 {
+		return credit.AccountID, nil
+	}
+
+	account, err := s.accountService.GetAccountByUserID(ctx, credit.UserID)
 	if err != nil {
-		s.logger.Error("transaction failed", "error", err)
-		return fmt.Errorf("transaction failed: %w", err)
+		return 0, fmt.Errorf("failed to get account: %w", err)
 	}
 
-	s.logger.Info("payment withdrawn successfully", "credit_id", credit.ID, "amount", amount)
+	return account.ID, nil
+}
+
+// CollectDuePayments списывает со счетов заемщиков платежи, срок которых наступил к концу дня now, включая ранее
+// просроченные и частично погашенные. Каждый кредит обрабатывается в отдельной транзакции: сначала блокируется
+// строка кредита, затем его наступившие платежи, в том же порядке, что и при досрочном погашении, списании штрафа
+// и реструктуризации. Поэтому повторный или параллельный запуск не спишет платеж дважды, а платежи, списать которые
+// не удалось, будут повторно обработаны при следующем запуске.
+func (s *CreditService) CollectDuePayments(ctx context.Context, now time.Time) error {
+	year, month, day := now.Date()
+	dueBy := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+
+	var afterID int32
+	var processed, failed int
+	for {
+		var done bool
+		lastID := afterID
+
+		err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+			credit, err := s.creditRepository.LockDueCredit(ctx, dueBy, afterID)
+			if errors.Is(err, entity.ErrCreditNotFound) {
+				done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			afterID = credit.ID
+
+			payments, err := s.creditRepository.LockDuePayments(ctx, credit.ID, dueBy)
+			if err != nil {
+				return err
+			}
+
+			for i := range payments {
+				if credit.Status != entity.CreditStatusActive {
+					break
+				}
+
+				if err := s.withdrawPayment(ctx, credit, &payments[i], now); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if done {
+			break
+		}
+
+		if err != nil {
+			if afterID == lastID {
+				s.logger.Error("failed to find due payments", "error", err)
+				return fmt.Errorf("failed to find due payments: %w", err)
+			}
+
+			s.logger.Error("failed to collect payments", "credit_id", afterID, "error", err)
+			failed++
+			continue
+		}
+
+		processed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to collect payments for %d of %d credits", failed, processed+failed)
+	}
+
+	s.logger.Info("due payments collected", "credits", processed)
 	return nil
 }
 
-// ApplyPenalty накладывает штраф на кредит, вычитая 10% от суммы кредита с банковского счета пользователя.
-func (s *CreditService) ApplyPenalty(ctx context.Context, credit *entity.Credit) error {
-	penaltyAmount := credit.Amount.Mul(decimal.NewFromFloat(0.1)) // 10% penalty
+// WithdrawPayment списывает со счета заемщика непогашенную часть платежа `payment` по кредиту `credit`.
+// Кредит и платеж блокируются и перечитываются в транзакции, а статус кредита проверяется по заблокированной строке.
+// Если средств недостаточно, списывается доступный остаток, а платеж помечается частично погашенным или просроченным.
+func (s *CreditService) WithdrawPayment(ctx context.Context, credit *entity.Credit, payment *entity.PaymentSchedule, now time.Time) error {
 	err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
-		account, err := s.accountService.GetAccountByUserID(ctx, credit.UserID)
+		locked, err := s.creditRepository.LockCreditByID(ctx, credit.ID)
 		if err != nil {
-			s.logger.Error("failed to get account", "error", err)
-			return fmt.Errorf("failed to get account: %w", err)
+			s.logger.Error("failed to lock credit", "error", err)
+			return fmt.Errorf("failed to lock credit: %w", err)
 		}
 
-		err = s.accountService.Withdraw(ctx, account.ID, penaltyAmount)
+		if locked.Status != entity.CreditStatusActive {
+			return entity.ErrCreditNotActive
+		}
+
+		current, err := s.creditRepository.LockPaymentByID(ctx, locked.ID, payment.ID)
 		if err != nil {
-			s.logger.Error("failed to withdraw penalty amount", "error", err)
-			return fmt.Errorf("failed to withdraw penalty amount: %w", err)
+			s.logger.Error("failed to lock payment", "error", err)
+			return fmt.Errorf("failed to lock payment: %w", err)
+		}
+
+		if err := s.withdrawPayment(ctx, locked, current, now); err != nil {
+			return err
 		}
-		s.logger.Info("penalty withdrawal successful", "account_id", account.ID, "amount", penaltyAmount)
 
+		*credit, *payment = *locked, *current
 		return nil
 	})
 
@@ -186,25 +535,166 @@ func (s *CreditService) ApplyPenalty(ctx context.Context, credit *entity.Credit)
 		return fmt.Errorf("transaction failed: %w", err)
 	}
 
-	s.logger.Info("penalty applied successfully", "credit_id", credit.ID, "penalty_amount", penaltyAmount)
 	return nil
 }
 
-// calculateAnnuityPayment вычисляет аннуитетный платеж для кредита на основе суммы, процентной ставки и срока в месяцах.
-// Возвращает рассчитанный аннуитетный платеж или ошибку в случае некорректных входных данных.
-func calculateAnnuityPayment(principal, interestRate decimal.Decimal, termMonths int) (decimal.Decimal, error) {
-	termMonthsDecimal := decimal.NewFromInt(int64(termMonths))
+// withdrawPayment списывает непогашенную часть платежа payment по кредиту credit, строки которых уже заблокированы
+// в текущей транзакции. Списанная сумма сверх штрафа сначала погашает начисленные по кредиту проценты, которые
+// предварительно доначисляются по день списания. Проценты за период платежа, приходящегося на кредитные каникулы,
+// переносятся в основной долг. Когда погашен последний платеж, кредит переводится в статус paid.
+func (s *CreditService) withdrawPayment(ctx context.Context, credit *entity.Credit, payment *entity.PaymentSchedule, now time.Time) error {
+	if err := s.accrueInterest(ctx, credit, accrualDay(now)); err != nil {
+		return err
+	}
+
+	accountID, err := s.creditAccountID(ctx, credit)
+	if err != nil {
+		s.logger.Error("failed to get account", "error", err)
+		return err
+	}
+
+	amount, err := s.accountService.WithdrawAvailable(ctx, accountID, payment.Outstanding())
+	if err != nil {
+		s.logger.Error("failed to withdraw amount", "error", err)
+		return fmt.Errorf("failed to withdraw amount: %w", err)
+	}
 
-	monthlyInterestRate := interestRate.Div(decimal.NewFromInt(100)).Div(decimal.NewFromInt(12))
+	penaltyPaid, principalPaid := payment.PenaltyPaid, payment.PrincipalPaid()
+	payment.Apply(amount, now)
+	if err := s.creditRepository.UpdatePayment(ctx, payment); err != nil {
+		s.logger.Error("failed to update payment", "error", err)
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
 
-	// Расчет аннуитетного платежа
-	// A = P * (r * (1 + r)^n) / ((1 + r)^n - 1)
-	onePlusR := decimal.NewFromInt(1).Add(monthlyInterestRate)
-	onePlusRToN := onePlusR.Pow(termMonthsDecimal)
-	numerator := monthlyInterestRate.Mul(onePlusRToN)
-	denominator := onePlusRToN.Sub(decimal.NewFromInt(1))
+	if err := s.postPayment(ctx, credit, payment.ID, amount, payment.PenaltyPaid.Sub(penaltyPaid), now); err != nil {
+		return err
+	}
 
-	annuityPayment := principal.Mul(numerator).Div(denominator)
+	repaid := payment.PrincipalPaid().Sub(principalPaid)
+	if err := s.postPrincipal(ctx, credit, entity.PrincipalRepayment, &payment.ID, repaid.Neg(), now); err != nil {
+		return err
+	}
+
+	if err := s.settleInterest(ctx, credit, payment.ID, amount.Sub(payment.PenaltyPaid.Sub(penaltyPaid)), now); err != nil {
+		return err
+	}
+
+	if payment.Status == entity.PaymentPaid {
+		if err := s.capitalizeInterest(ctx, credit, payment, now); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("payment withdrawn", "credit_id", credit.ID, "payment_id", payment.ID, "amount", amount, "status", payment.Status)
+
+	if payment.Status != entity.PaymentPaid {
+		return nil
+	}
+
+	return s.closeIfRepaid(ctx, credit)
+}
+
+// postPayment записывает поступление amount в счет платежа paymentID кредита credit, из которого penalty погасило
+// штраф. Нулевые поступления не записываются.
+func (s *CreditService) postPayment(ctx context.Context, credit *entity.Credit, paymentID int32, amount, penalty decimal.Decimal, now time.Time) error {
+	if amount.IsZero() {
+		return nil
+	}
+
+	posting := &entity.PaymentPosting{
+		CreditID:      credit.ID,
+		PaymentID:     paymentID,
+		PostingDate:   accrualDay(now),
+		Amount:        amount,
+		PenaltyAmount: penalty,
+	}
+	if err := s.creditRepository.CreatePaymentPosting(ctx, posting); err != nil {
+		s.logger.Error("failed to create payment posting", "error", err)
+		return fmt.Errorf("failed to create payment posting: %w", err)
+	}
+
+	return nil
+}
+
+// closeIfRepaid переводит кредит в статус paid, если по нему не осталось непогашенных платежей.
+func (s *CreditService) closeIfRepaid(ctx context.Context, credit *entity.Credit) error {
+	unpaid, err := s.creditRepository.CountUnpaidPayments(ctx, credit.ID)
+	if err != nil {
+		s.logger.Error("failed to count unpaid payments", "error", err)
+		return fmt.Errorf("failed to count unpaid payments: %w", err)
+	}
+
+	if unpaid > 0 {
+		return nil
+	}
+
+	if err := s.creditRepository.UpdateCreditStatus(ctx, credit.ID, entity.CreditStatusPaid); err != nil {
+		s.logger.Error("failed to update credit", "error", err)
+		return fmt.Errorf("failed to update credit: %w", err)
+	}
+
+	credit.Status = entity.CreditStatusPaid
+	s.logger.Info("credit repaid", "credit_id", credit.ID)
+	return nil
+}
+
+// buildPaymentSchedule составляет график платежей по условиям кредита, выданного в момент start. Платежи назначаются
+// ежемесячно в день выдачи и переносятся на ближайший рабочий день по календарю cal.
+func buildPaymentSchedule(credit *entity.Credit, start time.Time, cal *calendar.Calendar) ([]entity.PaymentSchedule, error) {
+	dates := make([]time.Time, 0, credit.TermInMonths)
+	for month := 1; month <= int(credit.TermInMonths); month++ {
+		dates = append(dates, paymentDate(cal, start, month))
+	}
+
+	installments, err := amortization.Generate(amortization.Params{
+		Principal:    credit.Amount,
+		AnnualRate:   credit.InterestRate,
+		Term:         int(credit.TermInMonths),
+		Method:       credit.ScheduleType,
+		DayCount:     credit.DayCount,
+		StartDate:    start,
+		PaymentDates: dates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toPaymentSchedule(credit.ID, installments), nil
+}
+
+// paymentDate возвращает дату платежа через month месяцев после start, перенесенную на ближайший рабочий день.
+func paymentDate(cal *calendar.Calendar, start time.Time, month int) time.Time {
+	return cal.NextBusinessDay(amortization.AddMonths(start, month))
+}
+
+// paymentMonth возвращает, через сколько месяцев после start назначен платеж с датой date. Перенос на следующий
+// рабочий день может вывести дату платежа в следующий месяц, но не дальше даты следующего платежа: нерабочие дни
+// подряд занимают меньше месяца. Поэтому это наибольшее число месяцев, прибавление которых к start дает дату
+// не позже date.
+func paymentMonth(start, date time.Time) int {
+	month := 0
+	for !amortization.AddMonths(start, month+1).After(date) {
+		month++
+	}
+
+	return month
+}
+
+// toPaymentSchedule преобразует рассчитанные платежи в строки графика кредита creditID.
+func toPaymentSchedule(creditID int32, installments []amortization.Installment) []entity.PaymentSchedule {
+	schedule := make([]entity.PaymentSchedule, 0, len(installments))
+	for _, installment := range installments {
+		schedule = append(schedule, entity.PaymentSchedule{
+			CreditID:        creditID,
+			PaymentDate:     installment.Date,
+			PaymentAmount:   installment.Payment,
+			PrincipalAmount: installment.Principal,
+			InterestAmount:  installment.Interest,
+			Balance:         installment.Balance,
+			Status:          entity.PaymentPending,
+			PaidAmount:      decimal.Zero,
+		})
+	}
 
-	return annuityPayment, nil
+	return schedule
 }
@@ -0,0 +1,46 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+)
+
+// AgreementRepository хранит выпущенные кредитные договоры. Договоры только добавляются и не изменяются.
+type AgreementRepository interface {
+	Create(ctx context.Context, agreement *entity.CreditAgreement) error
+	Get(ctx context.Context, creditID int32, format entity.DocumentFormat) (*entity.CreditAgreement, error)
+}
+
+// issueAgreement выпускает кредитный договор с графиком платежей schedule по кредиту credit во всех форматах.
+func (s *CreditService) issueAgreement(ctx context.Context, credit *entity.Credit, schedule []entity.PaymentSchedule) error {
+	agreements, err := s.documentGenerator.Agreement(credit, schedule)
+	if err != nil {
+		s.logger.Error("failed to render credit agreement", "error", err)
+		return fmt.Errorf("failed to render credit agreement: %w", err)
+	}
+
+	for i := range agreements {
+		if err := s.agreementRepository.Create(ctx, &agreements[i]); err != nil {
+			s.logger.Error("failed to save credit agreement", "error", err)
+			return fmt.Errorf("failed to save credit agreement: %w", err)
+		}
+	}
+
+	s.logger.Info("credit agreement issued", "credit_id", credit.ID)
+	return nil
+}
+
+// GetAgreement возвращает кредитный договор по кредиту creditID пользователя userID в формате format.
+func (s *CreditService) GetAgreement(ctx context.Context, userID, creditID int32, format entity.DocumentFormat) (*entity.CreditAgreement, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getUserCredit(ctx, userID, creditID); err != nil {
+		return nil, err
+	}
+
+	return s.agreementRepository.Get(ctx, creditID, format)
+}
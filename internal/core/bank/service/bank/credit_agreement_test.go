@@ -0,0 +1,36 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestCreditService_GetAgreement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	agreement := &entity.CreditAgreement{ID: 1, CreditID: 3, Format: entity.PDFDocument, ContentHash: "abc"}
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().GetCreditByID(gomock.Any(), int32(3)).Return(&entity.Credit{ID: 3, UserID: 2}, nil).Times(2)
+
+	agreementRepo := NewMockAgreementRepository(ctrl)
+	agreementRepo.EXPECT().Get(gomock.Any(), int32(3), entity.PDFDocument).Return(agreement, nil)
+
+	service := &CreditService{creditRepository: creditRepo, agreementRepository: agreementRepo, logger: logger}
+
+	got, err := service.GetAgreement(context.TODO(), 2, 3, entity.PDFDocument)
+	assert.NoError(t, err)
+	assert.Equal(t, agreement, got)
+
+	_, err = service.GetAgreement(context.TODO(), 1, 3, entity.PDFDocument)
+	assert.ErrorIs(t, err, entity.ErrCreditNotFound)
+
+	_, err = service.GetAgreement(context.TODO(), 2, 3, entity.DocumentFormat("docx"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,299 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"time"
+)
+
+// CreditApplicationRepository предоставляет методы для работы с заявками на кредит и решениями по ним.
+type CreditApplicationRepository interface {
+	Create(ctx context.Context, application *entity.CreditApplication) error
+	Update(ctx context.Context, application *entity.CreditApplication) error
+	FindByID(ctx context.Context, id int32) (*entity.CreditApplication, error)
+	LockByID(ctx context.Context, id int32) (*entity.CreditApplication, error)
+	ListByStatus(ctx context.Context, status entity.CreditApplicationStatus) ([]entity.CreditApplication, error)
+	CreateDecision(ctx context.Context, decision *entity.CreditDecision) error
+	FindDecisions(ctx context.Context, applicationID int32) ([]entity.CreditDecision, error)
+
+	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
+}
+
+// CreditApplicationService ведет заявки на кредит: подачу, автоматический скоринг, ручное рассмотрение
+// и выдачу кредита по одобренной заявке.
+type CreditApplicationService struct {
+	repo           CreditApplicationRepository
+	scorer         Scorer
+	creditService  *CreditService
+	accountService *AccountService
+	rate           func(ctx context.Context, now time.Time) (decimal.Decimal, error)
+	logger         *slog.Logger
+}
+
+// NewCreditApplicationService создает новый экземпляр CreditApplicationService. Ставка по заявке
+// определяется по текущей ключевой ставке ЦБ с маржой банка на момент подачи.
+func NewCreditApplicationService(
+	logger *slog.Logger,
+	repo CreditApplicationRepository,
+	scorer Scorer,
+	creditService *CreditService,
+	accountService *AccountService,
+) *CreditApplicationService {
+	return &CreditApplicationService{
+		repo:           repo,
+		scorer:         scorer,
+		creditService:  creditService,
+		accountService: accountService,
+		rate:           creditService.FixedRate,
+		logger:         logger,
+	}
+}
+
+// Submit принимает заявку на кредит, проводит скоринг и сохраняет его решение. Одобренная скорингом заявка
+// сразу исполняется: кредит выдается на счет accountID, который должен принадлежать пользователю.
+// Для плавающей ставки rateType заявке предлагается ключевая ставка из истории с маржой банка на момент подачи.
+func (s *CreditApplicationService) Submit(
+	ctx context.Context,
+	userID, accountID int32,
+	amount decimal.Decimal,
+	termMonths int32,
+	scheduleType amortization.Method,
+	rateType entity.RateType,
+	monthlyIncome decimal.Decimal,
+) (*entity.CreditApplication, error) {
+	if !amount.IsPositive() || termMonths <= 0 || !monthlyIncome.IsPositive() {
+		return nil, fmt.Errorf("%w: amount, term and monthly income must be positive", entity.ErrInvalidCreditApplication)
+	}
+	if err := scheduleType.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCreditApplication, err)
+	}
+	if err := rateType.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCreditApplication, err)
+	}
+
+	account, err := s.accountService.GetAccountByID(ctx, accountID)
+	if err != nil {
+		s.logger.Error("failed to get account", "error", err)
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account.UserID != userID {
+		return nil, entity.ErrAccountAccessDenied
+	}
+
+	rate, err := s.offerRate(ctx, rateType)
+	if err != nil {
+		s.logger.Error("failed to get interest rate", "error", err)
+		return nil, fmt.Errorf("failed to get interest rate: %w", err)
+	}
+
+	application := &entity.CreditApplication{
+		UserID:        userID,
+		AccountID:     accountID,
+		Amount:        amount,
+		TermInMonths:  termMonths,
+		ScheduleType:  scheduleType,
+		MonthlyIncome: monthlyIncome,
+		InterestRate:  rate,
+		RateType:      rateType,
+		Status:        entity.ApplicationSubmitted,
+	}
+
+	result, err := s.scorer.Score(ctx, application, time.Now())
+	if err != nil {
+		s.logger.Error("failed to score credit application", "error", err)
+		return nil, fmt.Errorf("failed to score credit application: %w", err)
+	}
+
+	err = s.repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, application); err != nil {
+			return err
+		}
+
+		application.Score = &result.Score
+		if err := s.decide(ctx, application, result.Status, result.Reasons, nil); err != nil {
+			return err
+		}
+
+		if application.Status != entity.ApplicationApproved {
+			return nil
+		}
+
+		return s.disburse(ctx, application)
+	})
+	if err != nil {
+		s.logger.Error("failed to submit credit application", "error", err)
+		return nil, fmt.Errorf("failed to submit credit application: %w", err)
+	}
+
+	s.logger.Info("credit application submitted", "application_id", application.ID, "status", application.Status)
+	return application, nil
+}
+
+// Approve одобряет заявку от имени сотрудника adminID и выдает по ней кредит.
+func (s *CreditApplicationService) Approve(ctx context.Context, adminID, applicationID int32, reasons []string) (*entity.CreditApplication, error) {
+	var application *entity.CreditApplication
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		application, err = s.repo.LockByID(ctx, applicationID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.decide(ctx, application, entity.ApplicationApproved, reasons, &adminID); err != nil {
+			return err
+		}
+
+		return s.disburse(ctx, application)
+	})
+	if err != nil {
+		s.logger.Error("failed to approve credit application", "error", err)
+		return nil, fmt.Errorf("failed to approve credit application: %w", err)
+	}
+
+	s.logger.Info("credit application approved", "application_id", application.ID, "admin_id", adminID)
+	return application, nil
+}
+
+// Reject отклоняет заявку от имени сотрудника adminID. Причина отказа обязательна.
+func (s *CreditApplicationService) Reject(ctx context.Context, adminID, applicationID int32, reasons []string) (*entity.CreditApplication, error) {
+	if len(reasons) == 0 {
+		return nil, entity.ErrDecisionReasonRequired
+	}
+
+	var application *entity.CreditApplication
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		application, err = s.repo.LockByID(ctx, applicationID)
+		if err != nil {
+			return err
+		}
+
+		return s.decide(ctx, application, entity.ApplicationRejected, reasons, &adminID)
+	})
+	if err != nil {
+		s.logger.Error("failed to reject credit application", "error", err)
+		return nil, fmt.Errorf("failed to reject credit application: %w", err)
+	}
+
+	s.logger.Info("credit application rejected", "application_id", application.ID, "admin_id", adminID)
+	return application, nil
+}
+
+// Get возвращает заявку пользователя userID вместе с решениями по ней.
+func (s *CreditApplicationService) Get(ctx context.Context, userID, applicationID int32) (*entity.CreditApplication, []entity.CreditDecision, error) {
+	application, err := s.repo.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if application.UserID != userID {
+		return nil, nil, entity.ErrCreditApplicationNotFound
+	}
+
+	return s.getDecisions(ctx, application)
+}
+
+// GetForReview возвращает любую заявку вместе с решениями по ней для рассмотрения сотрудником.
+func (s *CreditApplicationService) GetForReview(ctx context.Context, applicationID int32) (*entity.CreditApplication, []entity.CreditDecision, error) {
+	application, err := s.repo.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.getDecisions(ctx, application)
+}
+
+// List возвращает заявки в статусе status.
+func (s *CreditApplicationService) List(ctx context.Context, status entity.CreditApplicationStatus) ([]entity.CreditApplication, error) {
+	if err := status.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCreditApplication, err)
+	}
+
+	applications, err := s.repo.ListByStatus(ctx, status)
+	if err != nil {
+		s.logger.Error("failed to list credit applications", "error", err)
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+func (s *CreditApplicationService) getDecisions(ctx context.Context, application *entity.CreditApplication) (*entity.CreditApplication, []entity.CreditDecision, error) {
+	decisions, err := s.repo.FindDecisions(ctx, application.ID)
+	if err != nil {
+		s.logger.Error("failed to get credit decisions", "error", err)
+		return nil, nil, err
+	}
+
+	return application, decisions, nil
+}
+
+// decide переводит заявку в статус status и сохраняет решение с его причинами. decidedBy пуст для решений скоринга.
+func (s *CreditApplicationService) decide(
+	ctx context.Context,
+	application *entity.CreditApplication,
+	status entity.CreditApplicationStatus,
+	reasons []string,
+	decidedBy *int32,
+) error {
+	if err := application.Transition(status); err != nil {
+		return err
+	}
+
+	decision := &entity.CreditDecision{
+		ApplicationID: application.ID,
+		Status:        status,
+		Reasons:       reasons,
+		DecidedBy:     decidedBy,
+	}
+	if decidedBy == nil {
+		decision.Score = application.Score
+	}
+
+	if err := s.repo.CreateDecision(ctx, decision); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, application)
+}
+
+// offerRate возвращает ставку, предлагаемую заявке с видом ставки rateType на момент подачи.
+func (s *CreditApplicationService) offerRate(ctx context.Context, rateType entity.RateType) (decimal.Decimal, error) {
+	if rateType == entity.FloatingRate {
+		return s.creditService.FloatingRate(ctx, time.Now())
+	}
+
+	return s.rate(ctx, time.Now())
+}
+
+// disburse выдает кредит по одобренной заявке на условиях заявки и связывает его с заявкой.
+func (s *CreditApplicationService) disburse(ctx context.Context, application *entity.CreditApplication) error {
+	opts := []CreditOption{WithScheduleType(application.ScheduleType), WithInterestRate(application.InterestRate)}
+	if application.RateType == entity.FloatingRate {
+		opts = append(opts, s.creditService.floatingRateOption())
+	}
+
+	credit, err := s.creditService.Create(
+		ctx,
+		application.UserID,
+		application.AccountID,
+		application.Amount,
+		application.TermInMonths,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := application.Transition(entity.ApplicationDisbursed); err != nil {
+		return err
+	}
+	application.CreditID = &credit.ID
+
+	return s.repo.Update(ctx, application)
+}
@@ -0,0 +1,205 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCreditApplicationService_Submit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	rate := func(context.Context, time.Time) (decimal.Decimal, error) {
+		return decimal.RequireFromString("18.5"), nil
+	}
+
+	t.Run("scoring rejection is recorded without a credit", func(t *testing.T) {
+		accountRepo := NewMockAccountRepository(ctrl)
+		accountRepo.EXPECT().FindByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, UserID: 1}, nil)
+
+		scorer := NewMockScorer(ctrl)
+		scorer.EXPECT().Score(gomock.Any(), gomock.Any(), gomock.Any()).Return(&entity.ScoringResult{
+			Status:  entity.ApplicationRejected,
+			Reasons: []string{"applicant has 1 overdue payments"},
+		}, nil)
+
+		repo := NewMockCreditApplicationRepository(ctrl)
+		repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, application *entity.CreditApplication) error {
+			assert.True(t, decimal.RequireFromString("18.5").Equal(application.InterestRate))
+			application.ID = 7
+			return nil
+		})
+		score := int32(0)
+		repo.EXPECT().CreateDecision(gomock.Any(), &entity.CreditDecision{
+			ApplicationID: 7,
+			Status:        entity.ApplicationRejected,
+			Score:         &score,
+			Reasons:       []string{"applicant has 1 overdue payments"},
+		}).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := &CreditApplicationService{
+			repo:           repo,
+			scorer:         scorer,
+			accountService: NewAccountService(logger, accountRepo),
+			rate:           rate,
+			logger:         logger,
+		}
+		application, err := service.Submit(
+			context.TODO(), 1, 5, decimal.NewFromInt(50000), 12, amortization.Annuity, entity.FixedRate, decimal.NewFromInt(20000),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ApplicationRejected, application.Status)
+		assert.Nil(t, application.CreditID)
+	})
+
+	t.Run("foreign account is denied", func(t *testing.T) {
+		accountRepo := NewMockAccountRepository(ctrl)
+		accountRepo.EXPECT().FindByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, UserID: 2}, nil)
+
+		service := &CreditApplicationService{accountService: NewAccountService(logger, accountRepo), rate: rate, logger: logger}
+		_, err := service.Submit(
+			context.TODO(), 1, 5, decimal.NewFromInt(50000), 12, amortization.Annuity, entity.FixedRate, decimal.NewFromInt(20000),
+		)
+
+		assert.ErrorIs(t, err, entity.ErrAccountAccessDenied)
+	})
+
+	t.Run("unknown rate type", func(t *testing.T) {
+		service := &CreditApplicationService{logger: logger}
+		_, err := service.Submit(context.TODO(), 1, 5, decimal.NewFromInt(50000), 12, amortization.Annuity, entity.RateType("mixed"), decimal.NewFromInt(20000))
+
+		assert.ErrorIs(t, err, entity.ErrInvalidCreditApplication)
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		service := &CreditApplicationService{logger: logger}
+		_, err := service.Submit(context.TODO(), 1, 5, decimal.NewFromInt(50000), 0, amortization.Annuity, entity.FixedRate, decimal.NewFromInt(20000))
+
+		assert.ErrorIs(t, err, entity.ErrInvalidCreditApplication)
+	})
+}
+
+func TestCreditApplicationService_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	adminID := int32(42)
+	application := &entity.CreditApplication{
+		ID:           7,
+		UserID:       1,
+		AccountID:    5,
+		Amount:       decimal.NewFromInt(50000),
+		TermInMonths: 6,
+		ScheduleType: amortization.Differentiated,
+		InterestRate: decimal.NewFromInt(15),
+		Status:       entity.ApplicationUnderReview,
+	}
+
+	repo := NewMockCreditApplicationRepository(ctrl)
+	repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	repo.EXPECT().LockByID(gomock.Any(), int32(7)).Return(application, nil)
+	repo.EXPECT().CreateDecision(gomock.Any(), &entity.CreditDecision{
+		ApplicationID: 7,
+		Status:        entity.ApplicationApproved,
+		Reasons:       []string{"income confirmed"},
+		DecidedBy:     &adminID,
+	}).Return(nil)
+	repo.EXPECT().Update(gomock.Any(), application).Return(nil).Times(2)
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	creditRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, credit *entity.Credit) (*entity.Credit, error) {
+		assert.True(t, decimal.NewFromInt(15).Equal(credit.InterestRate))
+		assert.Equal(t, amortization.Differentiated, credit.ScheduleType)
+		credit.ID = 3
+		return credit, nil
+	})
+	creditRepo.EXPECT().CreatePaymentSchedule(gomock.Any(), gomock.Any()).Return(nil, nil).Times(6)
+	creditRepo.EXPECT().CreatePrincipalPosting(gomock.Any(), gomock.Any()).Return(nil)
+
+	accountRepo := NewMockAccountRepository(ctrl)
+	accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	accountRepo.EXPECT().FindByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, UserID: 1}, nil)
+	accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.Zero}, nil)
+	accountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	transactionRepo := NewMockFinancialTransactionRepository(ctrl)
+	transactionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int32(9), nil)
+
+	agreementRepo := NewMockAgreementRepository(ctrl)
+	agreementRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	accountService := NewAccountService(logger, accountRepo)
+	service := &CreditApplicationService{
+		repo:           repo,
+		creditService:  NewCreditService(logger, &config.Config{}, calendar.Russia(), creditRepo, transactionRepo, nil, agreementRepo, nil, nil, accountService, nil),
+		accountService: accountService,
+		logger:         logger,
+	}
+	approved, err := service.Approve(context.TODO(), adminID, 7, []string{"income confirmed"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ApplicationDisbursed, approved.Status)
+	assert.Equal(t, int32(3), *approved.CreditID)
+}
+
+func TestCreditApplicationService_Reject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	t.Run("reason is required", func(t *testing.T) {
+		service := &CreditApplicationService{logger: logger}
+		_, err := service.Reject(context.TODO(), 42, 7, nil)
+
+		assert.ErrorIs(t, err, entity.ErrDecisionReasonRequired)
+	})
+
+	t.Run("disbursed application cannot be rejected", func(t *testing.T) {
+		repo := NewMockCreditApplicationRepository(ctrl)
+		repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		repo.EXPECT().LockByID(gomock.Any(), int32(7)).Return(&entity.CreditApplication{ID: 7, Status: entity.ApplicationDisbursed}, nil)
+
+		service := &CreditApplicationService{repo: repo, logger: logger}
+		_, err := service.Reject(context.TODO(), 42, 7, []string{"fraud suspected"})
+
+		assert.ErrorIs(t, err, entity.ErrInvalidApplicationStatus)
+	})
+
+	t.Run("under review application is rejected", func(t *testing.T) {
+		adminID := int32(42)
+		application := &entity.CreditApplication{ID: 7, Status: entity.ApplicationUnderReview}
+
+		repo := NewMockCreditApplicationRepository(ctrl)
+		repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		repo.EXPECT().LockByID(gomock.Any(), int32(7)).Return(application, nil)
+		repo.EXPECT().CreateDecision(gomock.Any(), &entity.CreditDecision{
+			ApplicationID: 7,
+			Status:        entity.ApplicationRejected,
+			Reasons:       []string{"income not confirmed"},
+			DecidedBy:     &adminID,
+		}).Return(nil)
+		repo.EXPECT().Update(gomock.Any(), application).Return(nil)
+
+		service := &CreditApplicationService{repo: repo, logger: logger}
+		rejected, err := service.Reject(context.TODO(), adminID, 7, []string{"income not confirmed"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ApplicationRejected, rejected.Status)
+	})
+}
@@ -0,0 +1,257 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// FloatingRateRepository предоставляет историю ключевой ставки ЦБ и хранит пересмотры плавающих ставок по кредитам.
+type FloatingRateRepository interface {
+	FindKeyRate(ctx context.Context, at time.Time) (*entity.CentralBankRate, error)
+	CreateRateReset(ctx context.Context, reset *entity.RateReset) error
+	ListRateResets(ctx context.Context, creditID int32) ([]entity.RateReset, error)
+}
+
+// FloatingRate возвращает плавающую ставку на дату at: ключевую ставку ЦБ, действовавшую на эту дату, с маржой банка.
+func (s *CreditService) FloatingRate(ctx context.Context, at time.Time) (decimal.Decimal, error) {
+	keyRate, err := s.floatingRateRepository.FindKeyRate(ctx, at)
+	if err != nil {
+		s.logger.Error("failed to get key rate", "error", err)
+		return decimal.Zero, fmt.Errorf("failed to get key rate: %w", err)
+	}
+
+	return keyRate.Rate.Add(s.floatingRateMargin), nil
+}
+
+// FixedRate возвращает фиксированную ставку на момент now: текущую ключевую ставку ЦБ с маржой банка.
+func (s *CreditService) FixedRate(ctx context.Context, now time.Time) (decimal.Decimal, error) {
+	keyRate, err := s.rateProvider.KeyRate(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to get key rate", "error", err)
+		return decimal.Zero, fmt.Errorf("failed to get key rate: %w", err)
+	}
+
+	return keyRate.Rate.Add(s.fixedRateMargin), nil
+}
+
+// floatingRateOption возвращает условия плавающей ставки банка по умолчанию.
+func (s *CreditService) floatingRateOption() CreditOption {
+	return WithFloatingRate(s.floatingRateMargin, s.floatingRateResetMonths)
+}
+
+// GetRateResets возвращает пересмотры плавающей ставки по кредиту creditID пользователя userID.
+func (s *CreditService) GetRateResets(ctx context.Context, userID, creditID int32) ([]entity.RateReset, error) {
+	if _, err := s.getUserCredit(ctx, userID, creditID); err != nil {
+		return nil, err
+	}
+
+	resets, err := s.floatingRateRepository.ListRateResets(ctx, creditID)
+	if err != nil {
+		s.logger.Error("failed to get rate resets", "error", err)
+		return nil, err
+	}
+
+	return resets, nil
+}
+
+// ResetFloatingRates пересматривает ставки по кредитам с плавающей ставкой, пересмотр которых назначен не позже
+// дня now. Каждый кредит обрабатывается в отдельной транзакции под блокировкой строки. Если ставка изменилась,
+// прежний график сохраняется отдельной версией, оставшиеся платежи перестраиваются по новой ставке,
+// а заемщик получает уведомление.
+func (s *CreditService) ResetFloatingRates(ctx context.Context, now time.Time) error {
+	year, month, day := now.Date()
+	dueBy := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+
+	var afterID int32
+	var processed, failed int
+	for {
+		var done bool
+		var credit *entity.Credit
+		var reset *entity.RateReset
+		lastID := afterID
+
+		err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+			var err error
+			credit, err = s.creditRepository.LockRateResetDue(ctx, dueBy, afterID)
+			if errors.Is(err, entity.ErrCreditNotFound) {
+				done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			afterID = credit.ID
+
+			reset, err = s.resetRate(ctx, credit)
+			return err
+		})
+		if done {
+			break
+		}
+
+		if err != nil {
+			if afterID == lastID {
+				s.logger.Error("failed to find credits for rate reset", "error", err)
+				return fmt.Errorf("failed to find credits for rate reset: %w", err)
+			}
+
+			s.logger.Error("failed to reset floating rate", "credit_id", afterID, "error", err)
+			failed++
+			continue
+		}
+
+		processed++
+		if reset.ScheduleVersion != nil {
+			s.notifyRateReset(ctx, credit, reset)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to reset rates on %d of %d credits", failed, processed+failed)
+	}
+
+	s.logger.Info("floating rates reset", "count", processed)
+	return nil
+}
+
+// resetRate пересматривает ставку кредита credit на дату его очередного пересмотра и назначает следующий пересмотр.
+// Проценты до даты пересмотра начисляются по прежней ставке и прежнему графику до их замены.
+func (s *CreditService) resetRate(ctx context.Context, credit *entity.Credit) (*entity.RateReset, error) {
+	resetDate := *credit.NextRateReset
+
+	keyRate, err := s.floatingRateRepository.FindKeyRate(ctx, resetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accrueInterest(ctx, credit, accrualDay(resetDate)); err != nil {
+		return nil, err
+	}
+
+	reset := &entity.RateReset{
+		CreditID:     credit.ID,
+		ResetDate:    resetDate,
+		KeyRate:      keyRate.Rate,
+		KeyRateDate:  keyRate.RateDate,
+		PreviousRate: credit.InterestRate,
+		NewRate:      keyRate.Rate.Add(credit.RateMargin),
+	}
+
+	payments, err := s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := planRateReset(credit, payments, reset.NewRate, resetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schedule) > 0 && !reset.NewRate.Equal(credit.InterestRate) {
+		version := &entity.ScheduleVersion{CreditID: credit.ID, Schedule: payments}
+		if err := s.restructuringRepository.CreateScheduleVersion(ctx, version); err != nil {
+			return nil, err
+		}
+		reset.ScheduleVersion = &version.Version
+
+		if err := s.creditRepository.DeletePendingPaymentsAfter(ctx, credit.ID, resetDate); err != nil {
+			return nil, err
+		}
+
+		for i := range schedule {
+			if _, err := s.creditRepository.CreatePaymentSchedule(ctx, &schedule[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var nextReset *time.Time
+	if len(schedule) > 0 {
+		next := amortization.AddMonths(resetDate, int(credit.RateResetMonths))
+		nextReset = &next
+	}
+
+	if err := s.creditRepository.UpdateCreditRate(ctx, credit.ID, reset.NewRate, nextReset); err != nil {
+		return nil, err
+	}
+
+	if err := s.floatingRateRepository.CreateRateReset(ctx, reset); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("floating rate reset", "credit_id", credit.ID, "previous_rate", reset.PreviousRate, "new_rate", reset.NewRate)
+
+	credit.InterestRate = reset.NewRate
+	credit.NextRateReset = nextReset
+	return reset, nil
+}
+
+// notifyRateReset сообщает заемщику о новой ставке по кредиту. Ставка к этому моменту уже пересмотрена,
+// поэтому ошибка доставки уведомления только записывается в журнал.
+func (s *CreditService) notifyRateReset(ctx context.Context, credit *entity.Credit, reset *entity.RateReset) {
+	subject := fmt.Sprintf("Изменение ставки по кредиту № %d", credit.ID)
+	body := fmt.Sprintf(
+		"С %s ставка по кредиту № %d изменилась с %s%% до %s%% годовых: ключевая ставка ЦБ %s%% от %s и маржа %s%%. "+
+			"График оставшихся платежей пересчитан, его можно посмотреть в личном кабинете.",
+		reset.ResetDate.Format("02.01.2006"),
+		credit.ID,
+		reset.PreviousRate.StringFixed(2),
+		reset.NewRate.StringFixed(2),
+		reset.KeyRate.StringFixed(2),
+		reset.KeyRateDate.Format("02.01.2006"),
+		credit.RateMargin.StringFixed(2),
+	)
+
+	if err := s.notificationService.Notify(ctx, credit.UserID, subject, body); err != nil {
+		s.logger.Error("failed to notify about rate reset", "credit_id", credit.ID, "error", err)
+	}
+}
+
+// planRateReset перестраивает по ставке rate платежи с датой позже resetDate, по которым еще ничего не внесено.
+// Даты платежей сохраняются, остаток основного долга распределяется по ним заново. Проценты за период,
+// в который попадает дата пересмотра, начисляются по новой ставке. Возвращает пустой график, если перестраивать нечего.
+func planRateReset(
+	credit *entity.Credit,
+	payments []entity.PaymentSchedule,
+	rate decimal.Decimal,
+	resetDate time.Time,
+) ([]entity.PaymentSchedule, error) {
+	periodStart := credit.CreatedAt
+	principal := decimal.Zero
+	var dates []time.Time
+	for _, payment := range payments {
+		if !payment.PaymentDate.After(resetDate) || payment.Status != entity.PaymentPending || payment.PaidAmount.IsPositive() {
+			periodStart = payment.PaymentDate
+			continue
+		}
+
+		principal = principal.Add(payment.PrincipalAmount)
+		dates = append(dates, payment.PaymentDate)
+	}
+
+	if len(dates) == 0 {
+		return nil, nil
+	}
+
+	installments, err := amortization.Generate(amortization.Params{
+		Principal:    principal,
+		AnnualRate:   rate,
+		Term:         len(dates),
+		Method:       credit.ScheduleType,
+		DayCount:     credit.DayCount,
+		StartDate:    periodStart,
+		PaymentDates: dates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate payment schedule: %w", err)
+	}
+
+	return toPaymentSchedule(credit.ID, installments), nil
+}
@@ -0,0 +1,184 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func floatingCredit(t *testing.T) (*entity.Credit, []entity.PaymentSchedule) {
+	t.Helper()
+
+	createdAt := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	nextReset := amortization.AddMonths(createdAt, 3)
+	credit := &entity.Credit{
+		ID:              3,
+		UserID:          1,
+		Amount:          decimal.NewFromInt(60000),
+		InterestRate:    decimal.NewFromInt(20),
+		TermInMonths:    6,
+		ScheduleType:    amortization.Annuity,
+		DayCount:        amortization.Actual365,
+		RateType:        entity.FloatingRate,
+		RateMargin:      decimal.NewFromInt(5),
+		RateResetMonths: 3,
+		NextRateReset:   &nextReset,
+		Status:          entity.CreditStatusActive,
+		CreatedAt:       createdAt,
+	}
+
+	payments, err := buildPaymentSchedule(credit, createdAt, calendar.Russia())
+	require.NoError(t, err)
+	for i := range payments[:3] {
+		paidAt := payments[i].PaymentDate
+		payments[i].Status = entity.PaymentPaid
+		payments[i].PaidAmount = payments[i].PaymentAmount
+		payments[i].PaidAt = &paidAt
+	}
+
+	return credit, payments
+}
+
+func TestPlanRateReset(t *testing.T) {
+	credit, payments := floatingCredit(t)
+
+	schedule, err := planRateReset(credit, payments, decimal.NewFromInt(21), *credit.NextRateReset)
+	require.NoError(t, err)
+	require.Len(t, schedule, 3)
+
+	principal := decimal.Zero
+	for i, payment := range schedule {
+		assert.Equal(t, payments[i+3].PaymentDate, payment.PaymentDate)
+		assert.Equal(t, entity.PaymentPending, payment.Status)
+		principal = principal.Add(payment.PrincipalAmount)
+	}
+	assert.True(t, payments[2].Balance.Equal(principal), "remaining principal %s is redistributed", principal)
+	assert.True(t, schedule[0].InterestAmount.GreaterThan(payments[3].InterestAmount))
+	assert.True(t, schedule[2].Balance.IsZero())
+
+	empty, err := planRateReset(credit, payments, decimal.NewFromInt(21), payments[5].PaymentDate)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestCreditService_ResetFloatingRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 4, 15, 0, 50, 0, 0, time.UTC)
+	dueBy := time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC)
+	keyRateDate := time.Date(2025, 3, 21, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rate change rebuilds schedule and notifies borrower", func(t *testing.T) {
+		credit, payments := floatingCredit(t)
+		resetDate := *credit.NextRateReset
+		accruedTo := resetDate.AddDate(0, 0, -5)
+		credit.InterestAccruedTo = &accruedTo
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(2)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(0)).Return(credit, nil)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(3)).Return(nil, entity.ErrCreditNotFound)
+		creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), int32(3)).Return(payments, nil).Times(2)
+		// Проценты до дня пересмотра начисляются по прежней ставке.
+		creditRepo.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.InterestPosting) error {
+			assert.True(t, posting.PostingDate.Before(resetDate))
+			assert.True(t, decimal.NewFromInt(20).Equal(posting.InterestRate))
+			return nil
+		}).Times(5)
+		creditRepo.EXPECT().UpdateInterestReceivable(gomock.Any(), int32(3), gomock.Any(), &resetDate).Return(nil)
+		creditRepo.EXPECT().DeletePendingPaymentsAfter(gomock.Any(), int32(3), resetDate).Return(nil)
+		creditRepo.EXPECT().CreatePaymentSchedule(gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+		nextReset := amortization.AddMonths(resetDate, 3)
+		creditRepo.EXPECT().UpdateCreditRate(gomock.Any(), int32(3), decimal.NewFromInt(21), &nextReset).Return(nil)
+
+		restructuringRepo := NewMockRestructuringRepository(ctrl)
+		restructuringRepo.EXPECT().CreateScheduleVersion(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, version *entity.ScheduleVersion) error {
+			assert.Equal(t, payments, version.Schedule)
+			assert.Nil(t, version.RestructuringID)
+			version.Version = 1
+			return nil
+		})
+
+		floatingRepo := NewMockFloatingRateRepository(ctrl)
+		floatingRepo.EXPECT().FindKeyRate(gomock.Any(), resetDate).Return(&entity.CentralBankRate{Rate: decimal.NewFromInt(16), RateDate: keyRateDate}, nil)
+		floatingRepo.EXPECT().CreateRateReset(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, reset *entity.RateReset) error {
+			assert.True(t, decimal.NewFromInt(20).Equal(reset.PreviousRate))
+			assert.True(t, decimal.NewFromInt(21).Equal(reset.NewRate))
+			assert.Equal(t, keyRateDate, reset.KeyRateDate)
+			assert.Equal(t, int32(1), *reset.ScheduleVersion)
+			return nil
+		})
+
+		notificationRepo := NewMockNotificationRepository(ctrl)
+		sender := NewMockEmailSender(ctrl)
+		notificationRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(&entity.Notification{ID: 1}, nil)
+		notificationRepo.EXPECT().GetUserEmail(gomock.Any(), int32(1)).Return("user@example.com", nil)
+		sender.EXPECT().Send("user@example.com", "Изменение ставки по кредиту № 3", gomock.Any()).DoAndReturn(func(_, _, body string) error {
+			assert.True(t, strings.Contains(body, "с 20.00% до 21.00% годовых"), body)
+			return nil
+		})
+		notificationRepo.EXPECT().UpdateStatus(gomock.Any(), int32(1), entity.NotificationSent).Return(nil)
+
+		service := &CreditService{
+			creditRepository:        creditRepo,
+			restructuringRepository: restructuringRepo,
+			floatingRateRepository:  floatingRepo,
+			notificationService:     NewNotificationService(logger, notificationRepo, sender),
+			logger:                  logger,
+		}
+
+		assert.NoError(t, service.ResetFloatingRates(context.TODO(), now))
+	})
+
+	t.Run("unchanged rate only moves the next reset", func(t *testing.T) {
+		credit, payments := floatingCredit(t)
+		resetDate := *credit.NextRateReset
+		credit.InterestAccruedTo = &resetDate
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(2)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(0)).Return(credit, nil)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(3)).Return(nil, entity.ErrCreditNotFound)
+		creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), int32(3)).Return(payments, nil)
+		nextReset := amortization.AddMonths(resetDate, 3)
+		creditRepo.EXPECT().UpdateCreditRate(gomock.Any(), int32(3), decimal.NewFromInt(20), &nextReset).Return(nil)
+
+		floatingRepo := NewMockFloatingRateRepository(ctrl)
+		floatingRepo.EXPECT().FindKeyRate(gomock.Any(), resetDate).Return(&entity.CentralBankRate{Rate: decimal.NewFromInt(15), RateDate: keyRateDate}, nil)
+		floatingRepo.EXPECT().CreateRateReset(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, reset *entity.RateReset) error {
+			assert.Nil(t, reset.ScheduleVersion)
+			return nil
+		})
+
+		service := &CreditService{creditRepository: creditRepo, floatingRateRepository: floatingRepo, logger: logger}
+
+		assert.NoError(t, service.ResetFloatingRates(context.TODO(), now))
+	})
+
+	t.Run("missing key rate fails the credit", func(t *testing.T) {
+		credit, _ := floatingCredit(t)
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(2)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(0)).Return(credit, nil)
+		creditRepo.EXPECT().LockRateResetDue(gomock.Any(), dueBy, int32(3)).Return(nil, entity.ErrCreditNotFound)
+
+		floatingRepo := NewMockFloatingRateRepository(ctrl)
+		floatingRepo.EXPECT().FindKeyRate(gomock.Any(), *credit.NextRateReset).Return(nil, entity.ErrKeyRateNotFound)
+
+		service := &CreditService{creditRepository: creditRepo, floatingRateRepository: floatingRepo, logger: logger}
+
+		assert.Error(t, service.ResetFloatingRates(context.TODO(), now))
+	})
+}
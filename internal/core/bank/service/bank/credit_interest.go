@@ -0,0 +1,225 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// AccrueInterest ежедневно начисляет проценты по действующим кредитам за каждый день до дня now, не включая его,
+// и отражает их в начисленных процентах к получению. Каждый кредит обрабатывается в отдельной транзакции
+// под блокировкой строки, а день, до которого начислены проценты, сохраняется вместе с проводками, поэтому повторный
+// запуск не начислит проценты дважды, а пропущенные дни будут начислены при следующем запуске.
+func (s *CreditService) AccrueInterest(ctx context.Context, now time.Time) error {
+	today := accrualDay(now)
+
+	var afterID int32
+	var processed, failed int
+	for {
+		var done bool
+		lastID := afterID
+
+		err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+			credit, err := s.creditRepository.LockAccrualDue(ctx, today, afterID)
+			if errors.Is(err, entity.ErrCreditNotFound) {
+				done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			afterID = credit.ID
+
+			return s.accrueInterest(ctx, credit, today)
+		})
+		if done {
+			break
+		}
+
+		if err != nil {
+			if afterID == lastID {
+				s.logger.Error("failed to find credits for interest accrual", "error", err)
+				return fmt.Errorf("failed to find credits for interest accrual: %w", err)
+			}
+
+			s.logger.Error("failed to accrue interest", "credit_id", afterID, "error", err)
+			failed++
+			continue
+		}
+
+		processed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to accrue interest for %d of %d credits", failed, processed+failed)
+	}
+
+	s.logger.Info("interest accrued", "count", processed)
+	return nil
+}
+
+// accrueInterest начисляет проценты по заблокированному кредиту credit за дни после предыдущего начисления
+// до дня today, не включая его, и обновляет начисленные проценты кредита.
+func (s *CreditService) accrueInterest(ctx context.Context, credit *entity.Credit, today time.Time) error {
+	if !accrualStart(credit).Before(today) {
+		return nil
+	}
+
+	payments, err := s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+	if err != nil {
+		s.logger.Error("failed to get payment schedule", "error", err)
+		return fmt.Errorf("failed to get payment schedule: %w", err)
+	}
+
+	receivable := credit.InterestReceivable
+	postings := planInterestAccruals(credit, payments, today)
+	for i := range postings {
+		if err := s.creditRepository.CreateInterestPosting(ctx, &postings[i]); err != nil {
+			s.logger.Error("failed to create interest posting", "error", err)
+			return fmt.Errorf("failed to create interest posting: %w", err)
+		}
+
+		receivable = receivable.Add(postings[i].Amount)
+	}
+
+	if err := s.creditRepository.UpdateInterestReceivable(ctx, credit.ID, receivable, &today); err != nil {
+		s.logger.Error("failed to update interest receivable", "error", err)
+		return fmt.Errorf("failed to update interest receivable: %w", err)
+	}
+
+	s.logger.Info("credit interest accrued", "credit_id", credit.ID, "postings", len(postings), "amount", receivable.Sub(credit.InterestReceivable))
+
+	credit.InterestReceivable = receivable
+	credit.InterestAccruedTo = &today
+	return nil
+}
+
+// settleInterest погашает из суммы amount, внесенной по платежу paymentID, начисленные проценты заблокированного
+// кредита credit. Остаток суммы идет в счет основного долга и в начисленных процентах не отражается.
+func (s *CreditService) settleInterest(ctx context.Context, credit *entity.Credit, paymentID int32, amount decimal.Decimal, now time.Time) error {
+	return s.reduceInterestReceivable(ctx, credit, entity.InterestSettlement, paymentID, amount, now)
+}
+
+// capitalizeInterest переносит в основной долг заблокированного кредита credit проценты, начисленные за период
+// платежа payment, который приходится на кредитные каникулы, и списывает их из начисленных процентов.
+func (s *CreditService) capitalizeInterest(ctx context.Context, credit *entity.Credit, payment *entity.PaymentSchedule, now time.Time) error {
+	if !payment.CapitalizesInterest() {
+		return nil
+	}
+
+	if err := s.postPrincipal(ctx, credit, entity.PrincipalCapitalization, &payment.ID, payment.PrincipalAmount.Neg(), now); err != nil {
+		return err
+	}
+
+	return s.reduceInterestReceivable(ctx, credit, entity.InterestCapitalization, payment.ID, payment.PrincipalAmount.Neg(), now)
+}
+
+// reduceInterestReceivable уменьшает начисленные проценты кредита credit на amount, но не больше их остатка,
+// проводкой вида postingType по платежу paymentID.
+func (s *CreditService) reduceInterestReceivable(
+	ctx context.Context,
+	credit *entity.Credit,
+	postingType entity.InterestPostingType,
+	paymentID int32,
+	amount decimal.Decimal,
+	now time.Time,
+) error {
+	reduced := decimal.Min(amount, credit.InterestReceivable)
+	if !reduced.IsPositive() {
+		return nil
+	}
+
+	posting := &entity.InterestPosting{
+		CreditID:    credit.ID,
+		PostingDate: accrualDay(now),
+		Type:        postingType,
+		Amount:      reduced.Neg(),
+		PaymentID:   &paymentID,
+	}
+	if err := s.creditRepository.CreateInterestPosting(ctx, posting); err != nil {
+		s.logger.Error("failed to create interest posting", "error", err)
+		return fmt.Errorf("failed to create interest posting: %w", err)
+	}
+
+	receivable := credit.InterestReceivable.Sub(reduced)
+	if err := s.creditRepository.UpdateInterestReceivable(ctx, credit.ID, receivable, credit.InterestAccruedTo); err != nil {
+		s.logger.Error("failed to update interest receivable", "error", err)
+		return fmt.Errorf("failed to update interest receivable: %w", err)
+	}
+
+	s.logger.Info("accrued interest reduced", "credit_id", credit.ID, "payment_id", paymentID, "type", postingType, "amount", reduced)
+
+	credit.InterestReceivable = receivable
+	return nil
+}
+
+// planInterestAccruals рассчитывает проводки начисления процентов по кредиту credit за каждый день после
+// предыдущего начисления до дня today, не включая его. Проценты начисляются на фактический остаток основного долга:
+// основной долг по графику за вычетом погашенного, включая просроченные платежи. Погашения учитываются на момент
+// расчета, поэтому перед каждым платежом проценты доначисляются по день платежа. Проценты за кредитные каникулы
+// прибавляются к основному долгу в дату соответствующей строки графика. Начисление за день равно приросту процентов
+// с начала процентного периода, поэтому сумма начислений за период совпадает с процентами по графику, а дни
+// с нулевой суммой пропускаются.
+func planInterestAccruals(credit *entity.Credit, payments []entity.PaymentSchedule, today time.Time) []entity.InterestPosting {
+	outstanding := decimal.Zero
+	for i := range payments {
+		outstanding = outstanding.Add(payments[i].PrincipalOutstanding())
+	}
+
+	var postings []entity.InterestPosting
+	for day := accrualStart(credit); day.Before(today); day = day.AddDate(0, 0, 1) {
+		periodStart := accrualDay(credit.CreatedAt)
+		principal := outstanding
+		for _, payment := range payments {
+			date := accrualDay(payment.PaymentDate)
+			if date.After(day) {
+				if payment.CapitalizesInterest() {
+					principal = principal.Add(payment.PrincipalAmount)
+				}
+			} else if date.After(periodStart) {
+				periodStart = date
+			}
+		}
+
+		if !principal.IsPositive() {
+			continue
+		}
+
+		accrued := amortization.Interest(principal, credit.InterestRate, credit.DayCount, periodStart, day)
+		amount := amortization.Interest(principal, credit.InterestRate, credit.DayCount, periodStart, day.AddDate(0, 0, 1)).Sub(accrued)
+		if amount.IsZero() {
+			continue
+		}
+
+		postings = append(postings, entity.InterestPosting{
+			CreditID:     credit.ID,
+			PostingDate:  day,
+			Type:         entity.InterestAccrual,
+			Principal:    principal,
+			InterestRate: credit.InterestRate,
+			Amount:       amount,
+		})
+	}
+
+	return postings
+}
+
+// accrualStart возвращает день, с которого по кредиту credit еще не начислены проценты.
+func accrualStart(credit *entity.Credit) time.Time {
+	if credit.InterestAccruedTo != nil {
+		return accrualDay(*credit.InterestAccruedTo)
+	}
+
+	return accrualDay(credit.CreatedAt)
+}
+
+// accrualDay отбрасывает время, оставляя день начисления.
+func accrualDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
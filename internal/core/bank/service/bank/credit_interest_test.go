@@ -0,0 +1,187 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func accruingCredit(t *testing.T) (*entity.Credit, []entity.PaymentSchedule) {
+	t.Helper()
+
+	credit := &entity.Credit{
+		ID:           3,
+		UserID:       1,
+		Amount:       decimal.NewFromInt(60000),
+		InterestRate: decimal.NewFromInt(20),
+		TermInMonths: 6,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Actual365,
+		Status:       entity.CreditStatusActive,
+		CreatedAt:    time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+
+	payments, err := buildPaymentSchedule(credit, credit.CreatedAt, calendar.Russia())
+	require.NoError(t, err)
+
+	return credit, payments
+}
+
+func TestPlanInterestAccruals(t *testing.T) {
+	t.Run("accruals of a period add up to scheduled interest", func(t *testing.T) {
+		credit, payments := accruingCredit(t)
+
+		first := planInterestAccruals(credit, payments, accrualDay(payments[0].PaymentDate))
+		assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), first[0].PostingDate)
+		assert.True(t, payments[0].InterestAmount.Equal(sumPostings(t, first, credit.Amount)), "first period")
+
+		// Первый платеж внесен в срок, поэтому во втором периоде проценты начисляются на остаток после него.
+		payments[0].Apply(payments[0].PaymentAmount, payments[0].PaymentDate)
+		accruedTo := accrualDay(payments[0].PaymentDate)
+		credit.InterestAccruedTo = &accruedTo
+
+		second := planInterestAccruals(credit, payments, accrualDay(payments[1].PaymentDate))
+		assert.Equal(t, time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC), second[len(second)-1].PostingDate)
+		assert.True(t, payments[1].InterestAmount.Equal(sumPostings(t, second, payments[0].Balance)), "second period")
+	})
+
+	t.Run("continues after previous accrual", func(t *testing.T) {
+		credit, payments := accruingCredit(t)
+		accruedTo := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+		credit.InterestAccruedTo = &accruedTo
+
+		postings := planInterestAccruals(credit, payments, time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC))
+
+		require.Len(t, postings, 2)
+		assert.Equal(t, accruedTo, postings[0].PostingDate)
+		assert.Equal(t, accruedTo.AddDate(0, 0, 1), postings[1].PostingDate)
+	})
+
+	t.Run("unpaid principal keeps accruing after due date", func(t *testing.T) {
+		credit, payments := accruingCredit(t)
+		payments[0].Apply(payments[0].InterestAmount, payments[0].PaymentDate.AddDate(0, 0, 1))
+		accruedTo := accrualDay(payments[0].PaymentDate)
+		credit.InterestAccruedTo = &accruedTo
+
+		postings := planInterestAccruals(credit, payments, accruedTo.AddDate(0, 0, 3))
+
+		require.Len(t, postings, 3)
+		assert.True(t, credit.Amount.Equal(postings[0].Principal), "principal: %s", postings[0].Principal)
+	})
+
+	t.Run("overdue principal accrues after last payment date", func(t *testing.T) {
+		credit, payments := accruingCredit(t)
+		accruedTo := accrualDay(payments[len(payments)-1].PaymentDate)
+		credit.InterestAccruedTo = &accruedTo
+
+		postings := planInterestAccruals(credit, payments, accruedTo.AddDate(0, 0, 5))
+		require.Len(t, postings, 5)
+		assert.True(t, credit.Amount.Equal(postings[0].Principal))
+
+		for i := range payments {
+			payments[i].Apply(payments[i].PaymentAmount, payments[i].PaymentDate)
+		}
+		assert.Empty(t, planInterestAccruals(credit, payments, accruedTo.AddDate(0, 0, 5)))
+	})
+
+	t.Run("holiday interest is capitalized on holiday dates", func(t *testing.T) {
+		credit, payments := restructuringCredit(t)
+		schedule, err := planRestructuring(credit, payments, entity.PaymentHoliday, 2, calendar.Russia())
+		require.NoError(t, err)
+
+		restructured := append(payments[:1:1], schedule...)
+		principal := payments[0].Balance
+		accruedTo := accrualDay(payments[0].PaymentDate)
+		credit.InterestAccruedTo = &accruedTo
+
+		holiday := planInterestAccruals(credit, restructured, accrualDay(schedule[0].PaymentDate))
+		assert.True(t, schedule[0].InterestAmount.Equal(sumPostings(t, holiday, principal)), "holiday interest")
+
+		accruedTo = accrualDay(schedule[0].PaymentDate)
+		next := planInterestAccruals(credit, restructured, accruedTo.AddDate(0, 0, 1))
+		require.Len(t, next, 1)
+		assert.True(t, principal.Add(schedule[0].InterestAmount).Equal(next[0].Principal), "principal: %s", next[0].Principal)
+	})
+}
+
+// sumPostings проверяет, что все начисления сделаны на основной долг principal, и возвращает их сумму.
+func sumPostings(t *testing.T, postings []entity.InterestPosting, principal decimal.Decimal) decimal.Decimal {
+	t.Helper()
+
+	sum := decimal.Zero
+	for _, posting := range postings {
+		assert.Equal(t, entity.InterestAccrual, posting.Type)
+		assert.True(t, principal.Equal(posting.Principal), "principal: %s", posting.Principal)
+		sum = sum.Add(posting.Amount)
+	}
+
+	return sum
+}
+
+func TestCreditService_AccrueInterest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 2, 3, 0, 5, 0, 0, time.UTC)
+	today := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("posts missed days and updates receivable", func(t *testing.T) {
+		credit, payments := accruingCredit(t)
+		accruedTo := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+		credit.InterestAccruedTo = &accruedTo
+		credit.InterestReceivable = decimal.RequireFromString("557.26")
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		gomock.InOrder(
+			creditRepo.EXPECT().LockAccrualDue(gomock.Any(), today, int32(0)).Return(credit, nil),
+			creditRepo.EXPECT().LockAccrualDue(gomock.Any(), today, credit.ID).Return(nil, entity.ErrCreditNotFound),
+		)
+		creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), credit.ID).Return(payments, nil)
+
+		posted := decimal.Zero
+		var dates []time.Time
+		creditRepo.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.InterestPosting) error {
+			posted = posted.Add(posting.Amount)
+			dates = append(dates, posting.PostingDate)
+			return nil
+		}).Times(2)
+		creditRepo.EXPECT().UpdateInterestReceivable(gomock.Any(), credit.ID, gomock.Any(), &today).
+			DoAndReturn(func(_ context.Context, _ int32, receivable decimal.Decimal, _ *time.Time) error {
+				assert.True(t, decimal.RequireFromString("557.26").Add(posted).Equal(receivable), "receivable: %s", receivable)
+				return nil
+			})
+
+		service := &CreditService{creditRepository: creditRepo, logger: logger}
+		err := service.AccrueInterest(context.TODO(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{accruedTo, accruedTo.AddDate(0, 0, 1)}, dates)
+		assert.True(t, posted.IsPositive())
+		assert.Equal(t, today, *credit.InterestAccruedTo)
+	})
+
+	t.Run("failed credit does not stop accrual", func(t *testing.T) {
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		gomock.InOrder(
+			creditRepo.EXPECT().LockAccrualDue(gomock.Any(), today, int32(0)).Return(&entity.Credit{ID: 4, CreatedAt: today.AddDate(0, 0, -1)}, nil),
+			creditRepo.EXPECT().LockAccrualDue(gomock.Any(), today, int32(4)).Return(nil, entity.ErrCreditNotFound),
+		)
+		creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), int32(4)).Return(nil, assert.AnError)
+
+		service := &CreditService{creditRepository: creditRepo, logger: logger}
+		err := service.AccrueInterest(context.TODO(), now)
+
+		assert.ErrorContains(t, err, "failed to accrue interest for 1 of 1 credits")
+	})
+}
@@ -0,0 +1,389 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"time"
+)
+
+// CreditLineRepository хранит кредитные линии, их выписки и обороты по картам кредитных счетов.
+// Repayments и BalanceAt восстанавливают зачисления и баланс кредитного счета по журналу движений баланса.
+type CreditLineRepository interface {
+	Create(ctx context.Context, line *entity.CreditLine) error
+	GetByAccountID(ctx context.Context, accountID int32) (*entity.CreditLine, error)
+	LockUnprocessed(ctx context.Context, today time.Time, afterID int32) (*entity.CreditLine, error)
+	Update(ctx context.Context, line *entity.CreditLine) error
+	CreateStatement(ctx context.Context, statement *entity.Statement) error
+	UpdateStatement(ctx context.Context, statement *entity.Statement) error
+	ListDueStatements(ctx context.Context, accountID int32, today time.Time) ([]entity.Statement, error)
+	LastStatement(ctx context.Context, accountID int32) (*entity.Statement, error)
+	ListStatements(ctx context.Context, accountID int32) ([]entity.Statement, error)
+	Turnover(ctx context.Context, accountID int32, from, to time.Time) (*entity.CardTransactionTotals, error)
+	Repayments(ctx context.Context, accountID int32, from, to time.Time) (decimal.Decimal, error)
+	BalanceAt(ctx context.Context, accountID int32, at time.Time) (decimal.Decimal, error)
+
+	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
+}
+
+// CreditLineService управляет возобновляемыми кредитными линиями: открывает их, формирует ежемесячные выписки
+// и списывает проценты, если долг по выписке не погашен полностью в льготный период.
+type CreditLineService struct {
+	creditLineRepository CreditLineRepository
+	accountService       *AccountService
+	calendar             *calendar.Calendar
+	interestRate         decimal.Decimal
+	graceDays            int32
+	minPaymentPercent    decimal.Decimal
+	minPaymentAmount     decimal.Decimal
+	logger               *slog.Logger
+}
+
+// NewCreditLineService создает новый экземпляр CreditLineService с условиями кредитных линий по умолчанию из конфигурации
+// и производственным календарем, по которому срок погашения долга по выписке переносится на рабочий день.
+func NewCreditLineService(
+	logger *slog.Logger,
+	cfg *config.Config,
+	cal *calendar.Calendar,
+	creditLineRepository CreditLineRepository,
+	accountService *AccountService,
+) *CreditLineService {
+	return &CreditLineService{
+		creditLineRepository: creditLineRepository,
+		accountService:       accountService,
+		calendar:             cal,
+		interestRate:         decimal.NewFromFloat(cfg.CreditLineInterestRate),
+		graceDays:            cfg.CreditLineGraceDays,
+		minPaymentPercent:    decimal.NewFromFloat(cfg.CreditLineMinPaymentPercent),
+		minPaymentAmount:     decimal.NewFromFloat(cfg.CreditLineMinPaymentAmount),
+		logger:               logger,
+	}
+}
+
+// CreditLineOption задает условия открываемой кредитной линии.
+type CreditLineOption func(line *entity.CreditLine)
+
+// WithCreditLineRate задает годовую ставку кредитной линии в процентах.
+func WithCreditLineRate(rate decimal.Decimal) CreditLineOption {
+	return func(line *entity.CreditLine) {
+		line.InterestRate = rate
+	}
+}
+
+// WithGraceDays задает, сколько дней после выписки можно погасить долг без процентов.
+func WithGraceDays(days int32) CreditLineOption {
+	return func(line *entity.CreditLine) {
+		line.GraceDays = days
+	}
+}
+
+// WithStatementDay задает день месяца, в который формируется выписка. По умолчанию это день открытия линии.
+func WithStatementDay(day int32) CreditLineOption {
+	return func(line *entity.CreditLine) {
+		line.StatementDay = day
+	}
+}
+
+// Open открывает пользователю userID кредитный счет с лимитом creditLimit и кредитную линию на нем.
+// Первый расчетный период начинается в день now.
+func (s *CreditLineService) Open(
+	ctx context.Context,
+	userID int32,
+	creditLimit decimal.Decimal,
+	now time.Time,
+	opts ...CreditLineOption,
+) (*entity.CreditLineDetails, error) {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	line := &entity.CreditLine{
+		InterestRate:      s.interestRate,
+		GraceDays:         s.graceDays,
+		MinPaymentPercent: s.minPaymentPercent,
+		MinPaymentAmount:  s.minPaymentAmount,
+		StatementDay:      int32(min(day, entity.MaxStatementDay)),
+		CycleStartedAt:    today,
+		AccruedInterest:   decimal.Zero,
+		InterestAccruedAt: today,
+	}
+	for _, opt := range opts {
+		opt(line)
+	}
+
+	if err := line.Validate(); err != nil {
+		return nil, err
+	}
+	if !creditLimit.IsPositive() {
+		return nil, fmt.Errorf("%w: credit limit must be positive", entity.ErrInvalidCreditLine)
+	}
+
+	line.NextStatementAt = line.NextStatementDate(today)
+
+	var account *entity.Account
+	err := s.creditLineRepository.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		account, err = s.accountService.CreateCreditAccount(ctx, userID, creditLimit)
+		if err != nil {
+			return err
+		}
+
+		line.AccountID = account.ID
+		return s.creditLineRepository.Create(ctx, line)
+	})
+	if err != nil {
+		s.logger.Error("failed to open credit line", "error", err)
+		return nil, fmt.Errorf("failed to open credit line: %w", err)
+	}
+
+	s.logger.Info("credit line opened", "user_id", userID, "account_id", account.ID, "credit_limit", creditLimit)
+	return creditLineDetails(line, account), nil
+}
+
+// Get возвращает кредитную линию на счете accountID пользователя userID вместе с долгом и доступным остатком.
+func (s *CreditLineService) Get(ctx context.Context, userID, accountID int32) (*entity.CreditLineDetails, error) {
+	line, account, err := s.getUserCreditLine(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLineDetails(line, account), nil
+}
+
+// ListStatements возвращает выписки по кредитной линии на счете accountID пользователя userID, начиная с последней.
+func (s *CreditLineService) ListStatements(ctx context.Context, userID, accountID int32) ([]entity.Statement, error) {
+	if _, _, err := s.getUserCreditLine(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+
+	return s.creditLineRepository.ListStatements(ctx, accountID)
+}
+
+// getUserCreditLine возвращает кредитную линию на счете accountID и сам счет, если он принадлежит пользователю userID.
+func (s *CreditLineService) getUserCreditLine(ctx context.Context, userID, accountID int32) (*entity.CreditLine, *entity.Account, error) {
+	line, err := s.creditLineRepository.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.accountService.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, nil, entity.ErrCreditLineNotFound
+	}
+
+	return line, account, nil
+}
+
+// ProcessCreditLines обслуживает кредитные линии по день now: подводит итог льготного периода по выпискам,
+// срок которых истек, начисляет проценты на долг и формирует выписки за закончившиеся расчетные периоды.
+// Каждая линия обрабатывается в отдельной транзакции под блокировкой строки, а день последнего начисления
+// сохраняется вместе с линией, поэтому повторный запуск в тот же день ничего не изменит.
+func (s *CreditLineService) ProcessCreditLines(ctx context.Context, now time.Time) error {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	var afterID int32
+	var processed, failed int
+	for {
+		var done bool
+		lastID := afterID
+
+		err := s.creditLineRepository.WithTx(ctx, func(ctx context.Context) error {
+			line, err := s.creditLineRepository.LockUnprocessed(ctx, today, afterID)
+			if errors.Is(err, entity.ErrCreditLineNotFound) {
+				done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			afterID = line.AccountID
+
+			return s.processCreditLine(ctx, line, today)
+		})
+		if done {
+			break
+		}
+
+		if err != nil {
+			if afterID == lastID {
+				s.logger.Error("failed to find credit lines", "error", err)
+				return fmt.Errorf("failed to find credit lines: %w", err)
+			}
+
+			s.logger.Error("failed to process credit line", "account_id", afterID, "error", err)
+			failed++
+			continue
+		}
+
+		processed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to process %d of %d credit lines", failed, processed+failed)
+	}
+
+	s.logger.Info("credit lines processed", "count", processed)
+	return nil
+}
+
+// processCreditLine обслуживает кредитную линию line по день today. Если обработка была пропущена, проценты за каждый
+// пропущенный расчетный период начисляются на долг на конец этого периода, а не на текущий.
+func (s *CreditLineService) processCreditLine(ctx context.Context, line *entity.CreditLine, today time.Time) error {
+	if err := s.settleStatements(ctx, line.AccountID, today); err != nil {
+		return err
+	}
+
+	// Проценты начисляются отдельно за каждый расчетный период, чтобы попасть в выписку своего периода.
+	for !line.NextStatementAt.After(today) {
+		debt, err := s.debtAt(ctx, line.AccountID, line.NextStatementAt)
+		if err != nil {
+			return err
+		}
+
+		line.AccrueInterest(debt, line.NextStatementAt)
+
+		if err := s.closeStatement(ctx, line, debt); err != nil {
+			return err
+		}
+	}
+
+	debt, err := s.debtAt(ctx, line.AccountID, today)
+	if err != nil {
+		return err
+	}
+
+	line.AccrueInterest(debt, today)
+
+	if err := s.creditLineRepository.Update(ctx, line); err != nil {
+		s.logger.Error("failed to update credit line", "error", err)
+		return fmt.Errorf("failed to update credit line: %w", err)
+	}
+
+	return nil
+}
+
+// debtAt возвращает долг по кредитному счету accountID на начало дня at по журналу движений баланса.
+func (s *CreditLineService) debtAt(ctx context.Context, accountID int32, at time.Time) (decimal.Decimal, error) {
+	balance, err := s.creditLineRepository.BalanceAt(ctx, accountID, at)
+	if err != nil {
+		s.logger.Error("failed to get account balance", "error", err)
+		return decimal.Zero, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	return decimal.Max(balance.Neg(), decimal.Zero), nil
+}
+
+// settleStatements подводит итог льготного периода по выпискам счета accountID, срок погашения которых истек
+// раньше дня today. Погашением считаются все зачисления на счет после формирования выписки по последний день
+// льготного периода. Если долг по выписке не погашен полностью, начисленные за ее период проценты списываются со счета.
+func (s *CreditLineService) settleStatements(ctx context.Context, accountID int32, today time.Time) error {
+	statements, err := s.creditLineRepository.ListDueStatements(ctx, accountID, today)
+	if err != nil {
+		s.logger.Error("failed to list due statements", "error", err)
+		return fmt.Errorf("failed to list due statements: %w", err)
+	}
+
+	for i := range statements {
+		statement := &statements[i]
+
+		repaid, err := s.creditLineRepository.Repayments(ctx, accountID, statement.PeriodEnd, statement.DueDate.AddDate(0, 0, 1))
+		if err != nil {
+			s.logger.Error("failed to calculate repayments", "error", err)
+			return fmt.Errorf("failed to calculate repayments: %w", err)
+		}
+
+		interest := statement.Settle(repaid)
+		if interest.IsPositive() {
+			if err := s.accountService.Charge(ctx, accountID, interest); err != nil {
+				s.logger.Error("failed to charge interest", "error", err)
+				return fmt.Errorf("failed to charge interest: %w", err)
+			}
+		}
+
+		if err := s.creditLineRepository.UpdateStatement(ctx, statement); err != nil {
+			s.logger.Error("failed to update statement", "error", err)
+			return fmt.Errorf("failed to update statement: %w", err)
+		}
+
+		s.logger.Info("statement settled", "account_id", accountID, "statement_id", statement.ID, "status", statement.Status, "interest", interest)
+	}
+
+	return nil
+}
+
+// closeStatement формирует выписку с долгом debt на дату выписки за текущий расчетный период линии line и начинает
+// следующий период. Начисленные за период проценты переносятся в выписку и списываются, только если по ней будет
+// потерян льготный период.
+func (s *CreditLineService) closeStatement(ctx context.Context, line *entity.CreditLine, debt decimal.Decimal) error {
+	openingDebt := decimal.Zero
+	last, err := s.creditLineRepository.LastStatement(ctx, line.AccountID)
+	switch {
+	case err == nil:
+		openingDebt = last.ClosingDebt
+	case !errors.Is(err, entity.ErrStatementNotFound):
+		s.logger.Error("failed to get last statement", "error", err)
+		return fmt.Errorf("failed to get last statement: %w", err)
+	}
+
+	turnover, err := s.creditLineRepository.Turnover(ctx, line.AccountID, line.CycleStartedAt, line.NextStatementAt)
+	if err != nil {
+		s.logger.Error("failed to calculate turnover", "error", err)
+		return fmt.Errorf("failed to calculate turnover: %w", err)
+	}
+
+	payments, err := s.creditLineRepository.Repayments(ctx, line.AccountID, line.CycleStartedAt, line.NextStatementAt)
+	if err != nil {
+		s.logger.Error("failed to calculate repayments", "error", err)
+		return fmt.Errorf("failed to calculate repayments: %w", err)
+	}
+
+	statement := &entity.Statement{
+		AccountID:       line.AccountID,
+		PeriodStart:     line.CycleStartedAt,
+		PeriodEnd:       line.NextStatementAt,
+		OpeningDebt:     openingDebt,
+		Purchases:       turnover.Debit,
+		Payments:        payments,
+		ClosingDebt:     debt,
+		MinimumPayment:  line.MinimumPayment(debt),
+		DueDate:         s.calendar.NextBusinessDay(line.NextStatementAt.AddDate(0, 0, int(line.GraceDays))),
+		AccruedInterest: line.AccruedInterest,
+		InterestCharged: decimal.Zero,
+		RepaidAmount:    decimal.Zero,
+		Status:          entity.StatementOpen,
+	}
+	if err := s.creditLineRepository.CreateStatement(ctx, statement); err != nil {
+		s.logger.Error("failed to create statement", "error", err)
+		return fmt.Errorf("failed to create statement: %w", err)
+	}
+
+	line.AccruedInterest = decimal.Zero
+	line.CycleStartedAt = line.NextStatementAt
+	line.NextStatementAt = line.NextStatementDate(line.NextStatementAt)
+
+	s.logger.Info("statement created", "account_id", line.AccountID, "statement_id", statement.ID, "closing_debt", debt)
+	return nil
+}
+
+// creditLineDetails дополняет кредитную линию line сведениями о ее счете account.
+func creditLineDetails(line *entity.CreditLine, account *entity.Account) *entity.CreditLineDetails {
+	return &entity.CreditLineDetails{
+		CreditLine:    *line,
+		AccountNumber: account.AccountNumber,
+		CreditLimit:   account.CreditLimit,
+		Debt:          account.Debt(),
+		Available:     account.Available(),
+	}
+}
@@ -0,0 +1,291 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestAccount_WithdrawDrawsCreditLimit(t *testing.T) {
+	account := &entity.Account{Balance: decimal.NewFromInt(100), AccountType: entity.CreditAccount, CreditLimit: decimal.NewFromInt(1000)}
+
+	assert.NoError(t, account.Withdraw(decimal.NewFromInt(600)))
+	assert.True(t, decimal.NewFromInt(-500).Equal(account.Balance))
+	assert.True(t, decimal.NewFromInt(500).Equal(account.Debt()))
+	assert.True(t, decimal.NewFromInt(500).Equal(account.Available()))
+
+	assert.ErrorIs(t, account.Withdraw(decimal.NewFromInt(501)), entity.ErrInsufficientFunds)
+	assert.True(t, decimal.NewFromInt(-500).Equal(account.Balance))
+}
+
+func TestCreditLine_MinimumPayment(t *testing.T) {
+	line := &entity.CreditLine{MinPaymentPercent: decimal.NewFromInt(5), MinPaymentAmount: decimal.NewFromInt(300)}
+
+	assert.True(t, decimal.NewFromInt(500).Equal(line.MinimumPayment(decimal.NewFromInt(10000))))
+	// 5% от 4000 меньше нижней границы, поэтому платеж равен 300.
+	assert.True(t, decimal.NewFromInt(300).Equal(line.MinimumPayment(decimal.NewFromInt(4000))))
+	// Минимальный платеж не превышает сам долг.
+	assert.True(t, decimal.NewFromInt(120).Equal(line.MinimumPayment(decimal.NewFromInt(120))))
+	assert.True(t, decimal.Zero.Equal(line.MinimumPayment(decimal.Zero)))
+}
+
+func TestCreditLine_NextStatementDate(t *testing.T) {
+	line := &entity.CreditLine{StatementDay: 15}
+
+	assert.Equal(t, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), line.NextStatementDate(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), line.NextStatementDate(time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), line.NextStatementDate(time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCreditLineService_ProcessCreditLinesClosesStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 3, 16, 0, 40, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	line := &entity.CreditLine{
+		AccountID:         7,
+		InterestRate:      decimal.RequireFromString("36.5"),
+		GraceDays:         25,
+		MinPaymentPercent: decimal.NewFromInt(5),
+		MinPaymentAmount:  decimal.NewFromInt(300),
+		StatementDay:      15,
+		CycleStartedAt:    periodStart,
+		NextStatementAt:   periodEnd,
+		AccruedInterest:   decimal.NewFromInt(10),
+		InterestAccruedAt: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	lineRepo := NewMockCreditLineRepository(ctrl)
+	lineRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+	gomock.InOrder(
+		lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(0)).Return(line, nil),
+		lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(7)).Return(nil, entity.ErrCreditLineNotFound),
+	)
+	lineRepo.EXPECT().ListDueStatements(gomock.Any(), int32(7), today).Return(nil, nil)
+	lineRepo.EXPECT().LastStatement(gomock.Any(), int32(7)).Return(nil, entity.ErrStatementNotFound)
+	lineRepo.EXPECT().Turnover(gomock.Any(), int32(7), periodStart, periodEnd).
+		Return(&entity.CardTransactionTotals{Debit: decimal.NewFromInt(10000), Credit: decimal.Zero}, nil)
+	lineRepo.EXPECT().Repayments(gomock.Any(), int32(7), periodStart, periodEnd).Return(decimal.Zero, nil)
+	lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), periodEnd).Return(decimal.NewFromInt(-10000), nil)
+	lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), today).Return(decimal.NewFromInt(-10000), nil)
+
+	var statement *entity.Statement
+	lineRepo.EXPECT().CreateStatement(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Statement) error {
+		statement = s
+		return nil
+	})
+	lineRepo.EXPECT().Update(gomock.Any(), line).Return(nil)
+
+	service := &CreditLineService{
+		creditLineRepository: lineRepo,
+		calendar:             calendar.Russia(),
+		logger:               logger,
+	}
+	err := service.ProcessCreditLines(context.TODO(), now)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, statement) {
+		assert.Equal(t, periodStart, statement.PeriodStart)
+		assert.Equal(t, periodEnd, statement.PeriodEnd)
+		assert.Equal(t, time.Date(2025, 4, 9, 0, 0, 0, 0, time.UTC), statement.DueDate)
+		assert.True(t, decimal.NewFromInt(10000).Equal(statement.Purchases))
+		assert.True(t, decimal.NewFromInt(10000).Equal(statement.ClosingDebt))
+		assert.True(t, decimal.NewFromInt(500).Equal(statement.MinimumPayment))
+		// 10 рублей накоплено ранее и 10 рублей за 15 марта: 10000 * 36.5% / 365.
+		assert.True(t, decimal.NewFromInt(20).Equal(statement.AccruedInterest), "accrued: %s", statement.AccruedInterest)
+		assert.Equal(t, entity.StatementOpen, statement.Status)
+	}
+
+	assert.Equal(t, periodEnd, line.CycleStartedAt)
+	assert.Equal(t, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), line.NextStatementAt)
+	assert.True(t, decimal.NewFromInt(10).Equal(line.AccruedInterest), "accrued: %s", line.AccruedInterest)
+	assert.Equal(t, today, line.InterestAccruedAt)
+}
+
+func TestCreditLineService_ProcessCreditLinesSettlesStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 3, 13, 0, 40, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+	dueDate := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		repaid       string
+		wantStatus   entity.StatementStatus
+		wantInterest string
+	}{
+		{
+			name:         "full repayment keeps the grace period",
+			repaid:       "10000",
+			wantStatus:   entity.StatementPaid,
+			wantInterest: "0",
+		},
+		{
+			name:         "minimum payment loses the grace period",
+			repaid:       "600",
+			wantStatus:   entity.StatementGraceLost,
+			wantInterest: "150.12",
+		},
+		{
+			name:         "missed minimum payment is overdue",
+			repaid:       "100",
+			wantStatus:   entity.StatementOverdue,
+			wantInterest: "150.12",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			line := &entity.CreditLine{
+				AccountID:         7,
+				InterestRate:      decimal.RequireFromString("36.5"),
+				StatementDay:      15,
+				CycleStartedAt:    periodEnd,
+				NextStatementAt:   time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+				AccruedInterest:   decimal.Zero,
+				InterestAccruedAt: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+			}
+			statement := entity.Statement{
+				ID:              5,
+				AccountID:       7,
+				PeriodEnd:       periodEnd,
+				DueDate:         dueDate,
+				ClosingDebt:     decimal.NewFromInt(10000),
+				MinimumPayment:  decimal.NewFromInt(500),
+				AccruedInterest: decimal.RequireFromString("150.1234"),
+				Status:          entity.StatementOpen,
+			}
+			repaid := decimal.RequireFromString(tc.repaid)
+			account := &entity.Account{ID: 7, Balance: decimal.NewFromInt(-10000).Add(repaid), AccountType: entity.CreditAccount, CreditLimit: decimal.NewFromInt(50000)}
+
+			lineRepo := NewMockCreditLineRepository(ctrl)
+			lineRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			gomock.InOrder(
+				lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(0)).Return(line, nil),
+				lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(7)).Return(nil, entity.ErrCreditLineNotFound),
+			)
+			lineRepo.EXPECT().ListDueStatements(gomock.Any(), int32(7), today).Return([]entity.Statement{statement}, nil)
+			lineRepo.EXPECT().Repayments(gomock.Any(), int32(7), periodEnd, today).Return(repaid, nil)
+			lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), today).Return(decimal.NewFromInt(-10000).Add(repaid), nil)
+
+			var settled *entity.Statement
+			lineRepo.EXPECT().UpdateStatement(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Statement) error {
+				settled = s
+				return nil
+			})
+			lineRepo.EXPECT().Update(gomock.Any(), line).Return(nil)
+
+			accountRepo := NewMockAccountRepository(ctrl)
+			accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			wantInterest := decimal.RequireFromString(tc.wantInterest)
+			if wantInterest.IsPositive() {
+				accountRepo.EXPECT().LockByID(gomock.Any(), int32(7)).Return(account, nil)
+				accountRepo.EXPECT().Update(gomock.Any(), account).Return(nil)
+			}
+
+			service := &CreditLineService{
+				creditLineRepository: lineRepo,
+				accountService:       NewAccountService(logger, accountRepo),
+				calendar:             calendar.Russia(),
+				logger:               logger,
+			}
+			err := service.ProcessCreditLines(context.TODO(), now)
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, settled) {
+				assert.Equal(t, tc.wantStatus, settled.Status)
+				assert.True(t, repaid.Equal(settled.RepaidAmount))
+				assert.True(t, wantInterest.Equal(settled.InterestCharged), "interest: %s", settled.InterestCharged)
+			}
+
+			wantBalance := decimal.NewFromInt(-10000).Add(repaid).Sub(wantInterest)
+			assert.True(t, wantBalance.Equal(account.Balance), "balance: %s", account.Balance)
+			// Проценты за 12 марта начисляются на долг на конец этого дня, до списания процентов по выписке.
+			wantAccrued := decimal.NewFromInt(10000).Sub(repaid).Mul(decimal.RequireFromString("0.001")).Round(4)
+			assert.True(t, wantAccrued.Equal(line.AccruedInterest), "accrued: %s", line.AccruedInterest)
+		})
+	}
+}
+
+func TestCreditLineService_ProcessCreditLinesCatchesUpOnPeriodBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 3, 16, 0, 40, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	line := &entity.CreditLine{
+		AccountID:         7,
+		InterestRate:      decimal.RequireFromString("36.5"),
+		GraceDays:         25,
+		MinPaymentPercent: decimal.NewFromInt(5),
+		MinPaymentAmount:  decimal.NewFromInt(300),
+		StatementDay:      15,
+		CycleStartedAt:    time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		NextStatementAt:   february,
+		AccruedInterest:   decimal.Zero,
+		InterestAccruedAt: time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	var statements []*entity.Statement
+	lineRepo := NewMockCreditLineRepository(ctrl)
+	lineRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+	gomock.InOrder(
+		lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(0)).Return(line, nil),
+		lineRepo.EXPECT().LockUnprocessed(gomock.Any(), today, int32(7)).Return(nil, entity.ErrCreditLineNotFound),
+	)
+	lineRepo.EXPECT().ListDueStatements(gomock.Any(), int32(7), today).Return(nil, nil)
+	lineRepo.EXPECT().LastStatement(gomock.Any(), int32(7)).DoAndReturn(func(context.Context, int32) (*entity.Statement, error) {
+		if len(statements) == 0 {
+			return nil, entity.ErrStatementNotFound
+		}
+		return statements[len(statements)-1], nil
+	}).Times(2)
+	lineRepo.EXPECT().Turnover(gomock.Any(), int32(7), gomock.Any(), gomock.Any()).Return(&entity.CardTransactionTotals{}, nil).Times(2)
+	lineRepo.EXPECT().Repayments(gomock.Any(), int32(7), gomock.Any(), gomock.Any()).Return(decimal.Zero, nil).Times(2)
+	// Долг вырос с 5000 до 20000 в течение пропущенного периода.
+	lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), february).Return(decimal.NewFromInt(-5000), nil)
+	lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), march).Return(decimal.NewFromInt(-20000), nil)
+	lineRepo.EXPECT().BalanceAt(gomock.Any(), int32(7), today).Return(decimal.NewFromInt(-20000), nil)
+	lineRepo.EXPECT().CreateStatement(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Statement) error {
+		statements = append(statements, s)
+		return nil
+	}).Times(2)
+	lineRepo.EXPECT().Update(gomock.Any(), line).Return(nil)
+
+	service := &CreditLineService{
+		creditLineRepository: lineRepo,
+		calendar:             calendar.Russia(),
+		logger:               logger,
+	}
+	err := service.ProcessCreditLines(context.TODO(), now)
+
+	assert.NoError(t, err)
+	if assert.Len(t, statements, 2) {
+		assert.True(t, decimal.NewFromInt(5000).Equal(statements[0].ClosingDebt))
+		assert.True(t, decimal.NewFromInt(5).Equal(statements[0].AccruedInterest), "accrued: %s", statements[0].AccruedInterest)
+		assert.True(t, decimal.NewFromInt(5000).Equal(statements[1].OpeningDebt))
+		assert.True(t, decimal.NewFromInt(20000).Equal(statements[1].ClosingDebt))
+		// 28 дней февраля на долг 20000 по 0.1% в день.
+		assert.True(t, decimal.NewFromInt(560).Equal(statements[1].AccruedInterest), "accrued: %s", statements[1].AccruedInterest)
+	}
+	assert.True(t, decimal.NewFromInt(20).Equal(line.AccruedInterest), "accrued: %s", line.AccruedInterest)
+}
@@ -0,0 +1,188 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Ограничения неустойки по потребительским кредитам (ст. 5 Федерального закона № 353-ФЗ «О потребительском кредите»).
+var (
+	// maxPenaltyAnnualRate максимальная ставка неустойки на просроченную сумму, в процентах годовых,
+	// если за период просрочки продолжают начисляться проценты.
+	maxPenaltyAnnualRate = decimal.NewFromInt(20)
+	// maxPenaltyDailyRate максимальная ставка неустойки на просроченную сумму, в процентах в день,
+	// если проценты за период просрочки не начисляются.
+	maxPenaltyDailyRate = decimal.RequireFromString("0.1")
+	// maxShortTermCharges максимальная сумма процентов и неустоек по кредиту на срок до года в долях от суммы кредита.
+	maxShortTermCharges = decimal.RequireFromString("1.3")
+)
+
+// shortTermMonths срок кредита в месяцах, до которого действует ограничение maxShortTermCharges.
+const shortTermMonths = 12
+
+// AccruePenalties начисляет штраф по просроченным платежам действующих кредитов за каждый день просрочки по день now
+// включительно. Каждый кредит обрабатывается в отдельной транзакции: сначала блокируется строка кредита, затем его
+// просроченные платежи. День последнего начисления сохраняется вместе со штрафом, поэтому повторный запуск в тот же
+// день не начислит штраф дважды, а пропущенные дни будут начислены при следующем запуске.
+func (s *CreditService) AccruePenalties(ctx context.Context, now time.Time) error {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	var afterID int32
+	var processed, failed int
+	for {
+		var done bool
+		lastID := afterID
+
+		err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+			credit, err := s.creditRepository.LockOverdueCredit(ctx, today, afterID)
+			if errors.Is(err, entity.ErrCreditNotFound) {
+				done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			afterID = credit.ID
+
+			payments, err := s.creditRepository.LockOverduePayments(ctx, credit.ID, today)
+			if err != nil {
+				return err
+			}
+
+			for i := range payments {
+				if err := s.accruePenalty(ctx, credit, &payments[i], today); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if done {
+			break
+		}
+
+		if err != nil {
+			if afterID == lastID {
+				s.logger.Error("failed to find overdue payments", "error", err)
+				return fmt.Errorf("failed to find overdue payments: %w", err)
+			}
+
+			s.logger.Error("failed to accrue penalty", "credit_id", afterID, "error", err)
+			failed++
+			continue
+		}
+
+		processed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to accrue penalties for %d of %d credits", failed, processed+failed)
+	}
+
+	s.logger.Info("penalties accrued", "credits", processed)
+	return nil
+}
+
+// accruePenalty начисляет штраф по платежу payment за дни просрочки после предыдущего начисления по день today.
+func (s *CreditService) accruePenalty(ctx context.Context, credit *entity.Credit, payment *entity.PaymentSchedule, today time.Time) error {
+	from := payment.PaymentDate
+	if payment.PenaltyAccruedAt != nil {
+		from = *payment.PenaltyAccruedAt
+	}
+
+	year, month, day := from.Date()
+	days := int64(today.Sub(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	if days <= 0 {
+		return nil
+	}
+
+	// Проценты за просрочку продолжают начисляться только на непогашенный основной долг, поэтому к нему применяется
+	// ограничение в 20% годовых, а к просроченным процентам — ограничение в 0.1% в день.
+	overdue := payment.OverdueAmount()
+	principal := decimal.Min(decimal.Max(payment.PrincipalOutstanding(), decimal.Zero), overdue)
+	penalty := calculatePenalty(principal, s.penaltyDailyRate, days, true).
+		Add(calculatePenalty(overdue.Sub(principal), s.penaltyDailyRate, days, false))
+
+	if credit.TermInMonths <= shortTermMonths && penalty.IsPositive() {
+		schedule, err := s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+		if err != nil {
+			s.logger.Error("failed to get payment schedule", "error", err)
+			return fmt.Errorf("failed to get payment schedule: %w", err)
+		}
+
+		charged := decimal.Zero
+		for _, installment := range schedule {
+			charged = charged.Add(installment.InterestAmount).Add(installment.Penalty).Sub(installment.PenaltyWaived)
+		}
+
+		limit := credit.Amount.Mul(maxShortTermCharges).Sub(charged)
+		penalty = decimal.Max(decimal.Min(penalty, limit), decimal.Zero)
+	}
+
+	payment.Penalty = payment.Penalty.Add(penalty)
+	payment.PenaltyAccruedAt = &today
+	if err := s.creditRepository.UpdatePayment(ctx, payment); err != nil {
+		s.logger.Error("failed to update payment", "error", err)
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	s.logger.Info("penalty accrued", "credit_id", credit.ID, "payment_id", payment.ID, "days", days, "amount", penalty)
+	return nil
+}
+
+// calculatePenalty возвращает штраф на просроченную сумму overdue за days дней по дневной ставке dailyRate
+// в процентах. Ставка ограничена maxPenaltyAnnualRate, если на просроченную сумму начисляются проценты
+// (interestAccrues), и maxPenaltyDailyRate, если не начисляются.
+func calculatePenalty(overdue, dailyRate decimal.Decimal, days int64, interestAccrues bool) decimal.Decimal {
+	limit := maxPenaltyDailyRate
+	if interestAccrues {
+		limit = maxPenaltyAnnualRate.Div(decimal.NewFromInt(365))
+	}
+	rate := decimal.Min(dailyRate, limit)
+
+	return overdue.Mul(rate).Div(decimal.NewFromInt(100)).Mul(decimal.NewFromInt(days)).Round(2)
+}
+
+// WaivePenalty списывает непогашенный штраф по платежу paymentID кредита creditID от имени сотрудника adminID.
+// Если после списания платеж погашен полностью, он переводится в статус paid, а при отсутствии других
+// непогашенных платежей закрывается и кредит.
+func (s *CreditService) WaivePenalty(ctx context.Context, adminID, creditID, paymentID int32, now time.Time) (*entity.PaymentSchedule, error) {
+	var payment *entity.PaymentSchedule
+	err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+		credit, err := s.creditRepository.LockCreditByID(ctx, creditID)
+		if err != nil {
+			return err
+		}
+
+		payment, err = s.creditRepository.LockPaymentByID(ctx, creditID, paymentID)
+		if err != nil {
+			return err
+		}
+
+		waived := payment.WaivePenalty(adminID, now)
+		if err := s.creditRepository.UpdatePayment(ctx, payment); err != nil {
+			s.logger.Error("failed to update payment", "error", err)
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+
+		s.logger.Info("penalty waived", "credit_id", creditID, "payment_id", paymentID, "admin_id", adminID, "amount", waived)
+
+		if payment.Status != entity.PaymentPaid || credit.Status != entity.CreditStatusActive {
+			return nil
+		}
+
+		return s.closeIfRepaid(ctx, credit)
+	})
+	if err != nil {
+		s.logger.Error("failed to waive penalty", "error", err)
+		return nil, fmt.Errorf("failed to waive penalty: %w", err)
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,196 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCalculatePenalty(t *testing.T) {
+	overdue := decimal.NewFromInt(10000)
+
+	testCases := []struct {
+		name            string
+		dailyRate       string
+		interestAccrues bool
+		want            string
+	}{
+		{name: "rate below both limits", dailyRate: "0.05", interestAccrues: true, want: "15.00"},
+		// 0.1% в день превышает 20% годовых, поэтому применяется ставка 20/365.
+		{name: "interest accrues caps at 20% per annum", dailyRate: "0.1", interestAccrues: true, want: "16.44"},
+		{name: "no interest allows 0.1% per day", dailyRate: "0.1", want: "30.00"},
+		{name: "no interest caps at 0.1% per day", dailyRate: "0.2", want: "30.00"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := calculatePenalty(overdue, decimal.RequireFromString(tc.dailyRate), 3, tc.interestAccrues)
+			assert.True(t, decimal.RequireFromString(tc.want).Equal(got), "penalty: %s", got)
+		})
+	}
+}
+
+func TestCreditService_AccruePenalties(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 2, 18, 0, 30, 0, 0, time.UTC)
+	today := time.Date(2025, 2, 18, 0, 0, 0, 0, time.UTC)
+	accrued := time.Date(2025, 2, 17, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		term        int32
+		paid        string
+		penalty     string
+		accruedAt   *time.Time
+		charged     string
+		dailyRate   string
+		principal   string
+		wantPenalty string
+	}{
+		{
+			name:        "first accrual covers all days since the due date",
+			term:        24,
+			paid:        "0",
+			penalty:     "0",
+			wantPenalty: "13.33",
+		},
+		{
+			name:        "accrues only on the unpaid part of the installment",
+			term:        24,
+			paid:        "4884.88",
+			penalty:     "6.00",
+			accruedAt:   &accrued,
+			wantPenalty: "2.00",
+		},
+		{
+			name:        "overdue principal is capped at 20% per annum, overdue interest at 0.1% per day",
+			term:        24,
+			paid:        "0",
+			penalty:     "0",
+			dailyRate:   "0.1",
+			principal:   "8000",
+			wantPenalty: "15.80",
+		},
+		{
+			name:        "short-term credit is capped by total charges",
+			term:        12,
+			paid:        "0",
+			penalty:     "0",
+			charged:     "129995.00",
+			wantPenalty: "5.00",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			credit := &entity.Credit{ID: 3, Amount: decimal.NewFromInt(100000), TermInMonths: tc.term, Status: entity.CreditStatusActive}
+			principal, dailyRate := tc.principal, tc.dailyRate
+			if principal == "" {
+				principal = "0"
+			}
+			if dailyRate == "" {
+				dailyRate = "0.05"
+			}
+			payments := []entity.PaymentSchedule{{
+				ID:               11,
+				CreditID:         3,
+				PaymentDate:      time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+				PaymentAmount:    decimal.RequireFromString("8884.88"),
+				PrincipalAmount:  decimal.RequireFromString(principal),
+				InterestAmount:   decimal.RequireFromString("8884.88").Sub(decimal.RequireFromString(principal)),
+				Penalty:          decimal.RequireFromString(tc.penalty),
+				PenaltyPaid:      decimal.RequireFromString(tc.penalty),
+				PaidAmount:       decimal.RequireFromString(tc.paid).Add(decimal.RequireFromString(tc.penalty)),
+				Status:           entity.PaymentOverdue,
+				PenaltyAccruedAt: tc.accruedAt,
+			}}
+			payment := &payments[0]
+
+			creditRepo := NewMockCreditRepository(ctrl)
+			creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			gomock.InOrder(
+				creditRepo.EXPECT().LockOverdueCredit(gomock.Any(), today, int32(0)).Return(credit, nil),
+				creditRepo.EXPECT().LockOverdueCredit(gomock.Any(), today, int32(3)).Return(nil, entity.ErrCreditNotFound),
+			)
+			creditRepo.EXPECT().LockOverduePayments(gomock.Any(), int32(3), today).Return(payments, nil)
+			if tc.charged != "" {
+				creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), int32(3)).Return([]entity.PaymentSchedule{
+					{InterestAmount: decimal.RequireFromString(tc.charged)},
+				}, nil)
+			}
+			creditRepo.EXPECT().UpdatePayment(gomock.Any(), payment).Return(nil)
+
+			service := &CreditService{
+				creditRepository: creditRepo,
+				penaltyDailyRate: decimal.RequireFromString(dailyRate),
+				logger:           logger,
+			}
+			err := service.AccruePenalties(context.TODO(), now)
+
+			assert.NoError(t, err)
+			want := decimal.RequireFromString(tc.wantPenalty).Add(decimal.RequireFromString(tc.penalty))
+			assert.True(t, want.Equal(payment.Penalty), "penalty: %s", payment.Penalty)
+			assert.Equal(t, today, *payment.PenaltyAccruedAt)
+		})
+	}
+}
+
+func TestPaymentSchedule_ApplyCollectsPenaltyFirst(t *testing.T) {
+	payment := &entity.PaymentSchedule{
+		PaymentDate:   time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+		PaymentAmount: decimal.NewFromInt(1000),
+		Penalty:       decimal.NewFromInt(100),
+		Status:        entity.PaymentOverdue,
+	}
+
+	payment.Apply(decimal.NewFromInt(500), time.Date(2025, 2, 18, 1, 0, 0, 0, time.UTC))
+
+	assert.True(t, decimal.NewFromInt(100).Equal(payment.PenaltyPaid))
+	assert.True(t, decimal.NewFromInt(600).Equal(payment.OverdueAmount()))
+	assert.True(t, decimal.NewFromInt(600).Equal(payment.Outstanding()))
+	assert.Equal(t, entity.PaymentOverdue, payment.Status)
+}
+
+func TestCreditService_WaivePenalty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 2, 20, 12, 0, 0, 0, time.UTC)
+	credit := &entity.Credit{ID: 3, Status: entity.CreditStatusActive}
+	payment := &entity.PaymentSchedule{
+		ID:            11,
+		CreditID:      3,
+		PaymentDate:   time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+		PaymentAmount: decimal.NewFromInt(1000),
+		Penalty:       decimal.NewFromInt(40),
+		PenaltyPaid:   decimal.NewFromInt(10),
+		PaidAmount:    decimal.NewFromInt(1010),
+		Status:        entity.PaymentOverdue,
+	}
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	creditRepo.EXPECT().LockCreditByID(gomock.Any(), int32(3)).Return(credit, nil)
+	creditRepo.EXPECT().LockPaymentByID(gomock.Any(), int32(3), int32(11)).Return(payment, nil)
+	creditRepo.EXPECT().UpdatePayment(gomock.Any(), payment).Return(nil)
+	creditRepo.EXPECT().CountUnpaidPayments(gomock.Any(), int32(3)).Return(0, nil)
+	creditRepo.EXPECT().UpdateCreditStatus(gomock.Any(), int32(3), entity.CreditStatusPaid).Return(nil)
+
+	service := &CreditService{creditRepository: creditRepo, logger: logger}
+	waived, err := service.WaivePenalty(context.TODO(), 42, 3, 11, now)
+
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(30).Equal(waived.PenaltyWaived))
+	assert.Equal(t, int32(42), *waived.PenaltyWaivedBy)
+	assert.Equal(t, entity.PaymentPaid, waived.Status)
+	assert.Equal(t, entity.CreditStatusPaid, credit.Status)
+}
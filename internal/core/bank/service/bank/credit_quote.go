@@ -0,0 +1,80 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Quote рассчитывает условия кредита без его оформления: график платежей, сумму процентов и полную стоимость
+// кредита с учетом комиссий fees, уплачиваемых при выдаче.
+func (s *CreditService) Quote(
+	ctx context.Context,
+	principal decimal.Decimal,
+	termMonths int32,
+	fees []entity.CreditFee,
+	now time.Time,
+	opts ...CreditOption,
+) (*entity.CreditQuote, error) {
+	credit := &entity.Credit{
+		Amount:       principal,
+		TermInMonths: termMonths,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Actual365,
+		CreatedAt:    now,
+	}
+
+	for _, opt := range opts {
+		opt(credit)
+	}
+
+	if err := s.resolveInterestRate(ctx, credit); err != nil {
+		return nil, err
+	}
+
+	schedule, err := buildPaymentSchedule(credit, now, s.calendar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate payment schedule: %w", err)
+	}
+
+	quote := &entity.CreditQuote{
+		Amount:        principal,
+		InterestRate:  credit.InterestRate,
+		TermInMonths:  termMonths,
+		ScheduleType:  credit.ScheduleType,
+		Schedule:      schedule,
+		Fees:          fees,
+		TotalInterest: decimal.Zero,
+		TotalPayments: decimal.Zero,
+	}
+
+	// Комиссии при выдаче уменьшают сумму, фактически полученную заемщиком.
+	totalFees := decimal.Zero
+	for _, fee := range fees {
+		if fee.Amount.IsNegative() {
+			return nil, fmt.Errorf("%w: %s", entity.ErrInvalidCreditFee, fee.Name)
+		}
+		totalFees = totalFees.Add(fee.Amount)
+	}
+	if totalFees.GreaterThanOrEqual(principal) {
+		return nil, entity.ErrInvalidCreditFee
+	}
+
+	flows := []amortization.CashFlow{{Date: now, Amount: totalFees.Sub(principal)}}
+	for _, payment := range schedule {
+		quote.TotalInterest = quote.TotalInterest.Add(payment.InterestAmount)
+		quote.TotalPayments = quote.TotalPayments.Add(payment.PaymentAmount)
+		flows = append(flows, amortization.CashFlow{Date: payment.PaymentDate, Amount: payment.PaymentAmount})
+	}
+
+	quote.FullCostRate, err = amortization.FullCostRate(flows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate full cost of credit: %w", err)
+	}
+	quote.FullCostAmount = quote.TotalInterest.Add(totalFees)
+
+	return quote, nil
+}
@@ -0,0 +1,36 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCreditService_Quote(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	service := &CreditService{calendar: calendar.Russia(), logger: logger}
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	opts := []CreditOption{WithInterestRate(decimal.NewFromInt(12)), WithDayCount(amortization.Thirty360)}
+
+	quote, err := service.Quote(context.TODO(), decimal.NewFromInt(100000), 12, []entity.CreditFee{
+		{Name: "insurance", Amount: decimal.NewFromInt(2000)},
+	}, now, opts...)
+
+	assert.NoError(t, err)
+	assert.Len(t, quote.Schedule, 12)
+	assert.Equal(t, "6640.63", quote.TotalInterest.StringFixed(2))
+	assert.Equal(t, "106640.63", quote.TotalPayments.StringFixed(2))
+	assert.Equal(t, "8640.63", quote.FullCostAmount.StringFixed(2))
+	assert.Equal(t, "15.842", quote.FullCostRate.StringFixed(3))
+
+	_, err = service.Quote(context.TODO(), decimal.NewFromInt(1000), 12, []entity.CreditFee{
+		{Name: "insurance", Amount: decimal.NewFromInt(1000)},
+	}, now, opts...)
+	assert.ErrorIs(t, err, entity.ErrInvalidCreditFee)
+}
@@ -0,0 +1,213 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// earlyRepayment описывает результат досрочного погашения: строку графика с погашенной суммой
+// и пересчитанные оставшиеся платежи.
+type earlyRepayment struct {
+	payment   entity.PaymentSchedule
+	remaining []entity.PaymentSchedule
+}
+
+// RepayEarly досрочно погашает кредит creditID пользователя userID на сумму amount, списывая ее со счета заемщика.
+// Из суммы сначала погашаются проценты, начисленные с даты предыдущего платежа, остаток идет в счет основного долга.
+// Начисленные по кредиту проценты доначисляются по день погашения и погашаются в первую очередь.
+// Оставшиеся платежи пересчитываются способом mode; если долг погашен полностью, кредит закрывается.
+// Возвращает обновленный график платежей.
+func (s *CreditService) RepayEarly(
+	ctx context.Context,
+	userID, creditID int32,
+	amount decimal.Decimal,
+	mode entity.RepaymentMode,
+	now time.Time,
+) ([]entity.PaymentSchedule, error) {
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !amount.IsPositive() {
+		return nil, entity.ErrInvalidRepaymentAmount
+	}
+
+	var schedule []entity.PaymentSchedule
+	err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+		credit, err := s.creditRepository.LockCreditByID(ctx, creditID)
+		if err != nil {
+			return err
+		}
+
+		if credit.UserID != userID {
+			return entity.ErrCreditNotFound
+		}
+
+		if credit.Status != entity.CreditStatusActive {
+			return entity.ErrCreditNotActive
+		}
+
+		payments, err := s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+		if err != nil {
+			return err
+		}
+
+		repayment, err := planEarlyRepayment(credit, payments, amount, mode, now)
+		if err != nil {
+			return err
+		}
+
+		if err := s.accrueInterest(ctx, credit, accrualDay(now)); err != nil {
+			return err
+		}
+
+		accountID, err := s.creditAccountID(ctx, credit)
+		if err != nil {
+			return err
+		}
+
+		if err := s.accountService.Withdraw(ctx, accountID, repayment.payment.PaymentAmount); err != nil {
+			return err
+		}
+
+		if err := s.creditRepository.DeletePendingPayments(ctx, credit.ID); err != nil {
+			return err
+		}
+
+		paid, err := s.creditRepository.CreatePaymentSchedule(ctx, &repayment.payment)
+		if err != nil {
+			return err
+		}
+
+		if err := s.postPayment(ctx, credit, paid.ID, paid.PaidAmount, decimal.Zero, now); err != nil {
+			return err
+		}
+
+		if err := s.settleInterest(ctx, credit, paid.ID, paid.PaymentAmount, now); err != nil {
+			return err
+		}
+
+		if err := s.postPrincipal(ctx, credit, entity.PrincipalRepayment, &paid.ID, paid.PrincipalAmount.Neg(), now); err != nil {
+			return err
+		}
+
+		for _, payment := range repayment.remaining {
+			if _, err := s.creditRepository.CreatePaymentSchedule(ctx, &payment); err != nil {
+				return err
+			}
+		}
+
+		if len(repayment.remaining) == 0 {
+			if err := s.creditRepository.UpdateCreditStatus(ctx, credit.ID, entity.CreditStatusPaid); err != nil {
+				return err
+			}
+			s.logger.Info("credit repaid early", "credit_id", credit.ID)
+		}
+
+		schedule, err = s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("failed to repay credit early", "credit_id", creditID, "error", err)
+		return nil, fmt.Errorf("failed to repay credit early: %w", err)
+	}
+
+	s.logger.Info("early repayment completed", "credit_id", creditID, "amount", amount, "mode", mode)
+	return schedule, nil
+}
+
+// planEarlyRepayment рассчитывает досрочное погашение на сумму не больше amount на момент now.
+// Досрочно погасить можно только кредит без наступивших неоплаченных платежей.
+func planEarlyRepayment(
+	credit *entity.Credit,
+	payments []entity.PaymentSchedule,
+	amount decimal.Decimal,
+	mode entity.RepaymentMode,
+	now time.Time,
+) (*earlyRepayment, error) {
+	year, month, day := now.Date()
+	dueEnd := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+
+	periodStart := credit.CreatedAt
+	principal := decimal.Zero
+	var pending []entity.PaymentSchedule
+	for _, payment := range payments {
+		if payment.Status == entity.PaymentPaid {
+			periodStart = payment.PaymentDate
+			continue
+		}
+
+		if payment.PaymentDate.Before(dueEnd) || payment.PaidAmount.IsPositive() {
+			return nil, entity.ErrOutstandingPayments
+		}
+
+		pending = append(pending, payment)
+		principal = principal.Add(payment.PrincipalAmount)
+	}
+
+	if len(pending) == 0 {
+		return nil, entity.ErrCreditNotActive
+	}
+
+	accrued := amortization.Interest(principal, credit.InterestRate, credit.DayCount, periodStart, now)
+	if amount.LessThanOrEqual(accrued) {
+		return nil, entity.ErrInvalidRepaymentAmount
+	}
+
+	principalPaid := decimal.Min(amount.Sub(accrued), principal)
+	balance := principal.Sub(principalPaid)
+	paid := principalPaid.Add(accrued)
+
+	repayment := &earlyRepayment{
+		payment: entity.PaymentSchedule{
+			CreditID:        credit.ID,
+			PaymentDate:     now,
+			PaymentAmount:   paid,
+			PrincipalAmount: principalPaid,
+			InterestAmount:  accrued,
+			Balance:         balance,
+			Status:          entity.PaymentPaid,
+			PaidAmount:      paid,
+			PaidAt:          &now,
+		},
+	}
+
+	if balance.IsZero() {
+		return repayment, nil
+	}
+
+	dates := make([]time.Time, 0, len(pending))
+	for _, payment := range pending {
+		dates = append(dates, payment.PaymentDate)
+	}
+
+	term := len(dates)
+	if mode == entity.ReduceTerm {
+		// Для аннуитета сохраняется размер платежа, для дифференцированного графика — погашение основного долга.
+		limit := pending[0].PaymentAmount
+		if credit.ScheduleType == amortization.Differentiated {
+			limit = pending[0].PrincipalAmount
+		}
+		term = amortization.MinTerm(credit.ScheduleType, balance, credit.InterestRate, limit, term)
+	}
+
+	installments, err := amortization.Generate(amortization.Params{
+		Principal:    balance,
+		AnnualRate:   credit.InterestRate,
+		Term:         term,
+		Method:       credit.ScheduleType,
+		DayCount:     credit.DayCount,
+		StartDate:    now,
+		PaymentDates: dates[:term],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate payment schedule: %w", err)
+	}
+
+	repayment.remaining = toPaymentSchedule(credit.ID, installments)
+	return repayment, nil
+}
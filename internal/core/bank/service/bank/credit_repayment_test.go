@@ -0,0 +1,138 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestPlanEarlyRepayment(t *testing.T) {
+	issued := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	credit := &entity.Credit{
+		ID:           3,
+		Amount:       decimal.NewFromInt(100000),
+		InterestRate: decimal.NewFromInt(12),
+		TermInMonths: 12,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Thirty360,
+		Status:       entity.CreditStatusActive,
+		CreatedAt:    issued,
+	}
+
+	// paidSchedule возвращает исходный график, в котором погашены первые два платежа.
+	paidSchedule := func() []entity.PaymentSchedule {
+		schedule, err := buildPaymentSchedule(credit, issued, calendar.Russia())
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			schedule[i].Status = entity.PaymentPaid
+			schedule[i].PaidAmount = schedule[i].PaymentAmount
+		}
+		return schedule
+	}
+
+	// Второй платеж перенесен с субботы 15 марта на 17 марта. Остаток долга после него 84218.73,
+	// проценты за 3 дня по 30/360 — 84.22.
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		amount        string
+		mode          entity.RepaymentMode
+		now           time.Time
+		wantDebit     string
+		wantRemaining int
+		wantErr       error
+	}{
+		{
+			name:      "full repayment caps debit at outstanding debt",
+			amount:    "100000",
+			mode:      entity.ReducePayment,
+			now:       now,
+			wantDebit: "84302.95",
+		},
+		{
+			name:          "partial repayment keeps term",
+			amount:        "40084.22",
+			mode:          entity.ReducePayment,
+			now:           now,
+			wantDebit:     "40084.22",
+			wantRemaining: 10,
+		},
+		{
+			name:          "partial repayment shortens term",
+			amount:        "40084.22",
+			mode:          entity.ReduceTerm,
+			now:           now,
+			wantDebit:     "40084.22",
+			wantRemaining: 6,
+		},
+		{
+			name:    "amount does not cover accrued interest",
+			amount:  "84.22",
+			mode:    entity.ReduceTerm,
+			now:     now,
+			wantErr: entity.ErrInvalidRepaymentAmount,
+		},
+		{
+			name:    "due payment is not settled",
+			amount:  "1000",
+			mode:    entity.ReduceTerm,
+			now:     time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC),
+			wantErr: entity.ErrOutstandingPayments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule := paidSchedule()
+
+			repayment, err := planEarlyRepayment(credit, schedule, decimal.RequireFromString(tc.amount), tc.mode, tc.now)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tc.wantDebit).Equal(repayment.payment.PaymentAmount), "debit: %s", repayment.payment.PaymentAmount)
+			assert.True(t, decimal.RequireFromString("84.22").Equal(repayment.payment.InterestAmount))
+			assert.Equal(t, entity.PaymentPaid, repayment.payment.Status)
+			assert.Len(t, repayment.remaining, tc.wantRemaining)
+
+			principal := decimal.Zero
+			for i, payment := range repayment.remaining {
+				assert.Equal(t, schedule[2+i].PaymentDate, payment.PaymentDate)
+				assert.True(t, payment.PaymentAmount.LessThanOrEqual(schedule[2].PaymentAmount))
+				principal = principal.Add(payment.PrincipalAmount)
+			}
+			assert.True(t, repayment.payment.Balance.Equal(principal))
+
+			if tc.wantRemaining > 0 {
+				assert.True(t, repayment.remaining[tc.wantRemaining-1].Balance.IsZero())
+			}
+		})
+	}
+}
+
+func TestCreditService_RepayEarly_ForeignCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	creditRepo.EXPECT().LockCreditByID(gomock.Any(), int32(3)).Return(&entity.Credit{ID: 3, UserID: 2, Status: entity.CreditStatusActive}, nil)
+
+	service := &CreditService{creditRepository: creditRepo, logger: logger}
+	_, err := service.RepayEarly(context.TODO(), 1, 3, decimal.NewFromInt(1000), entity.ReduceTerm, time.Now())
+
+	assert.ErrorIs(t, err, entity.ErrCreditNotFound)
+}
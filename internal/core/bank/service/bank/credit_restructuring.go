@@ -0,0 +1,284 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// RestructuringRepository хранит заявки на реструктуризацию кредитов и версии графиков платежей.
+type RestructuringRepository interface {
+	Create(ctx context.Context, restructuring *entity.Restructuring) error
+	Update(ctx context.Context, restructuring *entity.Restructuring) error
+	LockByID(ctx context.Context, id int32) (*entity.Restructuring, error)
+	ListByStatus(ctx context.Context, status entity.RestructuringStatus) ([]entity.Restructuring, error)
+	CreateScheduleVersion(ctx context.Context, version *entity.ScheduleVersion) error
+	FindScheduleVersions(ctx context.Context, creditID int32) ([]entity.ScheduleVersion, error)
+
+	WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error
+}
+
+// RequestRestructuring принимает заявку заемщика userID на кредитные каникулы по кредиту creditID.
+// График перестраивается только после одобрения заявки сотрудником.
+func (s *CreditService) RequestRestructuring(
+	ctx context.Context,
+	userID, creditID int32,
+	restructuringType entity.RestructuringType,
+	months int32,
+	reason string,
+) (*entity.Restructuring, error) {
+	restructuring := &entity.Restructuring{
+		CreditID: creditID,
+		UserID:   userID,
+		Type:     restructuringType,
+		Months:   months,
+		Reason:   reason,
+		Status:   entity.RestructuringRequested,
+	}
+	if err := restructuring.Validate(); err != nil {
+		return nil, err
+	}
+
+	credit, err := s.getUserCredit(ctx, userID, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	if credit.Status != entity.CreditStatusActive {
+		return nil, entity.ErrCreditNotActive
+	}
+
+	if err := s.restructuringRepository.Create(ctx, restructuring); err != nil {
+		s.logger.Error("failed to create restructuring", "error", err)
+		return nil, fmt.Errorf("failed to create restructuring: %w", err)
+	}
+
+	s.logger.Info("restructuring requested", "credit_id", creditID, "restructuring_id", restructuring.ID)
+	return restructuring, nil
+}
+
+// ListRestructurings возвращает заявки на реструктуризацию в статусе status.
+func (s *CreditService) ListRestructurings(ctx context.Context, status entity.RestructuringStatus) ([]entity.Restructuring, error) {
+	if err := status.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidRestructuring, err)
+	}
+
+	restructurings, err := s.restructuringRepository.ListByStatus(ctx, status)
+	if err != nil {
+		s.logger.Error("failed to list restructurings", "error", err)
+		return nil, err
+	}
+
+	return restructurings, nil
+}
+
+// ApproveRestructuring одобряет заявку restructuringID от имени сотрудника adminID и перестраивает график кредита.
+// Прежний график сохраняется отдельной версией, срок кредита увеличивается на время каникул. Проценты по прежнему
+// графику предварительно доначисляются по день одобрения.
+func (s *CreditService) ApproveRestructuring(
+	ctx context.Context,
+	adminID, restructuringID int32,
+	note string,
+) (*entity.Restructuring, error) {
+	var restructuring *entity.Restructuring
+	err := s.creditRepository.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		restructuring, err = s.restructuringRepository.LockByID(ctx, restructuringID)
+		if err != nil {
+			return err
+		}
+
+		if restructuring.Status != entity.RestructuringRequested {
+			return entity.ErrRestructuringNotRequested
+		}
+
+		credit, err := s.creditRepository.LockCreditByID(ctx, restructuring.CreditID)
+		if err != nil {
+			return err
+		}
+
+		if credit.Status != entity.CreditStatusActive {
+			return entity.ErrCreditNotActive
+		}
+
+		if err := s.accrueInterest(ctx, credit, accrualDay(time.Now())); err != nil {
+			return err
+		}
+
+		payments, err := s.creditRepository.GetPaymentSchedule(ctx, credit.ID)
+		if err != nil {
+			return err
+		}
+
+		schedule, err := planRestructuring(credit, payments, restructuring.Type, restructuring.Months, s.calendar)
+		if err != nil {
+			return err
+		}
+
+		version := &entity.ScheduleVersion{CreditID: credit.ID, RestructuringID: &restructuring.ID, Schedule: payments}
+		if err := s.restructuringRepository.CreateScheduleVersion(ctx, version); err != nil {
+			return err
+		}
+
+		if err := s.creditRepository.DeletePendingPayments(ctx, credit.ID); err != nil {
+			return err
+		}
+
+		for i := range schedule {
+			if _, err := s.creditRepository.CreatePaymentSchedule(ctx, &schedule[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := s.creditRepository.UpdateCreditTerm(ctx, credit.ID, credit.TermInMonths+restructuring.Months); err != nil {
+			return err
+		}
+
+		restructuring.Status = entity.RestructuringApproved
+		restructuring.DecidedBy = &adminID
+		restructuring.DecisionNote = note
+		restructuring.ScheduleVersion = &version.Version
+
+		return s.restructuringRepository.Update(ctx, restructuring)
+	})
+	if err != nil {
+		s.logger.Error("failed to approve restructuring", "restructuring_id", restructuringID, "error", err)
+		return nil, fmt.Errorf("failed to approve restructuring: %w", err)
+	}
+
+	s.logger.Info("restructuring approved", "restructuring_id", restructuringID, "admin_id", adminID)
+	return restructuring, nil
+}
+
+// RejectRestructuring отклоняет заявку restructuringID от имени сотрудника adminID. Комментарий обязателен.
+func (s *CreditService) RejectRestructuring(ctx context.Context, adminID, restructuringID int32, note string) (*entity.Restructuring, error) {
+	if note == "" {
+		return nil, entity.ErrDecisionReasonRequired
+	}
+
+	var restructuring *entity.Restructuring
+	err := s.restructuringRepository.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		restructuring, err = s.restructuringRepository.LockByID(ctx, restructuringID)
+		if err != nil {
+			return err
+		}
+
+		if restructuring.Status != entity.RestructuringRequested {
+			return entity.ErrRestructuringNotRequested
+		}
+
+		restructuring.Status = entity.RestructuringRejected
+		restructuring.DecidedBy = &adminID
+		restructuring.DecisionNote = note
+
+		return s.restructuringRepository.Update(ctx, restructuring)
+	})
+	if err != nil {
+		s.logger.Error("failed to reject restructuring", "restructuring_id", restructuringID, "error", err)
+		return nil, fmt.Errorf("failed to reject restructuring: %w", err)
+	}
+
+	s.logger.Info("restructuring rejected", "restructuring_id", restructuringID, "admin_id", adminID)
+	return restructuring, nil
+}
+
+// GetScheduleVersions возвращает прежние версии графика кредита creditID пользователя userID.
+func (s *CreditService) GetScheduleVersions(ctx context.Context, userID, creditID int32) ([]entity.ScheduleVersion, error) {
+	if _, err := s.getUserCredit(ctx, userID, creditID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.restructuringRepository.FindScheduleVersions(ctx, creditID)
+	if err != nil {
+		s.logger.Error("failed to get schedule versions", "error", err)
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// planRestructuring перестраивает платежи, по которым еще ничего не внесено, с учетом каникул на months месяцев.
+// Каникулы занимают даты ближайших months платежей: при PaymentHoliday платежи в эти даты не вносятся, а проценты
+// за каждый период каникул прибавляются к основному долгу строкой графика с нулевым платежом и отрицательным основным
+// долгом; при InterestOnly в эти даты вносятся только проценты.
+// Оставшийся долг погашается в прежние даты, сдвинутые на months месяцев. Сдвинутые даты отсчитываются от дня выдачи
+// кредита и переносятся на ближайший рабочий день по календарю cal.
+func planRestructuring(
+	credit *entity.Credit,
+	payments []entity.PaymentSchedule,
+	restructuringType entity.RestructuringType,
+	months int32,
+	cal *calendar.Calendar,
+) ([]entity.PaymentSchedule, error) {
+	periodStart := credit.CreatedAt
+	principal := decimal.Zero
+	var dates []time.Time
+	for _, payment := range payments {
+		if payment.Status != entity.PaymentPending || payment.PaidAmount.IsPositive() {
+			periodStart = payment.PaymentDate
+			continue
+		}
+
+		principal = principal.Add(payment.PrincipalAmount)
+		dates = append(dates, payment.PaymentDate)
+	}
+
+	if len(dates) == 0 {
+		return nil, entity.ErrNothingToRestructure
+	}
+
+	first := paymentMonth(credit.CreatedAt, dates[0])
+
+	var schedule []entity.PaymentSchedule
+	for i := 0; i < int(months); i++ {
+		date := paymentDate(cal, credit.CreatedAt, first+i)
+		interest := amortization.Interest(principal, credit.InterestRate, credit.DayCount, periodStart, date)
+		periodStart = date
+
+		payment := entity.PaymentSchedule{
+			CreditID:        credit.ID,
+			PaymentDate:     date,
+			PaymentAmount:   interest,
+			PrincipalAmount: decimal.Zero,
+			InterestAmount:  interest,
+			Balance:         principal,
+			Status:          entity.PaymentPending,
+			PaidAmount:      decimal.Zero,
+		}
+		if restructuringType == entity.PaymentHoliday {
+			principal = principal.Add(interest)
+			payment.PaymentAmount = decimal.Zero
+			payment.PrincipalAmount = interest.Neg()
+			payment.Balance = principal
+		}
+
+		schedule = append(schedule, payment)
+	}
+
+	shifted := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		shifted = append(shifted, paymentDate(cal, credit.CreatedAt, paymentMonth(credit.CreatedAt, date)+int(months)))
+	}
+
+	installments, err := amortization.Generate(amortization.Params{
+		Principal:    principal,
+		AnnualRate:   credit.InterestRate,
+		Term:         len(shifted),
+		Method:       credit.ScheduleType,
+		DayCount:     credit.DayCount,
+		StartDate:    periodStart,
+		PaymentDates: shifted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate payment schedule: %w", err)
+	}
+
+	return append(schedule, toPaymentSchedule(credit.ID, installments)...), nil
+}
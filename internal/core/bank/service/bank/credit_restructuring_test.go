@@ -0,0 +1,224 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// restructuringCredit возвращает кредит 100000 под 12% на 12 месяцев и его график с погашенным первым платежом.
+func restructuringCredit(t *testing.T) (*entity.Credit, []entity.PaymentSchedule) {
+	issued := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	credit := &entity.Credit{
+		ID:           3,
+		UserID:       1,
+		Amount:       decimal.NewFromInt(100000),
+		InterestRate: decimal.NewFromInt(12),
+		TermInMonths: 12,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Thirty360,
+		Status:       entity.CreditStatusActive,
+		CreatedAt:    issued,
+	}
+
+	payments, err := buildPaymentSchedule(credit, issued, calendar.Russia())
+	assert.NoError(t, err)
+
+	payments[0].Status = entity.PaymentPaid
+	payments[0].PaidAmount = payments[0].PaymentAmount
+	return credit, payments
+}
+
+func TestPlanRestructuring(t *testing.T) {
+	testCases := []struct {
+		name            string
+		kind            entity.RestructuringType
+		wantPrincipal   string
+		wantFirst       string
+		wantCapitalized string
+		wantAmortizing  string
+	}{
+		{name: "interest only", kind: entity.InterestOnly, wantPrincipal: "92181.79", wantFirst: "921.82", wantCapitalized: "0.00", wantAmortizing: "8891.31"},
+		{name: "payment holiday capitalizes interest", kind: entity.PaymentHoliday, wantPrincipal: "92181.79", wantFirst: "0.00", wantCapitalized: "1790.79", wantAmortizing: "9064.04"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			credit, payments := restructuringCredit(t)
+
+			schedule, err := planRestructuring(credit, payments, tc.kind, 2, calendar.Russia())
+			assert.NoError(t, err)
+			assert.Len(t, schedule, 13)
+
+			principal, capitalized := decimal.Zero, decimal.Zero
+			for _, payment := range schedule {
+				principal = principal.Add(payment.PrincipalAmount)
+				if payment.CapitalizesInterest() {
+					capitalized = capitalized.Sub(payment.PrincipalAmount)
+					assert.True(t, payment.PaymentAmount.IsZero())
+				}
+				assert.Equal(t, entity.PaymentPending, payment.Status)
+			}
+
+			assert.Equal(t, tc.wantPrincipal, principal.StringFixed(2))
+			assert.Equal(t, tc.wantCapitalized, capitalized.StringFixed(2))
+			assert.Equal(t, tc.wantFirst, schedule[0].PaymentAmount.StringFixed(2))
+			assert.Equal(t, tc.wantAmortizing, schedule[2].PaymentAmount.StringFixed(2))
+			assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), schedule[len(schedule)-1].PaymentDate)
+			assert.True(t, schedule[len(schedule)-1].Balance.IsZero())
+		})
+	}
+
+	t.Run("nothing to restructure", func(t *testing.T) {
+		credit, payments := restructuringCredit(t)
+		for i := range payments {
+			payments[i].Status = entity.PaymentPaid
+		}
+
+		_, err := planRestructuring(credit, payments, entity.InterestOnly, 2, calendar.Russia())
+		assert.ErrorIs(t, err, entity.ErrNothingToRestructure)
+	})
+}
+
+func TestCreditService_ApproveRestructuring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	credit, payments := restructuringCredit(t)
+	today := accrualDay(time.Now())
+	accruedTo := today.AddDate(0, 0, -1)
+	credit.InterestAccruedTo = &accruedTo
+	restructuring := &entity.Restructuring{ID: 8, CreditID: 3, Type: entity.InterestOnly, Months: 3, Status: entity.RestructuringRequested}
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	creditRepo.EXPECT().LockCreditByID(gomock.Any(), int32(3)).Return(credit, nil)
+	// Проценты по прежнему графику доначисляются до его замены.
+	creditRepo.EXPECT().GetPaymentSchedule(gomock.Any(), int32(3)).Return(payments, nil).Times(2)
+	creditRepo.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	creditRepo.EXPECT().UpdateInterestReceivable(gomock.Any(), int32(3), gomock.Any(), &today).Return(nil)
+	creditRepo.EXPECT().DeletePendingPayments(gomock.Any(), int32(3)).Return(nil)
+	creditRepo.EXPECT().CreatePaymentSchedule(gomock.Any(), gomock.Any()).Return(nil, nil).Times(14)
+	creditRepo.EXPECT().UpdateCreditTerm(gomock.Any(), int32(3), int32(15)).Return(nil)
+
+	restructuringRepo := NewMockRestructuringRepository(ctrl)
+	restructuringRepo.EXPECT().LockByID(gomock.Any(), int32(8)).Return(restructuring, nil)
+	restructuringRepo.EXPECT().CreateScheduleVersion(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, version *entity.ScheduleVersion) error {
+		assert.Equal(t, payments, version.Schedule)
+		version.Version = 1
+		return nil
+	})
+	restructuringRepo.EXPECT().Update(gomock.Any(), restructuring).Return(nil)
+
+	service := &CreditService{creditRepository: creditRepo, restructuringRepository: restructuringRepo, calendar: calendar.Russia(), logger: logger}
+	approved, err := service.ApproveRestructuring(context.TODO(), 42, 8, "income loss confirmed")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RestructuringApproved, approved.Status)
+	assert.Equal(t, int32(42), *approved.DecidedBy)
+	assert.Equal(t, int32(1), *approved.ScheduleVersion)
+}
+
+func TestCreditService_PrincipalLedgerAfterRestructuring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	credit, payments := restructuringCredit(t)
+	credit.AccountID = 5
+	accruedTo := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	credit.InterestAccruedTo = &accruedTo
+
+	restructuredAt := payments[0].PaymentDate
+	ledger := []entity.PrincipalPosting{
+		{PostingDate: accrualDay(credit.CreatedAt), Type: entity.PrincipalDisbursement, Amount: credit.Amount},
+		{PostingDate: restructuredAt, Type: entity.PrincipalRepayment, Amount: payments[0].PrincipalPaid().Neg()},
+	}
+	principalAt := func(date time.Time) decimal.Decimal {
+		principal := decimal.Zero
+		for _, posting := range ledger {
+			if !posting.PostingDate.After(date) {
+				principal = principal.Add(posting.Amount)
+			}
+		}
+		return principal
+	}
+
+	schedule, err := planRestructuring(credit, payments, entity.PaymentHoliday, 2, calendar.Russia())
+	assert.NoError(t, err)
+	for i := range schedule {
+		schedule[i].ID = int32(100 + i)
+	}
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().UpdatePayment(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	creditRepo.EXPECT().CountUnpaidPayments(gomock.Any(), credit.ID).Return(len(schedule), nil).AnyTimes()
+	creditRepo.EXPECT().CreatePrincipalPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.PrincipalPosting) error {
+		ledger = append(ledger, *posting)
+		return nil
+	}).AnyTimes()
+	creditRepo.EXPECT().CreatePaymentPosting(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	accountRepo := NewMockAccountRepository(ctrl)
+	accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+	accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.NewFromInt(100000)}, nil).AnyTimes()
+	accountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	service := &CreditService{creditRepository: creditRepo, accountService: NewAccountService(logger, accountRepo), logger: logger}
+
+	// Две строки каникул капитализируют проценты, следующие две погашают основной долг.
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, service.withdrawPayment(context.TODO(), credit, &schedule[i], schedule[i].PaymentDate))
+	}
+
+	capitalized, outstanding := decimal.Zero, decimal.Zero
+	for i := range schedule {
+		if schedule[i].CapitalizesInterest() {
+			capitalized = capitalized.Sub(schedule[i].PrincipalAmount)
+		}
+		outstanding = outstanding.Add(schedule[i].PrincipalOutstanding())
+	}
+
+	assert.Equal(t, "92181.79", principalAt(restructuredAt).StringFixed(2))
+	assert.Equal(t, "93972.58", principalAt(schedule[1].PaymentDate).StringFixed(2))
+	assert.True(t, principalAt(restructuredAt).Add(capitalized).Equal(principalAt(schedule[1].PaymentDate)))
+	assert.True(t, outstanding.Equal(principalAt(schedule[3].PaymentDate)), "outstanding: %s", outstanding)
+}
+
+func TestCreditService_RejectRestructuring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	restructuringRepo := NewMockRestructuringRepository(ctrl)
+	restructuringRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	restructuringRepo.EXPECT().LockByID(gomock.Any(), int32(8)).Return(&entity.Restructuring{ID: 8, Status: entity.RestructuringApproved}, nil)
+
+	service := &CreditService{restructuringRepository: restructuringRepo, logger: logger}
+
+	_, err := service.RejectRestructuring(context.TODO(), 42, 8, "")
+	assert.ErrorIs(t, err, entity.ErrDecisionReasonRequired)
+
+	_, err = service.RejectRestructuring(context.TODO(), 42, 8, "too late")
+	assert.ErrorIs(t, err, entity.ErrRestructuringNotRequested)
+}
+
+func TestRestructuring_Validate(t *testing.T) {
+	valid := entity.Restructuring{Type: entity.PaymentHoliday, Months: 6}
+	assert.NoError(t, valid.Validate())
+
+	tooLong := entity.Restructuring{Type: entity.PaymentHoliday, Months: 7}
+	assert.ErrorIs(t, tooLong.Validate(), entity.ErrInvalidRestructuring)
+
+	unknown := entity.Restructuring{Type: "skip", Months: 1}
+	assert.ErrorIs(t, unknown.Validate(), entity.ErrInvalidRestructuring)
+}
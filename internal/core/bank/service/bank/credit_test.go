@@ -0,0 +1,476 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestBuildPaymentSchedule(t *testing.T) {
+	start := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		scheduleType amortization.Method
+		wantPayments []string
+	}{
+		{
+			name:         "annuity",
+			scheduleType: amortization.Annuity,
+			wantPayments: []string{"8884.88", "8884.88", "8884.88", "8884.88", "8884.88", "8884.88",
+				"8884.88", "8884.88", "8884.88", "8884.88", "8884.88", "8906.95"},
+		},
+		{
+			name:         "differentiated",
+			scheduleType: amortization.Differentiated,
+			// Основной долг гасится по 8333.33, остаток копеек переносится в последний платеж. Проценты начисляются
+			// за фактические периоды между датами, перенесенными на рабочие дни.
+			wantPayments: []string{"9400.00", "9250.00", "9111.11", "9083.33", "9022.22", "8897.22",
+				"8833.33", "8750.00", "8666.66", "8600.00", "8488.89", "8416.70"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			credit := &entity.Credit{
+				ID:           7,
+				Amount:       decimal.NewFromInt(100000),
+				InterestRate: decimal.NewFromInt(12),
+				TermInMonths: 12,
+				ScheduleType: tc.scheduleType,
+				DayCount:     amortization.Thirty360,
+			}
+
+			schedule, err := buildPaymentSchedule(credit, start, calendar.Russia())
+			assert.NoError(t, err)
+			assert.Len(t, schedule, 12)
+
+			principal := decimal.Zero
+			for i, payment := range schedule {
+				assert.Equal(t, int32(7), payment.CreditID)
+				assert.True(t, decimal.RequireFromString(tc.wantPayments[i]).Equal(payment.PaymentAmount), "payment %d: %s", i+1, payment.PaymentAmount)
+				assert.True(t, payment.PaymentAmount.Equal(payment.PrincipalAmount.Add(payment.InterestAmount)))
+				principal = principal.Add(payment.PrincipalAmount)
+			}
+
+			assert.True(t, credit.Amount.Equal(principal))
+			assert.True(t, schedule[11].Balance.IsZero())
+			// 15 февраля 2025 года — суббота, платеж переносится на понедельник.
+			assert.Equal(t, time.Date(2025, 2, 17, 0, 0, 0, 0, time.UTC), schedule[0].PaymentDate)
+			assert.Equal(t, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), schedule[2].PaymentDate)
+		})
+	}
+
+	t.Run("payment rolled into next month", func(t *testing.T) {
+		credit := &entity.Credit{Amount: decimal.NewFromInt(1000), InterestRate: decimal.NewFromInt(12), TermInMonths: 12,
+			ScheduleType: amortization.Annuity, DayCount: amortization.Actual365}
+
+		issued := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+		schedule, err := buildPaymentSchedule(credit, issued, calendar.Russia())
+		assert.NoError(t, err)
+
+		// 31 мая 2025 года — суббота, платеж переносится на понедельник 2 июня.
+		assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), schedule[3].PaymentDate)
+		for i, payment := range schedule {
+			assert.Equal(t, i+1, paymentMonth(issued, payment.PaymentDate), "payment %d", i+1)
+		}
+	})
+
+	t.Run("unknown schedule type", func(t *testing.T) {
+		credit := &entity.Credit{Amount: decimal.NewFromInt(1000), InterestRate: decimal.NewFromInt(12), TermInMonths: 3,
+			ScheduleType: "balloon", DayCount: amortization.Actual365}
+
+		_, err := buildPaymentSchedule(credit, start, calendar.Russia())
+		assert.Error(t, err)
+	})
+}
+
+func TestPaymentMonth(t *testing.T) {
+	start := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		date time.Time
+		want int
+	}{
+		{name: "on schedule", date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), want: 2},
+		{name: "clamped to month end", date: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), want: 1},
+		{name: "rolled forward into next month", date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), want: 4},
+		{name: "rolled past new year holidays", date: time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), want: 11},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, paymentMonth(start, tc.date))
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn transaction.AtomicFn, _ ...transaction.TxOption) error {
+	return fn(ctx)
+}
+
+func TestCreditService_CollectDuePayments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	now := time.Date(2025, 2, 15, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name        string
+		balance     string
+		paymentDate time.Time
+		paidAmount  string
+		receivable  string
+		unpaid      int
+		wantStatus  entity.PaymentStatus
+		wantPaid    string
+		wantDebit   string
+		wantSettled string
+		wantRepaid  string
+	}{
+		{
+			name:        "last payment is paid and credit is closed",
+			balance:     "10000",
+			paymentDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+			paidAmount:  "0",
+			receivable:  "84.88",
+			wantStatus:  entity.PaymentPaid,
+			wantPaid:    "8884.88",
+			wantDebit:   "8884.88",
+			wantSettled: "84.88",
+			wantRepaid:  "8000",
+		},
+		{
+			name:        "remaining part of partially paid payment",
+			balance:     "10000",
+			paymentDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+			paidAmount:  "884.88",
+			receivable:  "0",
+			unpaid:      2,
+			wantStatus:  entity.PaymentPaid,
+			wantPaid:    "8884.88",
+			wantDebit:   "8000",
+			wantRepaid:  "8000",
+		},
+		{
+			name:        "insufficient funds on payment day",
+			balance:     "500",
+			paymentDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+			paidAmount:  "0",
+			receivable:  "884.88",
+			wantStatus:  entity.PaymentPartiallyPaid,
+			wantPaid:    "500",
+			wantDebit:   "500",
+			wantSettled: "500",
+		},
+		{
+			name:        "no funds after payment day",
+			balance:     "0",
+			paymentDate: time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC),
+			paidAmount:  "0",
+			receivable:  "884.88",
+			wantStatus:  entity.PaymentOverdue,
+			wantPaid:    "0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			credit := &entity.Credit{ID: 3, UserID: 1, Status: entity.CreditStatusActive}
+			payments := []entity.PaymentSchedule{{
+				ID:              11,
+				CreditID:        credit.ID,
+				PaymentDate:     tc.paymentDate,
+				PaymentAmount:   decimal.RequireFromString("8884.88"),
+				PrincipalAmount: decimal.RequireFromString("8000"),
+				InterestAmount:  decimal.RequireFromString("884.88"),
+				PaidAmount:      decimal.RequireFromString(tc.paidAmount),
+				Status:          entity.PaymentPending,
+			}}
+			payment := &payments[0]
+			dueBy := time.Date(2025, 2, 16, 0, 0, 0, 0, time.UTC)
+			accruedTo := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+			credit.InterestReceivable = decimal.RequireFromString(tc.receivable)
+			credit.InterestAccruedTo = &accruedTo
+
+			creditRepo := NewMockCreditRepository(ctrl)
+			creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			gomock.InOrder(
+				creditRepo.EXPECT().LockDueCredit(gomock.Any(), dueBy, int32(0)).Return(credit, nil),
+				creditRepo.EXPECT().LockDuePayments(gomock.Any(), credit.ID, dueBy).Return(payments, nil),
+				creditRepo.EXPECT().LockDueCredit(gomock.Any(), dueBy, credit.ID).Return(nil, entity.ErrCreditNotFound),
+			)
+			creditRepo.EXPECT().UpdatePayment(gomock.Any(), payment).Return(nil)
+			if tc.wantSettled != "" {
+				settled := decimal.RequireFromString(tc.wantSettled)
+				creditRepo.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.InterestPosting) error {
+					assert.Equal(t, entity.InterestSettlement, posting.Type)
+					assert.True(t, settled.Neg().Equal(posting.Amount), "settled: %s", posting.Amount)
+					assert.Equal(t, payment.ID, *posting.PaymentID)
+					return nil
+				})
+				wantReceivable := decimal.RequireFromString(tc.receivable).Sub(settled)
+				creditRepo.EXPECT().UpdateInterestReceivable(gomock.Any(), credit.ID, wantReceivable, &accruedTo).Return(nil)
+			}
+			if tc.wantDebit != "" {
+				creditRepo.EXPECT().CreatePaymentPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.PaymentPosting) error {
+					assert.Equal(t, payment.ID, posting.PaymentID)
+					assert.True(t, decimal.RequireFromString(tc.wantDebit).Equal(posting.Amount), "received: %s", posting.Amount)
+					assert.True(t, posting.PenaltyAmount.IsZero())
+					return nil
+				})
+			}
+			if tc.wantRepaid != "" {
+				repaid := decimal.RequireFromString(tc.wantRepaid)
+				creditRepo.EXPECT().CreatePrincipalPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.PrincipalPosting) error {
+					assert.Equal(t, entity.PrincipalRepayment, posting.Type)
+					assert.True(t, repaid.Neg().Equal(posting.Amount), "repaid: %s", posting.Amount)
+					assert.Equal(t, payment.ID, *posting.PaymentID)
+					return nil
+				})
+			}
+			if tc.wantStatus == entity.PaymentPaid {
+				creditRepo.EXPECT().CountUnpaidPayments(gomock.Any(), credit.ID).Return(tc.unpaid, nil)
+			}
+			if tc.wantStatus == entity.PaymentPaid && tc.unpaid == 0 {
+				creditRepo.EXPECT().UpdateCreditStatus(gomock.Any(), credit.ID, entity.CreditStatusPaid).Return(nil)
+			}
+
+			accountRepo := NewMockAccountRepository(ctrl)
+			accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			accountRepo.EXPECT().GetAccountByUserID(gomock.Any(), credit.UserID).Return(&entity.Account{ID: 5, UserID: 1}, nil)
+			accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.RequireFromString(tc.balance)}, nil)
+			if tc.wantDebit != "" {
+				wantBalance := decimal.RequireFromString(tc.balance).Sub(decimal.RequireFromString(tc.wantDebit))
+				accountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, account *entity.Account) error {
+					assert.True(t, wantBalance.Equal(account.Balance), "balance: %s", account.Balance)
+					return nil
+				})
+			}
+
+			service := &CreditService{
+				creditRepository: creditRepo,
+				accountService:   NewAccountService(logger, accountRepo),
+				logger:           logger,
+			}
+			err := service.CollectDuePayments(context.TODO(), now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, payment.Status)
+			assert.True(t, decimal.RequireFromString(tc.wantPaid).Equal(payment.PaidAmount), "paid: %s", payment.PaidAmount)
+			assert.Equal(t, tc.wantStatus == entity.PaymentPaid, payment.PaidAt != nil)
+		})
+	}
+
+	t.Run("holiday payment capitalizes accrued interest", func(t *testing.T) {
+		credit := &entity.Credit{ID: 3, UserID: 1, Status: entity.CreditStatusActive}
+		payments := []entity.PaymentSchedule{{
+			ID:              12,
+			CreditID:        credit.ID,
+			PaymentDate:     time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC),
+			PaymentAmount:   decimal.Zero,
+			PrincipalAmount: decimal.RequireFromString("-921.82"),
+			InterestAmount:  decimal.RequireFromString("921.82"),
+			PaidAmount:      decimal.Zero,
+			Status:          entity.PaymentPending,
+		}}
+		payment := &payments[0]
+		accruedTo := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+		credit.InterestReceivable = decimal.RequireFromString("1000")
+		credit.InterestAccruedTo = &accruedTo
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		gomock.InOrder(
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), int32(0)).Return(credit, nil),
+			creditRepo.EXPECT().LockDuePayments(gomock.Any(), credit.ID, gomock.Any()).Return(payments, nil),
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), credit.ID).Return(nil, entity.ErrCreditNotFound),
+		)
+		creditRepo.EXPECT().UpdatePayment(gomock.Any(), payment).Return(nil)
+		creditRepo.EXPECT().CreatePrincipalPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.PrincipalPosting) error {
+			assert.Equal(t, entity.PrincipalCapitalization, posting.Type)
+			assert.True(t, decimal.RequireFromString("921.82").Equal(posting.Amount), "capitalized: %s", posting.Amount)
+			return nil
+		})
+		creditRepo.EXPECT().CreateInterestPosting(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, posting *entity.InterestPosting) error {
+			assert.Equal(t, entity.InterestCapitalization, posting.Type)
+			assert.True(t, decimal.RequireFromString("-921.82").Equal(posting.Amount), "capitalized: %s", posting.Amount)
+			return nil
+		})
+		creditRepo.EXPECT().UpdateInterestReceivable(gomock.Any(), credit.ID, decimal.RequireFromString("78.18"), &accruedTo).Return(nil)
+		creditRepo.EXPECT().CountUnpaidPayments(gomock.Any(), credit.ID).Return(10, nil)
+
+		accountRepo := NewMockAccountRepository(ctrl)
+		accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		accountRepo.EXPECT().GetAccountByUserID(gomock.Any(), credit.UserID).Return(&entity.Account{ID: 5, UserID: 1}, nil)
+		accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.NewFromInt(100)}, nil)
+
+		service := &CreditService{creditRepository: creditRepo, accountService: NewAccountService(logger, accountRepo), logger: logger}
+		err := service.CollectDuePayments(context.TODO(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PaymentPaid, payment.Status)
+	})
+
+	t.Run("failed credit does not stop collection", func(t *testing.T) {
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		gomock.InOrder(
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), int32(0)).Return(&entity.Credit{ID: 3, Status: entity.CreditStatusActive}, nil),
+			creditRepo.EXPECT().LockDuePayments(gomock.Any(), int32(3), gomock.Any()).Return(nil, assert.AnError),
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), int32(3)).Return(nil, entity.ErrCreditNotFound),
+		)
+
+		service := &CreditService{creditRepository: creditRepo, logger: logger}
+		err := service.CollectDuePayments(context.TODO(), now)
+
+		assert.ErrorContains(t, err, "failed to collect payments for 1 of 1 credits")
+	})
+
+	t.Run("collection stops once the credit is repaid", func(t *testing.T) {
+		credit := &entity.Credit{ID: 3, UserID: 1, Status: entity.CreditStatusActive}
+		accruedTo := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+		credit.InterestAccruedTo = &accruedTo
+		payments := []entity.PaymentSchedule{
+			{ID: 11, CreditID: 3, PaymentDate: accruedTo, PaymentAmount: decimal.NewFromInt(100), Status: entity.PaymentPending},
+			{ID: 12, CreditID: 3, PaymentDate: accruedTo, PaymentAmount: decimal.NewFromInt(100), Status: entity.PaymentPending},
+		}
+
+		creditRepo := NewMockCreditRepository(ctrl)
+		creditRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		gomock.InOrder(
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), int32(0)).Return(credit, nil),
+			creditRepo.EXPECT().LockDuePayments(gomock.Any(), credit.ID, gomock.Any()).Return(payments, nil),
+			creditRepo.EXPECT().LockDueCredit(gomock.Any(), gomock.Any(), credit.ID).Return(nil, entity.ErrCreditNotFound),
+		)
+		creditRepo.EXPECT().UpdatePayment(gomock.Any(), &payments[0]).Return(nil)
+		creditRepo.EXPECT().CreatePaymentPosting(gomock.Any(), gomock.Any()).Return(nil)
+		creditRepo.EXPECT().CountUnpaidPayments(gomock.Any(), credit.ID).Return(0, nil)
+		creditRepo.EXPECT().UpdateCreditStatus(gomock.Any(), credit.ID, entity.CreditStatusPaid).Return(nil)
+
+		accountRepo := NewMockAccountRepository(ctrl)
+		accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+		accountRepo.EXPECT().GetAccountByUserID(gomock.Any(), credit.UserID).Return(&entity.Account{ID: 5, UserID: 1}, nil)
+		accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.NewFromInt(500)}, nil)
+		accountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := &CreditService{creditRepository: creditRepo, accountService: NewAccountService(logger, accountRepo), logger: logger}
+		err := service.CollectDuePayments(context.TODO(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PaymentPaid, payments[0].Status)
+		assert.Equal(t, entity.PaymentPending, payments[1].Status)
+	})
+}
+
+func TestCreditService_Disburse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	issued := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	credit := &entity.Credit{ID: 3, UserID: 1, AccountID: 5, Amount: decimal.NewFromInt(100000), CreatedAt: issued}
+
+	accountRepo := NewMockAccountRepository(ctrl)
+	accountRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	accountRepo.EXPECT().LockByID(gomock.Any(), int32(5)).Return(&entity.Account{ID: 5, Balance: decimal.NewFromInt(250)}, nil)
+	accountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, account *entity.Account) error {
+		assert.True(t, decimal.NewFromInt(100250).Equal(account.Balance))
+		return nil
+	})
+
+	creditID, accountID := int32(3), int32(5)
+	transactionRepo := NewMockFinancialTransactionRepository(ctrl)
+	transactionRepo.EXPECT().Create(gomock.Any(), &entity.FinancialTransaction{
+		UserID:            1,
+		AccountID:         &accountID,
+		CreditID:          &creditID,
+		TransactionType:   entity.CreditDisbursementTransaction,
+		Amount:            decimal.NewFromInt(100000),
+		TransactionDate:   issued,
+		TransactionStatus: entity.TransactionSuccess,
+	}).Return(int32(9), nil)
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().CreatePrincipalPosting(gomock.Any(), &entity.PrincipalPosting{
+		CreditID:    3,
+		PostingDate: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Type:        entity.PrincipalDisbursement,
+		Amount:      decimal.NewFromInt(100000),
+	}).Return(nil)
+
+	service := &CreditService{
+		creditRepository:               creditRepo,
+		financialTransactionRepository: transactionRepo,
+		accountService:                 NewAccountService(logger, accountRepo),
+		logger:                         logger,
+	}
+
+	assert.NoError(t, service.disburse(context.TODO(), credit))
+}
+
+func TestCreditDetails(t *testing.T) {
+	issued := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	credit := &entity.Credit{
+		ID:           3,
+		UserID:       1,
+		Amount:       decimal.NewFromInt(100000),
+		InterestRate: decimal.NewFromInt(12),
+		TermInMonths: 12,
+		ScheduleType: amortization.Annuity,
+		DayCount:     amortization.Thirty360,
+		Status:       entity.CreditStatusActive,
+		CreatedAt:    issued,
+	}
+
+	payments, err := buildPaymentSchedule(credit, issued, calendar.Russia())
+	assert.NoError(t, err)
+
+	payments[0].Status = entity.PaymentPaid
+	payments[0].PaidAmount = payments[0].PaymentAmount
+	// Второй платеж просрочен: внесено 1010, из них 10 — штраф, 921.82 — проценты, 78.18 — основной долг.
+	payments[1].Status = entity.PaymentOverdue
+	payments[1].Penalty = decimal.NewFromInt(10)
+	payments[1].PenaltyPaid = decimal.NewFromInt(10)
+	payments[1].PaidAmount = decimal.NewFromInt(1010)
+
+	// Проценты за 17 марта уже начислены заданием, за 18 и 19 марта еще нет.
+	accruedTo := time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC)
+	credit.InterestAccruedTo = &accruedTo
+	credit.InterestReceivable = decimal.RequireFromString("30.70")
+
+	details := creditDetails(credit, payments, time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "92103.61", details.OutstandingPrincipal.StringFixed(2))
+	assert.Equal(t, "7884.88", details.OverdueAmount.StringFixed(2))
+	assert.Equal(t, "92.10", details.AccruedInterest.StringFixed(2))
+	assert.Equal(t, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), *details.NextPaymentDate)
+	assert.Len(t, details.Payments, 2)
+}
+
+func TestCreditService_GetCreditChecksOwnership(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	creditRepo := NewMockCreditRepository(ctrl)
+	creditRepo.EXPECT().GetCreditByID(gomock.Any(), int32(3)).Return(&entity.Credit{ID: 3, UserID: 2}, nil).Times(2)
+
+	service := &CreditService{creditRepository: creditRepo, logger: logger}
+
+	_, err := service.GetCredit(context.TODO(), 1, 3, time.Now())
+	assert.ErrorIs(t, err, entity.ErrCreditNotFound)
+
+	_, err = service.GetPaymentSchedule(context.TODO(), 1, 3)
+	assert.ErrorIs(t, err, entity.ErrCreditNotFound)
+}
@@ -0,0 +1,194 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/cb"
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/singleflight"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RateProvider предоставляет текущую ключевую ставку ЦБ.
+type RateProvider interface {
+	KeyRate(ctx context.Context, now time.Time) (*entity.CentralBankRate, error)
+}
+
+// KeyRateSource получает ключевую ставку из внешнего источника, например веб-сервиса ЦБ.
+type KeyRateSource interface {
+	KeyRate(ctx context.Context) (cb.Rate, error)
+	KeyRates(ctx context.Context, from, to time.Time) ([]cb.Rate, error)
+}
+
+// KeyRateRepository хранит историю ключевой ставки ЦБ.
+type KeyRateRepository interface {
+	FindKeyRate(ctx context.Context, at time.Time) (*entity.CentralBankRate, error)
+	SaveKeyRate(ctx context.Context, rate *entity.CentralBankRate) error
+	ListKeyRates(ctx context.Context, from, to time.Time) ([]entity.CentralBankRate, error)
+}
+
+// keyRateFallbackTTL сколько времени используется сохраненная ставка, полученная вместо недоступного источника,
+// прежде чем источник будет запрошен снова.
+const keyRateFallbackTTL = time.Minute
+
+// KeyRateProvider получает ключевую ставку из источника и кеширует ее на время cacheTTL. Каждая полученная ставка
+// сохраняется в историю. Если источник недоступен, используется последняя сохраненная ставка, но не старше maxAge;
+// она кешируется только на keyRateFallbackTTL, чтобы новая ставка была получена вскоре после восстановления источника.
+type KeyRateProvider struct {
+	repo     KeyRateRepository
+	source   KeyRateSource
+	cacheTTL time.Duration
+	maxAge   time.Duration
+	logger   *slog.Logger
+
+	group       singleflight.Group
+	mu          sync.Mutex
+	cached      *entity.CentralBankRate
+	cachedUntil time.Time
+}
+
+// NewKeyRateProvider создает новый экземпляр KeyRateProvider с заданным логгером, конфигурацией, историей ставок
+// и источником ставки.
+func NewKeyRateProvider(logger *slog.Logger, cfg *config.Config, repo KeyRateRepository, source KeyRateSource) *KeyRateProvider {
+	return &KeyRateProvider{
+		repo:     repo,
+		source:   source,
+		cacheTTL: cfg.KeyRateCacheTTL,
+		maxAge:   cfg.KeyRateMaxAge,
+		logger:   logger,
+	}
+}
+
+// KeyRate возвращает ключевую ставку на момент now. Возвращает entity.ErrKeyRateStale, если ставка установлена
+// раньше, чем за maxAge до now. Кеш блокируется только на время чтения, а одновременные запросы при пустом кеше
+// объединяются в один запрос к источнику, результат которого получают все ожидающие.
+func (p *KeyRateProvider) KeyRate(ctx context.Context, now time.Time) (*entity.CentralBankRate, error) {
+	if rate := p.cachedRate(now); rate != nil {
+		return p.fresh(rate, now)
+	}
+
+	result, err, _ := p.group.Do("key_rate", func() (any, error) {
+		if rate := p.cachedRate(now); rate != nil {
+			return rate, nil
+		}
+
+		// Запрос общий для всех ожидающих, поэтому отмена контекста одного из них его не прерывает.
+		return p.load(context.WithoutCancel(ctx), now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.fresh(result.(*entity.CentralBankRate), now)
+}
+
+// cachedRate возвращает ставку из кеша, если на момент now срок ее хранения не истек.
+func (p *KeyRateProvider) cachedRate(now time.Time) *entity.CentralBankRate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil && now.Before(p.cachedUntil) {
+		return p.cached
+	}
+
+	return nil
+}
+
+// load получает ставку из источника, а если он недоступен, из истории на момент now, и кеширует ее.
+func (p *KeyRateProvider) load(ctx context.Context, now time.Time) (*entity.CentralBankRate, error) {
+	ttl := p.cacheTTL
+	rate, err := p.fetch(ctx)
+	if err != nil {
+		p.logger.Warn("failed to fetch key rate, using stored rate", "error", err)
+
+		rate, err = p.repo.FindKeyRate(ctx, now)
+		if err != nil {
+			p.logger.Error("failed to get stored key rate", "error", err)
+			return nil, fmt.Errorf("failed to get stored key rate: %w", err)
+		}
+		ttl = min(ttl, keyRateFallbackTTL)
+	}
+
+	p.mu.Lock()
+	p.cached, p.cachedUntil = rate, now.Add(ttl)
+	p.mu.Unlock()
+
+	return rate, nil
+}
+
+// History возвращает сохраненные ключевые ставки с датами с from по to включительно в порядке возрастания дат.
+func (p *KeyRateProvider) History(ctx context.Context, from, to time.Time) ([]entity.CentralBankRate, error) {
+	if from.After(to) {
+		return nil, entity.ErrInvalidKeyRatePeriod
+	}
+
+	rates, err := p.repo.ListKeyRates(ctx, from, to)
+	if err != nil {
+		p.logger.Error("failed to list key rates", "error", err)
+		return nil, fmt.Errorf("failed to list key rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Backfill загружает из источника ключевые ставки за период с from по to и сохраняет их в историю. Уже сохраненные
+// ставки перезаписываются, поэтому загрузку можно повторять. Возвращает количество сохраненных ставок.
+func (p *KeyRateProvider) Backfill(ctx context.Context, from, to time.Time) (int, error) {
+	if from.After(to) {
+		return 0, entity.ErrInvalidKeyRatePeriod
+	}
+
+	values, err := p.source.KeyRates(ctx, from, to)
+	if err != nil {
+		p.logger.Error("failed to fetch key rates", "error", err)
+		return 0, fmt.Errorf("failed to fetch key rates: %w", err)
+	}
+
+	for i, value := range values {
+		if err := p.repo.SaveKeyRate(ctx, centralBankRate(value)); err != nil {
+			p.logger.Error("failed to save key rate", "error", err, "rate_date", value.Date)
+			return i, fmt.Errorf("failed to save key rate of %s: %w", value.Date.Format(time.DateOnly), err)
+		}
+	}
+
+	p.logger.Info("key rates backfilled", "from", from, "to", to, "count", len(values))
+	return len(values), nil
+}
+
+// fetch получает ставку из источника и сохраняет ее в историю. Ошибка сохранения не мешает использовать ставку.
+func (p *KeyRateProvider) fetch(ctx context.Context) (*entity.CentralBankRate, error) {
+	value, err := p.source.KeyRate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := centralBankRate(value)
+	if err := p.repo.SaveKeyRate(ctx, rate); err != nil {
+		p.logger.Error("failed to save key rate", "error", err)
+	}
+
+	return rate, nil
+}
+
+// fresh возвращает копию ставки rate, если на момент now она не старше maxAge.
+func (p *KeyRateProvider) fresh(rate *entity.CentralBankRate, now time.Time) (*entity.CentralBankRate, error) {
+	if now.Sub(rate.RateDate) > p.maxAge {
+		return nil, fmt.Errorf("%w: rate of %s", entity.ErrKeyRateStale, rate.RateDate.Format(time.DateOnly))
+	}
+
+	result := *rate
+	return &result, nil
+}
+
+// centralBankRate преобразует ставку источника в запись истории ставок.
+func centralBankRate(value cb.Rate) *entity.CentralBankRate {
+	return &entity.CentralBankRate{
+		Rate:     decimal.NewFromFloat(value.Value).Round(2),
+		RateDate: value.Date,
+	}
+}
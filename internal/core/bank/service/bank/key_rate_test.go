@@ -0,0 +1,240 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/cb"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyRateProvider_KeyRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	cfg := &config.Config{KeyRateCacheTTL: time.Hour, KeyRateMaxAge: 7 * 24 * time.Hour}
+	now := time.Date(2025, 7, 30, 9, 0, 0, 0, time.UTC)
+	rateDate := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fetched rate is saved and cached", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{Date: rateDate, Value: 18}, nil).Times(1)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rate *entity.CentralBankRate) error {
+			assert.True(t, decimal.NewFromInt(18).Equal(rate.Rate))
+			assert.Equal(t, rateDate, rate.RateDate)
+			rate.ID = 4
+			return nil
+		})
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+
+		rate, err := provider.KeyRate(context.TODO(), now)
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), rate.ID)
+
+		cached, err := provider.KeyRate(context.TODO(), now.Add(30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, rate, cached)
+	})
+
+	t.Run("expired cache is refreshed", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		gomock.InOrder(
+			source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{Date: rateDate, Value: 18}, nil),
+			source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{Date: rateDate.AddDate(0, 0, 1), Value: 17}, nil),
+		)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+		_, err := provider.KeyRate(context.TODO(), now)
+		require.NoError(t, err)
+
+		rate, err := provider.KeyRate(context.TODO(), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(17).Equal(rate.Rate))
+	})
+
+	t.Run("unreachable source falls back to stored rate", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{}, assert.AnError)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().FindKeyRate(gomock.Any(), now).Return(&entity.CentralBankRate{ID: 3, Rate: decimal.NewFromInt(20), RateDate: rateDate}, nil)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+		rate, err := provider.KeyRate(context.TODO(), now)
+
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(20).Equal(rate.Rate))
+	})
+
+	t.Run("stored rate is cached until the source is retried", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		gomock.InOrder(
+			source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{}, assert.AnError),
+			source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{Date: rateDate.AddDate(0, 0, 1), Value: 17}, nil),
+		)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().FindKeyRate(gomock.Any(), now).Return(&entity.CentralBankRate{ID: 3, Rate: decimal.NewFromInt(20), RateDate: rateDate}, nil)
+		repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).Return(nil)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+		_, err := provider.KeyRate(context.TODO(), now)
+		require.NoError(t, err)
+
+		cached, err := provider.KeyRate(context.TODO(), now.Add(keyRateFallbackTTL/2))
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(20).Equal(cached.Rate))
+
+		rate, err := provider.KeyRate(context.TODO(), now.Add(keyRateFallbackTTL))
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(17).Equal(rate.Rate))
+	})
+
+	t.Run("concurrent requests share one fetch", func(t *testing.T) {
+		release := make(chan struct{})
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRate(gomock.Any()).DoAndReturn(func(context.Context) (cb.Rate, error) {
+			<-release
+			return cb.Rate{Date: rateDate, Value: 18}, nil
+		}).Times(1)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).Return(nil)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+
+		var wg sync.WaitGroup
+		rates := make([]*entity.CentralBankRate, 5)
+		for i := range rates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rate, err := provider.KeyRate(context.TODO(), now)
+				assert.NoError(t, err)
+				rates[i] = rate
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		for _, rate := range rates {
+			assert.True(t, decimal.NewFromInt(18).Equal(rate.Rate))
+		}
+	})
+
+	t.Run("stored rate older than limit is rejected", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{}, assert.AnError)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().FindKeyRate(gomock.Any(), now).Return(&entity.CentralBankRate{Rate: decimal.NewFromInt(20), RateDate: rateDate.AddDate(0, 0, -10)}, nil)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+		_, err := provider.KeyRate(context.TODO(), now)
+
+		assert.ErrorIs(t, err, entity.ErrKeyRateStale)
+	})
+
+	t.Run("no stored rate", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRate(gomock.Any()).Return(cb.Rate{}, assert.AnError)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		repo.EXPECT().FindKeyRate(gomock.Any(), now).Return(nil, entity.ErrKeyRateNotFound)
+
+		provider := NewKeyRateProvider(logger, cfg, repo, source)
+		_, err := provider.KeyRate(context.TODO(), now)
+
+		assert.ErrorIs(t, err, entity.ErrKeyRateNotFound)
+	})
+}
+
+func TestKeyRateProvider_Backfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	from := time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC)
+	values := []cb.Rate{{Date: from, Value: 20}, {Date: to, Value: 18}}
+
+	t.Run("saves every rate of the period", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRates(gomock.Any(), from, to).Return(values, nil)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		var saved []time.Time
+		repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rate *entity.CentralBankRate) error {
+			saved = append(saved, rate.RateDate)
+			return nil
+		}).Times(2)
+
+		provider := NewKeyRateProvider(logger, &config.Config{}, repo, source)
+		count, err := provider.Backfill(context.TODO(), from, to)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []time.Time{from, to}, saved)
+	})
+
+	t.Run("failed save stops backfill", func(t *testing.T) {
+		source := NewMockKeyRateSource(ctrl)
+		source.EXPECT().KeyRates(gomock.Any(), from, to).Return(values, nil)
+
+		repo := NewMockKeyRateRepository(ctrl)
+		gomock.InOrder(
+			repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).Return(nil),
+			repo.EXPECT().SaveKeyRate(gomock.Any(), gomock.Any()).Return(assert.AnError),
+		)
+
+		provider := NewKeyRateProvider(logger, &config.Config{}, repo, source)
+		count, err := provider.Backfill(context.TODO(), from, to)
+
+		assert.ErrorContains(t, err, "failed to save key rate of 2025-07-28")
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		provider := NewKeyRateProvider(logger, &config.Config{}, nil, nil)
+		_, err := provider.Backfill(context.TODO(), to, from)
+
+		assert.ErrorIs(t, err, entity.ErrInvalidKeyRatePeriod)
+	})
+}
+
+func TestKeyRateProvider_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	repo := NewMockKeyRateRepository(ctrl)
+	repo.EXPECT().ListKeyRates(gomock.Any(), from, to).Return([]entity.CentralBankRate{{ID: 1, Rate: decimal.NewFromInt(21)}}, nil)
+
+	provider := NewKeyRateProvider(logger, &config.Config{}, repo, nil)
+
+	history, err := provider.History(context.TODO(), from, to)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	_, err = provider.History(context.TODO(), to, from)
+	assert.ErrorIs(t, err, entity.ErrInvalidKeyRatePeriod)
+}
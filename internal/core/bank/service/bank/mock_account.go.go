@@ -66,6 +66,21 @@ func (mr *MockAccountRepositoryMockRecorder) GetAccountByUserID(ctx, userID inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByUserID", reflect.TypeOf((*MockAccountRepository)(nil).GetAccountByUserID), ctx, userID)
 }
 
+// LockByID mocks base method.
+func (m *MockAccountRepository) LockByID(ctx context.Context, id int32) (*entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByID indicates an expected call of LockByID.
+func (mr *MockAccountRepositoryMockRecorder) LockByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByID", reflect.TypeOf((*MockAccountRepository)(nil).LockByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockAccountRepository) Save(ctx context.Context, account *entity.Account) (*entity.Account, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +96,20 @@ func (mr *MockAccountRepositoryMockRecorder) Save(ctx, account interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAccountRepository)(nil).Save), ctx, account)
 }
 
+// Update mocks base method.
+func (m *MockAccountRepository) Update(ctx context.Context, account *entity.Account) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAccountRepositoryMockRecorder) Update(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccountRepository)(nil).Update), ctx, account)
+}
+
 // WithTx mocks base method.
 func (m *MockAccountRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bureau.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBureauRepository is a mock of BureauRepository interface.
+type MockBureauRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBureauRepositoryMockRecorder
+}
+
+// MockBureauRepositoryMockRecorder is the mock recorder for MockBureauRepository.
+type MockBureauRepositoryMockRecorder struct {
+	mock *MockBureauRepository
+}
+
+// NewMockBureauRepository creates a new mock instance.
+func NewMockBureauRepository(ctrl *gomock.Controller) *MockBureauRepository {
+	mock := &MockBureauRepository{ctrl: ctrl}
+	mock.recorder = &MockBureauRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBureauRepository) EXPECT() *MockBureauRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSubmission mocks base method.
+func (m *MockBureauRepository) CreateSubmission(ctx context.Context, submission *entity.BureauSubmission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubmission", ctx, submission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubmission indicates an expected call of CreateSubmission.
+func (mr *MockBureauRepositoryMockRecorder) CreateSubmission(ctx, submission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubmission", reflect.TypeOf((*MockBureauRepository)(nil).CreateSubmission), ctx, submission)
+}
+
+// GetSubmission mocks base method.
+func (m *MockBureauRepository) GetSubmission(ctx context.Context, period time.Time) (*entity.BureauSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubmission", ctx, period)
+	ret0, _ := ret[0].(*entity.BureauSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubmission indicates an expected call of GetSubmission.
+func (mr *MockBureauRepositoryMockRecorder) GetSubmission(ctx, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubmission", reflect.TypeOf((*MockBureauRepository)(nil).GetSubmission), ctx, period)
+}
+
+// ListRecords mocks base method.
+func (m *MockBureauRepository) ListRecords(ctx context.Context, from, to time.Time) ([]entity.BureauRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecords", ctx, from, to)
+	ret0, _ := ret[0].([]entity.BureauRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecords indicates an expected call of ListRecords.
+func (mr *MockBureauRepositoryMockRecorder) ListRecords(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecords", reflect.TypeOf((*MockBureauRepository)(nil).ListRecords), ctx, from, to)
+}
+
+// ListSubmissions mocks base method.
+func (m *MockBureauRepository) ListSubmissions(ctx context.Context) ([]entity.BureauSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubmissions", ctx)
+	ret0, _ := ret[0].([]entity.BureauSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubmissions indicates an expected call of ListSubmissions.
+func (mr *MockBureauRepositoryMockRecorder) ListSubmissions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubmissions", reflect.TypeOf((*MockBureauRepository)(nil).ListSubmissions), ctx)
+}
@@ -7,6 +7,7 @@ package bank
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
 	transaction "github.com/MaxFando/bank-system/pkg/sqlext/transaction"
@@ -37,6 +38,21 @@ func (m *MockCardRepository) EXPECT() *MockCardRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ExpireOutdated mocks base method.
+func (m *MockCardRepository) ExpireOutdated(ctx context.Context, now time.Time) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOutdated", ctx, now)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireOutdated indicates an expected call of ExpireOutdated.
+func (mr *MockCardRepositoryMockRecorder) ExpireOutdated(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOutdated", reflect.TypeOf((*MockCardRepository)(nil).ExpireOutdated), ctx, now)
+}
+
 // FindByAccountID mocks base method.
 func (m *MockCardRepository) FindByAccountID(ctx context.Context, accountID int32) ([]entity.Card, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +83,81 @@ func (mr *MockCardRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCardRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByPANIndex mocks base method.
+func (m *MockCardRepository) FindByPANIndex(ctx context.Context, panIndex string) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPANIndex", ctx, panIndex)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPANIndex indicates an expected call of FindByPANIndex.
+func (mr *MockCardRepositoryMockRecorder) FindByPANIndex(ctx, panIndex interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPANIndex", reflect.TypeOf((*MockCardRepository)(nil).FindByPANIndex), ctx, panIndex)
+}
+
+// FindExpiring mocks base method.
+func (m *MockCardRepository) FindExpiring(ctx context.Context, before time.Time) ([]entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiring", ctx, before)
+	ret0, _ := ret[0].([]entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiring indicates an expected call of FindExpiring.
+func (mr *MockCardRepositoryMockRecorder) FindExpiring(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiring", reflect.TypeOf((*MockCardRepository)(nil).FindExpiring), ctx, before)
+}
+
+// FindRecipientByPANIndex mocks base method.
+func (m *MockCardRepository) FindRecipientByPANIndex(ctx context.Context, panIndex string) (*entity.CardRecipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRecipientByPANIndex", ctx, panIndex)
+	ret0, _ := ret[0].(*entity.CardRecipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRecipientByPANIndex indicates an expected call of FindRecipientByPANIndex.
+func (mr *MockCardRepositoryMockRecorder) FindRecipientByPANIndex(ctx, panIndex interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRecipientByPANIndex", reflect.TypeOf((*MockCardRepository)(nil).FindRecipientByPANIndex), ctx, panIndex)
+}
+
+// FindWithoutPANIndex mocks base method.
+func (m *MockCardRepository) FindWithoutPANIndex(ctx context.Context, afterID int32, limit int) ([]entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWithoutPANIndex", ctx, afterID, limit)
+	ret0, _ := ret[0].([]entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWithoutPANIndex indicates an expected call of FindWithoutPANIndex.
+func (mr *MockCardRepositoryMockRecorder) FindWithoutPANIndex(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWithoutPANIndex", reflect.TypeOf((*MockCardRepository)(nil).FindWithoutPANIndex), ctx, afterID, limit)
+}
+
+// LockByID mocks base method.
+func (m *MockCardRepository) LockByID(ctx context.Context, id int32) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByID indicates an expected call of LockByID.
+func (mr *MockCardRepositoryMockRecorder) LockByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByID", reflect.TypeOf((*MockCardRepository)(nil).LockByID), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockCardRepository) Save(ctx context.Context, card *entity.Card) (*entity.Card, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +173,35 @@ func (mr *MockCardRepositoryMockRecorder) Save(ctx, card interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCardRepository)(nil).Save), ctx, card)
 }
 
+// SetPANIndex mocks base method.
+func (m *MockCardRepository) SetPANIndex(ctx context.Context, id int32, panIndex string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPANIndex", ctx, id, panIndex)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPANIndex indicates an expected call of SetPANIndex.
+func (mr *MockCardRepositoryMockRecorder) SetPANIndex(ctx, id, panIndex interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPANIndex", reflect.TypeOf((*MockCardRepository)(nil).SetPANIndex), ctx, id, panIndex)
+}
+
+// Update mocks base method.
+func (m *MockCardRepository) Update(ctx context.Context, card *entity.Card) (*entity.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, card)
+	ret0, _ := ret[0].(*entity.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCardRepositoryMockRecorder) Update(ctx, card interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCardRepository)(nil).Update), ctx, card)
+}
+
 // MockCardTransactionRepository is a mock of CardTransactionRepository interface.
 type MockCardTransactionRepository struct {
 	ctrl     *gomock.Controller
@@ -105,19 +225,19 @@ func (m *MockCardTransactionRepository) EXPECT() *MockCardTransactionRepositoryM
 	return m.recorder
 }
 
-// Deposit mocks base method.
-func (m *MockCardTransactionRepository) Deposit(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+// Create mocks base method.
+func (m *MockCardTransactionRepository) Create(ctx context.Context, cardTransaction *entity.CardTransaction) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deposit", ctx, cardID, amount)
+	ret := m.ctrl.Call(m, "Create", ctx, cardTransaction)
 	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Deposit indicates an expected call of Deposit.
-func (mr *MockCardTransactionRepositoryMockRecorder) Deposit(ctx, cardID, amount interface{}) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockCardTransactionRepositoryMockRecorder) Create(ctx, cardTransaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockCardTransactionRepository)(nil).Deposit), ctx, cardID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCardTransactionRepository)(nil).Create), ctx, cardTransaction)
 }
 
 // FindByID mocks base method.
@@ -135,19 +255,78 @@ func (mr *MockCardTransactionRepositoryMockRecorder) FindByID(ctx, id interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCardTransactionRepository)(nil).FindByID), ctx, id)
 }
 
-// Transfer mocks base method.
-func (m *MockCardTransactionRepository) Transfer(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+// FindByRRN mocks base method.
+func (m *MockCardTransactionRepository) FindByRRN(ctx context.Context, rrn string) (*entity.CardTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transfer", ctx, cardID, amount)
-	ret0, _ := ret[0].(int32)
+	ret := m.ctrl.Call(m, "FindByRRN", ctx, rrn)
+	ret0, _ := ret[0].(*entity.CardTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByRRN indicates an expected call of FindByRRN.
+func (mr *MockCardTransactionRepositoryMockRecorder) FindByRRN(ctx, rrn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByRRN", reflect.TypeOf((*MockCardTransactionRepository)(nil).FindByRRN), ctx, rrn)
+}
+
+// List mocks base method.
+func (m *MockCardTransactionRepository) List(ctx context.Context, filter entity.CardTransactionFilter, after *entity.CardTransactionCursor, limit int) ([]entity.CardTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]entity.CardTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCardTransactionRepositoryMockRecorder) List(ctx, filter, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCardTransactionRepository)(nil).List), ctx, filter, after, limit)
+}
+
+// MarkReversed mocks base method.
+func (m *MockCardTransactionRepository) MarkReversed(ctx context.Context, id int32, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReversed", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReversed indicates an expected call of MarkReversed.
+func (mr *MockCardTransactionRepositoryMockRecorder) MarkReversed(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReversed", reflect.TypeOf((*MockCardTransactionRepository)(nil).MarkReversed), ctx, id, at)
+}
+
+// SumByMCCSince mocks base method.
+func (m *MockCardTransactionRepository) SumByMCCSince(ctx context.Context, cardID int32, since time.Time) (map[entity.MCC]decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByMCCSince", ctx, cardID, since)
+	ret0, _ := ret[0].(map[entity.MCC]decimal.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Transfer indicates an expected call of Transfer.
-func (mr *MockCardTransactionRepositoryMockRecorder) Transfer(ctx, cardID, amount interface{}) *gomock.Call {
+// SumByMCCSince indicates an expected call of SumByMCCSince.
+func (mr *MockCardTransactionRepositoryMockRecorder) SumByMCCSince(ctx, cardID, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockCardTransactionRepository)(nil).Transfer), ctx, cardID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByMCCSince", reflect.TypeOf((*MockCardTransactionRepository)(nil).SumByMCCSince), ctx, cardID, since)
+}
+
+// Totals mocks base method.
+func (m *MockCardTransactionRepository) Totals(ctx context.Context, filter entity.CardTransactionFilter) (*entity.CardTransactionTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Totals", ctx, filter)
+	ret0, _ := ret[0].(*entity.CardTransactionTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Totals indicates an expected call of Totals.
+func (mr *MockCardTransactionRepositoryMockRecorder) Totals(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Totals", reflect.TypeOf((*MockCardTransactionRepository)(nil).Totals), ctx, filter)
 }
 
 // WithTx mocks base method.
@@ -169,17 +348,55 @@ func (mr *MockCardTransactionRepositoryMockRecorder) WithTx(ctx, fn interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCardTransactionRepository)(nil).WithTx), varargs...)
 }
 
-// Withdraw mocks base method.
-func (m *MockCardTransactionRepository) Withdraw(ctx context.Context, cardID int32, amount decimal.Decimal) (int32, error) {
+// MockCardRuleRepository is a mock of CardRuleRepository interface.
+type MockCardRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardRuleRepositoryMockRecorder
+}
+
+// MockCardRuleRepositoryMockRecorder is the mock recorder for MockCardRuleRepository.
+type MockCardRuleRepositoryMockRecorder struct {
+	mock *MockCardRuleRepository
+}
+
+// NewMockCardRuleRepository creates a new mock instance.
+func NewMockCardRuleRepository(ctrl *gomock.Controller) *MockCardRuleRepository {
+	mock := &MockCardRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockCardRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCardRuleRepository) EXPECT() *MockCardRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByCardID mocks base method.
+func (m *MockCardRuleRepository) GetByCardID(ctx context.Context, cardID int32) (*entity.CardRules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, cardID, amount)
-	ret0, _ := ret[0].(int32)
+	ret := m.ctrl.Call(m, "GetByCardID", ctx, cardID)
+	ret0, _ := ret[0].(*entity.CardRules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Withdraw indicates an expected call of Withdraw.
-func (mr *MockCardTransactionRepositoryMockRecorder) Withdraw(ctx, cardID, amount interface{}) *gomock.Call {
+// GetByCardID indicates an expected call of GetByCardID.
+func (mr *MockCardRuleRepositoryMockRecorder) GetByCardID(ctx, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCardID", reflect.TypeOf((*MockCardRuleRepository)(nil).GetByCardID), ctx, cardID)
+}
+
+// Save mocks base method.
+func (m *MockCardRuleRepository) Save(ctx context.Context, rules *entity.CardRules) (*entity.CardRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, rules)
+	ret0, _ := ret[0].(*entity.CardRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCardRuleRepositoryMockRecorder) Save(ctx, rules interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockCardTransactionRepository)(nil).Withdraw), ctx, cardID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCardRuleRepository)(nil).Save), ctx, rules)
 }
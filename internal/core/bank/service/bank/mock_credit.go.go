@@ -7,10 +7,12 @@ package bank
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
 	transaction "github.com/MaxFando/bank-system/pkg/sqlext/transaction"
 	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
 )
 
 // MockCreditRepository is a mock of CreditRepository interface.
@@ -36,6 +38,49 @@ func (m *MockCreditRepository) EXPECT() *MockCreditRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountUnpaidPayments mocks base method.
+func (m *MockCreditRepository) CountUnpaidPayments(ctx context.Context, creditID int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnpaidPayments", ctx, creditID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnpaidPayments indicates an expected call of CountUnpaidPayments.
+func (mr *MockCreditRepositoryMockRecorder) CountUnpaidPayments(ctx, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnpaidPayments", reflect.TypeOf((*MockCreditRepository)(nil).CountUnpaidPayments), ctx, creditID)
+}
+
+// CreateInterestPosting mocks base method.
+func (m *MockCreditRepository) CreateInterestPosting(ctx context.Context, posting *entity.InterestPosting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestPosting", ctx, posting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInterestPosting indicates an expected call of CreateInterestPosting.
+func (mr *MockCreditRepositoryMockRecorder) CreateInterestPosting(ctx, posting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestPosting", reflect.TypeOf((*MockCreditRepository)(nil).CreateInterestPosting), ctx, posting)
+}
+
+// CreatePaymentPosting mocks base method.
+func (m *MockCreditRepository) CreatePaymentPosting(ctx context.Context, posting *entity.PaymentPosting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentPosting", ctx, posting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePaymentPosting indicates an expected call of CreatePaymentPosting.
+func (mr *MockCreditRepositoryMockRecorder) CreatePaymentPosting(ctx, posting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentPosting", reflect.TypeOf((*MockCreditRepository)(nil).CreatePaymentPosting), ctx, posting)
+}
+
 // CreatePaymentSchedule mocks base method.
 func (m *MockCreditRepository) CreatePaymentSchedule(ctx context.Context, paymentSchedule *entity.PaymentSchedule) (*entity.PaymentSchedule, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +96,48 @@ func (mr *MockCreditRepositoryMockRecorder) CreatePaymentSchedule(ctx, paymentSc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentSchedule", reflect.TypeOf((*MockCreditRepository)(nil).CreatePaymentSchedule), ctx, paymentSchedule)
 }
 
+// CreatePrincipalPosting mocks base method.
+func (m *MockCreditRepository) CreatePrincipalPosting(ctx context.Context, posting *entity.PrincipalPosting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrincipalPosting", ctx, posting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePrincipalPosting indicates an expected call of CreatePrincipalPosting.
+func (mr *MockCreditRepositoryMockRecorder) CreatePrincipalPosting(ctx, posting interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrincipalPosting", reflect.TypeOf((*MockCreditRepository)(nil).CreatePrincipalPosting), ctx, posting)
+}
+
+// DeletePendingPayments mocks base method.
+func (m *MockCreditRepository) DeletePendingPayments(ctx context.Context, creditID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingPayments", ctx, creditID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingPayments indicates an expected call of DeletePendingPayments.
+func (mr *MockCreditRepositoryMockRecorder) DeletePendingPayments(ctx, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingPayments", reflect.TypeOf((*MockCreditRepository)(nil).DeletePendingPayments), ctx, creditID)
+}
+
+// DeletePendingPaymentsAfter mocks base method.
+func (m *MockCreditRepository) DeletePendingPaymentsAfter(ctx context.Context, creditID int32, after time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingPaymentsAfter", ctx, creditID, after)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingPaymentsAfter indicates an expected call of DeletePendingPaymentsAfter.
+func (mr *MockCreditRepositoryMockRecorder) DeletePendingPaymentsAfter(ctx, creditID, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingPaymentsAfter", reflect.TypeOf((*MockCreditRepository)(nil).DeletePendingPaymentsAfter), ctx, creditID, after)
+}
+
 // GetCreditByID mocks base method.
 func (m *MockCreditRepository) GetCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +168,141 @@ func (mr *MockCreditRepositoryMockRecorder) GetPaymentSchedule(ctx, creditID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentSchedule", reflect.TypeOf((*MockCreditRepository)(nil).GetPaymentSchedule), ctx, creditID)
 }
 
+// ListByUserID mocks base method.
+func (m *MockCreditRepository) ListByUserID(ctx context.Context, userID int32) ([]entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockCreditRepositoryMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockCreditRepository)(nil).ListByUserID), ctx, userID)
+}
+
+// LockAccrualDue mocks base method.
+func (m *MockCreditRepository) LockAccrualDue(ctx context.Context, today time.Time, afterID int32) (*entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAccrualDue", ctx, today, afterID)
+	ret0, _ := ret[0].(*entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockAccrualDue indicates an expected call of LockAccrualDue.
+func (mr *MockCreditRepositoryMockRecorder) LockAccrualDue(ctx, today, afterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccrualDue", reflect.TypeOf((*MockCreditRepository)(nil).LockAccrualDue), ctx, today, afterID)
+}
+
+// LockCreditByID mocks base method.
+func (m *MockCreditRepository) LockCreditByID(ctx context.Context, creditID int32) (*entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCreditByID", ctx, creditID)
+	ret0, _ := ret[0].(*entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockCreditByID indicates an expected call of LockCreditByID.
+func (mr *MockCreditRepositoryMockRecorder) LockCreditByID(ctx, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCreditByID", reflect.TypeOf((*MockCreditRepository)(nil).LockCreditByID), ctx, creditID)
+}
+
+// LockDueCredit mocks base method.
+func (m *MockCreditRepository) LockDueCredit(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDueCredit", ctx, dueBy, afterID)
+	ret0, _ := ret[0].(*entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDueCredit indicates an expected call of LockDueCredit.
+func (mr *MockCreditRepositoryMockRecorder) LockDueCredit(ctx, dueBy, afterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDueCredit", reflect.TypeOf((*MockCreditRepository)(nil).LockDueCredit), ctx, dueBy, afterID)
+}
+
+// LockDuePayments mocks base method.
+func (m *MockCreditRepository) LockDuePayments(ctx context.Context, creditID int32, dueBy time.Time) ([]entity.PaymentSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDuePayments", ctx, creditID, dueBy)
+	ret0, _ := ret[0].([]entity.PaymentSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDuePayments indicates an expected call of LockDuePayments.
+func (mr *MockCreditRepositoryMockRecorder) LockDuePayments(ctx, creditID, dueBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDuePayments", reflect.TypeOf((*MockCreditRepository)(nil).LockDuePayments), ctx, creditID, dueBy)
+}
+
+// LockOverdueCredit mocks base method.
+func (m *MockCreditRepository) LockOverdueCredit(ctx context.Context, accruedBy time.Time, afterID int32) (*entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOverdueCredit", ctx, accruedBy, afterID)
+	ret0, _ := ret[0].(*entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockOverdueCredit indicates an expected call of LockOverdueCredit.
+func (mr *MockCreditRepositoryMockRecorder) LockOverdueCredit(ctx, accruedBy, afterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOverdueCredit", reflect.TypeOf((*MockCreditRepository)(nil).LockOverdueCredit), ctx, accruedBy, afterID)
+}
+
+// LockOverduePayments mocks base method.
+func (m *MockCreditRepository) LockOverduePayments(ctx context.Context, creditID int32, accruedBy time.Time) ([]entity.PaymentSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOverduePayments", ctx, creditID, accruedBy)
+	ret0, _ := ret[0].([]entity.PaymentSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockOverduePayments indicates an expected call of LockOverduePayments.
+func (mr *MockCreditRepositoryMockRecorder) LockOverduePayments(ctx, creditID, accruedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOverduePayments", reflect.TypeOf((*MockCreditRepository)(nil).LockOverduePayments), ctx, creditID, accruedBy)
+}
+
+// LockPaymentByID mocks base method.
+func (m *MockCreditRepository) LockPaymentByID(ctx context.Context, creditID, paymentID int32) (*entity.PaymentSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPaymentByID", ctx, creditID, paymentID)
+	ret0, _ := ret[0].(*entity.PaymentSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPaymentByID indicates an expected call of LockPaymentByID.
+func (mr *MockCreditRepositoryMockRecorder) LockPaymentByID(ctx, creditID, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPaymentByID", reflect.TypeOf((*MockCreditRepository)(nil).LockPaymentByID), ctx, creditID, paymentID)
+}
+
+// LockRateResetDue mocks base method.
+func (m *MockCreditRepository) LockRateResetDue(ctx context.Context, dueBy time.Time, afterID int32) (*entity.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockRateResetDue", ctx, dueBy, afterID)
+	ret0, _ := ret[0].(*entity.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockRateResetDue indicates an expected call of LockRateResetDue.
+func (mr *MockCreditRepositoryMockRecorder) LockRateResetDue(ctx, dueBy, afterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockRateResetDue", reflect.TypeOf((*MockCreditRepository)(nil).LockRateResetDue), ctx, dueBy, afterID)
+}
+
 // Save mocks base method.
 func (m *MockCreditRepository) Save(ctx context.Context, credit *entity.Credit) (*entity.Credit, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +318,76 @@ func (mr *MockCreditRepositoryMockRecorder) Save(ctx, credit interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCreditRepository)(nil).Save), ctx, credit)
 }
 
+// UpdateCreditRate mocks base method.
+func (m *MockCreditRepository) UpdateCreditRate(ctx context.Context, creditID int32, rate decimal.Decimal, nextReset *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditRate", ctx, creditID, rate, nextReset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCreditRate indicates an expected call of UpdateCreditRate.
+func (mr *MockCreditRepositoryMockRecorder) UpdateCreditRate(ctx, creditID, rate, nextReset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditRate", reflect.TypeOf((*MockCreditRepository)(nil).UpdateCreditRate), ctx, creditID, rate, nextReset)
+}
+
+// UpdateCreditStatus mocks base method.
+func (m *MockCreditRepository) UpdateCreditStatus(ctx context.Context, creditID int32, status entity.CreditStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditStatus", ctx, creditID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCreditStatus indicates an expected call of UpdateCreditStatus.
+func (mr *MockCreditRepositoryMockRecorder) UpdateCreditStatus(ctx, creditID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditStatus", reflect.TypeOf((*MockCreditRepository)(nil).UpdateCreditStatus), ctx, creditID, status)
+}
+
+// UpdateCreditTerm mocks base method.
+func (m *MockCreditRepository) UpdateCreditTerm(ctx context.Context, creditID, termInMonths int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditTerm", ctx, creditID, termInMonths)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCreditTerm indicates an expected call of UpdateCreditTerm.
+func (mr *MockCreditRepositoryMockRecorder) UpdateCreditTerm(ctx, creditID, termInMonths interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditTerm", reflect.TypeOf((*MockCreditRepository)(nil).UpdateCreditTerm), ctx, creditID, termInMonths)
+}
+
+// UpdateInterestReceivable mocks base method.
+func (m *MockCreditRepository) UpdateInterestReceivable(ctx context.Context, creditID int32, receivable decimal.Decimal, accruedTo *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInterestReceivable", ctx, creditID, receivable, accruedTo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInterestReceivable indicates an expected call of UpdateInterestReceivable.
+func (mr *MockCreditRepositoryMockRecorder) UpdateInterestReceivable(ctx, creditID, receivable, accruedTo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInterestReceivable", reflect.TypeOf((*MockCreditRepository)(nil).UpdateInterestReceivable), ctx, creditID, receivable, accruedTo)
+}
+
+// UpdatePayment mocks base method.
+func (m *MockCreditRepository) UpdatePayment(ctx context.Context, payment *entity.PaymentSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayment", ctx, payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePayment indicates an expected call of UpdatePayment.
+func (mr *MockCreditRepositoryMockRecorder) UpdatePayment(ctx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockCreditRepository)(nil).UpdatePayment), ctx, payment)
+}
+
 // WithTx mocks base method.
 func (m *MockCreditRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
 	m.ctrl.T.Helper()
@@ -114,3 +406,41 @@ func (mr *MockCreditRepositoryMockRecorder) WithTx(ctx, fn interface{}, opts ...
 	varargs := append([]interface{}{ctx, fn}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCreditRepository)(nil).WithTx), varargs...)
 }
+
+// MockFinancialTransactionRepository is a mock of FinancialTransactionRepository interface.
+type MockFinancialTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinancialTransactionRepositoryMockRecorder
+}
+
+// MockFinancialTransactionRepositoryMockRecorder is the mock recorder for MockFinancialTransactionRepository.
+type MockFinancialTransactionRepositoryMockRecorder struct {
+	mock *MockFinancialTransactionRepository
+}
+
+// NewMockFinancialTransactionRepository creates a new mock instance.
+func NewMockFinancialTransactionRepository(ctrl *gomock.Controller) *MockFinancialTransactionRepository {
+	mock := &MockFinancialTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockFinancialTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFinancialTransactionRepository) EXPECT() *MockFinancialTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFinancialTransactionRepository) Create(ctx context.Context, transaction *entity.FinancialTransaction) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transaction)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFinancialTransactionRepositoryMockRecorder) Create(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFinancialTransactionRepository)(nil).Create), ctx, transaction)
+}
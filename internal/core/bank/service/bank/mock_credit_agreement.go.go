@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: credit_agreement.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAgreementRepository is a mock of AgreementRepository interface.
+type MockAgreementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgreementRepositoryMockRecorder
+}
+
+// MockAgreementRepositoryMockRecorder is the mock recorder for MockAgreementRepository.
+type MockAgreementRepositoryMockRecorder struct {
+	mock *MockAgreementRepository
+}
+
+// NewMockAgreementRepository creates a new mock instance.
+func NewMockAgreementRepository(ctrl *gomock.Controller) *MockAgreementRepository {
+	mock := &MockAgreementRepository{ctrl: ctrl}
+	mock.recorder = &MockAgreementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgreementRepository) EXPECT() *MockAgreementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAgreementRepository) Create(ctx context.Context, agreement *entity.CreditAgreement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, agreement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAgreementRepositoryMockRecorder) Create(ctx, agreement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAgreementRepository)(nil).Create), ctx, agreement)
+}
+
+// Get mocks base method.
+func (m *MockAgreementRepository) Get(ctx context.Context, creditID int32, format entity.DocumentFormat) (*entity.CreditAgreement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, creditID, format)
+	ret0, _ := ret[0].(*entity.CreditAgreement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAgreementRepositoryMockRecorder) Get(ctx, creditID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAgreementRepository)(nil).Get), ctx, creditID, format)
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: credit_application.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	transaction "github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCreditApplicationRepository is a mock of CreditApplicationRepository interface.
+type MockCreditApplicationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditApplicationRepositoryMockRecorder
+}
+
+// MockCreditApplicationRepositoryMockRecorder is the mock recorder for MockCreditApplicationRepository.
+type MockCreditApplicationRepositoryMockRecorder struct {
+	mock *MockCreditApplicationRepository
+}
+
+// NewMockCreditApplicationRepository creates a new mock instance.
+func NewMockCreditApplicationRepository(ctrl *gomock.Controller) *MockCreditApplicationRepository {
+	mock := &MockCreditApplicationRepository{ctrl: ctrl}
+	mock.recorder = &MockCreditApplicationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreditApplicationRepository) EXPECT() *MockCreditApplicationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCreditApplicationRepository) Create(ctx context.Context, application *entity.CreditApplication) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, application)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCreditApplicationRepositoryMockRecorder) Create(ctx, application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreditApplicationRepository)(nil).Create), ctx, application)
+}
+
+// CreateDecision mocks base method.
+func (m *MockCreditApplicationRepository) CreateDecision(ctx context.Context, decision *entity.CreditDecision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDecision", ctx, decision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDecision indicates an expected call of CreateDecision.
+func (mr *MockCreditApplicationRepositoryMockRecorder) CreateDecision(ctx, decision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDecision", reflect.TypeOf((*MockCreditApplicationRepository)(nil).CreateDecision), ctx, decision)
+}
+
+// FindByID mocks base method.
+func (m *MockCreditApplicationRepository) FindByID(ctx context.Context, id int32) (*entity.CreditApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.CreditApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCreditApplicationRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCreditApplicationRepository)(nil).FindByID), ctx, id)
+}
+
+// FindDecisions mocks base method.
+func (m *MockCreditApplicationRepository) FindDecisions(ctx context.Context, applicationID int32) ([]entity.CreditDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDecisions", ctx, applicationID)
+	ret0, _ := ret[0].([]entity.CreditDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDecisions indicates an expected call of FindDecisions.
+func (mr *MockCreditApplicationRepositoryMockRecorder) FindDecisions(ctx, applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDecisions", reflect.TypeOf((*MockCreditApplicationRepository)(nil).FindDecisions), ctx, applicationID)
+}
+
+// ListByStatus mocks base method.
+func (m *MockCreditApplicationRepository) ListByStatus(ctx context.Context, status entity.CreditApplicationStatus) ([]entity.CreditApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status)
+	ret0, _ := ret[0].([]entity.CreditApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockCreditApplicationRepositoryMockRecorder) ListByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockCreditApplicationRepository)(nil).ListByStatus), ctx, status)
+}
+
+// LockByID mocks base method.
+func (m *MockCreditApplicationRepository) LockByID(ctx context.Context, id int32) (*entity.CreditApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByID", ctx, id)
+	ret0, _ := ret[0].(*entity.CreditApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByID indicates an expected call of LockByID.
+func (mr *MockCreditApplicationRepositoryMockRecorder) LockByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByID", reflect.TypeOf((*MockCreditApplicationRepository)(nil).LockByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCreditApplicationRepository) Update(ctx context.Context, application *entity.CreditApplication) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, application)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCreditApplicationRepositoryMockRecorder) Update(ctx, application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCreditApplicationRepository)(nil).Update), ctx, application)
+}
+
+// WithTx mocks base method.
+func (m *MockCreditApplicationRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockCreditApplicationRepositoryMockRecorder) WithTx(ctx, fn interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCreditApplicationRepository)(nil).WithTx), varargs...)
+}
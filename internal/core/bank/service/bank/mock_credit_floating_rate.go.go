@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: credit_floating_rate.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFloatingRateRepository is a mock of FloatingRateRepository interface.
+type MockFloatingRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFloatingRateRepositoryMockRecorder
+}
+
+// MockFloatingRateRepositoryMockRecorder is the mock recorder for MockFloatingRateRepository.
+type MockFloatingRateRepositoryMockRecorder struct {
+	mock *MockFloatingRateRepository
+}
+
+// NewMockFloatingRateRepository creates a new mock instance.
+func NewMockFloatingRateRepository(ctrl *gomock.Controller) *MockFloatingRateRepository {
+	mock := &MockFloatingRateRepository{ctrl: ctrl}
+	mock.recorder = &MockFloatingRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFloatingRateRepository) EXPECT() *MockFloatingRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRateReset mocks base method.
+func (m *MockFloatingRateRepository) CreateRateReset(ctx context.Context, reset *entity.RateReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRateReset", ctx, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRateReset indicates an expected call of CreateRateReset.
+func (mr *MockFloatingRateRepositoryMockRecorder) CreateRateReset(ctx, reset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRateReset", reflect.TypeOf((*MockFloatingRateRepository)(nil).CreateRateReset), ctx, reset)
+}
+
+// FindKeyRate mocks base method.
+func (m *MockFloatingRateRepository) FindKeyRate(ctx context.Context, at time.Time) (*entity.CentralBankRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindKeyRate", ctx, at)
+	ret0, _ := ret[0].(*entity.CentralBankRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKeyRate indicates an expected call of FindKeyRate.
+func (mr *MockFloatingRateRepositoryMockRecorder) FindKeyRate(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKeyRate", reflect.TypeOf((*MockFloatingRateRepository)(nil).FindKeyRate), ctx, at)
+}
+
+// ListRateResets mocks base method.
+func (m *MockFloatingRateRepository) ListRateResets(ctx context.Context, creditID int32) ([]entity.RateReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRateResets", ctx, creditID)
+	ret0, _ := ret[0].([]entity.RateReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRateResets indicates an expected call of ListRateResets.
+func (mr *MockFloatingRateRepositoryMockRecorder) ListRateResets(ctx, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRateResets", reflect.TypeOf((*MockFloatingRateRepository)(nil).ListRateResets), ctx, creditID)
+}
@@ -0,0 +1,234 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: credit_line.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	transaction "github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockCreditLineRepository is a mock of CreditLineRepository interface.
+type MockCreditLineRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditLineRepositoryMockRecorder
+}
+
+// MockCreditLineRepositoryMockRecorder is the mock recorder for MockCreditLineRepository.
+type MockCreditLineRepositoryMockRecorder struct {
+	mock *MockCreditLineRepository
+}
+
+// NewMockCreditLineRepository creates a new mock instance.
+func NewMockCreditLineRepository(ctrl *gomock.Controller) *MockCreditLineRepository {
+	mock := &MockCreditLineRepository{ctrl: ctrl}
+	mock.recorder = &MockCreditLineRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreditLineRepository) EXPECT() *MockCreditLineRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BalanceAt mocks base method.
+func (m *MockCreditLineRepository) BalanceAt(ctx context.Context, accountID int32, at time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, accountID, at)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockCreditLineRepositoryMockRecorder) BalanceAt(ctx, accountID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockCreditLineRepository)(nil).BalanceAt), ctx, accountID, at)
+}
+
+// Create mocks base method.
+func (m *MockCreditLineRepository) Create(ctx context.Context, line *entity.CreditLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCreditLineRepositoryMockRecorder) Create(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreditLineRepository)(nil).Create), ctx, line)
+}
+
+// CreateStatement mocks base method.
+func (m *MockCreditLineRepository) CreateStatement(ctx context.Context, statement *entity.Statement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatement", ctx, statement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStatement indicates an expected call of CreateStatement.
+func (mr *MockCreditLineRepositoryMockRecorder) CreateStatement(ctx, statement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatement", reflect.TypeOf((*MockCreditLineRepository)(nil).CreateStatement), ctx, statement)
+}
+
+// GetByAccountID mocks base method.
+func (m *MockCreditLineRepository) GetByAccountID(ctx context.Context, accountID int32) (*entity.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAccountID", ctx, accountID)
+	ret0, _ := ret[0].(*entity.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAccountID indicates an expected call of GetByAccountID.
+func (mr *MockCreditLineRepositoryMockRecorder) GetByAccountID(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountID", reflect.TypeOf((*MockCreditLineRepository)(nil).GetByAccountID), ctx, accountID)
+}
+
+// LastStatement mocks base method.
+func (m *MockCreditLineRepository) LastStatement(ctx context.Context, accountID int32) (*entity.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastStatement", ctx, accountID)
+	ret0, _ := ret[0].(*entity.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastStatement indicates an expected call of LastStatement.
+func (mr *MockCreditLineRepositoryMockRecorder) LastStatement(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastStatement", reflect.TypeOf((*MockCreditLineRepository)(nil).LastStatement), ctx, accountID)
+}
+
+// ListDueStatements mocks base method.
+func (m *MockCreditLineRepository) ListDueStatements(ctx context.Context, accountID int32, today time.Time) ([]entity.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueStatements", ctx, accountID, today)
+	ret0, _ := ret[0].([]entity.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueStatements indicates an expected call of ListDueStatements.
+func (mr *MockCreditLineRepositoryMockRecorder) ListDueStatements(ctx, accountID, today interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueStatements", reflect.TypeOf((*MockCreditLineRepository)(nil).ListDueStatements), ctx, accountID, today)
+}
+
+// ListStatements mocks base method.
+func (m *MockCreditLineRepository) ListStatements(ctx context.Context, accountID int32) ([]entity.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatements", ctx, accountID)
+	ret0, _ := ret[0].([]entity.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatements indicates an expected call of ListStatements.
+func (mr *MockCreditLineRepositoryMockRecorder) ListStatements(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatements", reflect.TypeOf((*MockCreditLineRepository)(nil).ListStatements), ctx, accountID)
+}
+
+// LockUnprocessed mocks base method.
+func (m *MockCreditLineRepository) LockUnprocessed(ctx context.Context, today time.Time, afterID int32) (*entity.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUnprocessed", ctx, today, afterID)
+	ret0, _ := ret[0].(*entity.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockUnprocessed indicates an expected call of LockUnprocessed.
+func (mr *MockCreditLineRepositoryMockRecorder) LockUnprocessed(ctx, today, afterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUnprocessed", reflect.TypeOf((*MockCreditLineRepository)(nil).LockUnprocessed), ctx, today, afterID)
+}
+
+// Repayments mocks base method.
+func (m *MockCreditLineRepository) Repayments(ctx context.Context, accountID int32, from, to time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repayments", ctx, accountID, from, to)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Repayments indicates an expected call of Repayments.
+func (mr *MockCreditLineRepositoryMockRecorder) Repayments(ctx, accountID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repayments", reflect.TypeOf((*MockCreditLineRepository)(nil).Repayments), ctx, accountID, from, to)
+}
+
+// Turnover mocks base method.
+func (m *MockCreditLineRepository) Turnover(ctx context.Context, accountID int32, from, to time.Time) (*entity.CardTransactionTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Turnover", ctx, accountID, from, to)
+	ret0, _ := ret[0].(*entity.CardTransactionTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Turnover indicates an expected call of Turnover.
+func (mr *MockCreditLineRepositoryMockRecorder) Turnover(ctx, accountID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Turnover", reflect.TypeOf((*MockCreditLineRepository)(nil).Turnover), ctx, accountID, from, to)
+}
+
+// Update mocks base method.
+func (m *MockCreditLineRepository) Update(ctx context.Context, line *entity.CreditLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCreditLineRepositoryMockRecorder) Update(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCreditLineRepository)(nil).Update), ctx, line)
+}
+
+// UpdateStatement mocks base method.
+func (m *MockCreditLineRepository) UpdateStatement(ctx context.Context, statement *entity.Statement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatement", ctx, statement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatement indicates an expected call of UpdateStatement.
+func (mr *MockCreditLineRepositoryMockRecorder) UpdateStatement(ctx, statement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatement", reflect.TypeOf((*MockCreditLineRepository)(nil).UpdateStatement), ctx, statement)
+}
+
+// WithTx mocks base method.
+func (m *MockCreditLineRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockCreditLineRepositoryMockRecorder) WithTx(ctx, fn interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockCreditLineRepository)(nil).WithTx), varargs...)
+}
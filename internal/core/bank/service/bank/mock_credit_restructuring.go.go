@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: credit_restructuring.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	transaction "github.com/MaxFando/bank-system/pkg/sqlext/transaction"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRestructuringRepository is a mock of RestructuringRepository interface.
+type MockRestructuringRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestructuringRepositoryMockRecorder
+}
+
+// MockRestructuringRepositoryMockRecorder is the mock recorder for MockRestructuringRepository.
+type MockRestructuringRepositoryMockRecorder struct {
+	mock *MockRestructuringRepository
+}
+
+// NewMockRestructuringRepository creates a new mock instance.
+func NewMockRestructuringRepository(ctrl *gomock.Controller) *MockRestructuringRepository {
+	mock := &MockRestructuringRepository{ctrl: ctrl}
+	mock.recorder = &MockRestructuringRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestructuringRepository) EXPECT() *MockRestructuringRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRestructuringRepository) Create(ctx context.Context, restructuring *entity.Restructuring) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, restructuring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRestructuringRepositoryMockRecorder) Create(ctx, restructuring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRestructuringRepository)(nil).Create), ctx, restructuring)
+}
+
+// CreateScheduleVersion mocks base method.
+func (m *MockRestructuringRepository) CreateScheduleVersion(ctx context.Context, version *entity.ScheduleVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduleVersion", ctx, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScheduleVersion indicates an expected call of CreateScheduleVersion.
+func (mr *MockRestructuringRepositoryMockRecorder) CreateScheduleVersion(ctx, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduleVersion", reflect.TypeOf((*MockRestructuringRepository)(nil).CreateScheduleVersion), ctx, version)
+}
+
+// FindScheduleVersions mocks base method.
+func (m *MockRestructuringRepository) FindScheduleVersions(ctx context.Context, creditID int32) ([]entity.ScheduleVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindScheduleVersions", ctx, creditID)
+	ret0, _ := ret[0].([]entity.ScheduleVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindScheduleVersions indicates an expected call of FindScheduleVersions.
+func (mr *MockRestructuringRepositoryMockRecorder) FindScheduleVersions(ctx, creditID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindScheduleVersions", reflect.TypeOf((*MockRestructuringRepository)(nil).FindScheduleVersions), ctx, creditID)
+}
+
+// ListByStatus mocks base method.
+func (m *MockRestructuringRepository) ListByStatus(ctx context.Context, status entity.RestructuringStatus) ([]entity.Restructuring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status)
+	ret0, _ := ret[0].([]entity.Restructuring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockRestructuringRepositoryMockRecorder) ListByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockRestructuringRepository)(nil).ListByStatus), ctx, status)
+}
+
+// LockByID mocks base method.
+func (m *MockRestructuringRepository) LockByID(ctx context.Context, id int32) (*entity.Restructuring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Restructuring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByID indicates an expected call of LockByID.
+func (mr *MockRestructuringRepositoryMockRecorder) LockByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByID", reflect.TypeOf((*MockRestructuringRepository)(nil).LockByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRestructuringRepository) Update(ctx context.Context, restructuring *entity.Restructuring) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, restructuring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRestructuringRepositoryMockRecorder) Update(ctx, restructuring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRestructuringRepository)(nil).Update), ctx, restructuring)
+}
+
+// WithTx mocks base method.
+func (m *MockRestructuringRepository) WithTx(ctx context.Context, fn transaction.AtomicFn, opts ...transaction.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRestructuringRepositoryMockRecorder) WithTx(ctx, fn interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRestructuringRepository)(nil).WithTx), varargs...)
+}
@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: key_rate.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	cb "github.com/MaxFando/bank-system/pkg/cb"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRateProvider is a mock of RateProvider interface.
+type MockRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateProviderMockRecorder
+}
+
+// MockRateProviderMockRecorder is the mock recorder for MockRateProvider.
+type MockRateProviderMockRecorder struct {
+	mock *MockRateProvider
+}
+
+// NewMockRateProvider creates a new mock instance.
+func NewMockRateProvider(ctrl *gomock.Controller) *MockRateProvider {
+	mock := &MockRateProvider{ctrl: ctrl}
+	mock.recorder = &MockRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateProvider) EXPECT() *MockRateProviderMockRecorder {
+	return m.recorder
+}
+
+// KeyRate mocks base method.
+func (m *MockRateProvider) KeyRate(ctx context.Context, now time.Time) (*entity.CentralBankRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyRate", ctx, now)
+	ret0, _ := ret[0].(*entity.CentralBankRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyRate indicates an expected call of KeyRate.
+func (mr *MockRateProviderMockRecorder) KeyRate(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyRate", reflect.TypeOf((*MockRateProvider)(nil).KeyRate), ctx, now)
+}
+
+// MockKeyRateSource is a mock of KeyRateSource interface.
+type MockKeyRateSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRateSourceMockRecorder
+}
+
+// MockKeyRateSourceMockRecorder is the mock recorder for MockKeyRateSource.
+type MockKeyRateSourceMockRecorder struct {
+	mock *MockKeyRateSource
+}
+
+// NewMockKeyRateSource creates a new mock instance.
+func NewMockKeyRateSource(ctrl *gomock.Controller) *MockKeyRateSource {
+	mock := &MockKeyRateSource{ctrl: ctrl}
+	mock.recorder = &MockKeyRateSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRateSource) EXPECT() *MockKeyRateSourceMockRecorder {
+	return m.recorder
+}
+
+// KeyRate mocks base method.
+func (m *MockKeyRateSource) KeyRate(ctx context.Context) (cb.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyRate", ctx)
+	ret0, _ := ret[0].(cb.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyRate indicates an expected call of KeyRate.
+func (mr *MockKeyRateSourceMockRecorder) KeyRate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyRate", reflect.TypeOf((*MockKeyRateSource)(nil).KeyRate), ctx)
+}
+
+// KeyRates mocks base method.
+func (m *MockKeyRateSource) KeyRates(ctx context.Context, from, to time.Time) ([]cb.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyRates", ctx, from, to)
+	ret0, _ := ret[0].([]cb.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyRates indicates an expected call of KeyRates.
+func (mr *MockKeyRateSourceMockRecorder) KeyRates(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyRates", reflect.TypeOf((*MockKeyRateSource)(nil).KeyRates), ctx, from, to)
+}
+
+// MockKeyRateRepository is a mock of KeyRateRepository interface.
+type MockKeyRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRateRepositoryMockRecorder
+}
+
+// MockKeyRateRepositoryMockRecorder is the mock recorder for MockKeyRateRepository.
+type MockKeyRateRepositoryMockRecorder struct {
+	mock *MockKeyRateRepository
+}
+
+// NewMockKeyRateRepository creates a new mock instance.
+func NewMockKeyRateRepository(ctrl *gomock.Controller) *MockKeyRateRepository {
+	mock := &MockKeyRateRepository{ctrl: ctrl}
+	mock.recorder = &MockKeyRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRateRepository) EXPECT() *MockKeyRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindKeyRate mocks base method.
+func (m *MockKeyRateRepository) FindKeyRate(ctx context.Context, at time.Time) (*entity.CentralBankRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindKeyRate", ctx, at)
+	ret0, _ := ret[0].(*entity.CentralBankRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKeyRate indicates an expected call of FindKeyRate.
+func (mr *MockKeyRateRepositoryMockRecorder) FindKeyRate(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKeyRate", reflect.TypeOf((*MockKeyRateRepository)(nil).FindKeyRate), ctx, at)
+}
+
+// ListKeyRates mocks base method.
+func (m *MockKeyRateRepository) ListKeyRates(ctx context.Context, from, to time.Time) ([]entity.CentralBankRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeyRates", ctx, from, to)
+	ret0, _ := ret[0].([]entity.CentralBankRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeyRates indicates an expected call of ListKeyRates.
+func (mr *MockKeyRateRepositoryMockRecorder) ListKeyRates(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeyRates", reflect.TypeOf((*MockKeyRateRepository)(nil).ListKeyRates), ctx, from, to)
+}
+
+// SaveKeyRate mocks base method.
+func (m *MockKeyRateRepository) SaveKeyRate(ctx context.Context, rate *entity.CentralBankRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveKeyRate", ctx, rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveKeyRate indicates an expected call of SaveKeyRate.
+func (mr *MockKeyRateRepositoryMockRecorder) SaveKeyRate(ctx, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveKeyRate", reflect.TypeOf((*MockKeyRateRepository)(nil).SaveKeyRate), ctx, rate)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: report.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
+}
+
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
+}
+
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreditExposures mocks base method.
+func (m *MockReportRepository) CreditExposures(ctx context.Context, asOf time.Time) ([]entity.CreditExposure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditExposures", ctx, asOf)
+	ret0, _ := ret[0].([]entity.CreditExposure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditExposures indicates an expected call of CreditExposures.
+func (mr *MockReportRepositoryMockRecorder) CreditExposures(ctx, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditExposures", reflect.TypeOf((*MockReportRepository)(nil).CreditExposures), ctx, asOf)
+}
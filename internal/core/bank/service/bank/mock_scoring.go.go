@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: scoring.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockScorer is a mock of Scorer interface.
+type MockScorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockScorerMockRecorder
+}
+
+// MockScorerMockRecorder is the mock recorder for MockScorer.
+type MockScorerMockRecorder struct {
+	mock *MockScorer
+}
+
+// NewMockScorer creates a new mock instance.
+func NewMockScorer(ctrl *gomock.Controller) *MockScorer {
+	mock := &MockScorer{ctrl: ctrl}
+	mock.recorder = &MockScorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScorer) EXPECT() *MockScorerMockRecorder {
+	return m.recorder
+}
+
+// Score mocks base method.
+func (m *MockScorer) Score(ctx context.Context, application *entity.CreditApplication, now time.Time) (*entity.ScoringResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Score", ctx, application, now)
+	ret0, _ := ret[0].(*entity.ScoringResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Score indicates an expected call of Score.
+func (mr *MockScorerMockRecorder) Score(ctx, application, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Score", reflect.TypeOf((*MockScorer)(nil).Score), ctx, application, now)
+}
+
+// MockScoringRepository is a mock of ScoringRepository interface.
+type MockScoringRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScoringRepositoryMockRecorder
+}
+
+// MockScoringRepositoryMockRecorder is the mock recorder for MockScoringRepository.
+type MockScoringRepositoryMockRecorder struct {
+	mock *MockScoringRepository
+}
+
+// NewMockScoringRepository creates a new mock instance.
+func NewMockScoringRepository(ctrl *gomock.Controller) *MockScoringRepository {
+	mock := &MockScoringRepository{ctrl: ctrl}
+	mock.recorder = &MockScoringRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScoringRepository) EXPECT() *MockScoringRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AccountTurnover mocks base method.
+func (m *MockScoringRepository) AccountTurnover(ctx context.Context, accountID int32, since time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountTurnover", ctx, accountID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountTurnover indicates an expected call of AccountTurnover.
+func (mr *MockScoringRepositoryMockRecorder) AccountTurnover(ctx, accountID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountTurnover", reflect.TypeOf((*MockScoringRepository)(nil).AccountTurnover), ctx, accountID, since)
+}
+
+// CreditHistory mocks base method.
+func (m *MockScoringRepository) CreditHistory(ctx context.Context, userID int32) (*entity.CreditHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditHistory", ctx, userID)
+	ret0, _ := ret[0].(*entity.CreditHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditHistory indicates an expected call of CreditHistory.
+func (mr *MockScoringRepositoryMockRecorder) CreditHistory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditHistory", reflect.TypeOf((*MockScoringRepository)(nil).CreditHistory), ctx, userID)
+}
+
+// FindUserByID mocks base method.
+func (m *MockScoringRepository) FindUserByID(ctx context.Context, id int32) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByID indicates an expected call of FindUserByID.
+func (mr *MockScoringRepositoryMockRecorder) FindUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByID", reflect.TypeOf((*MockScoringRepository)(nil).FindUserByID), ctx, id)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token_vault.go
+
+// Package bank is a generated GoMock package.
+package bank
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MaxFando/bank-system/internal/core/bank/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCardTokenRepository is a mock of CardTokenRepository interface.
+type MockCardTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardTokenRepositoryMockRecorder
+}
+
+// MockCardTokenRepositoryMockRecorder is the mock recorder for MockCardTokenRepository.
+type MockCardTokenRepositoryMockRecorder struct {
+	mock *MockCardTokenRepository
+}
+
+// NewMockCardTokenRepository creates a new mock instance.
+func NewMockCardTokenRepository(ctrl *gomock.Controller) *MockCardTokenRepository {
+	mock := &MockCardTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockCardTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCardTokenRepository) EXPECT() *MockCardTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByCardID mocks base method.
+func (m *MockCardTokenRepository) FindByCardID(ctx context.Context, cardID int32) (entity.CardToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCardID", ctx, cardID)
+	ret0, _ := ret[0].(entity.CardToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCardID indicates an expected call of FindByCardID.
+func (mr *MockCardTokenRepositoryMockRecorder) FindByCardID(ctx, cardID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCardID", reflect.TypeOf((*MockCardTokenRepository)(nil).FindByCardID), ctx, cardID)
+}
+
+// FindCardID mocks base method.
+func (m *MockCardTokenRepository) FindCardID(ctx context.Context, token entity.CardToken) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCardID", ctx, token)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCardID indicates an expected call of FindCardID.
+func (mr *MockCardTokenRepositoryMockRecorder) FindCardID(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCardID", reflect.TypeOf((*MockCardTokenRepository)(nil).FindCardID), ctx, token)
+}
+
+// Save mocks base method.
+func (m *MockCardTokenRepository) Save(ctx context.Context, cardID int32, token entity.CardToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, cardID, token)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCardTokenRepositoryMockRecorder) Save(ctx, cardID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCardTokenRepository)(nil).Save), ctx, cardID, token)
+}
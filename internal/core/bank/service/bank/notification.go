@@ -0,0 +1,81 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"log/slog"
+)
+
+// NotificationRepository сохраняет уведомления пользователей и предоставляет адреса для их доставки.
+type NotificationRepository interface {
+	Save(ctx context.Context, notification *entity.Notification) (*entity.Notification, error)
+	UpdateStatus(ctx context.Context, id int32, status entity.NotificationStatus) error
+	GetUserEmail(ctx context.Context, userID int32) (string, error)
+}
+
+// EmailSender отправляет письма пользователям.
+type EmailSender interface {
+	Send(to string, subject string, body string) error
+}
+
+// NotificationService сохраняет уведомления пользователей и доставляет их по электронной почте.
+type NotificationService struct {
+	repo   NotificationRepository
+	sender EmailSender
+	logger *slog.Logger
+}
+
+// NewNotificationService создает новый экземпляр NotificationService.
+func NewNotificationService(logger *slog.Logger, repo NotificationRepository, sender EmailSender) *NotificationService {
+	return &NotificationService{
+		repo:   repo,
+		sender: sender,
+		logger: logger,
+	}
+}
+
+// Notify сохраняет уведомление для пользователя и отправляет его на электронную почту.
+// Уведомление остается в хранилище со статусом failed, если письмо отправить не удалось.
+func (s *NotificationService) Notify(ctx context.Context, userID int32, subject, body string) error {
+	notification, err := s.repo.Save(ctx, &entity.Notification{
+		UserID:  userID,
+		Type:    entity.EmailNotification,
+		Subject: subject,
+		Body:    body,
+		Status:  entity.NotificationPending,
+	})
+	if err != nil {
+		s.logger.Error("failed to save notification", "error", err)
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	status := entity.NotificationSent
+	sendErr := s.send(ctx, userID, subject, body)
+	if sendErr != nil {
+		s.logger.Error("failed to send notification", "notification_id", notification.ID, "error", sendErr)
+		status = entity.NotificationFailed
+	}
+
+	if err := s.repo.UpdateStatus(ctx, notification.ID, status); err != nil {
+		s.logger.Error("failed to update notification status", "error", err)
+		return fmt.Errorf("failed to update notification status: %w", err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to send notification: %w", sendErr)
+	}
+
+	s.logger.Info("notification sent successfully", "notification_id", notification.ID, "user_id", userID)
+	return nil
+}
+
+func (s *NotificationService) send(ctx context.Context, userID int32, subject, body string) error {
+	email, err := s.repo.GetUserEmail(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	return s.sender.Send(email, subject, body)
+}
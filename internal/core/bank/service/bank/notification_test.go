@@ -0,0 +1,67 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	testCases := []struct {
+		name     string
+		mockFunc func(repo *MockNotificationRepository, sender *MockEmailSender)
+		wantErr  bool
+	}{
+		{
+			name: "notification sent",
+			mockFunc: func(repo *MockNotificationRepository, sender *MockEmailSender) {
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(&entity.Notification{ID: 1}, nil)
+				repo.EXPECT().GetUserEmail(gomock.Any(), int32(1)).Return("user@example.com", nil)
+				sender.EXPECT().Send("user@example.com", "subject", "body").Return(nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int32(1), entity.NotificationSent).Return(nil)
+			},
+		},
+		{
+			name: "email delivery failed",
+			mockFunc: func(repo *MockNotificationRepository, sender *MockEmailSender) {
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(&entity.Notification{ID: 2}, nil)
+				repo.EXPECT().GetUserEmail(gomock.Any(), int32(1)).Return("user@example.com", nil)
+				sender.EXPECT().Send("user@example.com", "subject", "body").Return(assert.AnError)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int32(2), entity.NotificationFailed).Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "notification not saved",
+			mockFunc: func(repo *MockNotificationRepository, sender *MockEmailSender) {
+				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewMockNotificationRepository(ctrl)
+			sender := NewMockEmailSender(ctrl)
+			tc.mockFunc(repo, sender)
+
+			service := NewNotificationService(logger, repo, sender)
+			err := service.Notify(context.TODO(), 1, "subject", "body")
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/report"
+	"log/slog"
+	"time"
+)
+
+// ReportRepository восстанавливает задолженность по кредитам на отчетную дату.
+type ReportRepository interface {
+	CreditExposures(ctx context.Context, asOf time.Time) ([]entity.CreditExposure, error)
+}
+
+// ReportService строит отчеты по кредитному портфелю для риск-менеджмента.
+type ReportService struct {
+	repo   ReportRepository
+	logger *slog.Logger
+}
+
+// NewReportService создает новый экземпляр ReportService.
+func NewReportService(logger *slog.Logger, repo ReportRepository) *ReportService {
+	return &ReportService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Portfolio возвращает состояние кредитного портфеля на конец дня asOf с распределением по корзинам просрочки.
+func (s *ReportService) Portfolio(ctx context.Context, asOf time.Time) (*report.Portfolio, error) {
+	asOf = reportDate(asOf)
+
+	exposures, err := s.repo.CreditExposures(ctx, asOf)
+	if err != nil {
+		s.logger.Error("failed to get credit exposures", "error", err)
+		return nil, fmt.Errorf("failed to get credit exposures: %w", err)
+	}
+
+	return report.NewPortfolio(asOf, exposures), nil
+}
+
+// RollRates возвращает миграцию кредитов между корзинами просрочки с конца дня from по конец дня to.
+func (s *ReportService) RollRates(ctx context.Context, from, to time.Time) (*report.RollRates, error) {
+	from, to = reportDate(from), reportDate(to)
+	if !from.Before(to) {
+		return nil, entity.ErrInvalidReportPeriod
+	}
+
+	start, err := s.repo.CreditExposures(ctx, from)
+	if err != nil {
+		s.logger.Error("failed to get credit exposures", "error", err)
+		return nil, fmt.Errorf("failed to get credit exposures: %w", err)
+	}
+
+	end, err := s.repo.CreditExposures(ctx, to)
+	if err != nil {
+		s.logger.Error("failed to get credit exposures", "error", err)
+		return nil, fmt.Errorf("failed to get credit exposures: %w", err)
+	}
+
+	return report.NewRollRates(from, to, start, end), nil
+}
+
+// reportDate отбрасывает время, оставляя отчетную дату.
+func reportDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,45 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestReportService_RollRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	from := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("period must move forward", func(t *testing.T) {
+		service := NewReportService(logger, NewMockReportRepository(ctrl))
+
+		_, err := service.RollRates(context.TODO(), to, from)
+		assert.ErrorIs(t, err, entity.ErrInvalidReportPeriod)
+	})
+
+	t.Run("compares exposures at both dates", func(t *testing.T) {
+		repo := NewMockReportRepository(ctrl)
+		repo.EXPECT().CreditExposures(gomock.Any(), from).Return([]entity.CreditExposure{{CreditID: 1, DaysPastDue: 5}}, nil)
+		repo.EXPECT().CreditExposures(gomock.Any(), to).Return([]entity.CreditExposure{{CreditID: 1, DaysPastDue: 36}}, nil)
+
+		service := NewReportService(logger, repo)
+		rates, err := service.RollRates(context.TODO(), from.Add(15*time.Hour), to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, from, rates.From)
+		for _, rate := range rates.Rates {
+			if rate.From == "1-30" && rate.To == "31-60" {
+				assert.True(t, decimal.NewFromInt(100).Equal(rate.Rate))
+			}
+		}
+	})
+}
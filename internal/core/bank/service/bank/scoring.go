@@ -0,0 +1,125 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Scorer оценивает заявку на кредит и предлагает решение по ней.
+type Scorer interface {
+	Score(ctx context.Context, application *entity.CreditApplication, now time.Time) (*entity.ScoringResult, error)
+}
+
+// ScoringRepository предоставляет данные о заявителе, необходимые для скоринга.
+type ScoringRepository interface {
+	FindUserByID(ctx context.Context, id int32) (*entity.User, error)
+	AccountTurnover(ctx context.Context, accountID int32, since time.Time) (decimal.Decimal, error)
+	CreditHistory(ctx context.Context, userID int32) (*entity.CreditHistory, error)
+}
+
+const (
+	minBorrowerAge       = 18  // Минимальный возраст заемщика на дату подачи заявки
+	maxBorrowerAge       = 75  // Максимальный возраст заемщика на дату последнего платежа
+	maxActiveCredits     = 3   // Число действующих кредитов, начиная с которого заявку рассматривает сотрудник
+	turnoverPeriodMonths = 3   // Период, за который оценивается оборот по счету
+	scoreMax             = 100 // Максимальный скоринговый балл
+)
+
+var (
+	dtiReviewThreshold = decimal.RequireFromString("0.4") // Долговая нагрузка, начиная с которой заявку рассматривает сотрудник
+	dtiRejectThreshold = decimal.RequireFromString("0.6") // Долговая нагрузка, начиная с которой заявка отклоняется
+)
+
+// RuleScorer оценивает заявку по набору правил: возраст заемщика, просрочки и число действующих кредитов,
+// долговая нагрузка (отношение ежемесячных платежей к доходу) и оборот по счету.
+type RuleScorer struct {
+	repo ScoringRepository
+}
+
+// NewRuleScorer создает скоринг по правилам с указанным репозиторием.
+func NewRuleScorer(repo ScoringRepository) *RuleScorer {
+	return &RuleScorer{repo: repo}
+}
+
+// Score возвращает отказ, если нарушено хотя бы одно обязательное правило, рассмотрение сотрудником, если заявка
+// требует проверки, и одобрение в остальных случаях.
+func (s *RuleScorer) Score(ctx context.Context, application *entity.CreditApplication, now time.Time) (*entity.ScoringResult, error) {
+	user, err := s.repo.FindUserByID(ctx, application.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applicant: %w", err)
+	}
+
+	history, err := s.repo.CreditHistory(ctx, application.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit history: %w", err)
+	}
+
+	turnover, err := s.repo.AccountTurnover(ctx, application.AccountID, now.AddDate(0, -turnoverPeriodMonths, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account turnover: %w", err)
+	}
+
+	schedule, err := amortization.Generate(amortization.Params{
+		Principal:  application.Amount,
+		AnnualRate: application.InterestRate,
+		Term:       int(application.TermInMonths),
+		Method:     application.ScheduleType,
+		DayCount:   amortization.Actual365,
+		StartDate:  now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidCreditApplication, err)
+	}
+	payment := schedule[0].Payment
+
+	result := &entity.ScoringResult{Status: entity.ApplicationApproved, Score: scoreMax}
+	reject := func(reason string) {
+		result.Status = entity.ApplicationRejected
+		result.Score = 0
+		result.Reasons = append(result.Reasons, reason)
+	}
+	review := func(penalty int32, reason string) {
+		if result.Status == entity.ApplicationApproved {
+			result.Status = entity.ApplicationUnderReview
+		}
+		result.Score = max(result.Score-penalty, 0)
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	if user.DateOfBirth.IsZero() {
+		review(20, "date of birth is unknown")
+	} else {
+		if user.Age(now) < minBorrowerAge {
+			reject(fmt.Sprintf("applicant is younger than %d", minBorrowerAge))
+		}
+		if user.Age(schedule[len(schedule)-1].Date) > maxBorrowerAge {
+			reject(fmt.Sprintf("applicant is older than %d at the end of the term", maxBorrowerAge))
+		}
+	}
+
+	if history.OverduePayments > 0 {
+		reject(fmt.Sprintf("applicant has %d overdue payments", history.OverduePayments))
+	}
+	if history.ActiveCredits >= maxActiveCredits {
+		review(20, fmt.Sprintf("applicant has %d active credits", history.ActiveCredits))
+	}
+
+	dti := history.MonthlyPayments.Add(payment).Div(application.MonthlyIncome)
+	switch {
+	case dti.GreaterThan(dtiRejectThreshold):
+		reject(fmt.Sprintf("debt-to-income ratio %s exceeds %s", dti.StringFixed(2), dtiRejectThreshold))
+	case dti.GreaterThan(dtiReviewThreshold):
+		review(30, fmt.Sprintf("debt-to-income ratio %s exceeds %s", dti.StringFixed(2), dtiReviewThreshold))
+	}
+
+	if turnover.Div(decimal.NewFromInt(turnoverPeriodMonths)).LessThan(payment) {
+		review(15, "account turnover does not cover the monthly payment")
+	}
+
+	return result, nil
+}
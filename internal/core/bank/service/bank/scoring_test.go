@@ -0,0 +1,134 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRuleScorer_Score(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	adult := time.Date(1990, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	// Первый платеж по аннуитету 120000 под 12% на 12 месяцев — около 10660.
+	testCases := []struct {
+		name       string
+		birth      time.Time
+		income     string
+		turnover   string
+		history    entity.CreditHistory
+		wantStatus entity.CreditApplicationStatus
+		wantScore  int32
+		wantReason string
+	}{
+		{
+			name:       "approved",
+			birth:      adult,
+			income:     "100000",
+			turnover:   "300000",
+			wantStatus: entity.ApplicationApproved,
+			wantScore:  100,
+		},
+		{
+			name:       "high debt-to-income goes to review",
+			birth:      adult,
+			income:     "25000",
+			turnover:   "300000",
+			history:    entity.CreditHistory{ActiveCredits: 1, MonthlyPayments: decimal.NewFromInt(1000)},
+			wantStatus: entity.ApplicationUnderReview,
+			wantScore:  70,
+			wantReason: "debt-to-income ratio 0.47 exceeds 0.4",
+		},
+		{
+			name:       "excessive debt-to-income is rejected",
+			birth:      adult,
+			income:     "30000",
+			turnover:   "300000",
+			history:    entity.CreditHistory{ActiveCredits: 2, MonthlyPayments: decimal.NewFromInt(10000)},
+			wantStatus: entity.ApplicationRejected,
+			wantReason: "debt-to-income ratio 0.69 exceeds 0.6",
+		},
+		{
+			name:       "underage applicant is rejected",
+			birth:      time.Date(2008, 1, 16, 0, 0, 0, 0, time.UTC),
+			income:     "100000",
+			turnover:   "300000",
+			wantStatus: entity.ApplicationRejected,
+			wantReason: "applicant is younger than 18",
+		},
+		{
+			name:       "applicant too old at the end of the term is rejected",
+			birth:      time.Date(1949, 6, 1, 0, 0, 0, 0, time.UTC),
+			income:     "100000",
+			turnover:   "300000",
+			wantStatus: entity.ApplicationRejected,
+			wantReason: "applicant is older than 75 at the end of the term",
+		},
+		{
+			name:       "overdue payments are rejected",
+			birth:      adult,
+			income:     "100000",
+			turnover:   "300000",
+			history:    entity.CreditHistory{ActiveCredits: 1, OverduePayments: 2},
+			wantStatus: entity.ApplicationRejected,
+			wantReason: "applicant has 2 overdue payments",
+		},
+		{
+			name:       "unknown birth date and many credits go to review",
+			income:     "100000",
+			turnover:   "300000",
+			history:    entity.CreditHistory{ActiveCredits: 3},
+			wantStatus: entity.ApplicationUnderReview,
+			wantScore:  60,
+			wantReason: "applicant has 3 active credits",
+		},
+		{
+			name:       "low turnover goes to review",
+			birth:      adult,
+			income:     "100000",
+			turnover:   "30000",
+			wantStatus: entity.ApplicationUnderReview,
+			wantScore:  85,
+			wantReason: "account turnover does not cover the monthly payment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			application := &entity.CreditApplication{
+				UserID:        1,
+				AccountID:     5,
+				Amount:        decimal.NewFromInt(120000),
+				TermInMonths:  12,
+				ScheduleType:  amortization.Annuity,
+				MonthlyIncome: decimal.RequireFromString(tc.income),
+				InterestRate:  decimal.NewFromInt(12),
+			}
+
+			history := tc.history
+			repo := NewMockScoringRepository(ctrl)
+			repo.EXPECT().FindUserByID(gomock.Any(), int32(1)).Return(&entity.User{ID: 1, DateOfBirth: tc.birth}, nil)
+			repo.EXPECT().CreditHistory(gomock.Any(), int32(1)).Return(&history, nil)
+			repo.EXPECT().AccountTurnover(gomock.Any(), int32(5), now.AddDate(0, -3, 0)).Return(decimal.RequireFromString(tc.turnover), nil)
+
+			result, err := NewRuleScorer(repo).Score(context.TODO(), application, now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, result.Status)
+			assert.Equal(t, tc.wantScore, result.Score)
+			if tc.wantReason != "" {
+				assert.Contains(t, result.Reasons, tc.wantReason)
+			} else {
+				assert.Empty(t, result.Reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mock_${GOFILE}.go -package=${GOPACKAGE}
+package bank
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/config"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"log/slog"
+	"math/big"
+)
+
+// maxTokenAttempts ограничивает число попыток выпустить токен, не совпадающий с уже выданными.
+const maxTokenAttempts = 5
+
+// CardTokenRepository хранит соответствие токенов и карт.
+// Save возвращает false, если у карты уже есть токен или токен уже выдан другой карте.
+type CardTokenRepository interface {
+	FindByCardID(ctx context.Context, cardID int32) (entity.CardToken, error)
+	FindCardID(ctx context.Context, token entity.CardToken) (int32, error)
+	Save(ctx context.Context, cardID int32, token entity.CardToken) (bool, error)
+}
+
+// TokenVault выпускает токены вместо номеров карт для внешних потребителей и раскрывает номер карты
+// по токену только вызывающим, предъявившим ключ доступа к хранилищу. Номера карт остаются в зашифрованном
+// хранилище карт и расшифровываются через CardService.
+type TokenVault struct {
+	repo        CardTokenRepository
+	cardService *CardService
+	apiKey      string
+	logger      *slog.Logger
+}
+
+// NewTokenVault создает новый экземпляр TokenVault.
+func NewTokenVault(logger *slog.Logger, cfg *config.Config, repo CardTokenRepository, cardService *CardService) *TokenVault {
+	return &TokenVault{
+		repo:        repo,
+		cardService: cardService,
+		apiKey:      cfg.VaultAPIKey,
+		logger:      logger,
+	}
+}
+
+// Tokenize возвращает токен карты, выпуская его при первом обращении. У карты всегда один и тот же токен.
+func (v *TokenVault) Tokenize(ctx context.Context, card *entity.Card) (entity.CardToken, error) {
+	token, err := v.repo.FindByCardID(ctx, card.ID)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, entity.ErrCardTokenNotFound) {
+		v.logger.Error("failed to find card token", "error", err)
+		return "", fmt.Errorf("failed to find card token: %w", err)
+	}
+
+	pan := entity.PAN(card.CardNumber)
+	if pan == "" {
+		decrypted, err := v.cardService.FindByID(ctx, card.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to read card number: %w", err)
+		}
+		pan = entity.PAN(decrypted.CardNumber)
+	}
+
+	if err := pan.Validate(); err != nil {
+		return "", fmt.Errorf("failed to read card number: %w", err)
+	}
+
+	for attempt := 0; attempt < maxTokenAttempts; attempt++ {
+		token, err := generateToken(pan)
+		if err != nil {
+			return "", err
+		}
+
+		saved, err := v.repo.Save(ctx, card.ID, token)
+		if err != nil {
+			v.logger.Error("failed to save card token", "error", err)
+			return "", fmt.Errorf("failed to save card token: %w", err)
+		}
+
+		if saved {
+			v.logger.Info("card token issued", "card_id", card.ID)
+			return token, nil
+		}
+
+		// Токен мог быть выпущен параллельным запросом, иначе сгенерированный токен уже занят.
+		existing, err := v.repo.FindByCardID(ctx, card.ID)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, entity.ErrCardTokenNotFound) {
+			return "", fmt.Errorf("failed to find card token: %w", err)
+		}
+	}
+
+	return "", fmt.Errorf("failed to issue unique card token after %d attempts", maxTokenAttempts)
+}
+
+// CardID возвращает идентификатор карты по токену для внутренних операций. Номер карты при этом не раскрывается.
+func (v *TokenVault) CardID(ctx context.Context, token entity.CardToken) (int32, error) {
+	if err := token.Validate(); err != nil {
+		return 0, fmt.Errorf("%w: %w", entity.ErrCardTokenNotFound, err)
+	}
+
+	cardID, err := v.repo.FindCardID(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	return cardID, nil
+}
+
+// Detokenize раскрывает номер карты по токену, если apiKey совпадает с ключом доступа к хранилищу.
+func (v *TokenVault) Detokenize(ctx context.Context, token entity.CardToken, apiKey string) (entity.PAN, error) {
+	if v.apiKey == "" || subtle.ConstantTimeCompare([]byte(apiKey), []byte(v.apiKey)) != 1 {
+		v.logger.Warn("unauthorized detokenization attempt", "token", token.Masked())
+		return "", entity.ErrVaultAccessDenied
+	}
+
+	cardID, err := v.CardID(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	card, err := v.cardService.FindByID(ctx, cardID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read card number: %w", err)
+	}
+
+	v.logger.Info("card token detokenized", "token", token.Masked())
+	return entity.PAN(card.CardNumber), nil
+}
+
+// generateToken создает токен из 16 цифр, сохраняющий последние 4 цифры номера карты.
+// Токен, случайно прошедший проверку по алгоритму Луна, отбрасывается, чтобы его нельзя было спутать с номером карты.
+func generateToken(pan entity.PAN) (entity.CardToken, error) {
+	last4 := string(pan[len(pan)-4:])
+
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(1_000_000_000_000))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate card token: %w", err)
+		}
+
+		token := entity.CardToken(fmt.Sprintf("%012d%s", n.Int64(), last4))
+		if token.Validate() == nil {
+			return token, nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package bank
+
+import (
+	"context"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	pan := entity.PAN("4111111111111111")
+
+	for i := 0; i < 100; i++ {
+		token, err := generateToken(pan)
+		assert.NoError(t, err)
+		assert.NoError(t, token.Validate())
+		assert.Equal(t, pan.Masked(), token.Masked())
+		assert.Error(t, entity.PAN(token).Validate())
+	}
+}
+
+func TestTokenVault_Tokenize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	card := &entity.Card{ID: 1, CardNumber: "4111111111111111"}
+	existing := entity.CardToken("1234567890121111")
+
+	testCases := []struct {
+		name     string
+		mockFunc func(m *MockCardTokenRepository)
+		want     entity.CardToken
+	}{
+		{
+			name: "existing token is reused",
+			mockFunc: func(m *MockCardTokenRepository) {
+				m.EXPECT().FindByCardID(gomock.Any(), int32(1)).Return(existing, nil)
+			},
+			want: existing,
+		},
+		{
+			name: "token issued concurrently is returned",
+			mockFunc: func(m *MockCardTokenRepository) {
+				gomock.InOrder(
+					m.EXPECT().FindByCardID(gomock.Any(), int32(1)).Return(entity.CardToken(""), entity.ErrCardTokenNotFound),
+					m.EXPECT().Save(gomock.Any(), int32(1), gomock.Any()).Return(false, nil),
+					m.EXPECT().FindByCardID(gomock.Any(), int32(1)).Return(existing, nil),
+				)
+			},
+			want: existing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewMockCardTokenRepository(ctrl)
+			tc.mockFunc(repo)
+
+			vault := &TokenVault{repo: repo, logger: logger}
+			token, err := vault.Tokenize(context.TODO(), card)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, token)
+		})
+	}
+
+	t.Run("new token keeps last digits", func(t *testing.T) {
+		repo := NewMockCardTokenRepository(ctrl)
+		repo.EXPECT().FindByCardID(gomock.Any(), int32(1)).Return(entity.CardToken(""), entity.ErrCardTokenNotFound)
+		repo.EXPECT().Save(gomock.Any(), int32(1), gomock.Any()).Return(true, nil)
+
+		vault := &TokenVault{repo: repo, logger: logger}
+		token, err := vault.Tokenize(context.TODO(), card)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "**** 1111", token.Masked())
+	})
+}
+
+func TestTokenVault_Detokenize_AccessDenied(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+
+	vault := &TokenVault{apiKey: "secret", logger: logger}
+	_, err := vault.Detokenize(context.TODO(), "1234567890121111", "wrong")
+	assert.ErrorIs(t, err, entity.ErrVaultAccessDenied)
+
+	vault = &TokenVault{logger: logger}
+	_, err = vault.Detokenize(context.TODO(), "1234567890121111", "")
+	assert.ErrorIs(t, err, entity.ErrVaultAccessDenied)
+}
+
+func TestTokenVault_CardID_InvalidToken(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(NullWriter{}, nil))
+	vault := &TokenVault{logger: logger}
+
+	// Настоящий номер карты проходит проверку по алгоритму Луна и не может быть токеном.
+	_, err := vault.CardID(context.TODO(), "4111111111111111")
+	assert.ErrorIs(t, err, entity.ErrCardTokenNotFound)
+}
@@ -54,7 +54,7 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", fmt.Errorf("invalid password: %w", err)
 	}
 
-	token, err := auth.GenerateJWTToken(fmt.Sprint(user.ID))
+	token, err := auth.GenerateJWTToken(fmt.Sprint(user.ID), string(user.Role))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate JWT token: %w", err)
 	}
@@ -2,22 +2,69 @@ package cli
 
 import (
 	"context"
+	"github.com/MaxFando/bank-system/internal/providers"
 	"github.com/go-co-op/gocron"
+	"log/slog"
 	"time"
 )
 
 type Handler struct {
 	Scheduler *gocron.Scheduler
+
+	provider *providers.ServiceProvider
+	logger   *slog.Logger
 }
 
-func New() *Handler {
+func New(logger *slog.Logger, provider *providers.ServiceProvider) *Handler {
 	return &Handler{
 		Scheduler: gocron.NewScheduler(time.UTC),
+		provider:  provider,
+		logger:    logger,
 	}
 }
 
-func (h *Handler) Start() {
-	_, err := h.Scheduler.Every(1).Hour().Do(h.CheckCredits)
+func (h *Handler) Start(ctx context.Context) {
+	_, err := h.Scheduler.Every(1).Hour().Do(h.CheckCredits, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:05").Do(h.AccrueInterest, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:30").Do(h.AccruePenalties, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:40").Do(h.ProcessCreditLines, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:45").Do(h.RefreshKeyRate, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:50").Do(h.ResetFloatingRates, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("01:00").Do(h.ExportBureauFile, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:10").Do(h.ExpireCards, ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = h.Scheduler.Every(1).Day().At("00:20").Do(h.ReissueCards, ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -25,6 +72,65 @@ func (h *Handler) Start() {
 	h.Scheduler.StartAsync()
 }
 
+// CheckCredits списывает наступившие платежи по кредитам и отмечает непогашенные как просроченные.
 func (h *Handler) CheckCredits(ctx context.Context) {
+	if err := h.provider.CreditService.CollectDuePayments(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to collect credit payments", "error", err)
+	}
+}
+
+// AccrueInterest начисляет проценты по действующим кредитам за прошедшие дни.
+func (h *Handler) AccrueInterest(ctx context.Context) {
+	if err := h.provider.CreditService.AccrueInterest(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to accrue interest", "error", err)
+	}
+}
+
+// AccruePenalties начисляет штрафы по просроченным платежам за прошедшие дни просрочки.
+func (h *Handler) AccruePenalties(ctx context.Context) {
+	if err := h.provider.CreditService.AccruePenalties(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to accrue penalties", "error", err)
+	}
+}
+
+// RefreshKeyRate загружает ключевую ставку ЦБ и сохраняет ее в историю до пересмотра плавающих ставок.
+func (h *Handler) RefreshKeyRate(ctx context.Context) {
+	if _, err := h.provider.KeyRateProvider.KeyRate(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to refresh key rate", "error", err)
+	}
+}
+
+// ResetFloatingRates пересматривает плавающие ставки по кредитам и перестраивает их графики.
+func (h *Handler) ResetFloatingRates(ctx context.Context) {
+	if err := h.provider.CreditService.ResetFloatingRates(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to reset floating rates", "error", err)
+	}
+}
+
+// ProcessCreditLines начисляет проценты по кредитным линиям, формирует выписки и подводит итог льготного периода.
+func (h *Handler) ProcessCreditLines(ctx context.Context) {
+	if err := h.provider.CreditLineService.ProcessCreditLines(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to process credit lines", "error", err)
+	}
+}
 
+// ExportBureauFile формирует файлы для бюро кредитных историй за закончившиеся месяцы, которые еще не переданы.
+func (h *Handler) ExportBureauFile(ctx context.Context) {
+	if err := h.provider.BureauService.ExportPending(ctx, time.Now().UTC()); err != nil {
+		h.logger.Error("failed to export bureau file", "error", err)
+	}
+}
+
+// ExpireCards переводит в статус expired карты, срок действия которых истек.
+func (h *Handler) ExpireCards(ctx context.Context) {
+	if err := h.provider.CardService.ExpireCards(ctx, time.Now()); err != nil {
+		h.logger.Error("failed to expire cards", "error", err)
+	}
+}
+
+// ReissueCards выпускает замену картам, срок действия которых скоро истекает.
+func (h *Handler) ReissueCards(ctx context.Context) {
+	if err := h.provider.CardService.ReissueExpiringCards(ctx, time.Now()); err != nil {
+		h.logger.Error("failed to reissue cards", "error", err)
+	}
 }
@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/iso8583"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// currencyRUB цифровой код рубля по ISO 4217; карты банка обслуживают только рублевые операции.
+	currencyRUB = "643"
+	// mccCashWithdrawal код категории для выдачи наличных, если эквайер не передал поле 18.
+	mccCashWithdrawal = "6011"
+	// posConditionECommerce код условий проведения операции в интернете (поле 25).
+	posConditionECommerce = "59"
+)
+
+// Коды вида операции — первые две цифры поля 3.
+const (
+	processingPurchase       = "00"
+	processingCashWithdrawal = "01"
+)
+
+// CardProcessor описывает операции по картам, на которые шлюз отображает сообщения ISO 8583.
+// Authorize и ReverseByRRN сохраняют и находят операции по RRN (поле 37) в истории операций по картам.
+type CardProcessor interface {
+	FindByPAN(ctx context.Context, pan entity.PAN) (*entity.Card, error)
+	Authorize(ctx context.Context, cardID int32, amount decimal.Decimal, merchant *entity.Merchant, rrn, stan string) (*entity.CardTransaction, error)
+	ReverseByRRN(ctx context.Context, rrn string) (*entity.CardTransaction, error)
+}
+
+// Handler обрабатывает запросы авторизации (0100), финансовые запросы (0200) и сторно (0400).
+// Шлюз работает как система с одним сообщением: одобренная авторизация сразу списывает средства с карты.
+// Одобренные операции сохраняются вместе с RRN (поле 37) и STAN (поле 11), чтобы повторная передача запроса,
+// в том числе после перезапуска шлюза, не приводила к повторному списанию, а сторно могло найти исходную операцию.
+// Запросы по одной карте упорядочиваются блокировкой карты, запросы по разным картам обрабатываются параллельно.
+type Handler struct {
+	processor CardProcessor
+	logger    *slog.Logger
+
+	now func() time.Time
+}
+
+func NewHandler(logger *slog.Logger, processor CardProcessor) *Handler {
+	return &Handler{
+		processor: processor,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Handle обрабатывает запрос и возвращает ответ с кодом в поле 39.
+func (h *Handler) Handle(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	switch request.MTI {
+	case iso8583.MTIAuthorizationRequest, iso8583.MTIFinancialRequest:
+		return h.authorize(ctx, request)
+	case iso8583.MTIReversalRequest:
+		return h.reverse(ctx, request)
+	default:
+		h.logger.Warn("unsupported iso8583 message type", "mti", request.MTI)
+		return request.Response(iso8583.ResponseInvalidTransaction)
+	}
+}
+
+func (h *Handler) authorize(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	for _, field := range []int{iso8583.FieldPAN, iso8583.FieldProcessingCode, iso8583.FieldAmount, iso8583.FieldSTAN, iso8583.FieldRRN} {
+		if !request.Has(field) {
+			h.logger.Warn("iso8583 request is missing required field", "mti", request.MTI, "field", field)
+			return request.Response(iso8583.ResponseFormatError)
+		}
+	}
+
+	rrn, _ := request.Get(iso8583.FieldRRN)
+	responseCode, transaction := h.debit(ctx, request)
+	h.logger.Info("iso8583 authorization processed", "mti", request.MTI, "rrn", rrn, "response_code", responseCode)
+
+	response := request.Response(responseCode)
+	if transaction != nil {
+		_ = response.Set(iso8583.FieldAuthorizationID, authorizationID(transaction))
+	}
+
+	return response
+}
+
+// debit проверяет запрос и списывает средства с карты. Возвращает код ответа и, если операция одобрена, операцию списания.
+func (h *Handler) debit(ctx context.Context, request *iso8583.Message) (string, *entity.CardTransaction) {
+	processingCode, _ := request.Get(iso8583.FieldProcessingCode)
+	kind := processingCode[:2]
+	if kind != processingPurchase && kind != processingCashWithdrawal {
+		return iso8583.ResponseInvalidTransaction, nil
+	}
+
+	if currency, ok := request.Get(iso8583.FieldCurrencyCode); ok && currency != currencyRUB {
+		return iso8583.ResponseInvalidTransaction, nil
+	}
+
+	amount, err := parseAmount(request)
+	if err != nil {
+		return iso8583.ResponseInvalidAmount, nil
+	}
+
+	expiration, hasExpiration := request.Get(iso8583.FieldExpirationDate)
+	if hasExpiration {
+		expired, err := isExpired(expiration, h.now())
+		if err != nil {
+			return iso8583.ResponseFormatError, nil
+		}
+		if expired {
+			return iso8583.ResponseExpiredCard, nil
+		}
+	}
+
+	pan, _ := request.Get(iso8583.FieldPAN)
+	card, err := h.processor.FindByPAN(ctx, entity.PAN(pan))
+	if err != nil {
+		return responseCode(err), nil
+	}
+
+	// Срок действия из запроса должен совпадать со сроком выпущенной карты, иначе запрос отклоняется.
+	if hasExpiration && expiration != card.ExpirationDate.Format("0601") {
+		return iso8583.ResponseExpiredCard, nil
+	}
+
+	rrn, _ := request.Get(iso8583.FieldRRN)
+	stan, _ := request.Get(iso8583.FieldSTAN)
+	transaction, err := h.processor.Authorize(ctx, card.ID, amount, merchant(request, kind), rrn, stan)
+	if err != nil {
+		return responseCode(err), nil
+	}
+
+	return iso8583.ResponseApproved, transaction
+}
+
+func (h *Handler) reverse(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	rrn, ok := request.Get(iso8583.FieldRRN)
+	if !ok {
+		return request.Response(iso8583.ResponseFormatError)
+	}
+
+	// Повторное сторно уже отмененной операции подтверждается без повторного зачисления.
+	transaction, err := h.processor.ReverseByRRN(ctx, rrn)
+	if errors.Is(err, entity.ErrCardTransactionNotFound) {
+		h.logger.Warn("original iso8583 operation not found", "rrn", rrn)
+		return request.Response(iso8583.ResponseOriginalNotFound)
+	}
+	if err != nil {
+		h.logger.Error("failed to reverse iso8583 operation", "rrn", rrn, "error", err)
+		return request.Response(iso8583.ResponseSystemMalfunction)
+	}
+
+	h.logger.Info("iso8583 operation reversed", "rrn", rrn, "card_id", transaction.CardID, "amount", transaction.Amount)
+	return request.Response(iso8583.ResponseApproved)
+}
+
+// authorizationID возвращает код авторизации для поля 38, полученный из идентификатора операции списания.
+func authorizationID(transaction *entity.CardTransaction) string {
+	return fmt.Sprintf("%06d", transaction.ID%1000000)
+}
+
+// responseCode сопоставляет ошибку операции по карте с кодом ответа ISO 8583.
+func responseCode(err error) string {
+	var violation *entity.CardRuleViolationError
+
+	switch {
+	case errors.Is(err, entity.ErrCardNotFound):
+		return iso8583.ResponseInvalidCardNumber
+	case errors.Is(err, entity.ErrInsufficientFunds):
+		return iso8583.ResponseInsufficientFunds
+	case errors.Is(err, entity.ErrCardExpired):
+		return iso8583.ResponseExpiredCard
+	case errors.Is(err, entity.ErrCardLimitExceeded):
+		return iso8583.ResponseExceedsAmountLimit
+	case errors.As(err, &violation):
+		if violation.Reason == entity.CardRuleCategoryLimitExceeded {
+			return iso8583.ResponseExceedsAmountLimit
+		}
+		return iso8583.ResponseNotPermittedCardhold
+	case errors.Is(err, entity.ErrCardNotActive):
+		return iso8583.ResponseRestrictedCard
+	case errors.Is(err, entity.ErrDuplicateRRN):
+		return iso8583.ResponseDuplicateTransaction
+	default:
+		return iso8583.ResponseSystemMalfunction
+	}
+}
+
+// parseAmount возвращает сумму операции из поля 4, переданную в копейках.
+func parseAmount(request *iso8583.Message) (decimal.Decimal, error) {
+	value, _ := request.Get(iso8583.FieldAmount)
+
+	minor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	if minor <= 0 {
+		return decimal.Zero, fmt.Errorf("amount must be positive")
+	}
+
+	return decimal.New(minor, -2), nil
+}
+
+// isExpired проверяет срок действия карты из поля 14 в формате YYMM. Карта действует до конца указанного месяца.
+func isExpired(expiration string, now time.Time) (bool, error) {
+	date, err := time.Parse("0601", expiration)
+	if err != nil {
+		return false, fmt.Errorf("invalid expiration date: %w", err)
+	}
+
+	validUntil := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1, 0)
+	return !now.Before(validUntil), nil
+}
+
+// merchant собирает сведения о торговой точке из полей 18, 25 и 43.
+// Поле 43 содержит наименование (позиции 1–25), город (26–38) и код страны ISO 3166 alpha-2 (39–40).
+func merchant(request *iso8583.Message, kind string) *entity.Merchant {
+	mcc, ok := request.Get(iso8583.FieldMCC)
+	if !ok {
+		if kind != processingCashWithdrawal {
+			return nil
+		}
+		mcc = mccCashWithdrawal
+	}
+
+	m := &entity.Merchant{MCC: entity.MCC(mcc), Country: entity.DomesticCountry}
+
+	if condition, ok := request.Get(iso8583.FieldPOSConditionCode); ok {
+		m.Online = condition == posConditionECommerce
+	}
+
+	if acceptor, ok := request.Get(iso8583.FieldCardAcceptorName); ok {
+		m.Name = strings.TrimSpace(acceptor[:25])
+		if country := strings.TrimSpace(acceptor[38:]); country != "" {
+			m.Country = strings.ToUpper(country)
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,305 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/pkg/iso8583"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+const (
+	testPAN     = "4276380012345675"
+	expiredPAN  = "4000000000000002"
+	unknownPAN  = "4111111111111111"
+	testCardID  = int32(1)
+	expiredCard = int32(2)
+)
+
+// testExpiration срок действия карты testPAN.
+var testExpiration = time.Date(2028, 6, 30, 0, 0, 0, 0, time.UTC)
+
+// fakeProcessor хранит балансы карт и одобренные операции по RRN в памяти вместо CardService и базы данных.
+type fakeProcessor struct {
+	balances     map[int32]decimal.Decimal
+	merchant     *entity.Merchant
+	transactions map[string]*entity.CardTransaction
+}
+
+func newFakeProcessor(balance int64) *fakeProcessor {
+	return &fakeProcessor{
+		balances:     map[int32]decimal.Decimal{testCardID: decimal.NewFromInt(balance)},
+		transactions: make(map[string]*entity.CardTransaction),
+	}
+}
+
+func (p *fakeProcessor) FindByPAN(_ context.Context, pan entity.PAN) (*entity.Card, error) {
+	switch pan {
+	case testPAN:
+		return &entity.Card{ID: testCardID, ExpirationDate: testExpiration}, nil
+	case expiredPAN:
+		return &entity.Card{ID: expiredCard, ExpirationDate: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)}, nil
+	default:
+		return nil, fmt.Errorf("failed to find card by PAN: %w", entity.ErrCardNotFound)
+	}
+}
+
+func (p *fakeProcessor) Authorize(
+	_ context.Context,
+	cardID int32,
+	amount decimal.Decimal,
+	merchant *entity.Merchant,
+	rrn, stan string,
+) (*entity.CardTransaction, error) {
+	if transaction, ok := p.transactions[rrn]; ok {
+		if transaction.CardID != cardID || !transaction.Amount.Equal(amount) {
+			return nil, entity.ErrDuplicateRRN
+		}
+		return transaction, nil
+	}
+
+	p.merchant = merchant
+
+	if cardID == expiredCard {
+		return nil, fmt.Errorf("card cannot be debited: %w", entity.ErrCardExpired)
+	}
+
+	if merchant != nil && merchant.MCC.Group() == entity.MCCGroupGambling {
+		return nil, &entity.CardRuleViolationError{Reason: entity.CardRuleMCCBlocked, Group: entity.MCCGroupGambling}
+	}
+
+	if p.balances[cardID].LessThan(amount) {
+		return nil, fmt.Errorf("failed to withdraw amount: %w", entity.ErrInsufficientFunds)
+	}
+
+	p.balances[cardID] = p.balances[cardID].Sub(amount)
+
+	transaction := &entity.CardTransaction{ID: int32(len(p.transactions) + 1), CardID: cardID, Amount: amount, RRN: rrn, STAN: stan}
+	p.transactions[rrn] = transaction
+	return transaction, nil
+}
+
+func (p *fakeProcessor) ReverseByRRN(_ context.Context, rrn string) (*entity.CardTransaction, error) {
+	transaction, ok := p.transactions[rrn]
+	if !ok {
+		return nil, entity.ErrCardTransactionNotFound
+	}
+
+	if transaction.ReversedAt == nil {
+		reversedAt := time.Now()
+		transaction.ReversedAt = &reversedAt
+		p.balances[transaction.CardID] = p.balances[transaction.CardID].Add(transaction.Amount)
+	}
+
+	return transaction, nil
+}
+
+func startGateway(t *testing.T, processor CardProcessor) *iso8583.Client {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	handler := NewHandler(logger, processor)
+	handler.now = func() time.Time { return time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC) }
+
+	server := NewServer(logger, handler, "127.0.0.1:0")
+	require.NoError(t, server.Serve())
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	client, err := iso8583.Dial(context.Background(), server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func request(t *testing.T, mti string, fields map[int]string) *iso8583.Message {
+	message := iso8583.NewMessage(mti)
+	for field, value := range fields {
+		require.NoError(t, message.Set(field, value))
+	}
+	return message
+}
+
+func purchase(pan, amount, rrn string) map[int]string {
+	return map[int]string{
+		iso8583.FieldPAN:              pan,
+		iso8583.FieldProcessingCode:   "000000",
+		iso8583.FieldAmount:           amount,
+		iso8583.FieldSTAN:             "1",
+		iso8583.FieldRRN:              rrn,
+		iso8583.FieldMCC:              "5411",
+		iso8583.FieldCardAcceptorName: fmt.Sprintf("%-25s%-13s%2s", "Magnit", "Moscow", "RU"),
+		iso8583.FieldCurrencyCode:     "643",
+	}
+}
+
+func TestGateway_Authorization(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mti    string
+		fields map[int]string
+		want   string
+	}{
+		{
+			name:   "approved purchase",
+			mti:    iso8583.MTIFinancialRequest,
+			fields: purchase(testPAN, "50000", "000000000001"),
+			want:   iso8583.ResponseApproved,
+		},
+		{
+			name:   "insufficient funds",
+			mti:    iso8583.MTIAuthorizationRequest,
+			fields: purchase(testPAN, "99999999", "000000000002"),
+			want:   iso8583.ResponseInsufficientFunds,
+		},
+		{
+			name:   "expired card",
+			mti:    iso8583.MTIAuthorizationRequest,
+			fields: purchase(expiredPAN, "100", "000000000003"),
+			want:   iso8583.ResponseExpiredCard,
+		},
+		{
+			name: "expiration date in request is in the past",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000004")
+				fields[iso8583.FieldExpirationDate] = "2505"
+				return fields
+			}(),
+			want: iso8583.ResponseExpiredCard,
+		},
+		{
+			name: "expiration date in request matches card",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000009")
+				fields[iso8583.FieldExpirationDate] = "2806"
+				return fields
+			}(),
+			want: iso8583.ResponseApproved,
+		},
+		{
+			name: "expiration date in request differs from card",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000011")
+				fields[iso8583.FieldExpirationDate] = "2712"
+				return fields
+			}(),
+			want: iso8583.ResponseExpiredCard,
+		},
+		{
+			name:   "unknown card",
+			mti:    iso8583.MTIAuthorizationRequest,
+			fields: purchase(unknownPAN, "100", "000000000005"),
+			want:   iso8583.ResponseInvalidCardNumber,
+		},
+		{
+			name: "blocked merchant category",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000006")
+				fields[iso8583.FieldMCC] = "7995"
+				return fields
+			}(),
+			want: iso8583.ResponseNotPermittedCardhold,
+		},
+		{
+			name: "foreign currency",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000007")
+				fields[iso8583.FieldCurrencyCode] = "840"
+				return fields
+			}(),
+			want: iso8583.ResponseInvalidTransaction,
+		},
+		{
+			name: "missing rrn",
+			mti:  iso8583.MTIAuthorizationRequest,
+			fields: func() map[int]string {
+				fields := purchase(testPAN, "100", "000000000008")
+				delete(fields, iso8583.FieldRRN)
+				return fields
+			}(),
+			want: iso8583.ResponseFormatError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			processor := newFakeProcessor(1000)
+			client := startGateway(t, processor)
+
+			response, err := client.Send(context.Background(), request(t, tc.mti, tc.fields))
+			require.NoError(t, err)
+
+			code, _ := response.Get(iso8583.FieldResponseCode)
+			assert.Equal(t, tc.want, code)
+			assert.Equal(t, tc.mti[:2]+"1"+tc.mti[3:], response.MTI)
+			assert.Equal(t, tc.want == iso8583.ResponseApproved, response.Has(iso8583.FieldAuthorizationID))
+		})
+	}
+}
+
+func TestGateway_DuplicateAndReversal(t *testing.T) {
+	processor := newFakeProcessor(1000)
+	client := startGateway(t, processor)
+	ctx := context.Background()
+
+	fields := purchase(testPAN, "30000", "000000000010")
+
+	response, err := client.Send(ctx, request(t, iso8583.MTIFinancialRequest, fields))
+	require.NoError(t, err)
+	code, _ := response.Get(iso8583.FieldResponseCode)
+	assert.Equal(t, iso8583.ResponseApproved, code)
+	assert.True(t, decimal.NewFromInt(700).Equal(processor.balances[testCardID]))
+	assert.Equal(t, &entity.Merchant{Name: "Magnit", MCC: "5411", Country: "RU"}, processor.merchant)
+	authorization, _ := response.Get(iso8583.FieldAuthorizationID)
+	assert.Equal(t, "000001", authorization)
+	assert.Equal(t, "000001", processor.transactions["000000000010"].STAN)
+
+	// Повторная передача того же запроса, в том числе после перезапуска шлюза, не списывает средства повторно.
+	client = startGateway(t, processor)
+	duplicate, err := client.Send(ctx, request(t, iso8583.MTIFinancialRequest, fields))
+	require.NoError(t, err)
+	code, _ = duplicate.Get(iso8583.FieldResponseCode)
+	assert.Equal(t, iso8583.ResponseApproved, code)
+	authorization, _ = duplicate.Get(iso8583.FieldAuthorizationID)
+	assert.Equal(t, "000001", authorization)
+	assert.True(t, decimal.NewFromInt(700).Equal(processor.balances[testCardID]))
+
+	// Запрос с тем же RRN на другую сумму не считается повторной передачей и отклоняется.
+	other, err := client.Send(ctx, request(t, iso8583.MTIFinancialRequest, purchase(testPAN, "20000", "000000000010")))
+	require.NoError(t, err)
+	code, _ = other.Get(iso8583.FieldResponseCode)
+	assert.Equal(t, iso8583.ResponseDuplicateTransaction, code)
+	assert.True(t, decimal.NewFromInt(700).Equal(processor.balances[testCardID]))
+
+	reversal := map[int]string{
+		iso8583.FieldPAN:    testPAN,
+		iso8583.FieldAmount: "30000",
+		iso8583.FieldSTAN:   "2",
+		iso8583.FieldRRN:    "000000000010",
+	}
+
+	for i := 0; i < 2; i++ {
+		response, err = client.Send(ctx, request(t, iso8583.MTIReversalRequest, reversal))
+		require.NoError(t, err)
+		code, _ = response.Get(iso8583.FieldResponseCode)
+		assert.Equal(t, iso8583.MTIReversalResponse, response.MTI)
+		assert.Equal(t, iso8583.ResponseApproved, code)
+		assert.True(t, decimal.NewFromInt(1000).Equal(processor.balances[testCardID]))
+	}
+
+	reversal[iso8583.FieldRRN] = "000000000099"
+	response, err = client.Send(ctx, request(t, iso8583.MTIReversalRequest, reversal))
+	require.NoError(t, err)
+	code, _ = response.Get(iso8583.FieldResponseCode)
+	assert.Equal(t, iso8583.ResponseOriginalNotFound, code)
+}
@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/pkg/iso8583"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+)
+
+const _defaultAddr = ":8583"
+
+// Server принимает TCP-соединения от процессингового центра и обрабатывает сообщения ISO 8583.
+// Сообщения передаются с двухбайтовым префиксом длины, ответ отправляется в то же соединение.
+type Server struct {
+	addr    string
+	handler *Handler
+	logger  *slog.Logger
+
+	listener net.Listener
+	errors   chan error
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewServer(logger *slog.Logger, handler *Handler, addr string) *Server {
+	if addr == "" {
+		addr = _defaultAddr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		addr:    addr,
+		handler: handler,
+		logger:  logger,
+		errors:  make(chan error, 1),
+		conns:   make(map[net.Conn]struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Serve открывает порт и начинает принимать соединения в отдельной горутине.
+func (s *Server) Serve() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s: %w", s.addr, err)
+	}
+
+	s.listener = listener
+
+	go func() {
+		s.errors <- s.accept()
+		close(s.errors)
+	}()
+
+	return nil
+}
+
+// Addr возвращает адрес, на котором сервер принимает соединения.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) Notify() <-chan error {
+	return s.errors
+}
+
+// Shutdown перестает принимать соединения, закрывает открытые и дожидается завершения их обработки.
+func (s *Server) Shutdown() error {
+	s.cancel()
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) accept() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+
+	logger := s.logger.With("remote_addr", conn.RemoteAddr().String())
+	logger.Info("iso8583 connection accepted")
+
+	for {
+		frame, err := iso8583.ReadFrame(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && s.ctx.Err() == nil {
+				logger.Error("failed to read iso8583 message", "error", err)
+			}
+			return
+		}
+
+		response := s.handle(logger, frame)
+		if response == nil {
+			continue
+		}
+
+		data, err := response.Encode()
+		if err != nil {
+			logger.Error("failed to encode iso8583 response", "error", err)
+			continue
+		}
+
+		if err := iso8583.WriteFrame(conn, data); err != nil {
+			logger.Error("failed to write iso8583 response", "error", err)
+			return
+		}
+	}
+}
+
+// handle разбирает сообщение и передает его обработчику. На сообщение, которое не удалось разобрать,
+// отвечает кодом 30, если из него можно извлечь MTI.
+func (s *Server) handle(logger *slog.Logger, frame []byte) *iso8583.Message {
+	request, err := iso8583.Decode(frame)
+	if err != nil {
+		logger.Warn("malformed iso8583 message", "error", err)
+		if len(frame) < 4 {
+			return nil
+		}
+
+		response := iso8583.NewMessage(string(frame[:4])).Response(iso8583.ResponseFormatError)
+		if _, err := response.Encode(); err != nil {
+			return nil
+		}
+		return response
+	}
+
+	return s.handler.Handle(s.ctx, request)
+}
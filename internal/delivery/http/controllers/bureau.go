@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+	"time"
+)
+
+type BureauController struct {
+	bureauService *bank.BureauService
+}
+
+func NewBureauController(bureauService *bank.BureauService) *BureauController {
+	return &BureauController{
+		bureauService: bureauService,
+	}
+}
+
+// List возвращает файлы, переданные в бюро кредитных историй. Доступно только сотрудникам.
+func (ctrl *BureauController) List(c echo.Context) error {
+	submissions, err := ctrl.bureauService.ListSubmissions(c.Request().Context())
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to list bureau submissions"})
+	}
+
+	return c.JSON(200, submissions)
+}
+
+// Export формирует файл для бюро кредитных историй за месяц period в формате YYYY-MM, если он закончился
+// и файл за него еще не передан. Доступно только сотрудникам.
+func (ctrl *BureauController) Export(c echo.Context) error {
+	type request struct {
+		Period string `json:"period" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	period, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid period"})
+	}
+
+	submission, err := ctrl.bureauService.Export(c.Request().Context(), period, time.Now().UTC())
+	if errors.Is(err, entity.ErrBureauPeriodSubmitted) || errors.Is(err, entity.ErrBureauPeriodNotClosed) ||
+		errors.Is(err, entity.ErrInvalidBureauFile) {
+		return c.JSON(422, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to export bureau file"})
+	}
+
+	return c.JSON(201, submission)
+}
+
+// Get отдает файл, переданный в бюро кредитных историй за месяц period в формате YYYY-MM. Доступно только сотрудникам.
+func (ctrl *BureauController) Get(c echo.Context) error {
+	type request struct {
+		Period string `param:"period" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	period, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid period"})
+	}
+
+	submission, err := ctrl.bureauService.GetSubmission(c.Request().Context(), period)
+	if errors.Is(err, entity.ErrBureauSubmissionNotFound) {
+		return c.JSON(404, map[string]string{"error": "Bureau submission not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get bureau submission"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", submission.FileName))
+	c.Response().Header().Set("X-Content-SHA256", submission.ContentHash)
+	return c.Blob(200, "application/xml; charset=utf-8", submission.Content)
+}
@@ -1,26 +1,35 @@
 package controllers
 
 import (
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
 	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
 	"github.com/labstack/echo/v4"
 	"github.com/shopspring/decimal"
+	"strings"
+	"time"
 )
 
 type CardController struct {
 	accountService *bank.AccountService
 	cardService    *bank.CardService
+	tokenVault     *bank.TokenVault
 }
 
-func NewCardController(accountService *bank.AccountService, cardService *bank.CardService) *CardController {
+func NewCardController(accountService *bank.AccountService, cardService *bank.CardService, tokenVault *bank.TokenVault) *CardController {
 	return &CardController{
 		accountService: accountService,
 		cardService:    cardService,
+		tokenVault:     tokenVault,
 	}
 }
 
 func (ctrl *CardController) CreateCard(c echo.Context) error {
 	type request struct {
-		AccountID int32 `json:"account_id" validate:"required"`
+		AccountID      int32            `json:"account_id" validate:"required"`
+		Kind           entity.CardKind  `json:"kind" validate:"omitempty,oneof=physical virtual single_use limited_use"`
+		SpendLimit     *decimal.Decimal `json:"spend_limit"`
+		ExpirationDate *time.Time       `json:"expiration_date"`
 	}
 
 	var req request
@@ -42,14 +51,33 @@ func (ctrl *CardController) CreateCard(c echo.Context) error {
 		return c.JSON(403, map[string]string{"error": "Unauthorized account access"})
 	}
 
-	card, err := ctrl.cardService.Create(c.Request().Context(), account)
+	var opts []bank.CardOption
+	if req.Kind != "" {
+		opts = append(opts, bank.WithCardKind(req.Kind))
+	}
+	if req.SpendLimit != nil {
+		opts = append(opts, bank.WithSpendLimit(*req.SpendLimit))
+	}
+	if req.ExpirationDate != nil {
+		opts = append(opts, bank.WithExpirationDate(*req.ExpirationDate))
+	}
+
+	card, err := ctrl.cardService.Create(c.Request().Context(), account, opts...)
+	if errors.Is(err, entity.ErrInvalidCard) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
 	if err != nil {
 		return c.JSON(500, map[string]string{"error": "Card creation failed"})
 	}
 
+	token, err := ctrl.tokenVault.Tokenize(c.Request().Context(), card)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Card tokenization failed"})
+	}
+
 	return c.JSON(200, map[string]interface{}{
 		"message": "Card created successfully",
-		"card":    card,
+		"card":    entity.NewCardView(card, token),
 	})
 }
 
@@ -82,17 +110,60 @@ func (ctrl *CardController) GetCardsByAccountID(c echo.Context) error {
 		return c.JSON(500, map[string]string{"error": "Failed to retrieve cards"})
 	}
 
+	views := make([]entity.CardView, 0, len(cards))
+	for i := range cards {
+		token, err := ctrl.tokenVault.Tokenize(c.Request().Context(), &cards[i])
+		if err != nil {
+			return c.JSON(500, map[string]string{"error": "Card tokenization failed"})
+		}
+		views = append(views, entity.NewCardView(&cards[i], token))
+	}
+
 	return c.JSON(200, map[string]interface{}{
 		"message": "Cards retrieved successfully",
-		"cards":   cards,
+		"cards":   views,
+	})
+}
+
+func (ctrl *CardController) ResolveRecipient(c echo.Context) error {
+	type request struct {
+		RecipientPAN entity.PAN `json:"recipient_pan" validate:"required,len=16,numeric"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	if err := req.RecipientPAN.Validate(); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid recipient card number"})
+	}
+
+	recipient, err := ctrl.cardService.ResolveRecipient(c.Request().Context(), req.RecipientPAN)
+	if errors.Is(err, entity.ErrCardNotFound) || errors.Is(err, entity.ErrCardNotActive) {
+		return c.JSON(404, map[string]string{"error": "Recipient card not found"})
+	}
+	if errors.Is(err, entity.ErrCardExpired) {
+		return c.JSON(422, map[string]string{"error": "Recipient card is expired"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve recipient"})
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"recipient": recipient,
 	})
 }
 
 func (ctrl *CardController) Transfer(c echo.Context) error {
 	type request struct {
-		CardID          int32           `json:"card_id" validate:"required"`
-		RecipientCardID int32           `json:"recipient_card_id" validate:"required"`
-		Amount          decimal.Decimal `json:"amount" validate:"required"`
+		CardToken    entity.CardToken `json:"card_token" validate:"required"`
+		RecipientPAN entity.PAN       `json:"recipient_pan" validate:"required,len=16,numeric"`
+		Amount       decimal.Decimal  `json:"amount" validate:"required"`
 	}
 
 	var req request
@@ -110,7 +181,15 @@ func (ctrl *CardController) Transfer(c echo.Context) error {
 		return c.JSON(500, map[string]string{"error": "Failed to retrieve account"})
 	}
 
-	card, err := ctrl.cardService.FindByID(c.Request().Context(), req.CardID)
+	cardID, err := ctrl.tokenVault.CardID(c.Request().Context(), req.CardToken)
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	card, err := ctrl.cardService.FindByID(c.Request().Context(), cardID)
 	if err != nil {
 		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
 	}
@@ -119,10 +198,274 @@ func (ctrl *CardController) Transfer(c echo.Context) error {
 		return c.JSON(403, map[string]string{"error": "Unauthorized card access"})
 	}
 
-	err = ctrl.cardService.Transfer(c.Request().Context(), req.CardID, req.RecipientCardID, req.Amount)
+	if err := req.RecipientPAN.Validate(); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid recipient card number"})
+	}
+
+	recipient, err := ctrl.cardService.TransferByPAN(c.Request().Context(), card.ID, req.RecipientPAN, req.Amount)
+	if errors.Is(err, entity.ErrCardNotFound) {
+		return c.JSON(404, map[string]string{"error": "Recipient card not found"})
+	}
+	if errors.Is(err, entity.ErrCardExpired) {
+		return c.JSON(422, map[string]string{"error": "Card is expired"})
+	}
 	if err != nil {
 		return c.JSON(500, map[string]string{"error": "Transfer failed"})
 	}
 
-	return c.JSON(200, map[string]string{"message": "Transfer successful"})
+	return c.JSON(200, map[string]interface{}{
+		"message":   "Transfer successful",
+		"recipient": recipient,
+	})
+}
+
+func (ctrl *CardController) Payment(c echo.Context) error {
+	type request struct {
+		CardToken entity.CardToken `json:"card_token" validate:"required"`
+		Amount    decimal.Decimal  `json:"amount" validate:"required"`
+		Merchant  struct {
+			Name    string     `json:"name" validate:"required"`
+			MCC     entity.MCC `json:"mcc" validate:"required,len=4,numeric"`
+			Country string     `json:"country" validate:"required,len=2"`
+			Online  bool       `json:"online"`
+		} `json:"merchant" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	account, err := ctrl.accountService.GetAccountByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve account"})
+	}
+
+	cardID, err := ctrl.tokenVault.CardID(c.Request().Context(), req.CardToken)
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	card, err := ctrl.cardService.FindByID(c.Request().Context(), cardID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	if card.AccountID != account.ID {
+		return c.JSON(403, map[string]string{"error": "Unauthorized card access"})
+	}
+
+	merchant := &entity.Merchant{
+		Name:    req.Merchant.Name,
+		MCC:     req.Merchant.MCC,
+		Country: strings.ToUpper(req.Merchant.Country),
+		Online:  req.Merchant.Online,
+	}
+
+	err = ctrl.cardService.Withdraw(c.Request().Context(), card.ID, req.Amount, merchant)
+	var violation *entity.CardRuleViolationError
+	if errors.As(err, &violation) {
+		return c.JSON(422, map[string]string{"error": "Operation rejected by card rules", "reason": string(violation.Reason)})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Payment failed"})
+	}
+
+	return c.JSON(200, map[string]string{"message": "Payment successful"})
+}
+
+func (ctrl *CardController) GetRules(c echo.Context) error {
+	type request struct {
+		CardToken entity.CardToken `param:"card_token" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	account, err := ctrl.accountService.GetAccountByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve account"})
+	}
+
+	cardID, err := ctrl.tokenVault.CardID(c.Request().Context(), req.CardToken)
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	card, err := ctrl.cardService.FindByID(c.Request().Context(), cardID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	if card.AccountID != account.ID {
+		return c.JSON(403, map[string]string{"error": "Unauthorized card access"})
+	}
+
+	rules, err := ctrl.cardService.GetRules(c.Request().Context(), card.ID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card rules"})
+	}
+
+	return c.JSON(200, rules)
+}
+
+func (ctrl *CardController) SetRules(c echo.Context) error {
+	type request struct {
+		CardToken        entity.CardToken                    `param:"card_token" validate:"required"`
+		BlockedMCCGroups []entity.MCCGroup                   `json:"blocked_mcc_groups"`
+		OnlineOnly       bool                                `json:"online_only"`
+		DomesticOnly     bool                                `json:"domestic_only"`
+		MonthlyLimits    map[entity.MCCGroup]decimal.Decimal `json:"monthly_limits"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	account, err := ctrl.accountService.GetAccountByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve account"})
+	}
+
+	cardID, err := ctrl.tokenVault.CardID(c.Request().Context(), req.CardToken)
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	card, err := ctrl.cardService.FindByID(c.Request().Context(), cardID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	if card.AccountID != account.ID {
+		return c.JSON(403, map[string]string{"error": "Unauthorized card access"})
+	}
+
+	rules := &entity.CardRules{
+		CardID:           card.ID,
+		BlockedMCCGroups: req.BlockedMCCGroups,
+		OnlineOnly:       req.OnlineOnly,
+		DomesticOnly:     req.DomesticOnly,
+		MonthlyLimits:    req.MonthlyLimits,
+	}
+	if err := rules.Validate(); err != nil {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+
+	rules, err = ctrl.cardService.SetRules(c.Request().Context(), rules)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to save card rules"})
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"message": "Card rules updated successfully",
+		"rules":   rules,
+	})
+}
+
+// ListTransactions возвращает историю операций по карте с фильтрами по периоду, типу и статусу,
+// сортировкой и постраничной выборкой по курсору. Период задается в формате RFC 3339, граница to не включается.
+func (ctrl *CardController) ListTransactions(c echo.Context) error {
+	type request struct {
+		CardToken entity.CardToken           `param:"card_token" validate:"required"`
+		From      time.Time                  `query:"from"`
+		To        time.Time                  `query:"to"`
+		Types     []entity.TransactionType   `query:"type"`
+		Statuses  []entity.TransactionStatus `query:"status"`
+		Sort      entity.CardTransactionSort `query:"sort"`
+		Order     entity.SortOrder           `query:"order"`
+		Cursor    string                     `query:"cursor"`
+		Limit     int                        `query:"limit" validate:"gte=0,lte=100"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	account, err := ctrl.accountService.GetAccountByUserID(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve account"})
+	}
+
+	cardID, err := ctrl.tokenVault.CardID(c.Request().Context(), req.CardToken)
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	card, err := ctrl.cardService.FindByID(c.Request().Context(), cardID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card"})
+	}
+
+	if card.AccountID != account.ID {
+		return c.JSON(403, map[string]string{"error": "Unauthorized card access"})
+	}
+
+	filter := entity.CardTransactionFilter{
+		CardID:   card.ID,
+		From:     req.From,
+		To:       req.To,
+		Types:    req.Types,
+		Statuses: req.Statuses,
+		Sort:     req.Sort,
+		Order:    req.Order,
+	}
+
+	page, err := ctrl.cardService.ListTransactions(c.Request().Context(), filter, req.Cursor, req.Limit)
+	if errors.Is(err, entity.ErrInvalidTransactionFilter) || errors.Is(err, entity.ErrInvalidCursor) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to retrieve card transactions"})
+	}
+
+	for i, transaction := range page.Transactions {
+		if transaction.CounterpartyCardID == nil {
+			continue
+		}
+
+		token, err := ctrl.tokenVault.Tokenize(c.Request().Context(), &entity.Card{ID: *transaction.CounterpartyCardID})
+		if err != nil {
+			return c.JSON(500, map[string]string{"error": "Card tokenization failed"})
+		}
+		page.Transactions[i].CounterpartyToken = token
+	}
+
+	return c.JSON(200, page)
 }
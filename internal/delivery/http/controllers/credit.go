@@ -1,9 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
 	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
 	"github.com/labstack/echo/v4"
 	"github.com/shopspring/decimal"
+	"time"
 )
 
 type CreditController struct {
@@ -16,10 +21,21 @@ func NewCreditController(creditService *bank.CreditService) *CreditController {
 	}
 }
 
-func (ctrl *CreditController) Create(c echo.Context) error {
+// List возвращает кредиты текущего пользователя.
+func (ctrl *CreditController) List(c echo.Context) error {
+	userID := c.Get("user_id").(int32)
+	credits, err := ctrl.creditService.ListCredits(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to list credits"})
+	}
+
+	return c.JSON(200, credits)
+}
+
+// Get возвращает кредит текущего пользователя с задолженностью и историей платежей.
+func (ctrl *CreditController) Get(c echo.Context) error {
 	type request struct {
-		Principal decimal.Decimal `json:"principal" validate:"required"`
-		Term      int32           `json:"term" validate:"required"`
+		CreditID int32 `param:"credit_id" validate:"required"`
 	}
 
 	var req request
@@ -32,15 +48,62 @@ func (ctrl *CreditController) Create(c echo.Context) error {
 	}
 
 	userID := c.Get("user_id").(int32)
-	credit, err := ctrl.creditService.Create(c.Request().Context(), userID, req.Principal, req.Term)
+	credit, err := ctrl.creditService.GetCredit(c.Request().Context(), userID, req.CreditID, time.Now().UTC())
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get credit"})
+	}
+
+	return c.JSON(200, credit)
+}
+
+// GetAgreement отдает кредитный договор текущего пользователя в формате pdf (по умолчанию) или html.
+// Контрольная сумма SHA-256 документа передается в заголовках ETag и X-Content-SHA256, подпись банка —
+// в заголовке X-Content-Signature.
+func (ctrl *CreditController) GetAgreement(c echo.Context) error {
+	type request struct {
+		CreditID int32  `param:"credit_id" validate:"required"`
+		Format   string `query:"format"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	format := entity.PDFDocument
+	if req.Format != "" {
+		format = entity.DocumentFormat(req.Format)
+	}
+	if err := format.Validate(); err != nil {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+
+	userID := c.Get("user_id").(int32)
+	agreement, err := ctrl.creditService.GetAgreement(c.Request().Context(), userID, req.CreditID, format)
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if errors.Is(err, entity.ErrAgreementNotFound) {
+		return c.JSON(404, map[string]string{"error": "Agreement not found"})
+	}
 	if err != nil {
-		return c.JSON(500, map[string]string{"error": "Credit creation failed"})
+		return c.JSON(500, map[string]string{"error": "Failed to get agreement"})
 	}
 
-	return c.JSON(200, map[string]interface{}{
-		"message": "Credit created successfully",
-		"credit":  credit,
-	})
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"credit-agreement-%d.%s\"", agreement.CreditID, agreement.Format))
+	c.Response().Header().Set("ETag", fmt.Sprintf("%q", agreement.ContentHash))
+	c.Response().Header().Set("X-Content-SHA256", agreement.ContentHash)
+	if agreement.Signature != "" {
+		c.Response().Header().Set("X-Content-Signature", agreement.Signature)
+	}
+	return c.Blob(200, agreement.Format.ContentType(), agreement.Content)
 }
 
 func (ctrl *CreditController) GetCreditSchedule(c echo.Context) error {
@@ -57,10 +120,315 @@ func (ctrl *CreditController) GetCreditSchedule(c echo.Context) error {
 		return c.JSON(400, map[string]string{"error": "Validation failed"})
 	}
 
-	schedule, err := ctrl.creditService.GetPaymentSchedule(c.Request().Context(), req.CreditID)
+	userID := c.Get("user_id").(int32)
+	schedule, err := ctrl.creditService.GetPaymentSchedule(c.Request().Context(), userID, req.CreditID)
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
 	if err != nil {
 		return c.JSON(500, map[string]string{"error": "Failed to get credit schedule"})
 	}
 
 	return c.JSON(200, schedule)
 }
+
+// RepayEarly досрочно погашает часть или весь кредит и возвращает пересчитанный график платежей.
+func (ctrl *CreditController) RepayEarly(c echo.Context) error {
+	type request struct {
+		CreditID int32                `param:"credit_id" validate:"required"`
+		Amount   decimal.Decimal      `json:"amount" validate:"required"`
+		Mode     entity.RepaymentMode `json:"mode" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	if err := req.Mode.Validate(); err != nil {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+
+	userID := c.Get("user_id").(int32)
+	schedule, err := ctrl.creditService.RepayEarly(c.Request().Context(), userID, req.CreditID, req.Amount, req.Mode, time.Now().UTC())
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+
+	for _, rejected := range []error{
+		entity.ErrCreditNotActive,
+		entity.ErrOutstandingPayments,
+		entity.ErrInvalidRepaymentAmount,
+		entity.ErrInsufficientFunds,
+	} {
+		if errors.Is(err, rejected) {
+			return c.JSON(422, map[string]string{"error": rejected.Error()})
+		}
+	}
+
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Early repayment failed"})
+	}
+
+	return c.JSON(200, schedule)
+}
+
+// WaivePenalty списывает непогашенный штраф по платежу. Доступно только сотрудникам.
+func (ctrl *CreditController) WaivePenalty(c echo.Context) error {
+	type request struct {
+		CreditID  int32 `param:"credit_id" validate:"required"`
+		PaymentID int32 `param:"payment_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	adminID := c.Get("user_id").(int32)
+	payment, err := ctrl.creditService.WaivePenalty(c.Request().Context(), adminID, req.CreditID, req.PaymentID, time.Now().UTC())
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if errors.Is(err, entity.ErrPaymentNotFound) {
+		return c.JSON(404, map[string]string{"error": "Payment not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Penalty waiver failed"})
+	}
+
+	return c.JSON(200, payment)
+}
+
+// Quote рассчитывает график платежей, сумму процентов и полную стоимость кредита без его оформления.
+func (ctrl *CreditController) Quote(c echo.Context) error {
+	type request struct {
+		Principal decimal.Decimal    `json:"principal" validate:"required"`
+		Term      int32              `json:"term" validate:"required,gt=0"`
+		Fees      []entity.CreditFee `json:"fees"`
+		// ScheduleType задает способ погашения: annuity (по умолчанию) или differentiated.
+		ScheduleType amortization.Method `json:"schedule_type"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	if !req.Principal.IsPositive() {
+		return c.JSON(400, map[string]string{"error": "Principal must be positive"})
+	}
+
+	var opts []bank.CreditOption
+	if req.ScheduleType != "" {
+		if err := req.ScheduleType.Validate(); err != nil {
+			return c.JSON(400, map[string]string{"error": err.Error()})
+		}
+		opts = append(opts, bank.WithScheduleType(req.ScheduleType))
+	}
+
+	quote, err := ctrl.creditService.Quote(c.Request().Context(), req.Principal, req.Term, req.Fees, time.Now().UTC(), opts...)
+	if errors.Is(err, entity.ErrInvalidCreditFee) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Credit quote failed"})
+	}
+
+	return c.JSON(200, quote)
+}
+
+// RequestRestructuring принимает заявку на кредитные каникулы. График перестраивается после одобрения сотрудником.
+func (ctrl *CreditController) RequestRestructuring(c echo.Context) error {
+	type request struct {
+		CreditID int32                    `param:"credit_id" validate:"required"`
+		Type     entity.RestructuringType `json:"type" validate:"required"`
+		Months   int32                    `json:"months" validate:"required"`
+		Reason   string                   `json:"reason"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	restructuring, err := ctrl.creditService.RequestRestructuring(c.Request().Context(), userID, req.CreditID, req.Type, req.Months, req.Reason)
+	if errors.Is(err, entity.ErrInvalidRestructuring) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if errors.Is(err, entity.ErrCreditNotActive) {
+		return c.JSON(422, map[string]string{"error": entity.ErrCreditNotActive.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Restructuring request failed"})
+	}
+
+	return c.JSON(200, restructuring)
+}
+
+// GetScheduleVersions возвращает прежние версии графика платежей по кредиту текущего пользователя.
+func (ctrl *CreditController) GetScheduleVersions(c echo.Context) error {
+	type request struct {
+		CreditID int32 `param:"credit_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	versions, err := ctrl.creditService.GetScheduleVersions(c.Request().Context(), userID, req.CreditID)
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get schedule versions"})
+	}
+
+	return c.JSON(200, versions)
+}
+
+// GetRateResets возвращает пересмотры плавающей ставки по кредиту текущего пользователя.
+func (ctrl *CreditController) GetRateResets(c echo.Context) error {
+	type request struct {
+		CreditID int32 `param:"credit_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	resets, err := ctrl.creditService.GetRateResets(c.Request().Context(), userID, req.CreditID)
+	if errors.Is(err, entity.ErrCreditNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get rate resets"})
+	}
+
+	return c.JSON(200, resets)
+}
+
+// ListRestructurings возвращает заявки на реструктуризацию, по умолчанию ожидающие решения. Доступно только сотрудникам.
+func (ctrl *CreditController) ListRestructurings(c echo.Context) error {
+	type request struct {
+		Status entity.RestructuringStatus `query:"status"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if req.Status == "" {
+		req.Status = entity.RestructuringRequested
+	}
+
+	restructurings, err := ctrl.creditService.ListRestructurings(c.Request().Context(), req.Status)
+	if errors.Is(err, entity.ErrInvalidRestructuring) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to list restructurings"})
+	}
+
+	return c.JSON(200, restructurings)
+}
+
+// ApproveRestructuring одобряет заявку и перестраивает график платежей. Доступно только сотрудникам.
+func (ctrl *CreditController) ApproveRestructuring(c echo.Context) error {
+	type request struct {
+		RestructuringID int32  `param:"restructuring_id" validate:"required"`
+		Note            string `json:"note"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	adminID := c.Get("user_id").(int32)
+	restructuring, err := ctrl.creditService.ApproveRestructuring(c.Request().Context(), adminID, req.RestructuringID, req.Note)
+
+	return restructuringResponse(c, restructuring, err)
+}
+
+// RejectRestructuring отклоняет заявку с комментарием. Доступно только сотрудникам.
+func (ctrl *CreditController) RejectRestructuring(c echo.Context) error {
+	type request struct {
+		RestructuringID int32  `param:"restructuring_id" validate:"required"`
+		Note            string `json:"note" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	adminID := c.Get("user_id").(int32)
+	restructuring, err := ctrl.creditService.RejectRestructuring(c.Request().Context(), adminID, req.RestructuringID, req.Note)
+
+	return restructuringResponse(c, restructuring, err)
+}
+
+func restructuringResponse(c echo.Context, restructuring *entity.Restructuring, err error) error {
+	if errors.Is(err, entity.ErrRestructuringNotFound) {
+		return c.JSON(404, map[string]string{"error": "Restructuring not found"})
+	}
+
+	for _, rejected := range []error{
+		entity.ErrRestructuringNotRequested,
+		entity.ErrCreditNotActive,
+		entity.ErrNothingToRestructure,
+		entity.ErrDecisionReasonRequired,
+	} {
+		if errors.Is(err, rejected) {
+			return c.JSON(422, map[string]string{"error": rejected.Error()})
+		}
+	}
+
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Restructuring decision failed"})
+	}
+
+	return c.JSON(200, restructuring)
+}
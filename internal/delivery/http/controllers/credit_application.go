@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/amortization"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+type CreditApplicationController struct {
+	applicationService *bank.CreditApplicationService
+}
+
+func NewCreditApplicationController(applicationService *bank.CreditApplicationService) *CreditApplicationController {
+	return &CreditApplicationController{
+		applicationService: applicationService,
+	}
+}
+
+// Submit принимает заявку на кредит. Если скоринг одобрил заявку, кредит выдается сразу.
+func (ctrl *CreditApplicationController) Submit(c echo.Context) error {
+	type request struct {
+		AccountID     int32           `json:"account_id" validate:"required"`
+		Principal     decimal.Decimal `json:"principal" validate:"required"`
+		Term          int32           `json:"term" validate:"required"`
+		MonthlyIncome decimal.Decimal `json:"monthly_income" validate:"required"`
+		// ScheduleType задает способ погашения: annuity (по умолчанию) или differentiated.
+		ScheduleType amortization.Method `json:"schedule_type"`
+		// RateType задает вид ставки: fixed (по умолчанию) или floating.
+		RateType entity.RateType `json:"rate_type"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	if req.ScheduleType == "" {
+		req.ScheduleType = amortization.Annuity
+	}
+	if req.RateType == "" {
+		req.RateType = entity.FixedRate
+	}
+
+	userID := c.Get("user_id").(int32)
+	application, err := ctrl.applicationService.Submit(
+		c.Request().Context(),
+		userID,
+		req.AccountID,
+		req.Principal,
+		req.Term,
+		req.ScheduleType,
+		req.RateType,
+		req.MonthlyIncome,
+	)
+	if errors.Is(err, entity.ErrAccountAccessDenied) {
+		return c.JSON(403, map[string]string{"error": "Unauthorized account access"})
+	}
+	if errors.Is(err, entity.ErrInvalidCreditApplication) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Credit application failed"})
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"message":     "Credit application submitted",
+		"application": application,
+	})
+}
+
+// Get возвращает заявку текущего пользователя вместе с решениями по ней.
+func (ctrl *CreditApplicationController) Get(c echo.Context) error {
+	type request struct {
+		ApplicationID int32 `param:"application_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	application, decisions, err := ctrl.applicationService.Get(c.Request().Context(), userID, req.ApplicationID)
+
+	return applicationResponse(c, application, decisions, err)
+}
+
+// GetForReview возвращает любую заявку вместе с решениями по ней. Доступно только сотрудникам.
+func (ctrl *CreditApplicationController) GetForReview(c echo.Context) error {
+	type request struct {
+		ApplicationID int32 `param:"application_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	application, decisions, err := ctrl.applicationService.GetForReview(c.Request().Context(), req.ApplicationID)
+
+	return applicationResponse(c, application, decisions, err)
+}
+
+// List возвращает заявки в указанном статусе, по умолчанию ожидающие рассмотрения. Доступно только сотрудникам.
+func (ctrl *CreditApplicationController) List(c echo.Context) error {
+	type request struct {
+		Status entity.CreditApplicationStatus `query:"status"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if req.Status == "" {
+		req.Status = entity.ApplicationUnderReview
+	}
+
+	applications, err := ctrl.applicationService.List(c.Request().Context(), req.Status)
+	if errors.Is(err, entity.ErrInvalidCreditApplication) {
+		return c.JSON(400, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to list credit applications"})
+	}
+
+	return c.JSON(200, applications)
+}
+
+// Approve одобряет заявку и выдает по ней кредит. Доступно только сотрудникам.
+func (ctrl *CreditApplicationController) Approve(c echo.Context) error {
+	type request struct {
+		ApplicationID int32    `param:"application_id" validate:"required"`
+		Reasons       []string `json:"reasons"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	adminID := c.Get("user_id").(int32)
+	application, err := ctrl.applicationService.Approve(c.Request().Context(), adminID, req.ApplicationID, req.Reasons)
+
+	return decisionResponse(c, application, err)
+}
+
+// Reject отклоняет заявку с указанием причин. Доступно только сотрудникам.
+func (ctrl *CreditApplicationController) Reject(c echo.Context) error {
+	type request struct {
+		ApplicationID int32    `param:"application_id" validate:"required"`
+		Reasons       []string `json:"reasons" validate:"required,min=1"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	adminID := c.Get("user_id").(int32)
+	application, err := ctrl.applicationService.Reject(c.Request().Context(), adminID, req.ApplicationID, req.Reasons)
+
+	return decisionResponse(c, application, err)
+}
+
+func applicationResponse(c echo.Context, application *entity.CreditApplication, decisions []entity.CreditDecision, err error) error {
+	if errors.Is(err, entity.ErrCreditApplicationNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit application not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get credit application"})
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"application": application,
+		"decisions":   decisions,
+	})
+}
+
+func decisionResponse(c echo.Context, application *entity.CreditApplication, err error) error {
+	if errors.Is(err, entity.ErrCreditApplicationNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit application not found"})
+	}
+
+	for _, rejected := range []error{
+		entity.ErrInvalidApplicationStatus,
+		entity.ErrDecisionReasonRequired,
+	} {
+		if errors.Is(err, rejected) {
+			return c.JSON(422, map[string]string{"error": rejected.Error()})
+		}
+	}
+
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Credit decision failed"})
+	}
+
+	return c.JSON(200, application)
+}
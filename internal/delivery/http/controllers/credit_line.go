@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type CreditLineController struct {
+	creditLineService *bank.CreditLineService
+}
+
+func NewCreditLineController(creditLineService *bank.CreditLineService) *CreditLineController {
+	return &CreditLineController{
+		creditLineService: creditLineService,
+	}
+}
+
+// Open открывает пользователю кредитный счет с возобновляемой кредитной линией. Доступно только сотрудникам.
+func (ctrl *CreditLineController) Open(c echo.Context) error {
+	type request struct {
+		UserID       int32           `json:"user_id" validate:"required"`
+		CreditLimit  decimal.Decimal `json:"credit_limit" validate:"required"`
+		InterestRate *float64        `json:"interest_rate"`
+		GraceDays    *int32          `json:"grace_days"`
+		StatementDay *int32          `json:"statement_day"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	var opts []bank.CreditLineOption
+	if req.InterestRate != nil {
+		opts = append(opts, bank.WithCreditLineRate(decimal.NewFromFloat(*req.InterestRate)))
+	}
+	if req.GraceDays != nil {
+		opts = append(opts, bank.WithGraceDays(*req.GraceDays))
+	}
+	if req.StatementDay != nil {
+		opts = append(opts, bank.WithStatementDay(*req.StatementDay))
+	}
+
+	line, err := ctrl.creditLineService.Open(c.Request().Context(), req.UserID, req.CreditLimit, time.Now().UTC(), opts...)
+	if errors.Is(err, entity.ErrInvalidCreditLine) {
+		return c.JSON(422, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to open credit line"})
+	}
+
+	return c.JSON(200, line)
+}
+
+// Get возвращает кредитную линию текущего пользователя с долгом и доступным остатком.
+func (ctrl *CreditLineController) Get(c echo.Context) error {
+	type request struct {
+		AccountID int32 `param:"account_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	line, err := ctrl.creditLineService.Get(c.Request().Context(), userID, req.AccountID)
+	if errors.Is(err, entity.ErrCreditLineNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit line not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get credit line"})
+	}
+
+	return c.JSON(200, line)
+}
+
+// ListStatements возвращает выписки по кредитной линии текущего пользователя.
+func (ctrl *CreditLineController) ListStatements(c echo.Context) error {
+	type request struct {
+		AccountID int32 `param:"account_id" validate:"required"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	userID := c.Get("user_id").(int32)
+	statements, err := ctrl.creditLineService.ListStatements(c.Request().Context(), userID, req.AccountID)
+	if errors.Is(err, entity.ErrCreditLineNotFound) {
+		return c.JSON(404, map[string]string{"error": "Credit line not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to list statements"})
+	}
+
+	return c.JSON(200, statements)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+	"time"
+)
+
+type KeyRateController struct {
+	keyRateProvider *bank.KeyRateProvider
+}
+
+func NewKeyRateController(keyRateProvider *bank.KeyRateProvider) *KeyRateController {
+	return &KeyRateController{
+		keyRateProvider: keyRateProvider,
+	}
+}
+
+// Get возвращает текущую ключевую ставку ЦБ, по которой оцениваются кредиты, и историю ставок с даты from
+// (по умолчанию за год до to) по дату to (по умолчанию сегодня). Если актуальной ставки нет, current равен null.
+func (ctrl *KeyRateController) Get(c echo.Context) error {
+	type request struct {
+		From string `query:"from"`
+		To   string `query:"to"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	now := time.Now().UTC()
+	to, err := parseReportDate(req.To, now)
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid to date"})
+	}
+
+	from, err := parseReportDate(req.From, to.AddDate(-1, 0, 0))
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid from date"})
+	}
+
+	history, err := ctrl.keyRateProvider.History(c.Request().Context(), from, to)
+	if errors.Is(err, entity.ErrInvalidKeyRatePeriod) {
+		return c.JSON(422, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to get key rate history"})
+	}
+
+	current, err := ctrl.keyRateProvider.KeyRate(c.Request().Context(), now)
+	if err != nil && !errors.Is(err, entity.ErrKeyRateStale) && !errors.Is(err, entity.ErrKeyRateNotFound) {
+		return c.JSON(500, map[string]string{"error": "Failed to get key rate"})
+	}
+
+	return c.JSON(200, map[string]interface{}{
+		"current": current,
+		"history": history,
+	})
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/report"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+	"time"
+)
+
+type ReportController struct {
+	reportService *bank.ReportService
+}
+
+func NewReportController(reportService *bank.ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// Portfolio возвращает распределение кредитного портфеля по корзинам просрочки на дату as_of (по умолчанию сегодня).
+// При format=csv отчет отдается файлом CSV. Доступно только сотрудникам.
+func (ctrl *ReportController) Portfolio(c echo.Context) error {
+	type request struct {
+		AsOf   string `query:"as_of"`
+		Format string `query:"format" validate:"omitempty,oneof=json csv"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	asOf, err := parseReportDate(req.AsOf, time.Now().UTC())
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid as_of date"})
+	}
+
+	portfolio, err := ctrl.reportService.Portfolio(c.Request().Context(), asOf)
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to build portfolio report"})
+	}
+
+	if req.Format == "csv" {
+		var buf bytes.Buffer
+		if err := report.WritePortfolioCSV(&buf, portfolio); err != nil {
+			return c.JSON(500, map[string]string{"error": "Failed to build portfolio report"})
+		}
+		return csvAttachment(c, fmt.Sprintf("portfolio-%s.csv", asOf.Format(time.DateOnly)), buf.Bytes())
+	}
+
+	return c.JSON(200, portfolio)
+}
+
+// RollRates возвращает миграцию кредитов между корзинами просрочки с даты from (по умолчанию за месяц до as_of)
+// по дату as_of (по умолчанию сегодня). При format=csv отчет отдается файлом CSV. Доступно только сотрудникам.
+func (ctrl *ReportController) RollRates(c echo.Context) error {
+	type request struct {
+		From   string `query:"from"`
+		AsOf   string `query:"as_of"`
+		Format string `query:"format" validate:"omitempty,oneof=json csv"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	asOf, err := parseReportDate(req.AsOf, time.Now().UTC())
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid as_of date"})
+	}
+
+	from, err := parseReportDate(req.From, asOf.AddDate(0, -1, 0))
+	if err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid from date"})
+	}
+
+	rates, err := ctrl.reportService.RollRates(c.Request().Context(), from, asOf)
+	if errors.Is(err, entity.ErrInvalidReportPeriod) {
+		return c.JSON(422, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Failed to build roll rates report"})
+	}
+
+	if req.Format == "csv" {
+		var buf bytes.Buffer
+		if err := report.WriteRollRatesCSV(&buf, rates); err != nil {
+			return c.JSON(500, map[string]string{"error": "Failed to build roll rates report"})
+		}
+		return csvAttachment(c, fmt.Sprintf("roll-rates-%s.csv", asOf.Format(time.DateOnly)), buf.Bytes())
+	}
+
+	return c.JSON(200, rates)
+}
+
+// parseReportDate разбирает дату в формате YYYY-MM-DD или возвращает fallback, если дата не указана.
+func parseReportDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
+// csvAttachment отдает содержимое content файлом CSV с именем filename.
+func csvAttachment(c echo.Context, filename string, content []byte) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(200, "text/csv; charset=utf-8", content)
+}
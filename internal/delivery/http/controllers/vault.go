@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/MaxFando/bank-system/internal/core/bank/entity"
+	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
+	"github.com/labstack/echo/v4"
+)
+
+// vaultKeyHeader заголовок, в котором внешняя система передает ключ доступа к хранилищу токенов.
+const vaultKeyHeader = "X-Vault-Key"
+
+type VaultController struct {
+	tokenVault *bank.TokenVault
+}
+
+func NewVaultController(tokenVault *bank.TokenVault) *VaultController {
+	return &VaultController{
+		tokenVault: tokenVault,
+	}
+}
+
+// Detokenize раскрывает номер карты по токену для систем, предъявивших ключ доступа к хранилищу.
+func (ctrl *VaultController) Detokenize(c echo.Context) error {
+	type request struct {
+		Token entity.CardToken `json:"token" validate:"required,len=16,numeric"`
+	}
+
+	var req request
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Invalid request"})
+	}
+
+	if err := c.Validate(req); err != nil {
+		return c.JSON(400, map[string]string{"error": "Validation failed"})
+	}
+
+	pan, err := ctrl.tokenVault.Detokenize(c.Request().Context(), req.Token, c.Request().Header.Get(vaultKeyHeader))
+	if errors.Is(err, entity.ErrVaultAccessDenied) {
+		return c.JSON(403, map[string]string{"error": "Vault access denied"})
+	}
+	if errors.Is(err, entity.ErrCardTokenNotFound) {
+		return c.JSON(404, map[string]string{"error": "Card token not found"})
+	}
+	if err != nil {
+		return c.JSON(500, map[string]string{"error": "Detokenization failed"})
+	}
+
+	return c.JSON(200, map[string]string{"pan": pan.String()})
+}
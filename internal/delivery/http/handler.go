@@ -28,13 +28,59 @@ func NewHandler(ctx context.Context, provider *providers.ServiceProvider) *echo.
 	accountController := controllers.NewAccountController(provider.AccountService)
 	echoMainServer.POST("/accounts", accountController.CreateAccount, echo.WrapMiddleware(auth.AuthMiddleware))
 
-	cardController := controllers.NewCardController(provider.AccountService, provider.CardService)
+	cardController := controllers.NewCardController(provider.AccountService, provider.CardService, provider.TokenVault)
 	echoMainServer.POST("/cards", cardController.CreateCard, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.POST("/cards/transfer/recipient", cardController.ResolveRecipient, echo.WrapMiddleware(auth.AuthMiddleware))
 	echoMainServer.POST("/cards/transfer", cardController.Transfer, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.POST("/cards/payment", cardController.Payment, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/cards/:card_token/rules", cardController.GetRules, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.PUT("/cards/:card_token/rules", cardController.SetRules, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/cards/:card_token/transactions", cardController.ListTransactions, echo.WrapMiddleware(auth.AuthMiddleware))
+
+	vaultController := controllers.NewVaultController(provider.TokenVault)
+	echoMainServer.POST("/vault/detokenize", vaultController.Detokenize)
 
 	creditController := controllers.NewCreditController(provider.CreditService)
-	echoMainServer.POST("/credits", creditController.Create, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits", creditController.List, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits/:credit_id", creditController.Get, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.POST("/credits/quote", creditController.Quote, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits/:credit_id/agreement", creditController.GetAgreement, echo.WrapMiddleware(auth.AuthMiddleware))
 	echoMainServer.GET("/credits/:credit_id/schedule", creditController.GetCreditSchedule, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.POST("/credits/:credit_id/repayment", creditController.RepayEarly, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.POST("/credits/:credit_id/restructuring", creditController.RequestRestructuring, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits/:credit_id/schedule/versions", creditController.GetScheduleVersions, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits/:credit_id/rate-resets", creditController.GetRateResets, echo.WrapMiddleware(auth.AuthMiddleware))
+
+	keyRateController := controllers.NewKeyRateController(provider.KeyRateProvider)
+	echoMainServer.GET("/rates/key", keyRateController.Get, echo.WrapMiddleware(auth.AuthMiddleware))
+
+	applicationController := controllers.NewCreditApplicationController(provider.CreditApplicationService)
+	echoMainServer.POST("/credits", applicationController.Submit, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credits/applications/:application_id", applicationController.Get, echo.WrapMiddleware(auth.AuthMiddleware))
+
+	creditLineController := controllers.NewCreditLineController(provider.CreditLineService)
+	echoMainServer.GET("/credit-lines/:account_id", creditLineController.Get, echo.WrapMiddleware(auth.AuthMiddleware))
+	echoMainServer.GET("/credit-lines/:account_id/statements", creditLineController.ListStatements, echo.WrapMiddleware(auth.AuthMiddleware))
+
+	admin := echoMainServer.Group("/admin", echo.WrapMiddleware(auth.AuthMiddleware), echo.WrapMiddleware(auth.AdminMiddleware))
+	admin.GET("/credits/applications", applicationController.List)
+	admin.GET("/credits/applications/:application_id", applicationController.GetForReview)
+	admin.POST("/credits/applications/:application_id/approve", applicationController.Approve)
+	admin.POST("/credits/applications/:application_id/reject", applicationController.Reject)
+	admin.POST("/credits/:credit_id/payments/:payment_id/penalty/waive", creditController.WaivePenalty)
+	admin.GET("/credits/restructurings", creditController.ListRestructurings)
+	admin.POST("/credits/restructurings/:restructuring_id/approve", creditController.ApproveRestructuring)
+	admin.POST("/credits/restructurings/:restructuring_id/reject", creditController.RejectRestructuring)
+	admin.POST("/credit-lines", creditLineController.Open)
+
+	reportController := controllers.NewReportController(provider.ReportService)
+	admin.GET("/reports/portfolio", reportController.Portfolio)
+	admin.GET("/reports/roll-rates", reportController.RollRates)
+
+	bureauController := controllers.NewBureauController(provider.BureauService)
+	admin.GET("/bureau/submissions", bureauController.List)
+	admin.POST("/bureau/submissions", bureauController.Export)
+	admin.GET("/bureau/submissions/:period", bureauController.Get)
 
 	return echoMainServer
 }
@@ -14,6 +14,20 @@ type RepositoryProvider struct {
 	cardRepository        *bank.CardRepository
 	creditRepository      *bank.CreditRepository
 	transactionRepository *bank.CardTransactionRepository
+	cardRuleRepository    *bank.CardRuleRepository
+	cardTokenRepository   *bank.CardTokenRepository
+
+	financialTransactionRepository *bank.FinancialTransactionRepository
+	creditApplicationRepository    *bank.CreditApplicationRepository
+	scoringRepository              *bank.ScoringRepository
+	restructuringRepository        *bank.RestructuringRepository
+	creditLineRepository           *bank.CreditLineRepository
+	agreementRepository            *bank.AgreementRepository
+	reportRepository               *bank.ReportRepository
+	bureauRepository               *bank.BureauRepository
+	floatingRateRepository         *bank.FloatingRateRepository
+
+	notificationRepository *bank.NotificationRepository
 }
 
 func NewRepositoryProvider(db sqlext.DB) *RepositoryProvider {
@@ -28,4 +42,16 @@ func (p *RepositoryProvider) RegisterDependency() {
 	p.cardRepository = bank.NewCardRepository(p.db)
 	p.creditRepository = bank.NewCreditRepository(p.db)
 	p.transactionRepository = bank.NewCardTransactionRepository(p.db)
+	p.cardRuleRepository = bank.NewCardRuleRepository(p.db)
+	p.cardTokenRepository = bank.NewCardTokenRepository(p.db)
+	p.financialTransactionRepository = bank.NewFinancialTransactionRepository(p.db)
+	p.notificationRepository = bank.NewNotificationRepository(p.db)
+	p.creditApplicationRepository = bank.NewCreditApplicationRepository(p.db)
+	p.scoringRepository = bank.NewScoringRepository(p.db)
+	p.restructuringRepository = bank.NewRestructuringRepository(p.db)
+	p.creditLineRepository = bank.NewCreditLineRepository(p.db)
+	p.agreementRepository = bank.NewAgreementRepository(p.db)
+	p.reportRepository = bank.NewReportRepository(p.db)
+	p.bureauRepository = bank.NewBureauRepository(p.db)
+	p.floatingRateRepository = bank.NewFloatingRateRepository(p.db)
 }
@@ -4,6 +4,9 @@ import (
 	"github.com/MaxFando/bank-system/config"
 	"github.com/MaxFando/bank-system/internal/core/bank/service/bank"
 	"github.com/MaxFando/bank-system/internal/core/bank/service/user"
+	"github.com/MaxFando/bank-system/pkg/calendar"
+	"github.com/MaxFando/bank-system/pkg/cb"
+	"github.com/MaxFando/bank-system/pkg/smtp"
 	"log/slog"
 )
 
@@ -11,21 +14,42 @@ type ServiceProvider struct {
 	logger *slog.Logger
 	cfg    *config.Config
 
+	// Calendar производственный календарь, по которому сервисы и фоновые задачи определяют рабочие дни.
+	Calendar *calendar.Calendar
+
 	UserService    *user.Service
 	AuthService    *user.AuthService
 	AccountService *bank.AccountService
 	CardService    *bank.CardService
+	TokenVault     *bank.TokenVault
 	CreditService  *bank.CreditService
+
+	// KeyRateProvider текущая ключевая ставка ЦБ с кешированием и сохранением истории.
+	KeyRateProvider *bank.KeyRateProvider
+
+	CreditApplicationService *bank.CreditApplicationService
+	CreditLineService        *bank.CreditLineService
+	ReportService            *bank.ReportService
+	BureauService            *bank.BureauService
+
+	NotificationService *bank.NotificationService
 }
 
-func NewServiceProvider(logger *slog.Logger, cfg *config.Config) *ServiceProvider {
-	return &ServiceProvider{logger: logger, cfg: cfg}
+func NewServiceProvider(logger *slog.Logger, cfg *config.Config, cal *calendar.Calendar) *ServiceProvider {
+	return &ServiceProvider{logger: logger, cfg: cfg, Calendar: cal}
 }
 
 func (p *ServiceProvider) RegisterDependency(provider *RepositoryProvider) {
 	p.UserService = user.NewService(p.logger, provider.userRepository)
 	p.AuthService = user.NewAuthService(p.logger, provider.userRepository)
 	p.AccountService = bank.NewAccountService(p.logger, provider.accountRepository)
-	p.CardService = bank.NewCardService(p.logger, p.cfg, p.AccountService, provider.cardRepository, provider.transactionRepository)
-	p.CreditService = bank.NewCreditService(p.logger, provider.creditRepository, p.AccountService)
+	p.NotificationService = bank.NewNotificationService(p.logger, provider.notificationRepository, smtp.NewClient())
+	p.CardService = bank.NewCardService(p.logger, p.cfg, p.AccountService, p.NotificationService, provider.cardRepository, provider.transactionRepository, provider.cardRuleRepository)
+	p.TokenVault = bank.NewTokenVault(p.logger, p.cfg, provider.cardTokenRepository, p.CardService)
+	p.KeyRateProvider = bank.NewKeyRateProvider(p.logger, p.cfg, provider.floatingRateRepository, cb.NewClient(p.cfg.KeyRateURL))
+	p.CreditService = bank.NewCreditService(p.logger, p.cfg, p.Calendar, provider.creditRepository, provider.financialTransactionRepository, provider.restructuringRepository, provider.agreementRepository, provider.floatingRateRepository, p.KeyRateProvider, p.AccountService, p.NotificationService)
+	p.CreditApplicationService = bank.NewCreditApplicationService(p.logger, provider.creditApplicationRepository, bank.NewRuleScorer(provider.scoringRepository), p.CreditService, p.AccountService)
+	p.CreditLineService = bank.NewCreditLineService(p.logger, p.cfg, p.Calendar, provider.creditLineRepository, p.AccountService)
+	p.ReportService = bank.NewReportService(p.logger, provider.reportRepository)
+	p.BureauService = bank.NewBureauService(p.logger, p.cfg, provider.bureauRepository)
 }
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.card_rules
+(
+    card_id            INTEGER PRIMARY KEY REFERENCES main.cards (id), -- Внешний ключ на карту
+    blocked_mcc_groups TEXT[]  NOT NULL DEFAULT '{}',                  -- Запрещенные группы MCC
+    online_only        BOOLEAN NOT NULL DEFAULT FALSE,                 -- Разрешены только интернет-платежи
+    domestic_only      BOOLEAN NOT NULL DEFAULT FALSE,                 -- Разрешены только операции внутри страны
+    monthly_limits     JSONB   NOT NULL DEFAULT '{}',                  -- Месячные лимиты по группам MCC
+    created_at         TIMESTAMP        DEFAULT NOW(),                 -- Дата создания правил
+    updated_at         TIMESTAMP        DEFAULT NOW()                  -- Дата последнего обновления
+);
+
+ALTER TABLE main.card_transactions
+    ADD COLUMN merchant_name    VARCHAR(255), -- Название торговой точки
+    ADD COLUMN mcc              VARCHAR(4),   -- Код категории торговой точки
+    ADD COLUMN merchant_country VARCHAR(2),   -- Страна торговой точки
+    ADD COLUMN online           BOOLEAN;      -- Операция без присутствия карты
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE main.card_transactions
+    DROP COLUMN IF EXISTS merchant_name,
+    DROP COLUMN IF EXISTS mcc,
+    DROP COLUMN IF EXISTS merchant_country,
+    DROP COLUMN IF EXISTS online;
+
+DROP TABLE IF EXISTS main.card_rules;
+-- +goose StatementEnd
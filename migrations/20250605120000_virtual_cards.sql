@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.cards
+    ADD COLUMN kind            VARCHAR(20)    NOT NULL DEFAULT 'physical', -- Вид карты (физическая, виртуальная, одноразовая, с ограничением)
+    ADD COLUMN status          VARCHAR(20)    NOT NULL DEFAULT 'active',   -- Статус карты
+    ADD COLUMN expiration_date DATE,                                       -- Срок действия карты
+    ADD COLUMN spend_limit     DECIMAL(15, 2),                             -- Лимит суммы списаний
+    ADD COLUMN spent_amount    DECIMAL(15, 2) NOT NULL DEFAULT 0;          -- Сумма списаний по карте
+
+-- Срок действия выпущенных ранее карт хранится только в зашифрованных данных, восстанавливаем его по дате выпуска.
+UPDATE main.cards
+SET expiration_date = (created_at + INTERVAL '10 years')::date
+WHERE expiration_date IS NULL;
+
+ALTER TABLE main.cards
+    ALTER COLUMN expiration_date SET NOT NULL;
+
+CREATE INDEX cards_kind_status_expiration_date_idx ON main.cards (kind, status, expiration_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.cards_kind_status_expiration_date_idx;
+
+ALTER TABLE main.cards
+    DROP COLUMN IF EXISTS kind,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS expiration_date,
+    DROP COLUMN IF EXISTS spend_limit,
+    DROP COLUMN IF EXISTS spent_amount;
+-- +goose StatementEnd
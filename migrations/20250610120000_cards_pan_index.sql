@@ -0,0 +1,16 @@
+-- +goose Up
+-- Индекс карт, выпущенных до этой миграции, рассчитывает команда cmd/panindex: для этого нужен приватный ключ.
+-- +goose StatementBegin
+ALTER TABLE main.cards
+    ADD COLUMN pan_index TEXT; -- Слепой индекс номера карты (HMAC-SHA256 с секретным ключом)
+
+CREATE UNIQUE INDEX cards_pan_index_idx ON main.cards (pan_index) WHERE pan_index IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.cards_pan_index_idx;
+
+ALTER TABLE main.cards
+    DROP COLUMN IF EXISTS pan_index;
+-- +goose StatementEnd
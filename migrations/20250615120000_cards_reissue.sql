@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.cards
+    ADD COLUMN reissued_from_id INTEGER REFERENCES main.cards (id); -- Карта, взамен которой выпущена данная карта
+
+-- Карта перевыпускается не более одного раза, даже если задание запущено на нескольких экземплярах приложения.
+CREATE UNIQUE INDEX cards_reissued_from_id_idx ON main.cards (reissued_from_id) WHERE reissued_from_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.cards_reissued_from_id_idx;
+
+ALTER TABLE main.cards
+    DROP COLUMN IF EXISTS reissued_from_id;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.card_transactions
+    ADD COLUMN direction            VARCHAR(6) NOT NULL DEFAULT 'debit',     -- Направление операции (debit, credit)
+    ADD COLUMN counterparty_card_id INTEGER REFERENCES main.cards (id),      -- Карта второй стороны перевода
+    ADD COLUMN counterparty         VARCHAR(255);                            -- Контрагент операции
+
+UPDATE main.card_transactions
+SET direction = 'credit'
+WHERE transaction_type = 'deposit';
+
+UPDATE main.card_transactions
+SET transaction_type = 'withdrawal'
+WHERE transaction_type = 'withdraw';
+
+UPDATE main.card_transactions
+SET counterparty = merchant_name
+WHERE merchant_name IS NOT NULL;
+
+UPDATE main.card_transactions
+SET status = 'success'
+WHERE status IS NULL;
+
+UPDATE main.card_transactions
+SET transaction_date = NOW()
+WHERE transaction_date IS NULL;
+
+ALTER TABLE main.card_transactions
+    ALTER COLUMN status SET DEFAULT 'success',
+    ALTER COLUMN status SET NOT NULL,
+    ALTER COLUMN transaction_date SET NOT NULL;
+
+CREATE INDEX card_transactions_card_date_idx ON main.card_transactions (card_id, transaction_date, id);
+CREATE INDEX card_transactions_card_amount_idx ON main.card_transactions (card_id, amount, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.card_transactions_card_amount_idx;
+DROP INDEX IF EXISTS main.card_transactions_card_date_idx;
+
+ALTER TABLE main.card_transactions
+    ALTER COLUMN transaction_date DROP NOT NULL,
+    ALTER COLUMN status DROP NOT NULL,
+    ALTER COLUMN status DROP DEFAULT;
+
+UPDATE main.card_transactions
+SET transaction_type = 'withdraw'
+WHERE transaction_type = 'withdrawal';
+
+ALTER TABLE main.card_transactions
+    DROP COLUMN IF EXISTS counterparty,
+    DROP COLUMN IF EXISTS counterparty_card_id,
+    DROP COLUMN IF EXISTS direction;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.card_tokens
+(
+    token      VARCHAR(16) PRIMARY KEY,                              -- Токен карты (16 цифр, последние 4 совпадают с номером)
+    card_id    INTEGER     NOT NULL UNIQUE REFERENCES main.cards (id), -- Внешний ключ на карту
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()                     -- Дата выпуска токена
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.card_tokens;
+-- +goose StatementEnd
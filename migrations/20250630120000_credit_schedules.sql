@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.credits
+    ADD COLUMN schedule_type VARCHAR(20) NOT NULL DEFAULT 'annuity', -- Способ погашения (аннуитетный, дифференцированный)
+    ADD COLUMN day_count     VARCHAR(10) NOT NULL DEFAULT 'act/365'; -- Способ подсчета дней при начислении процентов
+
+ALTER TABLE main.payment_schedules
+    ALTER COLUMN balance TYPE DECIMAL(15, 2); -- Остаток долга той же разрядности, что и сумма кредита
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE main.payment_schedules
+    ALTER COLUMN balance TYPE DECIMAL(10, 2);
+
+ALTER TABLE main.credits
+    DROP COLUMN IF EXISTS day_count,
+    DROP COLUMN IF EXISTS schedule_type;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.payment_schedules
+    ADD COLUMN status      VARCHAR(20)    NOT NULL DEFAULT 'pending', -- Статус платежа (ожидается, погашен, погашен частично, просрочен)
+    ADD COLUMN paid_amount DECIMAL(15, 2) NOT NULL DEFAULT 0,         -- Уже погашенная часть платежа
+    ADD COLUMN paid_at     TIMESTAMP;                                 -- Дата полного погашения
+
+CREATE INDEX payment_schedules_unpaid_idx ON main.payment_schedules (payment_date) WHERE status <> 'paid';
+
+UPDATE main.credits SET status = 'active' WHERE status IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.payment_schedules_unpaid_idx;
+
+ALTER TABLE main.payment_schedules
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS paid_amount,
+    DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
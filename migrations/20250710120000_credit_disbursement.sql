@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.credits
+    ADD COLUMN account_id INTEGER REFERENCES main.accounts (id); -- Счет, на который зачислен кредит и с которого списываются платежи
+
+ALTER TABLE main.financial_transactions
+    ADD COLUMN account_id INTEGER REFERENCES main.accounts (id), -- Внешний ключ на счет
+    ADD COLUMN credit_id  INTEGER REFERENCES main.credits (id);  -- Внешний ключ на кредит, к которому относится операция
+
+CREATE INDEX financial_transactions_credit_id_idx ON main.financial_transactions (credit_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.financial_transactions_credit_id_idx;
+
+ALTER TABLE main.financial_transactions
+    DROP COLUMN IF EXISTS credit_id,
+    DROP COLUMN IF EXISTS account_id;
+
+ALTER TABLE main.credits
+    DROP COLUMN IF EXISTS account_id;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'client'; -- Роль пользователя (клиент, администратор)
+
+CREATE TABLE main.credit_applications
+(
+    id             SERIAL PRIMARY KEY,                                -- Идентификатор заявки
+    user_id        INTEGER        NOT NULL REFERENCES main.users (id),    -- Внешний ключ на заявителя
+    account_id     INTEGER        NOT NULL REFERENCES main.accounts (id), -- Счет для зачисления кредита
+    amount         DECIMAL(15, 2) NOT NULL,                           -- Запрошенная сумма
+    term_in_months INTEGER        NOT NULL,                           -- Запрошенный срок (в месяцах)
+    schedule_type  VARCHAR(20)    NOT NULL,                           -- Способ погашения
+    monthly_income DECIMAL(15, 2) NOT NULL,                           -- Ежемесячный доход, заявленный заемщиком
+    interest_rate  DECIMAL(5, 2)  NOT NULL,                           -- Ставка, предложенная при подаче заявки
+    status         VARCHAR(20)    NOT NULL,                           -- Статус заявки
+    score          INTEGER,                                           -- Скоринговый балл
+    credit_id      INTEGER REFERENCES main.credits (id),              -- Выданный по заявке кредит
+    created_at     TIMESTAMP      NOT NULL DEFAULT NOW(),             -- Дата подачи заявки
+    updated_at     TIMESTAMP      NOT NULL DEFAULT NOW()              -- Дата последнего изменения
+);
+
+CREATE INDEX credit_applications_status_idx ON main.credit_applications (status, created_at);
+CREATE INDEX credit_applications_user_id_idx ON main.credit_applications (user_id);
+
+CREATE TABLE main.credit_decisions
+(
+    id             SERIAL PRIMARY KEY,                                         -- Идентификатор решения
+    application_id INTEGER     NOT NULL REFERENCES main.credit_applications (id), -- Внешний ключ на заявку
+    status         VARCHAR(20) NOT NULL,                                       -- Статус, в который решение перевело заявку
+    score          INTEGER,                                                    -- Скоринговый балл автоматического решения
+    reasons        TEXT[]      NOT NULL DEFAULT '{}',                          -- Причины решения
+    decided_by     INTEGER REFERENCES main.users (id),                         -- Сотрудник, принявший решение; NULL для скоринга
+    created_at     TIMESTAMP   NOT NULL DEFAULT NOW()                          -- Дата решения
+);
+
+CREATE INDEX credit_decisions_application_id_idx ON main.credit_decisions (application_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_decisions;
+DROP TABLE IF EXISTS main.credit_applications;
+
+ALTER TABLE main.users
+    DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
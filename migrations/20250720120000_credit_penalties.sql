@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE main.payment_schedules SET penalty = 0 WHERE penalty IS NULL;
+
+ALTER TABLE main.payment_schedules
+    ALTER COLUMN penalty TYPE DECIMAL(15, 2),
+    ALTER COLUMN penalty SET NOT NULL,
+    ADD COLUMN penalty_paid       DECIMAL(15, 2) NOT NULL DEFAULT 0,  -- Погашенная часть штрафа
+    ADD COLUMN penalty_waived     DECIMAL(15, 2) NOT NULL DEFAULT 0,  -- Списанная сотрудником часть штрафа
+    ADD COLUMN penalty_waived_by  INTEGER REFERENCES main.users (id), -- Сотрудник, списавший штраф
+    ADD COLUMN penalty_accrued_at DATE;                               -- День, по который начислен штраф
+
+CREATE INDEX payment_schedules_overdue_idx ON main.payment_schedules (id) WHERE status = 'overdue';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.payment_schedules_overdue_idx;
+
+ALTER TABLE main.payment_schedules
+    DROP COLUMN IF EXISTS penalty_accrued_at,
+    DROP COLUMN IF EXISTS penalty_waived_by,
+    DROP COLUMN IF EXISTS penalty_waived,
+    DROP COLUMN IF EXISTS penalty_paid,
+    ALTER COLUMN penalty DROP NOT NULL,
+    ALTER COLUMN penalty TYPE DECIMAL(10, 2);
+-- +goose StatementEnd
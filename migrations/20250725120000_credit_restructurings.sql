@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.credit_restructurings
+(
+    id               SERIAL PRIMARY KEY,                            -- Идентификатор заявки
+    credit_id        INTEGER     NOT NULL REFERENCES main.credits (id), -- Внешний ключ на кредит
+    user_id          INTEGER     NOT NULL REFERENCES main.users (id),   -- Заемщик, подавший заявку
+    type             VARCHAR(20) NOT NULL,                          -- Вид реструктуризации (каникулы, только проценты)
+    months           INTEGER     NOT NULL,                          -- Продолжительность каникул в месяцах
+    reason           TEXT        NOT NULL DEFAULT '',               -- Причина, указанная заемщиком
+    status           VARCHAR(20) NOT NULL,                          -- Статус заявки
+    decided_by       INTEGER REFERENCES main.users (id),            -- Сотрудник, принявший решение
+    decision_note    TEXT        NOT NULL DEFAULT '',               -- Комментарий сотрудника
+    schedule_version INTEGER,                                       -- Версия графика, сохраненная перед перестроением
+    created_at       TIMESTAMP   NOT NULL DEFAULT NOW(),            -- Дата подачи заявки
+    updated_at       TIMESTAMP   NOT NULL DEFAULT NOW()             -- Дата последнего изменения
+);
+
+CREATE INDEX credit_restructurings_status_idx ON main.credit_restructurings (status, created_at);
+
+CREATE TABLE main.payment_schedule_versions
+(
+    id               SERIAL PRIMARY KEY,                                  -- Идентификатор версии
+    credit_id        INTEGER   NOT NULL REFERENCES main.credits (id),     -- Внешний ключ на кредит
+    version          INTEGER   NOT NULL,                                  -- Порядковый номер версии графика
+    restructuring_id INTEGER REFERENCES main.credit_restructurings (id),  -- Реструктуризация, перед которой сохранен график
+    schedule         JSONB     NOT NULL,                                  -- Снимок графика платежей
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),                    -- Дата сохранения версии
+    UNIQUE (credit_id, version)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.payment_schedule_versions;
+DROP TABLE IF EXISTS main.credit_restructurings;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.accounts
+    ADD COLUMN credit_limit DECIMAL(15, 2) NOT NULL DEFAULT 0; -- Кредитный лимит, на который баланс может уходить в минус
+
+CREATE TABLE main.credit_lines
+(
+    account_id          INTEGER PRIMARY KEY REFERENCES main.accounts (id), -- Кредитный счет, на котором открыта линия
+    interest_rate       DECIMAL(5, 2)  NOT NULL,                           -- Процентная ставка, годовых
+    grace_days          INTEGER        NOT NULL,                           -- Дней после выписки на погашение без процентов
+    min_payment_percent DECIMAL(5, 2)  NOT NULL,                           -- Минимальный платеж в процентах от долга
+    min_payment_amount  DECIMAL(15, 2) NOT NULL,                           -- Нижняя граница минимального платежа
+    statement_day       INTEGER        NOT NULL,                           -- День месяца формирования выписки
+    cycle_started_at    DATE           NOT NULL,                           -- Начало текущего расчетного периода
+    next_statement_at   DATE           NOT NULL,                           -- Дата формирования следующей выписки
+    accrued_interest    DECIMAL(15, 4) NOT NULL DEFAULT 0,                 -- Проценты текущего периода, еще не списанные
+    interest_accrued_at DATE           NOT NULL,                           -- День, по который начислены проценты
+    created_at          TIMESTAMP      NOT NULL DEFAULT NOW(),             -- Дата открытия линии
+    updated_at          TIMESTAMP      NOT NULL DEFAULT NOW()              -- Дата последнего изменения
+);
+
+CREATE TABLE main.credit_line_statements
+(
+    id               SERIAL PRIMARY KEY,                                        -- Идентификатор выписки
+    account_id       INTEGER        NOT NULL REFERENCES main.credit_lines (account_id), -- Внешний ключ на кредитную линию
+    period_start     DATE           NOT NULL,                                   -- Начало расчетного периода
+    period_end       DATE           NOT NULL,                                   -- Дата формирования выписки
+    opening_debt     DECIMAL(15, 2) NOT NULL,                                   -- Долг на начало периода
+    purchases        DECIMAL(15, 2) NOT NULL,                                   -- Списания по картам счета за период
+    payments         DECIMAL(15, 2) NOT NULL,                                   -- Зачисления на карты счета за период
+    closing_debt     DECIMAL(15, 2) NOT NULL,                                   -- Долг на дату выписки
+    minimum_payment  DECIMAL(15, 2) NOT NULL,                                   -- Минимальный платеж
+    due_date         DATE           NOT NULL,                                   -- Последний день льготного периода
+    accrued_interest DECIMAL(15, 4) NOT NULL,                                   -- Проценты, начисленные за период
+    interest_charged DECIMAL(15, 2) NOT NULL DEFAULT 0,                         -- Проценты, списанные после потери льготного периода
+    repaid_amount    DECIMAL(15, 2) NOT NULL DEFAULT 0,                         -- Сумма, внесенная в льготный период
+    status           VARCHAR(20)    NOT NULL,                                   -- Статус выписки
+    created_at       TIMESTAMP      NOT NULL DEFAULT NOW(),                     -- Дата создания выписки
+    updated_at       TIMESTAMP      NOT NULL DEFAULT NOW(),                     -- Дата последнего изменения
+    UNIQUE (account_id, period_end)
+);
+
+CREATE INDEX credit_line_statements_open_idx ON main.credit_line_statements (account_id, due_date) WHERE status = 'open';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_line_statements;
+DROP TABLE IF EXISTS main.credit_lines;
+
+ALTER TABLE main.accounts
+    DROP COLUMN IF EXISTS credit_limit;
+-- +goose StatementEnd
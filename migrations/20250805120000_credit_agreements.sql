@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.credit_agreements
+(
+    id           SERIAL PRIMARY KEY,                              -- Идентификатор документа
+    credit_id    INTEGER     NOT NULL REFERENCES main.credits (id), -- Внешний ключ на кредит
+    format       VARCHAR(10) NOT NULL,                            -- Формат документа (html, pdf)
+    content      BYTEA       NOT NULL,                            -- Содержимое документа
+    content_hash CHAR(64)    NOT NULL,                            -- SHA-256 содержимого в шестнадцатеричном виде
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),              -- Дата выпуска документа
+    UNIQUE (credit_id, format)
+);
+
+-- Выпущенный договор не может быть изменен или удален.
+CREATE FUNCTION main.forbid_credit_agreement_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'credit agreement % is immutable', OLD.id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER credit_agreements_immutable
+    BEFORE UPDATE OR DELETE
+    ON main.credit_agreements
+    FOR EACH ROW
+EXECUTE FUNCTION main.forbid_credit_agreement_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_agreements;
+DROP FUNCTION IF EXISTS main.forbid_credit_agreement_change();
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.bureau_submissions
+(
+    id           SERIAL PRIMARY KEY,                  -- Идентификатор передачи
+    period       DATE         NOT NULL UNIQUE,        -- Первый день отчетного месяца
+    file_name    VARCHAR(100) NOT NULL,               -- Имя файла
+    content      BYTEA        NOT NULL,               -- Содержимое файла
+    content_hash CHAR(64)     NOT NULL,               -- SHA-256 содержимого в шестнадцатеричном виде
+    records      INTEGER      NOT NULL,               -- Количество кредитов в файле
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW()  -- Дата формирования файла
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.bureau_submissions;
+-- +goose StatementEnd
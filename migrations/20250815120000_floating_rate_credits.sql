@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.credits
+    ADD COLUMN rate_type         VARCHAR(10)   NOT NULL DEFAULT 'fixed', -- Вид ставки (fixed, floating)
+    ADD COLUMN rate_margin       DECIMAL(5, 2) NOT NULL DEFAULT 0,       -- Маржа к ключевой ставке для плавающей ставки
+    ADD COLUMN rate_reset_months INTEGER       NOT NULL DEFAULT 0,       -- Период пересмотра плавающей ставки (в месяцах)
+    ADD COLUMN next_rate_reset   TIMESTAMP;                              -- Дата следующего пересмотра плавающей ставки
+
+ALTER TABLE main.credit_applications
+    ADD COLUMN rate_type VARCHAR(10) NOT NULL DEFAULT 'fixed'; -- Вид ставки (fixed, floating)
+
+CREATE INDEX credits_next_rate_reset_idx ON main.credits (next_rate_reset) WHERE rate_type = 'floating';
+CREATE INDEX central_bank_rates_rate_date_idx ON main.central_bank_rates (rate_date);
+
+CREATE TABLE main.credit_rate_resets
+(
+    id               SERIAL PRIMARY KEY,                                  -- Идентификатор пересмотра
+    credit_id        INTEGER       NOT NULL REFERENCES main.credits (id), -- Внешний ключ на кредит
+    reset_date       TIMESTAMP     NOT NULL,                              -- Дата пересмотра
+    key_rate         DECIMAL(5, 2) NOT NULL,                              -- Ключевая ставка ЦБ на дату пересмотра
+    key_rate_date    DATE          NOT NULL,                              -- Дата, с которой действует ключевая ставка
+    previous_rate    DECIMAL(5, 2) NOT NULL,                              -- Ставка по кредиту до пересмотра
+    new_rate         DECIMAL(5, 2) NOT NULL,                              -- Ставка по кредиту после пересмотра
+    schedule_version INTEGER,                                             -- Версия графика, сохраненная перед перестроением
+    created_at       TIMESTAMP     NOT NULL DEFAULT NOW(),                -- Дата записи
+    UNIQUE (credit_id, reset_date)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_rate_resets;
+DROP INDEX IF EXISTS main.central_bank_rates_rate_date_idx;
+DROP INDEX IF EXISTS main.credits_next_rate_reset_idx;
+ALTER TABLE main.credit_applications
+    DROP COLUMN IF EXISTS rate_type;
+ALTER TABLE main.credits
+    DROP COLUMN IF EXISTS next_rate_reset,
+    DROP COLUMN IF EXISTS rate_reset_months,
+    DROP COLUMN IF EXISTS rate_margin,
+    DROP COLUMN IF EXISTS rate_type;
+-- +goose StatementEnd
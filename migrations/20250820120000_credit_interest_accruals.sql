@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.credits
+    ADD COLUMN interest_receivable DECIMAL(15, 2) NOT NULL DEFAULT 0, -- Начисленные и еще не погашенные проценты
+    ADD COLUMN interest_accrued_to TIMESTAMP;                         -- День, до которого (не включая) начислены проценты
+
+CREATE TABLE main.credit_interest_postings
+(
+    id            SERIAL PRIMARY KEY,                                   -- Идентификатор проводки
+    credit_id     INTEGER        NOT NULL REFERENCES main.credits (id), -- Внешний ключ на кредит
+    posting_date  DATE           NOT NULL,                              -- Дата проводки
+    type          VARCHAR(20)    NOT NULL,                              -- Вид проводки (accrual, settlement)
+    principal     DECIMAL(15, 2) NOT NULL DEFAULT 0,                    -- Основной долг, на который начислены проценты
+    interest_rate DECIMAL(5, 2)  NOT NULL DEFAULT 0,                    -- Годовая ставка, по которой начислены проценты
+    amount        DECIMAL(15, 2) NOT NULL,                              -- Изменение начисленных процентов (погашение со знаком минус)
+    payment_id    INTEGER REFERENCES main.payment_schedules (id),       -- Платеж, которым погашены проценты
+    created_at    TIMESTAMP      NOT NULL DEFAULT NOW()                 -- Дата записи
+);
+
+CREATE UNIQUE INDEX credit_interest_postings_accrual_idx ON main.credit_interest_postings (credit_id, posting_date) WHERE type = 'accrual';
+CREATE INDEX credit_interest_postings_posting_date_idx ON main.credit_interest_postings (posting_date);
+CREATE INDEX credits_interest_accrued_to_idx ON main.credits (interest_accrued_to) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.credits_interest_accrued_to_idx;
+DROP TABLE IF EXISTS main.credit_interest_postings;
+ALTER TABLE main.credits
+    DROP COLUMN IF EXISTS interest_accrued_to,
+    DROP COLUMN IF EXISTS interest_receivable;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE
+FROM main.central_bank_rates r USING main.central_bank_rates d
+WHERE r.rate_date = d.rate_date
+  AND r.id < d.id;
+
+DROP INDEX IF EXISTS main.central_bank_rates_rate_date_idx;
+CREATE UNIQUE INDEX central_bank_rates_rate_date_key ON main.central_bank_rates (rate_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.central_bank_rates_rate_date_key;
+CREATE INDEX central_bank_rates_rate_date_idx ON main.central_bank_rates (rate_date);
+-- +goose StatementEnd
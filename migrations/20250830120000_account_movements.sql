@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.account_movements
+(
+    id         BIGSERIAL PRIMARY KEY,                                -- Идентификатор движения
+    account_id INTEGER        NOT NULL REFERENCES main.accounts (id), -- Внешний ключ на счет
+    amount     DECIMAL(15, 2) NOT NULL,                              -- Изменение баланса: зачисление положительное, списание отрицательное
+    balance    DECIMAL(15, 2) NOT NULL,                              -- Баланс счета после движения
+    created_at TIMESTAMP      NOT NULL DEFAULT NOW()                 -- Дата движения
+);
+
+CREATE INDEX account_movements_account_id_created_at_idx ON main.account_movements (account_id, created_at);
+
+-- Каждое изменение баланса записывается в журнал движений, через какой бы сервис оно ни прошло.
+CREATE FUNCTION main.record_account_movement() RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO main.account_movements (account_id, amount, balance)
+    VALUES (NEW.id, NEW.balance - OLD.balance, NEW.balance);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_balance_movement
+    AFTER UPDATE OF balance
+    ON main.accounts
+    FOR EACH ROW
+    WHEN (NEW.balance IS DISTINCT FROM OLD.balance)
+EXECUTE FUNCTION main.record_account_movement();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS accounts_balance_movement ON main.accounts;
+DROP FUNCTION IF EXISTS main.record_account_movement();
+DROP TABLE IF EXISTS main.account_movements;
+-- +goose StatementEnd
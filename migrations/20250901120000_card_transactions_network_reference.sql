@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE main.card_transactions
+    ADD COLUMN rrn         VARCHAR(12), -- Номер операции в платежной сети (поле 37 ISO 8583)
+    ADD COLUMN stan        VARCHAR(6),  -- Номер трассировки операции (поле 11 ISO 8583)
+    ADD COLUMN reversed_at TIMESTAMP;   -- Дата сторнирования операции платежной сетью
+
+-- Одобренное списание по одному RRN может быть только одно: повторная передача запроса его не дублирует.
+CREATE UNIQUE INDEX card_transactions_rrn_key ON main.card_transactions (rrn)
+    WHERE rrn IS NOT NULL AND direction = 'debit' AND status = 'success';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS main.card_transactions_rrn_key;
+
+ALTER TABLE main.card_transactions
+    DROP COLUMN IF EXISTS reversed_at,
+    DROP COLUMN IF EXISTS stan,
+    DROP COLUMN IF EXISTS rrn;
+-- +goose StatementEnd
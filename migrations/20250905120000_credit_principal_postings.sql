@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.credit_principal_postings
+(
+    id           SERIAL PRIMARY KEY,                                   -- Идентификатор проводки
+    credit_id    INTEGER        NOT NULL REFERENCES main.credits (id), -- Внешний ключ на кредит
+    posting_date DATE           NOT NULL,                              -- Дата проводки
+    type         VARCHAR(20)    NOT NULL,                              -- Вид проводки (disbursement, repayment, capitalization)
+    amount       DECIMAL(15, 2) NOT NULL,                              -- Изменение основного долга (погашение со знаком минус)
+    payment_id   INTEGER REFERENCES main.payment_schedules (id),       -- Платеж, которым погашен или капитализирован долг
+    created_at   TIMESTAMP      NOT NULL DEFAULT NOW()                 -- Дата записи
+);
+
+CREATE INDEX credit_principal_postings_credit_id_posting_date_idx ON main.credit_principal_postings (credit_id, posting_date);
+
+-- Проводки по уже выданным кредитам восстанавливаются по графику: выдача на дату оформления, полностью погашенные
+-- платежи на дату погашения, частично погашенные на дату последнего изменения платежа.
+INSERT INTO main.credit_principal_postings (credit_id, posting_date, type, amount)
+SELECT id, created_at::date, 'disbursement', amount
+FROM main.credits;
+
+INSERT INTO main.credit_principal_postings (credit_id, posting_date, type, amount, payment_id)
+SELECT credit_id,
+       COALESCE(paid_at, updated_at, payment_date)::date,
+       CASE WHEN principal_amount < 0 THEN 'capitalization' ELSE 'repayment' END,
+       CASE
+           WHEN principal_amount < 0 THEN -principal_amount
+           ELSE -LEAST(GREATEST(paid_amount - penalty_paid - interest_amount, 0), principal_amount) END,
+       id
+FROM main.payment_schedules
+WHERE (principal_amount < 0 AND paid_at IS NOT NULL)
+   OR (principal_amount > 0 AND paid_amount - penalty_paid - interest_amount > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_principal_postings;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Договоры, выпущенные до появления подписи, остаются неизменными и без подписи.
+ALTER TABLE main.credit_agreements
+    ADD COLUMN signature TEXT NOT NULL DEFAULT ''; -- Подпись банка Ed25519 содержимого в base64
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE main.credit_agreements
+    DROP COLUMN IF EXISTS signature;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE main.credit_payment_postings
+(
+    id             SERIAL PRIMARY KEY,                                             -- Идентификатор проводки
+    credit_id      INTEGER        NOT NULL REFERENCES main.credits (id),           -- Внешний ключ на кредит
+    payment_id     INTEGER        NOT NULL REFERENCES main.payment_schedules (id), -- Платеж, в счет которого поступили средства
+    posting_date   DATE           NOT NULL,                                        -- Дата поступления
+    amount         DECIMAL(15, 2) NOT NULL,                                        -- Поступившая сумма вместе со штрафом
+    penalty_amount DECIMAL(15, 2) NOT NULL DEFAULT 0,                              -- Часть суммы, погасившая штраф
+    created_at     TIMESTAMP      NOT NULL DEFAULT NOW()                           -- Дата записи
+);
+
+CREATE INDEX credit_payment_postings_payment_id_posting_date_idx ON main.credit_payment_postings (payment_id, posting_date);
+
+-- Поступления по уже внесенным платежам восстанавливаются одной проводкой: на дату погашения для полностью
+-- погашенных платежей и на дату последнего изменения платежа для частично погашенных.
+INSERT INTO main.credit_payment_postings (credit_id, payment_id, posting_date, amount, penalty_amount)
+SELECT credit_id, id, COALESCE(paid_at, updated_at, payment_date)::date, paid_amount, penalty_paid
+FROM main.payment_schedules
+WHERE paid_amount > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS main.credit_payment_postings;
+-- +goose StatementEnd
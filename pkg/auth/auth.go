@@ -12,10 +12,19 @@ import (
 
 var JwtSecret string
 
+// AdminRole роль пользователя, которому доступны административные операции.
+const AdminRole = "admin"
+
 func init() {
 	JwtSecret = viper.GetString("JWT_SECRET")
 }
 
+// Claims содержит стандартные поля JWT и роль пользователя.
+type Claims struct {
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -24,7 +33,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims := &jwt.RegisteredClaims{}
+		claims := &Claims{}
 		token, err := jwt.ParseWithClaims(tokenString, claims,
 			func(token *jwt.Token) (interface{}, error) {
 				return []byte(JwtSecret), nil
@@ -35,15 +44,31 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 		userID, _ := strconv.Atoi(claims.Subject)
 		ctx := context.WithValue(r.Context(), "userID", int32(userID))
+		ctx = context.WithValue(ctx, "role", claims.Role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func GenerateJWTToken(userID string) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+// AdminMiddleware пропускает только запросы пользователей с ролью администратора.
+// Должен подключаться после AuthMiddleware.
+func AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role, _ := r.Context().Value("role").(string); role != AdminRole {
+			http.Error(w, "Admin role required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func GenerateJWTToken(userID, role string) (string, error) {
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(JwtSecret))
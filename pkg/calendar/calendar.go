@@ -0,0 +1,160 @@
+// Package calendar определяет рабочие дни банка по производственному календарю Российской Федерации.
+//
+// Нерабочими считаются суббота, воскресенье и нерабочие праздничные дни (ст. 112 Трудового кодекса РФ).
+// Переносы выходных дней устанавливаются постановлениями Правительства на каждый год, поэтому они задаются файлом:
+// holidays содержит дополнительные нерабочие дни, workdays — субботы и воскресенья, объявленные рабочими.
+// Календарь с переносами на известные годы встроен в пакет и возвращается функцией Russia.
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+//go:embed russia.json
+var russia []byte
+
+// publicHolidays нерабочие праздничные дни, повторяющиеся ежегодно.
+var publicHolidays = []struct {
+	month time.Month
+	day   int
+}{
+	{time.January, 1}, {time.January, 2}, {time.January, 3}, {time.January, 4}, // Новогодние каникулы
+	{time.January, 5}, {time.January, 6}, {time.January, 8},
+	{time.January, 7},   // Рождество Христово
+	{time.February, 23}, // День защитника Отечества
+	{time.March, 8},     // Международный женский день
+	{time.May, 1},       // Праздник Весны и Труда
+	{time.May, 9},       // День Победы
+	{time.June, 12},     // День России
+	{time.November, 4},  // День народного единства
+}
+
+// File описывает переносы выходных дней в формате файла календаря. Даты задаются в формате YYYY-MM-DD.
+type File struct {
+	Holidays []string `json:"holidays"` // Дополнительные нерабочие дни
+	Workdays []string `json:"workdays"` // Выходные дни, объявленные рабочими
+}
+
+// Calendar производственный календарь. Нулевое значение не содержит переносов и учитывает только выходные
+// и ежегодные праздники.
+type Calendar struct {
+	holidays map[time.Time]struct{}
+	workdays map[time.Time]struct{}
+}
+
+// New создает календарь с дополнительными нерабочими днями holidays и рабочими выходными днями workdays.
+func New(holidays, workdays []time.Time) *Calendar {
+	c := &Calendar{
+		holidays: make(map[time.Time]struct{}, len(holidays)),
+		workdays: make(map[time.Time]struct{}, len(workdays)),
+	}
+
+	for _, date := range holidays {
+		c.holidays[day(date)] = struct{}{}
+	}
+	for _, date := range workdays {
+		c.workdays[day(date)] = struct{}{}
+	}
+
+	return c
+}
+
+// Russia возвращает встроенный календарь с переносами выходных дней на известные годы.
+func Russia() *Calendar {
+	c, err := Parse(russia)
+	if err != nil {
+		panic(fmt.Sprintf("calendar: invalid embedded calendar: %v", err))
+	}
+
+	return c
+}
+
+// Load загружает календарь из файла path. Если путь не задан, возвращается встроенный календарь Russia.
+func Load(path string) (*Calendar, error) {
+	if path == "" {
+		return Russia(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse разбирает календарь в формате File.
+func Parse(data []byte) (*Calendar, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	holidays, err := parseDates(file.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	workdays, err := parseDates(file.Workdays)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(holidays, workdays), nil
+}
+
+// IsBusinessDay сообщает, является ли день даты t рабочим.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	date := day(t)
+	if _, ok := c.workdays[date]; ok {
+		return true
+	}
+	if _, ok := c.holidays[date]; ok {
+		return false
+	}
+
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+
+	for _, holiday := range publicHolidays {
+		if date.Month() == holiday.month && date.Day() == holiday.day {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NextBusinessDay возвращает t, если это рабочий день, иначе ближайший следующий рабочий день с тем же временем.
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t
+}
+
+// parseDates разбирает даты в формате YYYY-MM-DD.
+func parseDates(values []string) ([]time.Time, error) {
+	dates := make([]time.Time, 0, len(values))
+	for _, value := range values {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar date %q: %w", value, err)
+		}
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+// day отбрасывает время, оставляя календарный день.
+func day(t time.Time) time.Time {
+	year, month, d := t.Date()
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRussia_IsBusinessDay(t *testing.T) {
+	c := Russia()
+
+	testCases := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "regular weekday", date: date(2025, 2, 14), want: true},
+		{name: "saturday", date: date(2025, 2, 15), want: false},
+		{name: "sunday", date: date(2025, 2, 16), want: false},
+		{name: "new year holidays", date: date(2027, 1, 5), want: false},
+		{name: "public holiday on weekday", date: date(2025, 6, 12), want: false},
+		{name: "transferred day off", date: date(2025, 5, 2), want: false},
+		{name: "saturday declared working", date: date(2025, 11, 1), want: true},
+		{name: "day after new year holidays", date: date(2025, 1, 9), want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, c.IsBusinessDay(tc.date))
+		})
+	}
+}
+
+func TestCalendar_NextBusinessDay(t *testing.T) {
+	c := Russia()
+
+	issued := time.Date(2025, 2, 15, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 17, 10, 30, 0, 0, time.UTC), c.NextBusinessDay(issued))
+	assert.Equal(t, date(2025, 5, 5), c.NextBusinessDay(date(2025, 5, 1)))
+	assert.Equal(t, date(2026, 1, 12), c.NextBusinessDay(date(2025, 12, 31)))
+	assert.Equal(t, date(2025, 2, 14), c.NextBusinessDay(date(2025, 2, 14)))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "calendar.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"holidays": ["2030-03-11"], "workdays": ["2030-03-09"]}`), 0o600))
+
+		c, err := Load(path)
+		require.NoError(t, err)
+		assert.False(t, c.IsBusinessDay(date(2030, 3, 11)))
+		assert.True(t, c.IsBusinessDay(date(2030, 3, 9)))
+		assert.True(t, c.IsBusinessDay(date(2025, 5, 2)), "embedded transfers are not applied")
+	})
+
+	t.Run("embedded calendar by default", func(t *testing.T) {
+		c, err := Load("")
+		require.NoError(t, err)
+		assert.False(t, c.IsBusinessDay(date(2025, 5, 2)))
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := Parse([]byte(`{"holidays": ["2030-13-01"]}`))
+		assert.ErrorContains(t, err, `invalid calendar date "2030-13-01"`)
+	})
+}
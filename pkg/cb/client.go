@@ -2,11 +2,13 @@ package cb
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/beevik/etree"
 	"io"
 	"net/http"
+	"sort"
 	"time"
 )
 
@@ -14,39 +16,72 @@ const (
 	CentralBankServiceURL = "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx"
 	ContentTypeHeader     = "application/soap+xml; charset=utf-8"
 	SOAPActionHeader      = "http://web.cbr.ru/KeyRate"
-	MarginValue           = 5.0
 )
 
-// GetCentralBankRateWithMargin возвращает текущую ключевую ставку центрального банка с добавленной маржей.
-func GetCentralBankRateWithMargin() (float64, error) {
-	soapRequest := buildSOAPRequest()
-	rawBody, err := sendRequest(soapRequest)
+// Rate значение ключевой ставки Центрального Банка России.
+type Rate struct {
+	Date  time.Time // Дата, на которую установлена ставка
+	Value float64   // Ставка в процентах годовых
+}
+
+// Client получает ключевую ставку из веб-сервиса Центрального Банка России по адресу url.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient создает клиента веб-сервиса ЦБ с адресом url.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// KeyRate возвращает последнюю опубликованную ключевую ставку вместе с датой, на которую она установлена.
+func (c *Client) KeyRate(ctx context.Context) (Rate, error) {
+	now := time.Now()
+	rates, err := c.KeyRates(ctx, now.AddDate(0, 0, -30), now)
+	if err != nil {
+		return Rate{}, err
+	}
+	if len(rates) == 0 {
+		return Rate{}, errors.New("данные ставки не найдены в ответе")
+	}
+	return rates[len(rates)-1], nil
+}
+
+// KeyRates возвращает значения ключевой ставки на каждую дату с from по to включительно в порядке возрастания дат.
+func (c *Client) KeyRates(ctx context.Context, from, to time.Time) ([]Rate, error) {
+	soapRequest := buildSOAPRequest(from, to)
+	rawBody, err := c.sendRequest(ctx, soapRequest)
 	if err != nil {
-		return 0, fmt.Errorf("ошибка при отправке SOAP-запроса: %w", err)
+		return nil, fmt.Errorf("ошибка при отправке SOAP-запроса: %w", err)
 	}
-	rate, err := parseXMLResponse(rawBody)
+	rates, err := parseXMLResponse(rawBody)
 	if err != nil {
-		return 0, fmt.Errorf("ошибка при обработке XML-ответа: %w", err)
+		return nil, fmt.Errorf("ошибка при обработке XML-ответа: %w", err)
 	}
-	// Добавление маржи
-	return rate + MarginValue, nil
+	return rates, nil
 }
 
-// sendRequest отправляет SOAP-запрос к заданному сервису и возвращает необработанный ответ или ошибку.
-func sendRequest(soapRequest string) ([]byte, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("POST", CentralBankServiceURL, bytes.NewBuffer([]byte(soapRequest)))
+// sendRequest отправляет SOAP-запрос к сервису ЦБ и возвращает необработанный ответ или ошибку.
+func (c *Client) sendRequest(ctx context.Context, soapRequest string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewBuffer([]byte(soapRequest)))
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания HTTP-запроса: %w", err)
 	}
 	// Установка заголовков
 	req.Header.Set("Content-Type", ContentTypeHeader)
 	req.Header.Set("SOAPAction", SOAPActionHeader)
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения HTTP-запроса: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("сервис вернул статус %d", resp.StatusCode)
+	}
 	// Чтение ответа
 	rawBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -55,10 +90,11 @@ func sendRequest(soapRequest string) ([]byte, error) {
 	return rawBody, nil
 }
 
-// buildSOAPRequest создает строку SOAP-запроса для получения ключевой ставки из Центрального Банка России.
-func buildSOAPRequest() string {
-	fromDate := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	toDate := time.Now().Format("2006-01-02")
+// buildSOAPRequest создает строку SOAP-запроса для получения ключевой ставки из Центрального Банка России
+// за период с from по to.
+func buildSOAPRequest(from, to time.Time) string {
+	fromDate := from.Format("2006-01-02")
+	toDate := to.Format("2006-01-02")
 	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
         <soap12:Envelope xmlns:soap12="http://www.w3.org/2003/05/soap-envelope">
             <soap12:Body>
@@ -70,23 +106,46 @@ func buildSOAPRequest() string {
         </soap12:Envelope>`, fromDate, toDate)
 }
 
-// parseXMLResponse парсит XML-ответ и извлекает значение ключевой ставки из тега Rate.
-func parseXMLResponse(rawBody []byte) (float64, error) {
+// parseXMLResponse парсит XML-ответ и извлекает ряд ключевой ставки: значение из тега Rate и дату из тега DT
+// каждого элемента KR. Ставки возвращаются в порядке возрастания дат.
+func parseXMLResponse(rawBody []byte) ([]Rate, error) {
 	doc := etree.NewDocument()
 	if err := doc.ReadFromBytes(rawBody); err != nil {
-		return 0, fmt.Errorf("ошибка парсинга XML: %w", err)
+		return nil, fmt.Errorf("ошибка парсинга XML: %w", err)
 	}
 	krElements := doc.FindElements("//diffgram/KeyRate/KR")
-	if len(krElements) == 0 {
-		return 0, errors.New("данные ставки не найдены в ответе")
+	rates := make([]Rate, 0, len(krElements))
+	for _, kr := range krElements {
+		rate, err := parseRate(kr)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
 	}
-	rateElement := krElements[0].FindElement("./Rate")
+	// Сервис ЦБ отдает ряд от новых дат к старым.
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Date.Before(rates[j].Date) })
+	return rates, nil
+}
+
+// parseRate извлекает значение ставки и ее дату из элемента KR.
+func parseRate(kr *etree.Element) (Rate, error) {
+	rateElement := kr.FindElement("./Rate")
 	if rateElement == nil {
-		return 0, errors.New("тег Rate отсутствует в ответе")
+		return Rate{}, errors.New("тег Rate отсутствует в ответе")
+	}
+	dateElement := kr.FindElement("./DT")
+	if dateElement == nil {
+		return Rate{}, errors.New("тег DT отсутствует в ответе")
 	}
 	var rate float64
 	if _, err := fmt.Sscanf(rateElement.Text(), "%f", &rate); err != nil {
-		return 0, fmt.Errorf("ошибка конвертации ставки в число: %w", err)
+		return Rate{}, fmt.Errorf("ошибка конвертации ставки в число: %w", err)
+	}
+	date, err := time.Parse(time.RFC3339, dateElement.Text())
+	if err != nil {
+		return Rate{}, fmt.Errorf("ошибка конвертации даты ставки: %w", err)
 	}
-	return rate, nil
+	// Дата ставки указывается по московскому времени, поэтому сохраняется только календарный день.
+	year, month, day := date.Date()
+	return Rate{Date: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Value: rate}, nil
 }
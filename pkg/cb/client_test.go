@@ -0,0 +1,79 @@
+package cb
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const keyRateResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>
+    <KeyRateResponse xmlns="http://web.cbr.ru/">
+      <KeyRateResult>
+        <diffgr:diffgram xmlns:diffgr="urn:schemas-microsoft-com:xml-diffgram-v1">
+          <KeyRate xmlns="">
+            <KR><DT>2025-07-28T00:00:00+03:00</DT><Rate>18.00</Rate></KR>
+            <KR><DT>2025-07-25T00:00:00+03:00</DT><Rate>20.00</Rate></KR>
+          </KeyRate>
+        </diffgr:diffgram>
+      </KeyRateResult>
+    </KeyRateResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestClient_KeyRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, SOAPActionHeader, r.Header.Get("SOAPAction"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "<KeyRate xmlns=\"http://web.cbr.ru/\">")
+
+		_, _ = w.Write([]byte(keyRateResponse))
+	}))
+	defer server.Close()
+
+	rate, err := NewClient(server.URL).KeyRate(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 18.0, rate.Value)
+	assert.Equal(t, time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), rate.Date)
+}
+
+func TestClient_KeyRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "<fromDate>2025-07-25</fromDate>")
+		assert.Contains(t, string(body), "<ToDate>2025-07-28</ToDate>")
+
+		_, _ = w.Write([]byte(keyRateResponse))
+	}))
+	defer server.Close()
+
+	rates, err := NewClient(server.URL).KeyRates(
+		context.Background(), time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Rate{
+		{Date: time.Date(2025, 7, 25, 0, 0, 0, 0, time.UTC), Value: 20},
+		{Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), Value: 18},
+	}, rates)
+}
+
+func TestClient_KeyRateUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).KeyRate(context.Background())
+
+	assert.ErrorContains(t, err, "503")
+}
@@ -0,0 +1,103 @@
+package iso8583
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	maxFrameSize    = 1<<16 - 1
+	_defaultTimeout = 10 * time.Second
+)
+
+// WriteFrame записывает сообщение с двухбайтовым префиксом длины (big-endian).
+func WriteFrame(w io.Writer, data []byte) error {
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("iso8583: frame of %d bytes exceeds %d", len(data), maxFrameSize)
+	}
+
+	frame := make([]byte, 2+len(data))
+	binary.BigEndian.PutUint16(frame, uint16(len(data)))
+	copy(frame[2:], data)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadFrame читает сообщение с двухбайтовым префиксом длины (big-endian).
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint16(header[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Client отправляет сообщения ISO 8583 по TCP и ждет ответа на каждое из них.
+// Используется для проверки шлюза без подключения к реальному процессинговому центру.
+type Client struct {
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+// Dial устанавливает соединение со шлюзом по адресу addr.
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Send отправляет запрос и возвращает ответ шлюза. Если в ctx не задан дедлайн, ответ ожидается не дольше 10 секунд.
+func (c *Client) Send(ctx context.Context, request *Message) (*Message, error) {
+	data, err := request.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(_defaultTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	if err := WriteFrame(c.conn, data); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	frame, err := ReadFrame(c.conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	response, err := Decode(frame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response, nil
+}
+
+// Close закрывает соединение со шлюзом.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
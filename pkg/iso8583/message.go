@@ -0,0 +1,328 @@
+package iso8583
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Типы сообщений (MTI), поддерживаемые шлюзом.
+const (
+	MTIAuthorizationRequest  = "0100"
+	MTIAuthorizationResponse = "0110"
+	MTIFinancialRequest      = "0200"
+	MTIFinancialResponse     = "0210"
+	MTIReversalRequest       = "0400"
+	MTIReversalResponse      = "0410"
+)
+
+// Номера полей сообщения.
+const (
+	FieldPAN                   = 2
+	FieldProcessingCode        = 3
+	FieldAmount                = 4
+	FieldTransmissionDateTime  = 7
+	FieldSTAN                  = 11
+	FieldLocalTime             = 12
+	FieldLocalDate             = 13
+	FieldExpirationDate        = 14
+	FieldMCC                   = 18
+	FieldPOSEntryMode          = 22
+	FieldPOSConditionCode      = 25
+	FieldAcquiringInstitution  = 32
+	FieldRRN                   = 37
+	FieldAuthorizationID       = 38
+	FieldResponseCode          = 39
+	FieldTerminalID            = 41
+	FieldCardAcceptorID        = 42
+	FieldCardAcceptorName      = 43
+	FieldCurrencyCode          = 49
+	FieldOriginalDataElements  = 90
+	primaryBitmapFieldsCount   = 64
+	secondaryBitmapFieldsCount = 128
+)
+
+// Коды ответа (поле 39).
+const (
+	ResponseApproved             = "00"
+	ResponseDoNotHonor           = "05"
+	ResponseInvalidTransaction   = "12"
+	ResponseInvalidAmount        = "13"
+	ResponseInvalidCardNumber    = "14"
+	ResponseOriginalNotFound     = "25"
+	ResponseFormatError          = "30"
+	ResponseInsufficientFunds    = "51"
+	ResponseExpiredCard          = "54"
+	ResponseNotPermittedCardhold = "57"
+	ResponseExceedsAmountLimit   = "61"
+	ResponseRestrictedCard       = "62"
+	ResponseDuplicateTransaction = "94"
+	ResponseSystemMalfunction    = "96"
+)
+
+var (
+	ErrUnknownField = errors.New("iso8583: unknown field")
+	ErrInvalidField = errors.New("iso8583: invalid field value")
+	ErrShortMessage = errors.New("iso8583: message is too short")
+)
+
+type fieldFormat int
+
+const (
+	fixed fieldFormat = iota
+	llvar
+	lllvar
+)
+
+// fieldSpec описывает формат поля: фиксированная длина или переменная с префиксом длины из 2 или 3 цифр.
+type fieldSpec struct {
+	format  fieldFormat
+	length  int
+	numeric bool
+}
+
+var specs = map[int]fieldSpec{
+	FieldPAN:                  {format: llvar, length: 19, numeric: true},
+	FieldProcessingCode:       {format: fixed, length: 6, numeric: true},
+	FieldAmount:               {format: fixed, length: 12, numeric: true},
+	FieldTransmissionDateTime: {format: fixed, length: 10, numeric: true},
+	FieldSTAN:                 {format: fixed, length: 6, numeric: true},
+	FieldLocalTime:            {format: fixed, length: 6, numeric: true},
+	FieldLocalDate:            {format: fixed, length: 4, numeric: true},
+	FieldExpirationDate:       {format: fixed, length: 4, numeric: true},
+	FieldMCC:                  {format: fixed, length: 4, numeric: true},
+	FieldPOSEntryMode:         {format: fixed, length: 3, numeric: true},
+	FieldPOSConditionCode:     {format: fixed, length: 2, numeric: true},
+	FieldAcquiringInstitution: {format: llvar, length: 11, numeric: true},
+	FieldRRN:                  {format: fixed, length: 12},
+	FieldAuthorizationID:      {format: fixed, length: 6},
+	FieldResponseCode:         {format: fixed, length: 2},
+	FieldTerminalID:           {format: fixed, length: 8},
+	FieldCardAcceptorID:       {format: fixed, length: 15},
+	FieldCardAcceptorName:     {format: fixed, length: 40},
+	FieldCurrencyCode:         {format: fixed, length: 3, numeric: true},
+	FieldOriginalDataElements: {format: fixed, length: 42, numeric: true},
+}
+
+// Message представляет сообщение ISO 8583 в ASCII-кодировке с бинарной битовой картой.
+type Message struct {
+	MTI    string
+	fields map[int]string
+}
+
+// NewMessage создает пустое сообщение указанного типа.
+func NewMessage(mti string) *Message {
+	return &Message{
+		MTI:    mti,
+		fields: make(map[int]string),
+	}
+}
+
+// Set задает значение поля. Поля фиксированной длины дополняются: числовые — нулями слева, остальные — пробелами справа.
+func (m *Message) Set(field int, value string) error {
+	spec, ok := specs[field]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownField, field)
+	}
+
+	if spec.numeric && !isDigits(value) {
+		return fmt.Errorf("%w: field %d must be numeric", ErrInvalidField, field)
+	}
+
+	if len(value) > spec.length {
+		return fmt.Errorf("%w: field %d exceeds %d characters", ErrInvalidField, field, spec.length)
+	}
+
+	if spec.format == fixed && len(value) < spec.length {
+		if spec.numeric {
+			value = fmt.Sprintf("%0*s", spec.length, value)
+		} else {
+			value = fmt.Sprintf("%-*s", spec.length, value)
+		}
+	}
+
+	m.fields[field] = value
+	return nil
+}
+
+// Get возвращает значение поля и признак его наличия в сообщении.
+func (m *Message) Get(field int) (string, bool) {
+	value, ok := m.fields[field]
+	return value, ok
+}
+
+// Has сообщает, присутствует ли поле в сообщении.
+func (m *Message) Has(field int) bool {
+	_, ok := m.fields[field]
+	return ok
+}
+
+// Fields возвращает номера присутствующих полей в порядке возрастания.
+func (m *Message) Fields() []int {
+	fields := make([]int, 0, len(m.fields))
+	for field := range m.fields {
+		fields = append(fields, field)
+	}
+	sort.Ints(fields)
+	return fields
+}
+
+// Response создает ответ на сообщение: MTI ответа и эхо полей, которые процессинг ожидает получить обратно.
+func (m *Message) Response(responseCode string) *Message {
+	response := NewMessage(responseMTI(m.MTI))
+
+	for _, field := range []int{
+		FieldPAN, FieldProcessingCode, FieldAmount, FieldTransmissionDateTime, FieldSTAN,
+		FieldLocalTime, FieldLocalDate, FieldAcquiringInstitution, FieldRRN, FieldTerminalID,
+		FieldCardAcceptorID, FieldCurrencyCode, FieldOriginalDataElements,
+	} {
+		if value, ok := m.fields[field]; ok {
+			response.fields[field] = value
+		}
+	}
+
+	response.fields[FieldResponseCode] = responseCode
+	return response
+}
+
+// Encode кодирует сообщение: MTI, битовая карта (первичная и при необходимости вторичная) и поля по порядку.
+func (m *Message) Encode() ([]byte, error) {
+	if len(m.MTI) != 4 || !isDigits(m.MTI) {
+		return nil, fmt.Errorf("%w: invalid mti %q", ErrInvalidField, m.MTI)
+	}
+
+	fields := m.Fields()
+
+	bitmapSize := primaryBitmapFieldsCount / 8
+	if len(fields) > 0 && fields[len(fields)-1] > primaryBitmapFieldsCount {
+		bitmapSize = secondaryBitmapFieldsCount / 8
+	}
+
+	bitmap := make([]byte, bitmapSize)
+	if bitmapSize*8 == secondaryBitmapFieldsCount {
+		setBit(bitmap, 1)
+	}
+
+	var body bytes.Buffer
+	for _, field := range fields {
+		spec := specs[field]
+		value := m.fields[field]
+		setBit(bitmap, field)
+
+		switch spec.format {
+		case llvar:
+			body.WriteString(fmt.Sprintf("%02d", len(value)))
+		case lllvar:
+			body.WriteString(fmt.Sprintf("%03d", len(value)))
+		case fixed:
+		}
+		body.WriteString(value)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(m.MTI)
+	buf.Write(bitmap)
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Decode разбирает сообщение, закодированное Encode.
+func Decode(data []byte) (*Message, error) {
+	if len(data) < 4+primaryBitmapFieldsCount/8 {
+		return nil, ErrShortMessage
+	}
+
+	message := NewMessage(string(data[:4]))
+	if !isDigits(message.MTI) {
+		return nil, fmt.Errorf("%w: invalid mti %q", ErrInvalidField, message.MTI)
+	}
+
+	pos := 4
+	bitmap := data[pos : pos+primaryBitmapFieldsCount/8]
+	pos += primaryBitmapFieldsCount / 8
+
+	fieldsCount := primaryBitmapFieldsCount
+	if bitSet(bitmap, 1) {
+		if len(data) < pos+primaryBitmapFieldsCount/8 {
+			return nil, ErrShortMessage
+		}
+		bitmap = append(append([]byte{}, bitmap...), data[pos:pos+primaryBitmapFieldsCount/8]...)
+		pos += primaryBitmapFieldsCount / 8
+		fieldsCount = secondaryBitmapFieldsCount
+	}
+
+	for field := 2; field <= fieldsCount; field++ {
+		if !bitSet(bitmap, field) {
+			continue
+		}
+
+		spec, ok := specs[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownField, field)
+		}
+
+		length := spec.length
+		if spec.format != fixed {
+			prefix := 2
+			if spec.format == lllvar {
+				prefix = 3
+			}
+			if len(data) < pos+prefix {
+				return nil, ErrShortMessage
+			}
+
+			var err error
+			length, err = strconv.Atoi(string(data[pos : pos+prefix]))
+			if err != nil || length > spec.length {
+				return nil, fmt.Errorf("%w: field %d has invalid length", ErrInvalidField, field)
+			}
+			pos += prefix
+		}
+
+		if len(data) < pos+length {
+			return nil, ErrShortMessage
+		}
+
+		value := string(data[pos : pos+length])
+		if spec.numeric && !isDigits(value) {
+			return nil, fmt.Errorf("%w: field %d must be numeric", ErrInvalidField, field)
+		}
+
+		message.fields[field] = value
+		pos += length
+	}
+
+	if pos != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidField, len(data)-pos)
+	}
+
+	return message, nil
+}
+
+// responseMTI возвращает тип ответа для типа запроса: 0100 -> 0110, 0200 -> 0210, 0400 -> 0410.
+func responseMTI(mti string) string {
+	if len(mti) != 4 {
+		return mti
+	}
+	return mti[:2] + "1" + mti[3:]
+}
+
+func setBit(bitmap []byte, field int) {
+	bitmap[(field-1)/8] |= 0x80 >> uint((field-1)%8)
+}
+
+func bitSet(bitmap []byte, field int) bool {
+	return bitmap[(field-1)/8]&(0x80>>uint((field-1)%8)) != 0
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
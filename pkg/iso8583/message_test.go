@@ -0,0 +1,129 @@
+package iso8583
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMessage_EncodeDecode(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mti    string
+		fields map[int]string
+		want   map[int]string
+	}{
+		{
+			name: "authorization request with primary bitmap",
+			mti:  MTIAuthorizationRequest,
+			fields: map[int]string{
+				FieldPAN:            "4276380012345678",
+				FieldProcessingCode: "0",
+				FieldAmount:         "150050",
+				FieldSTAN:           "42",
+				FieldRRN:            "123456789012",
+				FieldTerminalID:     "TERM01",
+				FieldCurrencyCode:   "643",
+			},
+			want: map[int]string{
+				FieldPAN:            "4276380012345678",
+				FieldProcessingCode: "000000",
+				FieldAmount:         "000000150050",
+				FieldSTAN:           "000042",
+				FieldRRN:            "123456789012",
+				FieldTerminalID:     "TERM01  ",
+				FieldCurrencyCode:   "643",
+			},
+		},
+		{
+			name: "reversal with secondary bitmap",
+			mti:  MTIReversalRequest,
+			fields: map[int]string{
+				FieldAmount:               "100",
+				FieldRRN:                  "123456789012",
+				FieldOriginalDataElements: "0200",
+			},
+			want: map[int]string{
+				FieldAmount:               "000000000100",
+				FieldRRN:                  "123456789012",
+				FieldOriginalDataElements: "000000000000000000000000000000000000000200",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			message := NewMessage(tc.mti)
+			for field, value := range tc.fields {
+				require.NoError(t, message.Set(field, value))
+			}
+
+			data, err := message.Encode()
+			require.NoError(t, err)
+
+			decoded, err := Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.mti, decoded.MTI)
+
+			for field, value := range tc.want {
+				got, ok := decoded.Get(field)
+				assert.True(t, ok, "field %d", field)
+				assert.Equal(t, value, got, "field %d", field)
+			}
+			assert.Len(t, decoded.Fields(), len(tc.want))
+		})
+	}
+}
+
+func TestMessage_Set(t *testing.T) {
+	message := NewMessage(MTIAuthorizationRequest)
+
+	assert.ErrorIs(t, message.Set(FieldAmount, "12a"), ErrInvalidField)
+	assert.ErrorIs(t, message.Set(FieldPAN, "12345678901234567890"), ErrInvalidField)
+	assert.ErrorIs(t, message.Set(5, "1"), ErrUnknownField)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	message := NewMessage(MTIFinancialRequest)
+	require.NoError(t, message.Set(FieldPAN, "4276380012345678"))
+	require.NoError(t, message.Set(FieldAmount, "100"))
+
+	data, err := message.Encode()
+	require.NoError(t, err)
+
+	_, err = Decode(data[:len(data)-3])
+	assert.ErrorIs(t, err, ErrShortMessage)
+
+	_, err = Decode(append(data, '0'))
+	assert.ErrorIs(t, err, ErrInvalidField)
+
+	_, err = Decode([]byte("01"))
+	assert.ErrorIs(t, err, ErrShortMessage)
+}
+
+func TestMessage_Response(t *testing.T) {
+	request := NewMessage(MTIFinancialRequest)
+	require.NoError(t, request.Set(FieldSTAN, "42"))
+	require.NoError(t, request.Set(FieldRRN, "123456789012"))
+	require.NoError(t, request.Set(FieldMCC, "5411"))
+
+	response := request.Response(ResponseInsufficientFunds)
+
+	assert.Equal(t, MTIFinancialResponse, response.MTI)
+	code, _ := response.Get(FieldResponseCode)
+	assert.Equal(t, ResponseInsufficientFunds, code)
+	stan, _ := response.Get(FieldSTAN)
+	assert.Equal(t, "000042", stan)
+	assert.False(t, response.Has(FieldMCC))
+}
+
+func TestFrame(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteFrame(&buf, []byte("0800")))
+	assert.Equal(t, []byte{0, 4, '0', '8', '0', '0'}, buf.Bytes())
+
+	data, err := ReadFrame(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0800"), data)
+}
@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+)
+
+// fontFile моноширинный шрифт DejaVu Sans Mono с кириллицей. Условия распространения — в fonts/LICENSE.
+//
+//go:embed fonts/DejaVuSansMono.ttf
+var fontFile []byte
+
+// fontName имя шрифта в документе.
+const fontName = "DejaVuSansMono"
+
+// font метрики и таблица символов шрифта TrueType, необходимые для встраивания в PDF.
+// Размеры указаны в тысячных долях кегля, как принято в PDF.
+type font struct {
+	bbox    [4]int          // Габариты глифов: xMin, yMin, xMax, yMax
+	ascent  int             // Высота над базовой линией
+	descent int             // Глубина под базовой линией (отрицательная)
+	width   int             // Ширина глифа моноширинного шрифта
+	glyphs  map[rune]uint16 // Номера глифов по символам Unicode
+	stream  []byte          // Файл шрифта, сжатый FlateDecode
+}
+
+// embedded шрифт, встраиваемый в каждый документ. Разбирается один раз при загрузке пакета.
+var embedded = func() *font {
+	f, err := parseFont(fontFile)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: failed to parse embedded font: %v", err))
+	}
+
+	return f
+}()
+
+// glyph возвращает номер глифа символа r и символ, который этим глифом изображается. Символы, которых нет
+// в шрифте, заменяются на «?».
+func (f *font) glyph(r rune) (uint16, rune) {
+	if r == '\u00a0' {
+		r = ' '
+	}
+	if gid, ok := f.glyphs[r]; ok {
+		return gid, r
+	}
+
+	return f.glyphs['?'], '?'
+}
+
+// parseFont читает из файла TrueType метрики шрифта и таблицу символов Unicode (cmap формата 4).
+func parseFont(data []byte) (*font, error) {
+	tables := map[string][]byte{}
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font file is too short")
+	}
+	for i, n := 0, int(u16(data, 4)); i < n; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, fmt.Errorf("font table directory is truncated")
+		}
+		offset, length := int(u32(data, record+8)), int(u32(data, record+12))
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("font table %s is truncated", data[record:record+4])
+		}
+		tables[string(data[record:record+4])] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("font table %s is missing", tag)
+		}
+	}
+
+	head, hhea, hmtx := tables["head"], tables["hhea"], tables["hmtx"]
+	unitsPerEm := int(u16(head, 18))
+	scale := func(v int) int { return v * 1000 / unitsPerEm }
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	// В моноширинном шрифте у всех глифов одинаковая ширина, поэтому берется ширина первого глифа.
+	f := &font{
+		bbox:    [4]int{scale(int(i16(head, 36))), scale(int(i16(head, 38))), scale(int(i16(head, 40))), scale(int(i16(head, 42)))},
+		ascent:  scale(int(i16(hhea, 4))),
+		descent: scale(int(i16(hhea, 6))),
+		width:   scale(int(u16(hmtx, 0))),
+		glyphs:  glyphs,
+	}
+
+	var buf bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	_, _ = w.Write(data)
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress font: %w", err)
+	}
+	f.stream = buf.Bytes()
+
+	return f, nil
+}
+
+// parseCmap читает подтаблицу Unicode BMP (платформа 3, кодировка 1) формата 4.
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	for i, n := 0, int(u16(cmap, 2)); i < n; i++ {
+		record := 4 + 8*i
+		if u16(cmap, record) != 3 || u16(cmap, record+2) != 1 {
+			continue
+		}
+
+		table := cmap[u32(cmap, record+4):]
+		if u16(table, 0) != 4 {
+			return nil, fmt.Errorf("unsupported cmap format %d", u16(table, 0))
+		}
+
+		segments := int(u16(table, 6)) / 2
+		ends, starts, deltas, ranges := 14, 16+2*segments, 16+4*segments, 16+6*segments
+
+		glyphs := map[rune]uint16{}
+		for s := 0; s < segments; s++ {
+			end, start := u16(table, ends+2*s), u16(table, starts+2*s)
+			delta, rangeOffset := u16(table, deltas+2*s), int(u16(table, ranges+2*s))
+			for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+				gid := uint16(c) + delta
+				if rangeOffset != 0 {
+					gid = u16(table, ranges+2*s+rangeOffset+2*int(c-uint32(start)))
+					if gid != 0 {
+						gid += delta
+					}
+				}
+				if gid != 0 {
+					glyphs[rune(c)] = gid
+				}
+			}
+		}
+
+		return glyphs, nil
+	}
+
+	return nil, fmt.Errorf("font has no unicode cmap")
+}
+
+func u16(b []byte, offset int) uint16 { return binary.BigEndian.Uint16(b[offset:]) }
+
+func i16(b []byte, offset int) int16 { return int16(binary.BigEndian.Uint16(b[offset:])) }
+
+func u32(b []byte, offset int) uint32 { return binary.BigEndian.Uint32(b[offset:]) }
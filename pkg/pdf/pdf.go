@@ -0,0 +1,222 @@
+// Package pdf формирует простые текстовые PDF-документы без внешних зависимостей.
+//
+// Текст выводится моноширинным шрифтом DejaVu Sans Mono, который встраивается в файл, поэтому кириллица
+// отображается в любой программе просмотра. Символы кодируются номерами глифов (Identity-H), а таблица ToUnicode
+// позволяет копировать и искать текст. Документ не содержит даты создания, поэтому одинаковый текст всегда дает
+// одинаковые байты.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	pageWidth  = 595 // Ширина страницы A4 в пунктах
+	pageHeight = 842 // Высота страницы A4 в пунктах
+	margin     = 50  // Поля страницы в пунктах
+	fontSize   = 9   // Кегль шрифта в пунктах
+	leading    = 12  // Межстрочный интервал в пунктах
+
+	// MaxLineLength количество символов, которое помещается в строку: ширина глифа DejaVu Sans Mono составляет
+	// около 0,6 кегля.
+	MaxLineLength = (pageWidth - 2*margin) * 10 / (fontSize * 6)
+	// linesPerPage количество строк на странице.
+	linesPerPage = (pageHeight - 2*margin) / leading
+)
+
+// Document текстовый PDF-документ, который заполняется построчно.
+type Document struct {
+	title string
+	lines []string
+}
+
+// New создает пустой документ с заголовком title, который отображается в свойствах файла.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Write добавляет в документ текст. Каждая строка текста начинается с новой строки документа,
+// а строки длиннее MaxLineLength переносятся по словам. Символ перевода страницы \f начинает новую страницу.
+func (d *Document) Write(text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		d.lines = append(d.lines, wrap(strings.TrimRight(line, " \t\r"))...)
+	}
+}
+
+// Bytes возвращает документ в формате PDF.
+func (d *Document) Bytes() []byte {
+	pages := d.pages()
+
+	var buf bytes.Buffer
+	offsets := make([]int, 0, 8+2*len(pages))
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 9+2*i)
+	}
+
+	used := map[uint16]rune{}
+	contents := make([][]byte, len(pages))
+	for i, lines := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+		for _, line := range lines {
+			content.WriteString("T* <")
+			for _, r := range line {
+				gid, shown := embedded.glyph(r)
+				used[gid] = shown
+				fmt.Fprintf(&content, "%04X", gid)
+			}
+			content.WriteString("> Tj\n")
+		}
+		content.WriteString("ET")
+		contents[i] = content.Bytes()
+	}
+
+	f := embedded
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /" + fontName + " /Encoding /Identity-H /DescendantFonts [5 0 R] /ToUnicode 8 0 R >>")
+	object(fmt.Sprintf("<< /Title <%s> /Producer (bank-system) >>", utf16Hex(d.title)))
+	object(fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor 6 0 R /DW %d /CIDToGIDMap /Identity >>",
+		fontName, f.width,
+	))
+	object(fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 33 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d "+
+			"/CapHeight %d /StemV 80 /FontFile2 7 0 R >>",
+		fontName, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.ascent,
+	))
+	object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(f.stream), len(fontFile), f.stream))
+
+	cmap := toUnicode(used)
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(cmap), cmap))
+
+	for i, content := range contents {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 10+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pages разбивает строки документа на страницы.
+func (d *Document) pages() [][]string {
+	pages := [][]string{{}}
+	for _, line := range d.lines {
+		last := len(pages) - 1
+		if strings.HasPrefix(line, "\f") {
+			if len(pages[last]) > 0 {
+				pages = append(pages, []string{})
+				last++
+			}
+			line = strings.TrimPrefix(line, "\f")
+		}
+
+		if len(pages[last]) == linesPerPage {
+			pages = append(pages, []string{})
+			last++
+		}
+		pages[last] = append(pages[last], line)
+	}
+
+	return pages
+}
+
+// wrap переносит строку line по словам так, чтобы каждая часть была не длиннее MaxLineLength символов.
+// Слова длиннее строки разрываются.
+func wrap(line string) []string {
+	if utf8.RuneCountInString(line) <= MaxLineLength {
+		return []string{line}
+	}
+
+	var result []string
+	var current []rune
+	for _, word := range strings.Fields(line) {
+		runes := []rune(word)
+		for len(runes) > MaxLineLength {
+			if len(current) > 0 {
+				result = append(result, string(current))
+				current = nil
+			}
+			result = append(result, string(runes[:MaxLineLength]))
+			runes = runes[MaxLineLength:]
+		}
+
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= MaxLineLength:
+			current = append(append(current, ' '), runes...)
+		default:
+			result = append(result, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+
+	return result
+}
+
+// toUnicode формирует таблицу ToUnicode, сопоставляющую использованные в документе глифы used символам Unicode.
+func toUnicode(used map[uint16]rune) string {
+	gids := make([]int, 0, len(used))
+	for gid := range used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// В одном блоке bfchar допускается не больше 100 записей.
+	for start := 0; start < len(gids); start += 100 {
+		end := min(start+100, len(gids))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			fmt.Fprintf(&b, "<%04X> <%s>\n", gid, strings.TrimPrefix(utf16Hex(string(used[uint16(gid)])), "FEFF"))
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+
+	return b.String()
+}
+
+// utf16Hex кодирует строку в UTF-16BE с меткой порядка байтов для текстовых полей PDF.
+func utf16Hex(s string) string {
+	var b strings.Builder
+	b.WriteString("FEFF")
+	for _, c := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := New("Договор")
+	doc.Write("Кредитный договор № 1 (тест)\n")
+	doc.Write(strings.Repeat("строка\n", linesPerPage))
+	doc.Write("\fПриложение 😀")
+
+	data := doc.Bytes()
+
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 3")
+	assert.Contains(t, string(data), "/FontFile2 7 0 R")
+	assert.Contains(t, string(data), fmt.Sprintf("/Length1 %d /Filter /FlateDecode", len(fontFile)))
+	assert.Contains(t, string(data), "/DW 602")
+	assert.Equal(t, data, doc.Bytes())
+
+	// Кириллица выводится глифами встроенного шрифта, а ToUnicode возвращает исходные символы.
+	assert.Contains(t, string(data), "T* <0359")
+	assert.Contains(t, string(data), "<0359> <041A>\n")
+	assert.Contains(t, string(data), "<0790> <2116>\n")
+	assert.Contains(t, string(data), "<0022> <003F>\n")
+	assert.NotContains(t, string(data), "<0022> <D83DDE00>")
+
+	// Смещения в таблице xref должны указывать на начало соответствующих объектов.
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	require.NotNil(t, xref)
+	start, err := strconv.Atoi(string(xref[1]))
+	require.NoError(t, err)
+
+	size := regexp.MustCompile(`/Size (\d+) `).FindSubmatch(data)
+	require.NotNil(t, size)
+	objects, err := strconv.Atoi(string(size[1]))
+	require.NoError(t, err)
+	objects-- // Нулевая запись таблицы xref не соответствует объекту.
+	assert.Equal(t, 14, objects)
+
+	entries := strings.Split(string(data[start:]), "\n")[3:]
+	for i := 1; i <= objects; i++ {
+		offset, err := strconv.Atoi(entries[i-1][:10])
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i))), "object %d", i)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	line := strings.Repeat("слово ", 30)
+
+	parts := wrap(line)
+
+	assert.Len(t, parts, 2)
+	for _, part := range parts {
+		assert.LessOrEqual(t, len([]rune(part)), MaxLineLength)
+	}
+	assert.Equal(t, strings.TrimSpace(line), parts[0]+" "+parts[1])
+	assert.Equal(t, []string{strings.Repeat("x", MaxLineLength), "xx"}, wrap(strings.Repeat("x", MaxLineLength+2)))
+}
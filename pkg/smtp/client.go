@@ -39,3 +39,20 @@ func sendEmail(d *mail.Dialer, m *mail.Message) error {
 	}
 	return nil
 }
+
+// Client отправляет письма через SMTP-сервер банка.
+type Client struct {
+	dialer *mail.Dialer
+}
+
+// NewClient создает клиент SMTP с настройками подключения по умолчанию.
+func NewClient() *Client {
+	return &Client{
+		dialer: createDialer(),
+	}
+}
+
+// Send отправляет письмо с HTML-телом на адрес to.
+func (c *Client) Send(to string, subject string, body string) error {
+	return sendEmail(c.dialer, createMessage(to, subject, body))
+}